@@ -2,12 +2,23 @@ package main
 
 import (
 	"fxserver/middleware"
+	"fxserver/modules/achievement"
+	"fxserver/modules/attendance"
 	"fxserver/modules/auth"
+	"fxserver/modules/campaign"
 	"fxserver/modules/coupon"
+	"fxserver/modules/crafting"
+	"fxserver/modules/enhancement"
+	"fxserver/modules/gacha"
 	"fxserver/modules/item"
+	"fxserver/modules/mailbox"
 	"fxserver/modules/payment"
 	"fxserver/modules/reward"
+	"fxserver/modules/shop"
+	"fxserver/modules/trade"
 	"fxserver/modules/user"
+	"fxserver/pkg/events"
+	"fxserver/pkg/random"
 	"fxserver/pkg/validator"
 	"fxserver/server"
 
@@ -21,6 +32,8 @@ func main() {
 		fx.Provide(
 			zap.NewProduction,
 			validator.New,
+			random.NewFromEnv,
+			events.NewBus, // 도메인 이벤트 (결제 완료, 쿠폰 사용, 아이템 사용, 로그인)
 			middleware.NewLoggerMiddleware,
 			middleware.NewErrorMiddleware,
 			server.NewEchoServer,
@@ -28,9 +41,18 @@ func main() {
 		auth.Module,
 		item.Module,     // 기본 아이템 시스템
 		payment.Module,  // 결제 처리 (item 의존)
-		reward.Module,   // 통합 보상 시스템 (item, payment 의존)
+		reward.Module,   // 통합 보상 시스템 (item, payment, mailbox, user 의존)
 		user.Module,
 		coupon.Module,   // 쿠폰 시스템 (reward 의존하여 아이템 지급)
+		enhancement.Module, // 장비 강화 (item 의존)
+		crafting.Module,    // 아이템 제작 (item 의존)
+		gacha.Module,       // 가챠 뽑기 (item, reward 의존)
+		trade.Module,       // 선물/거래 (item, user 의존)
+		shop.Module,        // 상점 (item 의존)
+		mailbox.Module,     // 우편함 (item 의존)
+		attendance.Module,  // 출석 보상 달력 (reward 의존)
+		achievement.Module, // 업적 (reward 의존, 도메인 이벤트 구독)
+		campaign.Module,    // 예약 보상 캠페인 (reward, user 의존)
 		fx.Invoke(func(s *server.EchoServer) {
 			// Server will be started by lifecycle hooks
 		}),
@@ -0,0 +1,49 @@
+package achievement
+
+import (
+	"fxserver/modules/achievement/entity"
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Achievement management DTOs (Admin only)
+type CreateAchievementRequest struct {
+	Name        string        `json:"name" validate:"required,min=2,max=100"`
+	Description string        `json:"description,omitempty" validate:"omitempty,max=500"`
+	Metric      entity.Metric `json:"metric" validate:"required"`
+	Tiers       []entity.Tier `json:"tiers" validate:"required,min=1,max=20,dive"` // 임계값 오름차순
+	IsActive    *bool         `json:"is_active,omitempty"`                         // 미지정 시 활성
+}
+
+type UpdateAchievementRequest struct {
+	Name        string        `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description *string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Tiers       []entity.Tier `json:"tiers,omitempty" validate:"omitempty,min=1,max=20,dive"` // 지정 시 전체 교체
+	IsActive    *bool         `json:"is_active,omitempty"`
+}
+
+// Response DTOs
+type AchievementStatus struct {
+	Achievement   *entity.Achievement `json:"achievement"`
+	Progress      int                 `json:"progress"`                 // 지표의 현재 값
+	ReachedTiers  int                 `json:"reached_tiers"`            // 달성한 단계 수
+	ClaimedTiers  int                 `json:"claimed_tiers"`            // 보상을 받은 단계 수
+	Claimable     bool                `json:"claimable"`                // 받을 수 있는 보상이 있는지
+	NextThreshold int                 `json:"next_threshold,omitempty"` // 다음 단계 임계값 (0: 모든 단계 달성)
+}
+
+type ClaimResponse struct {
+	Claim       *entity.Claim           `json:"claim"`
+	Status      AchievementStatus       `json:"status"`
+	GrantResult *itemEntity.GrantResult `json:"grant_result,omitempty"`
+}
+
+// Query DTOs
+type AchievementQuery struct {
+	IncludeInactive bool `query:"include_inactive"`
+}
+
+type ClaimQuery struct {
+	UserID        int `query:"user_id" validate:"omitempty,gt=0"` // 관리자 전용
+	AchievementID int `query:"achievement_id" validate:"omitempty,gt=0"`
+	Limit         int `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
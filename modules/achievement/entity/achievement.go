@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Metric is a per-player counter that domain events advance
+type Metric string
+
+const (
+	MetricLoginDays         Metric = "login_days"          // 로그인한 날 수 (UTC 기준 하루 1회)
+	MetricPaymentCount      Metric = "payment_count"       // 완료된 결제 횟수
+	MetricCouponRedeemCount Metric = "coupon_redeem_count" // 사용한 쿠폰 수
+	MetricItemUseCount      Metric = "item_use_count"      // 사용한 아이템 개수
+)
+
+func IsValidMetric(metric string) bool {
+	switch Metric(metric) {
+	case MetricLoginDays, MetricPaymentCount, MetricCouponRedeemCount, MetricItemUseCount:
+		return true
+	default:
+		return false
+	}
+}
+
+// Tier is one step of an achievement; reaching Threshold on the metric unlocks Items
+type Tier struct {
+	Threshold int                     `json:"threshold" validate:"required,gt=0"`
+	Items     []itemEntity.RewardItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// Achievement is an admin-defined goal on a metric with tiered rewards
+type Achievement struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Metric      Metric    `json:"metric"`
+	Tiers       []Tier    `json:"tiers"` // 임계값 오름차순
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ReachedTiers returns how many tiers a metric value unlocks
+func (a *Achievement) ReachedTiers(value int) int {
+	reached := 0
+	for _, tier := range a.Tiers {
+		if value < tier.Threshold {
+			break
+		}
+		reached++
+	}
+	return reached
+}
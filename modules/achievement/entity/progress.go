@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Progress is a player's value on one metric, shared by every achievement on that metric
+type Progress struct {
+	UserID        int       `json:"user_id"`
+	Metric        Metric    `json:"metric"`
+	Value         int       `json:"value"`
+	LastEventDate string    `json:"last_event_date,omitempty"` // 하루 1회만 증가하는 지표의 마지막 반영일 (YYYY-MM-DD, UTC)
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Claim records the tiers a player took rewards for in one request
+type Claim struct {
+	ID            int                     `json:"id"`
+	UserID        int                     `json:"user_id"`
+	AchievementID int                     `json:"achievement_id"`
+	FromTier      int                     `json:"from_tier"` // 이번에 받은 첫 단계 (1부터)
+	ToTier        int                     `json:"to_tier"`   // 이번에 받은 마지막 단계
+	Items         []itemEntity.RewardItem `json:"items"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
@@ -0,0 +1,45 @@
+package achievement
+
+import (
+	"fxserver/modules/achievement/entity"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/coupon"
+	"fxserver/modules/item"
+	"fxserver/modules/payment"
+	"fxserver/pkg/events"
+
+	"go.uber.org/zap"
+)
+
+// metricRule describes how one domain event advances a metric
+type metricRule struct {
+	metric     entity.Metric
+	countValue bool // 이벤트 수량(Value)만큼 증가, false면 1씩 증가
+	oncePerDay bool // 같은 날(UTC)에는 한 번만 증가
+}
+
+// metricRules maps each domain event type to the metrics it advances
+var metricRules = map[string][]metricRule{
+	userauth.EventUserLogin:       {{metric: entity.MetricLoginDays, oncePerDay: true}},
+	payment.EventPaymentCompleted: {{metric: entity.MetricPaymentCount}},
+	coupon.EventCouponRedeemed:    {{metric: entity.MetricCouponRedeemCount}},
+	item.EventItemUsed:            {{metric: entity.MetricItemUseCount, countValue: true}},
+}
+
+// RegisterEventHandlers subscribes the achievement service to the events its metrics track.
+// A failed progress update is logged and never fails the request that published the event.
+func RegisterEventHandlers(bus *events.Bus, service Service, logger *zap.Logger) {
+	eventTypes := make([]string, 0, len(metricRules))
+	for eventType := range metricRules {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	bus.Subscribe(func(event events.Event) {
+		if err := service.RecordEvent(event); err != nil {
+			logger.Error("Failed to record achievement progress",
+				zap.Error(err),
+				zap.String("event_type", event.Type),
+				zap.Int("user_id", event.UserID))
+		}
+	}, eventTypes...)
+}
@@ -0,0 +1,206 @@
+package achievement
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/modules/reward"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// GetMyAchievements returns the active achievements with the authenticated user's progress
+func (h *Handler) GetMyAchievements(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	statuses, err := h.service.GetStatus(userID)
+	if err != nil {
+		h.logger.Error("Failed to get achievement status", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get achievements"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(statuses))
+}
+
+// Claim takes the rewards of every reached tier not claimed yet
+func (h *Handler) Claim(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	achievementID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid achievement ID", "invalid_request_error"))
+	}
+
+	response, err := h.service.Claim(userID, achievementID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAchievementNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Achievement"))
+		case errors.Is(err, ErrNothingToClaim):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, reward.ErrSourceInactive) || errors.Is(err, reward.ErrSourceCapReached) ||
+			errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to claim achievement", zap.Error(err), zap.Int("user_id", userID), zap.Int("achievement_id", achievementID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to claim achievement"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetMyClaims returns the authenticated user's achievement claims
+func (h *Handler) GetMyClaims(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query ClaimQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	// 사용자는 본인 수령 기록만 조회 가능
+	query.UserID = userID
+
+	claims, err := h.service.ListClaims(query)
+	if err != nil {
+		h.logger.Error("Failed to list achievement claims", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get achievement claims"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(claims))
+}
+
+// Admin APIs
+
+// GetAchievements returns achievements, including inactive ones on request (Admin only)
+func (h *Handler) GetAchievements(c echo.Context) error {
+	var query AchievementQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	achievements, err := h.service.ListAchievements(query.IncludeInactive)
+	if err != nil {
+		h.logger.Error("Failed to list achievements", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get achievements"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(achievements))
+}
+
+// CreateAchievement creates an achievement with tiered rewards (Admin only)
+func (h *Handler) CreateAchievement(c echo.Context) error {
+	var req CreateAchievementRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	achievement, err := h.service.CreateAchievement(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidAchievement) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create achievement", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create achievement"))
+	}
+
+	return c.JSON(http.StatusCreated, achievement)
+}
+
+// UpdateAchievement updates an achievement (Admin only)
+func (h *Handler) UpdateAchievement(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid achievement ID", "invalid_request_error"))
+	}
+
+	var req UpdateAchievementRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	achievement, err := h.service.UpdateAchievement(id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAchievementNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Achievement"))
+		case errors.Is(err, ErrInvalidAchievement):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update achievement", zap.Error(err), zap.Int("achievement_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update achievement"))
+	}
+
+	return c.JSON(http.StatusOK, achievement)
+}
+
+// GetAllClaims returns achievement claims of all users with optional filters (Admin only)
+func (h *Handler) GetAllClaims(c echo.Context) error {
+	var query ClaimQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	claims, err := h.service.ListClaims(query)
+	if err != nil {
+		h.logger.Error("Failed to list achievement claims", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get achievement claims"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(claims))
+}
@@ -0,0 +1,22 @@
+package achievement
+
+import (
+	"fxserver/modules/achievement/repository"
+	"fxserver/pkg/router"
+
+	"go.uber.org/fx"
+)
+
+var Module = fx.Options(
+	repository.Module,
+	fx.Provide(
+		NewService,
+		NewHandler,
+		fx.Annotate(
+			NewRoutes,
+			fx.As(new(router.RouteRegistrar)),
+			fx.ResultTags(`group:"routes"`),
+		),
+	),
+	fx.Invoke(RegisterEventHandlers),
+)
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+
+	"fxserver/modules/achievement/entity"
+)
+
+var (
+	ErrAchievementNotFound = errors.New("achievement not found")
+	ErrClaimNotFound       = errors.New("claim not found")
+	ErrClaimConflict       = errors.New("claimed tiers changed concurrently")
+)
+
+// ClaimFilter narrows claim queries; zero values are ignored
+type ClaimFilter struct {
+	UserID        int
+	AchievementID int
+	Limit         int
+}
+
+type AchievementRepository interface {
+	Create(achievement *entity.Achievement) error
+	// GetByID returns a copy of the stored achievement
+	GetByID(id int) (*entity.Achievement, error)
+	Update(achievement *entity.Achievement) error
+	// List returns achievements ordered by ID
+	List() ([]*entity.Achievement, error)
+}
+
+type ProgressRepository interface {
+	// AddProgress adds delta to the player's metric. When date is set the metric advances at
+	// most once per date, so a second call with the same date is a no-op.
+	AddProgress(userID int, metric entity.Metric, delta int, date string) (*entity.Progress, error)
+	// GetProgress returns every metric of the player; metrics never advanced are absent
+	GetProgress(userID int) (map[entity.Metric]*entity.Progress, error)
+}
+
+type ClaimRepository interface {
+	// GetClaimedTiers returns how many tiers of each achievement the player has claimed
+	GetClaimedTiers(userID int) (map[int]int, error)
+	// SaveClaim stores the claim and marks tiers up to claim.ToTier as claimed.
+	// It fails with ErrClaimConflict unless exactly claim.FromTier-1 tiers were claimed before.
+	SaveClaim(claim *entity.Claim) error
+	// RevertClaim removes a claim whose rewards could not be granted and restores the claimed tiers
+	RevertClaim(claimID int) error
+	// ListClaims returns matching claims, newest first
+	ListClaims(filter ClaimFilter) ([]*entity.Claim, error)
+}
+
+type Repository interface {
+	AchievementRepository
+	ProgressRepository
+	ClaimRepository
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"fxserver/modules/achievement/entity"
+)
+
+type memoryRepository struct {
+	mu           sync.RWMutex
+	achievements map[int]*entity.Achievement
+	progress     map[int]map[entity.Metric]*entity.Progress // key: userID
+	claimedTiers map[string]int                             // key: "userID:achievementID"
+	claims       []*entity.Claim
+	nextID       int
+	nextClaimID  int
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		achievements: make(map[int]*entity.Achievement),
+		progress:     make(map[int]map[entity.Metric]*entity.Progress),
+		claimedTiers: make(map[string]int),
+		nextID:       1,
+		nextClaimID:  1,
+	}
+}
+
+func claimedKey(userID, achievementID int) string {
+	return fmt.Sprintf("%d:%d", userID, achievementID)
+}
+
+// Achievement operations
+
+func (r *memoryRepository) Create(achievement *entity.Achievement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	achievement.ID = r.nextID
+	achievement.CreatedAt = now
+	achievement.UpdatedAt = now
+	r.achievements[achievement.ID] = copyAchievement(achievement)
+	r.nextID++
+	return nil
+}
+
+func (r *memoryRepository) GetByID(id int) (*entity.Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	achievement, exists := r.achievements[id]
+	if !exists {
+		return nil, ErrAchievementNotFound
+	}
+	return copyAchievement(achievement), nil
+}
+
+func (r *memoryRepository) Update(achievement *entity.Achievement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.achievements[achievement.ID]; !exists {
+		return ErrAchievementNotFound
+	}
+	achievement.UpdatedAt = time.Now()
+	r.achievements[achievement.ID] = copyAchievement(achievement)
+	return nil
+}
+
+func (r *memoryRepository) List() ([]*entity.Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.achievements))
+	for id := range r.achievements {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	achievements := make([]*entity.Achievement, len(ids))
+	for i, id := range ids {
+		achievements[i] = copyAchievement(r.achievements[id])
+	}
+	return achievements, nil
+}
+
+func copyAchievement(achievement *entity.Achievement) *entity.Achievement {
+	copied := *achievement
+	copied.Tiers = slices.Clone(achievement.Tiers)
+	return &copied
+}
+
+// Progress operations
+
+func (r *memoryRepository) AddProgress(userID int, metric entity.Metric, delta int, date string) (*entity.Progress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics, exists := r.progress[userID]
+	if !exists {
+		metrics = make(map[entity.Metric]*entity.Progress)
+		r.progress[userID] = metrics
+	}
+	progress, exists := metrics[metric]
+	if !exists {
+		progress = &entity.Progress{UserID: userID, Metric: metric}
+		metrics[metric] = progress
+	}
+
+	if date == "" || progress.LastEventDate != date {
+		progress.Value += delta
+		progress.UpdatedAt = time.Now()
+		if date != "" {
+			progress.LastEventDate = date
+		}
+	}
+
+	copied := *progress
+	return &copied, nil
+}
+
+func (r *memoryRepository) GetProgress(userID int) (map[entity.Metric]*entity.Progress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[entity.Metric]*entity.Progress, len(r.progress[userID]))
+	for metric, progress := range r.progress[userID] {
+		copied := *progress
+		result[metric] = &copied
+	}
+	return result, nil
+}
+
+// Claim operations
+
+func (r *memoryRepository) GetClaimedTiers(userID int) (map[int]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[int]int)
+	for id := range r.achievements {
+		if claimed := r.claimedTiers[claimedKey(userID, id)]; claimed > 0 {
+			result[id] = claimed
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) SaveClaim(claim *entity.Claim) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := claimedKey(claim.UserID, claim.AchievementID)
+	if r.claimedTiers[key] != claim.FromTier-1 {
+		return ErrClaimConflict
+	}
+
+	claim.ID = r.nextClaimID
+	claim.CreatedAt = time.Now()
+	r.claims = append(r.claims, claim)
+	r.claimedTiers[key] = claim.ToTier
+	r.nextClaimID++
+	return nil
+}
+
+func (r *memoryRepository) RevertClaim(claimID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := slices.IndexFunc(r.claims, func(claim *entity.Claim) bool { return claim.ID == claimID })
+	if index < 0 {
+		return ErrClaimNotFound
+	}
+	claim := r.claims[index]
+	r.claims = slices.Delete(r.claims, index, index+1)
+	r.claimedTiers[claimedKey(claim.UserID, claim.AchievementID)] = claim.FromTier - 1
+	return nil
+}
+
+func (r *memoryRepository) ListClaims(filter ClaimFilter) ([]*entity.Claim, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var claims []*entity.Claim
+	for i := len(r.claims) - 1; i >= 0; i-- {
+		claim := r.claims[i]
+		if filter.UserID != 0 && claim.UserID != filter.UserID {
+			continue
+		}
+		if filter.AchievementID != 0 && claim.AchievementID != filter.AchievementID {
+			continue
+		}
+		claims = append(claims, claim)
+		if filter.Limit > 0 && len(claims) >= filter.Limit {
+			break
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,49 @@
+package achievement
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// User achievement routes (user auth required)
+	achievements := api.Group("/achievements")
+	achievements.GET("", r.handler.GetMyAchievements, r.userMiddleware.VerifyAccessToken())  // Get achievements with my progress
+	achievements.POST("/:id/claim", r.handler.Claim, r.userMiddleware.VerifyAccessToken())   // Claim reached tiers
+	achievements.GET("/claims", r.handler.GetMyClaims, r.userMiddleware.VerifyAccessToken()) // Get my claim history
+
+	// Admin achievement management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminAchievements := admin.Group("/achievements")
+	adminAchievements.GET("", r.handler.GetAchievements, r.adminMiddleware.VerifyAdminToken())       // List achievements (include_inactive=true for all)
+	adminAchievements.POST("", r.handler.CreateAchievement, r.adminMiddleware.VerifyAdminToken())    // Create achievement
+	adminAchievements.PUT("/:id", r.handler.UpdateAchievement, r.adminMiddleware.VerifyAdminToken()) // Update achievement
+	adminAchievements.GET("/claims", r.handler.GetAllClaims, r.adminMiddleware.VerifyAdminToken())   // Get achievement claims
+}
@@ -0,0 +1,334 @@
+package achievement
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"fxserver/modules/achievement/entity"
+	"fxserver/modules/achievement/repository"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/reward"
+	"fxserver/pkg/events"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAchievementNotFound = errors.New("achievement not found")
+	ErrNothingToClaim      = errors.New("no reached tier left to claim")
+	ErrInvalidAchievement  = errors.New("invalid achievement")
+)
+
+// Inventory ledger reference type for achievement claims; the ledger source is reward.RewardSourceAchievement
+const ReferenceTypeAchievement = "achievement"
+
+type Service interface {
+	// Achievement management (Admin)
+	CreateAchievement(req CreateAchievementRequest) (*entity.Achievement, error)
+	UpdateAchievement(id int, req UpdateAchievementRequest) (*entity.Achievement, error)
+	ListAchievements(includeInactive bool) ([]*entity.Achievement, error)
+
+	// Player operations
+	GetStatus(userID int) ([]AchievementStatus, error)
+	Claim(userID, achievementID int) (*ClaimResponse, error)
+
+	// Progress tracking
+	RecordEvent(event events.Event) error
+
+	// Claim history
+	ListClaims(query ClaimQuery) ([]*entity.Claim, error)
+}
+
+type service struct {
+	repo          repository.Repository
+	rewardService reward.Service
+	logger        *zap.Logger
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository    repository.Repository
+	RewardService reward.Service
+	Logger        *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:          p.Repository,
+		rewardService: p.RewardService,
+		logger:        p.Logger,
+	}
+}
+
+// Achievement management
+
+func (s *service) CreateAchievement(req CreateAchievementRequest) (*entity.Achievement, error) {
+	achievement := &entity.Achievement{
+		Name:        req.Name,
+		Description: req.Description,
+		Metric:      req.Metric,
+		Tiers:       req.Tiers,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		achievement.IsActive = *req.IsActive
+	}
+	if err := s.validateAchievement(achievement); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(achievement); err != nil {
+		s.logger.Error("Failed to create achievement", zap.Error(err))
+		return nil, fmt.Errorf("failed to create achievement: %w", err)
+	}
+
+	s.logger.Info("Achievement created",
+		zap.Int("achievement_id", achievement.ID),
+		zap.String("metric", string(achievement.Metric)),
+		zap.Int("tiers", len(achievement.Tiers)))
+
+	return achievement, nil
+}
+
+func (s *service) UpdateAchievement(id int, req UpdateAchievementRequest) (*entity.Achievement, error) {
+	achievement, err := s.getAchievement(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		achievement.Name = req.Name
+	}
+	if req.Description != nil {
+		achievement.Description = *req.Description
+	}
+	if req.Tiers != nil {
+		achievement.Tiers = req.Tiers
+	}
+	if req.IsActive != nil {
+		achievement.IsActive = *req.IsActive
+	}
+	if err := s.validateAchievement(achievement); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(achievement); err != nil {
+		s.logger.Error("Failed to update achievement", zap.Error(err), zap.Int("achievement_id", id))
+		return nil, fmt.Errorf("failed to update achievement: %w", err)
+	}
+
+	s.logger.Info("Achievement updated",
+		zap.Int("achievement_id", achievement.ID),
+		zap.Bool("is_active", achievement.IsActive))
+
+	return achievement, nil
+}
+
+func (s *service) validateAchievement(achievement *entity.Achievement) error {
+	if !entity.IsValidMetric(string(achievement.Metric)) {
+		return fmt.Errorf("%w: unknown metric %s", ErrInvalidAchievement, achievement.Metric)
+	}
+	for i, tier := range achievement.Tiers {
+		if i > 0 && tier.Threshold <= achievement.Tiers[i-1].Threshold {
+			return fmt.Errorf("%w: tier thresholds must be strictly increasing", ErrInvalidAchievement)
+		}
+		if err := s.rewardService.ValidateRewardItems(tier.Items); err != nil {
+			return fmt.Errorf("%w: tier %d: %v", ErrInvalidAchievement, i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) ListAchievements(includeInactive bool) ([]*entity.Achievement, error) {
+	achievements, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+	if includeInactive {
+		return achievements, nil
+	}
+
+	active := make([]*entity.Achievement, 0, len(achievements))
+	for _, achievement := range achievements {
+		if achievement.IsActive {
+			active = append(active, achievement)
+		}
+	}
+	return active, nil
+}
+
+func (s *service) getAchievement(id int) (*entity.Achievement, error) {
+	achievement, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAchievementNotFound) {
+			return nil, ErrAchievementNotFound
+		}
+		return nil, fmt.Errorf("failed to get achievement: %w", err)
+	}
+	return achievement, nil
+}
+
+// Player operations
+
+func (s *service) GetStatus(userID int) ([]AchievementStatus, error) {
+	achievements, err := s.ListAchievements(false)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.repo.GetProgress(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+	claimed, err := s.repo.GetClaimedTiers(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed tiers: %w", err)
+	}
+
+	statuses := make([]AchievementStatus, len(achievements))
+	for i, achievement := range achievements {
+		statuses[i] = buildStatus(achievement, progress[achievement.Metric], claimed[achievement.ID])
+	}
+	return statuses, nil
+}
+
+func buildStatus(achievement *entity.Achievement, progress *entity.Progress, claimedTiers int) AchievementStatus {
+	value := 0
+	if progress != nil {
+		value = progress.Value
+	}
+	reached := achievement.ReachedTiers(value)
+
+	status := AchievementStatus{
+		Achievement:  achievement,
+		Progress:     value,
+		ReachedTiers: reached,
+		ClaimedTiers: claimedTiers,
+		Claimable:    reached > claimedTiers,
+	}
+	if reached < len(achievement.Tiers) {
+		status.NextThreshold = achievement.Tiers[reached].Threshold
+	}
+	return status
+}
+
+// Claim grants the rewards of every reached tier not claimed yet in one request
+func (s *service) Claim(userID, achievementID int) (*ClaimResponse, error) {
+	achievement, err := s.getAchievement(achievementID)
+	if err != nil {
+		return nil, err
+	}
+	if !achievement.IsActive {
+		return nil, ErrAchievementNotFound
+	}
+
+	progress, err := s.repo.GetProgress(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+	claimed, err := s.repo.GetClaimedTiers(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimed tiers: %w", err)
+	}
+
+	status := buildStatus(achievement, progress[achievement.Metric], claimed[achievementID])
+	if !status.Claimable {
+		return nil, ErrNothingToClaim
+	}
+
+	var items []itemEntity.RewardItem
+	for _, tier := range achievement.Tiers[status.ClaimedTiers:status.ReachedTiers] {
+		items = append(items, tier.Items...)
+	}
+	items = itemEntity.MergeRewardItems(items)
+
+	// 지급 전에 수령 단계를 먼저 기록하여 같은 단계가 두 번 지급되지 않도록 함
+	// 동시에 들어온 요청도 SaveClaim에서 하나만 성공함
+	claim := &entity.Claim{
+		UserID:        userID,
+		AchievementID: achievementID,
+		FromTier:      status.ClaimedTiers + 1,
+		ToTier:        status.ReachedTiers,
+		Items:         items,
+	}
+	if err := s.repo.SaveClaim(claim); err != nil {
+		if errors.Is(err, repository.ErrClaimConflict) {
+			return nil, ErrNothingToClaim
+		}
+		return nil, fmt.Errorf("failed to save claim: %w", err)
+	}
+
+	result, err := s.rewardService.GrantItemsToUser(
+		userID,
+		items,
+		"",
+		reward.RewardSourceAchievement,
+		fmt.Sprintf("%s tier %d-%d", achievement.Name, claim.FromTier, claim.ToTier),
+		itemEntity.TransactionRef{
+			Type:  ReferenceTypeAchievement,
+			ID:    strconv.Itoa(claim.ID),
+			Actor: itemEntity.UserActor(userID),
+		},
+	)
+	if err != nil {
+		if revertErr := s.repo.RevertClaim(claim.ID); revertErr != nil {
+			s.logger.Error("Failed to revert achievement claim after grant failure",
+				zap.Error(revertErr),
+				zap.Int("claim_id", claim.ID),
+				zap.Int("user_id", userID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Achievement claimed",
+		zap.Int("user_id", userID),
+		zap.Int("achievement_id", achievementID),
+		zap.Int("from_tier", claim.FromTier),
+		zap.Int("to_tier", claim.ToTier))
+
+	status.ClaimedTiers = claim.ToTier
+	status.Claimable = false
+	return &ClaimResponse{Claim: claim, Status: status, GrantResult: result}, nil
+}
+
+// Progress tracking
+
+// RecordEvent advances the metrics the event counts towards; events no metric tracks are ignored
+func (s *service) RecordEvent(event events.Event) error {
+	for _, rule := range metricRules[event.Type] {
+		delta := 1
+		if rule.countValue {
+			delta = event.Value
+		}
+		date := ""
+		if rule.oncePerDay {
+			date = event.OccurredAt.UTC().Format("2006-01-02")
+		}
+
+		progress, err := s.repo.AddProgress(event.UserID, rule.metric, delta, date)
+		if err != nil {
+			return fmt.Errorf("failed to add %s progress: %w", rule.metric, err)
+		}
+
+		s.logger.Debug("Achievement progress updated",
+			zap.Int("user_id", event.UserID),
+			zap.String("metric", string(rule.metric)),
+			zap.Int("value", progress.Value))
+	}
+	return nil
+}
+
+// Claim history
+
+func (s *service) ListClaims(query ClaimQuery) ([]*entity.Claim, error) {
+	claims, err := s.repo.ListClaims(repository.ClaimFilter{
+		UserID:        query.UserID,
+		AchievementID: query.AchievementID,
+		Limit:         query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,225 @@
+package achievement
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"fxserver/modules/achievement/entity"
+	"fxserver/modules/achievement/repository"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/payment"
+	paymentEntity "fxserver/modules/payment/entity"
+	paymentRepository "fxserver/modules/payment/repository"
+	"fxserver/modules/reward"
+	"fxserver/modules/reward/rewardtest"
+	"fxserver/pkg/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID   = 1 // currency, takes no slot
+	potionID = 3 // stackable, takes one slot
+	userID   = 1
+)
+
+type fixture struct {
+	svc   Service
+	bus   *events.Bus
+	items *itemtest.Fixture
+}
+
+func setupAchievementService(t *testing.T) *fixture {
+	logger := zap.NewNop()
+	bus := events.NewBus(logger)
+	items := itemtest.NewWithEvents(bus)
+
+	svc := NewService(ServiceParam{
+		Repository:    repository.NewMemoryRepository(),
+		RewardService: rewardtest.New(items).Service,
+		Logger:        logger,
+	})
+	RegisterEventHandlers(bus, svc, logger)
+	return &fixture{svc: svc, bus: bus, items: items}
+}
+
+func goldTiers(thresholds ...int) []entity.Tier {
+	tiers := make([]entity.Tier, len(thresholds))
+	for i, threshold := range thresholds {
+		tiers[i] = entity.Tier{Threshold: threshold, Items: []itemEntity.RewardItem{{ItemID: goldID, Count: threshold * 10}}}
+	}
+	return tiers
+}
+
+func TestTieredClaims(t *testing.T) {
+	f := setupAchievementService(t)
+	achievement, err := f.svc.CreateAchievement(CreateAchievementRequest{
+		Name:   "Big spender",
+		Metric: entity.MetricPaymentCount,
+		Tiers:  goldTiers(1, 3, 5),
+	})
+	require.NoError(t, err)
+
+	_, err = f.svc.Claim(userID, achievement.ID)
+	assert.ErrorIs(t, err, ErrNothingToClaim)
+
+	for i := 0; i < 3; i++ {
+		f.bus.Publish(events.Event{Type: "payment.completed", UserID: userID})
+	}
+
+	statuses, err := f.svc.GetStatus(userID)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, 3, statuses[0].Progress)
+	assert.Equal(t, 2, statuses[0].ReachedTiers)
+	assert.True(t, statuses[0].Claimable)
+	assert.Equal(t, 5, statuses[0].NextThreshold)
+
+	// Both reached tiers are granted together
+	response, err := f.svc.Claim(userID, achievement.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Claim.FromTier)
+	assert.Equal(t, 2, response.Claim.ToTier)
+	assert.Equal(t, 40, f.items.Balance(userID, goldID))
+
+	_, err = f.svc.Claim(userID, achievement.ID)
+	assert.ErrorIs(t, err, ErrNothingToClaim)
+
+	// The ledger records the achievement source and points at the claim
+	entries, err := f.items.Items.GetInventoryTransactions(itemRepository.TransactionFilter{UserID: userID, Source: reward.RewardSourceAchievement})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ReferenceTypeAchievement, entries[0].ReferenceType)
+}
+
+func TestEventMetrics(t *testing.T) {
+	f := setupAchievementService(t)
+	day := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	// Logging in twice on one day counts once
+	f.bus.Publish(events.Event{Type: userauth.EventUserLogin, UserID: userID, OccurredAt: day})
+	f.bus.Publish(events.Event{Type: userauth.EventUserLogin, UserID: userID, OccurredAt: day.Add(time.Hour)})
+	f.bus.Publish(events.Event{Type: userauth.EventUserLogin, UserID: userID, OccurredAt: day.AddDate(0, 0, 1)})
+	// Item use counts the used quantity
+	f.bus.Publish(events.Event{Type: item.EventItemUsed, UserID: userID, Value: 4})
+	// Events of other players and unknown events are ignored
+	f.bus.Publish(events.Event{Type: userauth.EventUserLogin, UserID: 2, OccurredAt: day})
+	f.bus.Publish(events.Event{Type: "unknown.event", UserID: userID})
+
+	for _, metric := range []entity.Metric{entity.MetricLoginDays, entity.MetricItemUseCount} {
+		_, err := f.svc.CreateAchievement(CreateAchievementRequest{Name: string(metric), Metric: metric, Tiers: goldTiers(10)})
+		require.NoError(t, err)
+	}
+
+	// Progress recorded before an achievement existed still counts towards it
+	statuses, err := f.svc.GetStatus(userID)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, 2, statuses[0].Progress)
+	assert.Equal(t, 4, statuses[1].Progress)
+}
+
+func TestCompletedPaymentCountsOnce(t *testing.T) {
+	f := setupAchievementService(t)
+	_, err := f.svc.CreateAchievement(CreateAchievementRequest{Name: "First purchase", Metric: entity.MetricPaymentCount, Tiers: goldTiers(1)})
+	require.NoError(t, err)
+
+	payments := payment.NewService(payment.ServiceParam{
+		Repository: paymentRepository.NewMemoryRepository(),
+		Events:     f.bus,
+		Logger:     zap.NewNop(),
+	})
+	created, err := payments.ProcessPayment(payment.CreatePaymentRequest{
+		UserID:      userID,
+		Amount:      9.99,
+		Currency:    "USD",
+		Method:      paymentEntity.PaymentMethodCard,
+		ExternalID:  "ext-1",
+		RewardItems: []itemEntity.RewardItem{{ItemID: goldID, Count: 100}},
+	})
+	require.NoError(t, err)
+
+	// Completing twice publishes payment.completed only for the first transition
+	for i := 0; i < 2; i++ {
+		_, err = payments.UpdatePaymentStatus(created.PaymentID, payment.UpdatePaymentStatusRequest{Status: paymentEntity.PaymentStatusCompleted})
+		require.NoError(t, err)
+	}
+
+	statuses, err := f.svc.GetStatus(userID)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, 1, statuses[0].Progress)
+}
+
+func TestInvalidAchievement(t *testing.T) {
+	f := setupAchievementService(t)
+
+	_, err := f.svc.CreateAchievement(CreateAchievementRequest{Name: "Unknown", Metric: "kills", Tiers: goldTiers(1)})
+	assert.ErrorIs(t, err, ErrInvalidAchievement)
+
+	_, err = f.svc.CreateAchievement(CreateAchievementRequest{Name: "Unordered", Metric: entity.MetricLoginDays, Tiers: goldTiers(5, 5)})
+	assert.ErrorIs(t, err, ErrInvalidAchievement)
+
+	inactive := false
+	achievement, err := f.svc.CreateAchievement(CreateAchievementRequest{Name: "Hidden", Metric: entity.MetricLoginDays, Tiers: goldTiers(1), IsActive: &inactive})
+	require.NoError(t, err)
+	f.bus.Publish(events.Event{Type: userauth.EventUserLogin, UserID: userID})
+	_, err = f.svc.Claim(userID, achievement.ID)
+	assert.ErrorIs(t, err, ErrAchievementNotFound)
+}
+
+func TestConcurrentAchievementClaims(t *testing.T) {
+	f := setupAchievementService(t)
+	achievement, err := f.svc.CreateAchievement(CreateAchievementRequest{Name: "Coupon fan", Metric: entity.MetricCouponRedeemCount, Tiers: goldTiers(1)})
+	require.NoError(t, err)
+	f.bus.Publish(events.Event{Type: "coupon.redeemed", UserID: userID})
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.svc.Claim(userID, achievement.ID); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 10, f.items.Balance(userID, goldID))
+}
+
+func TestFailedAchievementGrantCanBeRetried(t *testing.T) {
+	f := setupAchievementService(t)
+	require.NoError(t, f.items.Items.SetSlotCapacity(userID, 0))
+	achievement, err := f.svc.CreateAchievement(CreateAchievementRequest{
+		Name:   "First login",
+		Metric: entity.MetricLoginDays,
+		Tiers:  []entity.Tier{{Threshold: 1, Items: []itemEntity.RewardItem{{ItemID: potionID, Count: 1}}}},
+	})
+	require.NoError(t, err)
+	f.bus.Publish(events.Event{Type: userauth.EventUserLogin, UserID: userID})
+
+	_, err = f.svc.Claim(userID, achievement.ID)
+	assert.ErrorIs(t, err, item.ErrInventoryFull)
+
+	require.NoError(t, f.items.Items.SetSlotCapacity(userID, 10))
+	response, err := f.svc.Claim(userID, achievement.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Status.ClaimedTiers)
+	assert.Equal(t, 1, f.items.Balance(userID, potionID))
+}
@@ -0,0 +1,51 @@
+package attendance
+
+import (
+	"fxserver/modules/attendance/entity"
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Calendar management DTOs (Admin only)
+type CreateCalendarRequest struct {
+	Name     string               `json:"name" validate:"required,min=2,max=100"`
+	Mode     entity.CalendarMode  `json:"mode" validate:"required,oneof=monthly rolling"`
+	Days     []entity.CalendarDay `json:"days" validate:"required,min=1,max=365,dive"` // monthly는 최대 31일
+	Timezone string               `json:"timezone,omitempty"`                          // 기본값: UTC
+	CatchUp  entity.CatchUpRule   `json:"catch_up"`
+	IsActive *bool                `json:"is_active,omitempty"` // 미지정 시 활성
+}
+
+type UpdateCalendarRequest struct {
+	Name     string               `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Days     []entity.CalendarDay `json:"days,omitempty" validate:"omitempty,min=1,max=365,dive"` // 지정 시 전체 교체
+	Timezone string               `json:"timezone,omitempty"`
+	CatchUp  *entity.CatchUpRule  `json:"catch_up,omitempty"`
+	IsActive *bool                `json:"is_active,omitempty"`
+}
+
+// Response DTOs
+type CalendarStatus struct {
+	Calendar     *entity.Calendar        `json:"calendar"`
+	Attendance   *entity.Attendance      `json:"attendance"`
+	Today        string                  `json:"today"` // 달력 시간대 기준 오늘
+	ClaimedToday bool                    `json:"claimed_today"`
+	NextDay      int                     `json:"next_day,omitempty"` // 다음 출석 시 받을 일차 (0: 이번 달 모두 수령)
+	NextItems    []itemEntity.RewardItem `json:"next_items,omitempty"`
+}
+
+type ClaimResponse struct {
+	Claim       *entity.Claim           `json:"claim"`
+	Attendance  *entity.Attendance      `json:"attendance"`
+	GrantResult *itemEntity.GrantResult `json:"grant_result,omitempty"`
+}
+
+// Query DTOs
+type CalendarQuery struct {
+	IncludeInactive bool `query:"include_inactive"`
+}
+
+type ClaimQuery struct {
+	UserID     int `query:"user_id" validate:"omitempty,gt=0"` // 관리자 전용
+	CalendarID int `query:"calendar_id" validate:"omitempty,gt=0"`
+	Limit      int `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Attendance is a player's progress on one calendar
+type Attendance struct {
+	UserID        int    `json:"user_id"`
+	CalendarID    int    `json:"calendar_id"`
+	LastClaimDate string `json:"last_claim_date,omitempty"` // 마지막 출석일 (달력 시간대 기준)
+	Period        string `json:"period,omitempty"`          // monthly 달력의 진행 월 (YYYY-MM)
+	DaysClaimed   int    `json:"days_claimed"`              // 현재 주기에서 받은 일차 수
+	Streak        int    `json:"streak"`                    // 연속 출석 일수
+	LongestStreak int    `json:"longest_streak"`
+	TotalClaims   int    `json:"total_claims"`
+}
+
+// Claim is one day's reward taken from a calendar
+type Claim struct {
+	ID         int                     `json:"id"`
+	UserID     int                     `json:"user_id"`
+	CalendarID int                     `json:"calendar_id"`
+	Date       string                  `json:"date"` // 출석일 (달력 시간대 기준)
+	Day        int                     `json:"day"`  // 받은 일차
+	Items      []itemEntity.RewardItem `json:"items"`
+	Streak     int                     `json:"streak"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// CalendarMode decides how a player moves through a calendar's days
+type CalendarMode string
+
+const (
+	CalendarModeMonthly CalendarMode = "monthly" // 그 달의 N번째 출석에 N일차 보상, 매월 1일 초기화
+	CalendarModeRolling CalendarMode = "rolling" // N일 주기로 반복
+)
+
+// DateLayout is how claim dates are stored, in the calendar's timezone
+const DateLayout = "2006-01-02"
+
+// CalendarDay is the reward for one day of a calendar
+type CalendarDay struct {
+	Items []itemEntity.RewardItem `json:"items" validate:"required,min=1,dive"`
+}
+
+// CatchUpRule decides what happens when a player misses days
+type CatchUpRule struct {
+	GraceDays     int  `json:"grace_days" validate:"gte=0,lte=30"` // 연속 출석이 끊기지 않고 넘어갈 수 있는 결석 일수
+	ResetProgress bool `json:"reset_progress"`                     // 연속 출석이 끊기면 rolling 달력을 1일차부터 다시 시작
+}
+
+// Calendar is an admin-configured login reward schedule
+type Calendar struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Mode      CalendarMode  `json:"mode"`
+	Days      []CalendarDay `json:"days"`     // 1일차부터 순서대로
+	Timezone  string        `json:"timezone"` // 하루가 바뀌는 기준 시간대 (IANA, 예: Asia/Seoul)
+	CatchUp   CatchUpRule   `json:"catch_up"`
+	IsActive  bool          `json:"is_active"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// Location returns the calendar's timezone; an unknown timezone falls back to UTC
+func (c *Calendar) Location() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Date returns the calendar date of t in the calendar's timezone
+func (c *Calendar) Date(t time.Time) string {
+	return t.In(c.Location()).Format(DateLayout)
+}
@@ -0,0 +1,206 @@
+package attendance
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/modules/reward"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// GetMyAttendance returns the active calendars with the authenticated user's progress
+func (h *Handler) GetMyAttendance(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	statuses, err := h.service.GetStatus(userID)
+	if err != nil {
+		h.logger.Error("Failed to get attendance status", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get attendance"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(statuses))
+}
+
+// Claim takes today's reward from a calendar
+func (h *Handler) Claim(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	calendarID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid calendar ID", "invalid_request_error"))
+	}
+
+	response, err := h.service.Claim(userID, calendarID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCalendarNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Calendar"))
+		case errors.Is(err, ErrAlreadyClaimed):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, ErrCalendarComplete) || errors.Is(err, reward.ErrSourceInactive) || errors.Is(err, reward.ErrSourceCapReached) ||
+			errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to claim attendance", zap.Error(err), zap.Int("user_id", userID), zap.Int("calendar_id", calendarID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to claim attendance"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetMyClaims returns the authenticated user's attendance history
+func (h *Handler) GetMyClaims(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query ClaimQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	// 사용자는 본인 출석 기록만 조회 가능
+	query.UserID = userID
+
+	claims, err := h.service.ListClaims(query)
+	if err != nil {
+		h.logger.Error("Failed to list attendance claims", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get attendance claims"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(claims))
+}
+
+// Admin APIs
+
+// GetCalendars returns calendars, including inactive ones on request (Admin only)
+func (h *Handler) GetCalendars(c echo.Context) error {
+	var query CalendarQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	calendars, err := h.service.ListCalendars(query.IncludeInactive)
+	if err != nil {
+		h.logger.Error("Failed to list calendars", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get calendars"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(calendars))
+}
+
+// CreateCalendar creates a login reward calendar (Admin only)
+func (h *Handler) CreateCalendar(c echo.Context) error {
+	var req CreateCalendarRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	calendar, err := h.service.CreateCalendar(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCalendar) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create calendar", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create calendar"))
+	}
+
+	return c.JSON(http.StatusCreated, calendar)
+}
+
+// UpdateCalendar updates a login reward calendar (Admin only)
+func (h *Handler) UpdateCalendar(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid calendar ID", "invalid_request_error"))
+	}
+
+	var req UpdateCalendarRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	calendar, err := h.service.UpdateCalendar(id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCalendarNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Calendar"))
+		case errors.Is(err, ErrInvalidCalendar):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update calendar", zap.Error(err), zap.Int("calendar_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update calendar"))
+	}
+
+	return c.JSON(http.StatusOK, calendar)
+}
+
+// GetAllClaims returns attendance claims of all users with optional filters (Admin only)
+func (h *Handler) GetAllClaims(c echo.Context) error {
+	var query ClaimQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	claims, err := h.service.ListClaims(query)
+	if err != nil {
+		h.logger.Error("Failed to list attendance claims", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get attendance claims"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(claims))
+}
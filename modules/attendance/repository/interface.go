@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+
+	"fxserver/modules/attendance/entity"
+)
+
+var (
+	ErrCalendarNotFound = errors.New("calendar not found")
+	ErrClaimNotFound    = errors.New("claim not found")
+	ErrAlreadyClaimed   = errors.New("already claimed for the day")
+)
+
+// ClaimFilter narrows claim queries; zero values are ignored
+type ClaimFilter struct {
+	UserID     int
+	CalendarID int
+	Limit      int
+}
+
+type CalendarRepository interface {
+	Create(calendar *entity.Calendar) error
+	// GetByID returns a copy of the stored calendar
+	GetByID(id int) (*entity.Calendar, error)
+	Update(calendar *entity.Calendar) error
+	// List returns calendars ordered by ID
+	List() ([]*entity.Calendar, error)
+}
+
+type AttendanceRepository interface {
+	// GetAttendance returns the player's progress; a player who never claimed gets empty progress
+	GetAttendance(userID, calendarID int) (*entity.Attendance, error)
+	// SaveClaim stores the claim and the player's new progress together.
+	// A second claim for the same user, calendar and date fails with ErrAlreadyClaimed.
+	SaveClaim(claim *entity.Claim, attendance *entity.Attendance) error
+	// RevertClaim removes a claim whose rewards could not be granted and restores the previous progress
+	RevertClaim(claimID int, previous *entity.Attendance) error
+	// ListClaims returns matching claims, newest first
+	ListClaims(filter ClaimFilter) ([]*entity.Claim, error)
+}
+
+type Repository interface {
+	CalendarRepository
+	AttendanceRepository
+}
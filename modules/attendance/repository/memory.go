@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"fxserver/modules/attendance/entity"
+)
+
+type memoryRepository struct {
+	mu          sync.RWMutex
+	calendars   map[int]*entity.Calendar
+	attendances map[string]*entity.Attendance // key: "userID:calendarID"
+	claims      []*entity.Claim
+	claimDates  map[string]int // key: "userID:calendarID:date", value: claim ID
+	nextID      int
+	nextClaimID int
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		calendars:   make(map[int]*entity.Calendar),
+		attendances: make(map[string]*entity.Attendance),
+		claimDates:  make(map[string]int),
+		nextID:      1,
+		nextClaimID: 1,
+	}
+}
+
+func attendanceKey(userID, calendarID int) string {
+	return fmt.Sprintf("%d:%d", userID, calendarID)
+}
+
+func claimDateKey(userID, calendarID int, date string) string {
+	return fmt.Sprintf("%d:%d:%s", userID, calendarID, date)
+}
+
+// Calendar operations
+
+func (r *memoryRepository) Create(calendar *entity.Calendar) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	calendar.ID = r.nextID
+	calendar.CreatedAt = now
+	calendar.UpdatedAt = now
+	r.calendars[calendar.ID] = copyCalendar(calendar)
+	r.nextID++
+	return nil
+}
+
+func (r *memoryRepository) GetByID(id int) (*entity.Calendar, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	calendar, exists := r.calendars[id]
+	if !exists {
+		return nil, ErrCalendarNotFound
+	}
+	return copyCalendar(calendar), nil
+}
+
+func (r *memoryRepository) Update(calendar *entity.Calendar) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.calendars[calendar.ID]; !exists {
+		return ErrCalendarNotFound
+	}
+	calendar.UpdatedAt = time.Now()
+	r.calendars[calendar.ID] = copyCalendar(calendar)
+	return nil
+}
+
+func (r *memoryRepository) List() ([]*entity.Calendar, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.calendars))
+	for id := range r.calendars {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	calendars := make([]*entity.Calendar, len(ids))
+	for i, id := range ids {
+		calendars[i] = copyCalendar(r.calendars[id])
+	}
+	return calendars, nil
+}
+
+func copyCalendar(calendar *entity.Calendar) *entity.Calendar {
+	copied := *calendar
+	copied.Days = slices.Clone(calendar.Days)
+	return &copied
+}
+
+// Attendance operations
+
+func (r *memoryRepository) GetAttendance(userID, calendarID int) (*entity.Attendance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if attendance, exists := r.attendances[attendanceKey(userID, calendarID)]; exists {
+		copied := *attendance
+		return &copied, nil
+	}
+	return &entity.Attendance{UserID: userID, CalendarID: calendarID}, nil
+}
+
+func (r *memoryRepository) SaveClaim(claim *entity.Claim, attendance *entity.Attendance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dateKey := claimDateKey(claim.UserID, claim.CalendarID, claim.Date)
+	if _, exists := r.claimDates[dateKey]; exists {
+		return ErrAlreadyClaimed
+	}
+
+	claim.ID = r.nextClaimID
+	claim.CreatedAt = time.Now()
+	r.claims = append(r.claims, claim)
+	r.claimDates[dateKey] = claim.ID
+	r.nextClaimID++
+
+	copied := *attendance
+	r.attendances[attendanceKey(attendance.UserID, attendance.CalendarID)] = &copied
+	return nil
+}
+
+func (r *memoryRepository) RevertClaim(claimID int, previous *entity.Attendance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := slices.IndexFunc(r.claims, func(claim *entity.Claim) bool { return claim.ID == claimID })
+	if index < 0 {
+		return ErrClaimNotFound
+	}
+	claim := r.claims[index]
+	r.claims = slices.Delete(r.claims, index, index+1)
+	delete(r.claimDates, claimDateKey(claim.UserID, claim.CalendarID, claim.Date))
+
+	copied := *previous
+	r.attendances[attendanceKey(previous.UserID, previous.CalendarID)] = &copied
+	return nil
+}
+
+func (r *memoryRepository) ListClaims(filter ClaimFilter) ([]*entity.Claim, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var claims []*entity.Claim
+	for i := len(r.claims) - 1; i >= 0; i-- {
+		claim := r.claims[i]
+		if filter.UserID != 0 && claim.UserID != filter.UserID {
+			continue
+		}
+		if filter.CalendarID != 0 && claim.CalendarID != filter.CalendarID {
+			continue
+		}
+		claims = append(claims, claim)
+		if filter.Limit > 0 && len(claims) >= filter.Limit {
+			break
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,49 @@
+package attendance
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// User attendance routes (user auth required)
+	attendance := api.Group("/attendance")
+	attendance.GET("", r.handler.GetMyAttendance, r.userMiddleware.VerifyAccessToken())    // Get calendars with my progress
+	attendance.POST("/:id/claim", r.handler.Claim, r.userMiddleware.VerifyAccessToken())   // Claim today's reward
+	attendance.GET("/claims", r.handler.GetMyClaims, r.userMiddleware.VerifyAccessToken()) // Get my attendance history
+
+	// Admin attendance management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminAttendance := admin.Group("/attendance")
+	adminAttendance.GET("/calendars", r.handler.GetCalendars, r.adminMiddleware.VerifyAdminToken())       // List calendars (include_inactive=true for all)
+	adminAttendance.POST("/calendars", r.handler.CreateCalendar, r.adminMiddleware.VerifyAdminToken())    // Create calendar
+	adminAttendance.PUT("/calendars/:id", r.handler.UpdateCalendar, r.adminMiddleware.VerifyAdminToken()) // Update calendar
+	adminAttendance.GET("/claims", r.handler.GetAllClaims, r.adminMiddleware.VerifyAdminToken())          // Get attendance claims
+}
@@ -0,0 +1,369 @@
+package attendance
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	_ "time/tzdata" // 서버에 zoneinfo가 없어도 달력 시간대를 사용할 수 있도록 함
+
+	"fxserver/modules/attendance/entity"
+	"fxserver/modules/attendance/repository"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/reward"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCalendarNotFound = errors.New("calendar not found")
+	ErrAlreadyClaimed   = errors.New("already claimed today")
+	ErrCalendarComplete = errors.New("all rewards of this month have been claimed")
+	ErrInvalidCalendar  = errors.New("invalid calendar")
+)
+
+// Inventory ledger reference type for attendance claims; the ledger source is reward.RewardSourceDaily
+const ReferenceTypeAttendance = "attendance"
+
+// MaxMonthlyDays is the longest schedule a monthly calendar can have
+const MaxMonthlyDays = 31
+
+type Service interface {
+	// Calendar management (Admin)
+	CreateCalendar(req CreateCalendarRequest) (*entity.Calendar, error)
+	UpdateCalendar(id int, req UpdateCalendarRequest) (*entity.Calendar, error)
+	ListCalendars(includeInactive bool) ([]*entity.Calendar, error)
+
+	// Player operations
+	GetStatus(userID int) ([]CalendarStatus, error)
+	Claim(userID, calendarID int) (*ClaimResponse, error)
+
+	// Claim history
+	ListClaims(query ClaimQuery) ([]*entity.Claim, error)
+}
+
+type service struct {
+	repo          repository.Repository
+	rewardService reward.Service
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository    repository.Repository
+	RewardService reward.Service
+	Logger        *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:          p.Repository,
+		rewardService: p.RewardService,
+		logger:        p.Logger,
+		now:           time.Now,
+	}
+}
+
+// Calendar management
+
+func (s *service) CreateCalendar(req CreateCalendarRequest) (*entity.Calendar, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	calendar := &entity.Calendar{
+		Name:     req.Name,
+		Mode:     req.Mode,
+		Days:     req.Days,
+		Timezone: timezone,
+		CatchUp:  req.CatchUp,
+		IsActive: true,
+	}
+	if req.IsActive != nil {
+		calendar.IsActive = *req.IsActive
+	}
+	if err := s.validateCalendar(calendar); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(calendar); err != nil {
+		s.logger.Error("Failed to create calendar", zap.Error(err))
+		return nil, fmt.Errorf("failed to create calendar: %w", err)
+	}
+
+	s.logger.Info("Attendance calendar created",
+		zap.Int("calendar_id", calendar.ID),
+		zap.String("mode", string(calendar.Mode)),
+		zap.Int("days", len(calendar.Days)),
+		zap.String("timezone", calendar.Timezone))
+
+	return calendar, nil
+}
+
+func (s *service) UpdateCalendar(id int, req UpdateCalendarRequest) (*entity.Calendar, error) {
+	calendar, err := s.getCalendar(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		calendar.Name = req.Name
+	}
+	if req.Days != nil {
+		calendar.Days = req.Days
+	}
+	if req.Timezone != "" {
+		calendar.Timezone = req.Timezone
+	}
+	if req.CatchUp != nil {
+		calendar.CatchUp = *req.CatchUp
+	}
+	if req.IsActive != nil {
+		calendar.IsActive = *req.IsActive
+	}
+	if err := s.validateCalendar(calendar); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(calendar); err != nil {
+		s.logger.Error("Failed to update calendar", zap.Error(err), zap.Int("calendar_id", id))
+		return nil, fmt.Errorf("failed to update calendar: %w", err)
+	}
+
+	s.logger.Info("Attendance calendar updated",
+		zap.Int("calendar_id", calendar.ID),
+		zap.Bool("is_active", calendar.IsActive))
+
+	return calendar, nil
+}
+
+func (s *service) validateCalendar(calendar *entity.Calendar) error {
+	if calendar.Mode == entity.CalendarModeMonthly && len(calendar.Days) > MaxMonthlyDays {
+		return fmt.Errorf("%w: monthly calendar can have at most %d days", ErrInvalidCalendar, MaxMonthlyDays)
+	}
+	if _, err := time.LoadLocation(calendar.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %s", ErrInvalidCalendar, calendar.Timezone)
+	}
+	for i, day := range calendar.Days {
+		if err := s.rewardService.ValidateRewardItems(day.Items); err != nil {
+			return fmt.Errorf("%w: day %d: %v", ErrInvalidCalendar, i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) ListCalendars(includeInactive bool) ([]*entity.Calendar, error) {
+	calendars, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %w", err)
+	}
+	if includeInactive {
+		return calendars, nil
+	}
+
+	active := make([]*entity.Calendar, 0, len(calendars))
+	for _, calendar := range calendars {
+		if calendar.IsActive {
+			active = append(active, calendar)
+		}
+	}
+	return active, nil
+}
+
+func (s *service) getCalendar(id int) (*entity.Calendar, error) {
+	calendar, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCalendarNotFound) {
+			return nil, ErrCalendarNotFound
+		}
+		return nil, fmt.Errorf("failed to get calendar: %w", err)
+	}
+	return calendar, nil
+}
+
+// Player operations
+
+func (s *service) GetStatus(userID int) ([]CalendarStatus, error) {
+	calendars, err := s.ListCalendars(false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	statuses := make([]CalendarStatus, 0, len(calendars))
+	for _, calendar := range calendars {
+		attendance, err := s.repo.GetAttendance(userID, calendar.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attendance: %w", err)
+		}
+
+		today := calendar.Date(now)
+		status := CalendarStatus{
+			Calendar:     calendar,
+			Attendance:   attendance,
+			Today:        today,
+			ClaimedToday: attendance.LastClaimDate == today,
+		}
+
+		// 오늘 이미 출석했다면 내일 받을 보상을 보여줌
+		nextDate := today
+		if status.ClaimedToday {
+			nextDate = calendar.Date(now.In(calendar.Location()).AddDate(0, 0, 1))
+		}
+		if _, day, err := advance(calendar, attendance, nextDate); err == nil {
+			status.NextDay = day
+			status.NextItems = calendar.Days[day-1].Items
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *service) Claim(userID, calendarID int) (*ClaimResponse, error) {
+	calendar, err := s.getCalendar(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if !calendar.IsActive {
+		return nil, ErrCalendarNotFound
+	}
+
+	previous, err := s.repo.GetAttendance(userID, calendarID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance: %w", err)
+	}
+
+	today := calendar.Date(s.now())
+	attendance, day, err := advance(calendar, previous, today)
+	if err != nil {
+		return nil, err
+	}
+
+	// 지급 전에 출석을 먼저 기록하여 같은 날 두 번 지급되지 않도록 함
+	// 동시에 들어온 요청도 SaveClaim에서 하나만 성공함
+	claim := &entity.Claim{
+		UserID:     userID,
+		CalendarID: calendarID,
+		Date:       today,
+		Day:        day,
+		Items:      calendar.Days[day-1].Items,
+		Streak:     attendance.Streak,
+	}
+	if err := s.repo.SaveClaim(claim, attendance); err != nil {
+		if errors.Is(err, repository.ErrAlreadyClaimed) {
+			return nil, ErrAlreadyClaimed
+		}
+		return nil, fmt.Errorf("failed to save claim: %w", err)
+	}
+
+	result, err := s.rewardService.GrantItemsToUser(
+		userID,
+		claim.Items,
+		"",
+		reward.RewardSourceDaily,
+		fmt.Sprintf("%s day %d", calendar.Name, day),
+		itemEntity.TransactionRef{
+			Type:  ReferenceTypeAttendance,
+			ID:    strconv.Itoa(claim.ID),
+			Actor: itemEntity.UserActor(userID),
+		},
+	)
+	if err != nil {
+		if revertErr := s.repo.RevertClaim(claim.ID, previous); revertErr != nil {
+			s.logger.Error("Failed to revert attendance claim after grant failure",
+				zap.Error(revertErr),
+				zap.Int("claim_id", claim.ID),
+				zap.Int("user_id", userID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Attendance claimed",
+		zap.Int("user_id", userID),
+		zap.Int("calendar_id", calendarID),
+		zap.String("date", today),
+		zap.Int("day", day),
+		zap.Int("streak", attendance.Streak))
+
+	return &ClaimResponse{Claim: claim, Attendance: attendance, GrantResult: result}, nil
+}
+
+// advance returns the player's progress after claiming on date and the calendar day (1-based) they receive
+func advance(calendar *entity.Calendar, previous *entity.Attendance, date string) (*entity.Attendance, int, error) {
+	if previous.LastClaimDate == date {
+		return nil, 0, ErrAlreadyClaimed
+	}
+
+	next := *previous
+	next.LastClaimDate = date
+	next.TotalClaims++
+
+	// 마지막 출석 이후 결석 일수가 유예 기간 이내면 연속 출석 유지
+	streakKept := false
+	if previous.LastClaimDate != "" {
+		gap, err := daysBetween(previous.LastClaimDate, date)
+		if err != nil {
+			return nil, 0, err
+		}
+		streakKept = gap >= 1 && gap <= 1+calendar.CatchUp.GraceDays
+	}
+	if streakKept {
+		next.Streak++
+	} else {
+		next.Streak = 1
+	}
+	next.LongestStreak = max(next.LongestStreak, next.Streak)
+
+	var day int
+	switch calendar.Mode {
+	case entity.CalendarModeMonthly:
+		period := date[:len("2006-01")]
+		if next.Period != period {
+			next.Period = period
+			next.DaysClaimed = 0
+		}
+		if next.DaysClaimed >= len(calendar.Days) {
+			return nil, 0, ErrCalendarComplete
+		}
+		day = next.DaysClaimed + 1
+
+	default:
+		if !streakKept && calendar.CatchUp.ResetProgress {
+			next.DaysClaimed = 0
+		}
+		day = next.DaysClaimed%len(calendar.Days) + 1
+	}
+	next.DaysClaimed++
+
+	return &next, day, nil
+}
+
+func daysBetween(from, to string) (int, error) {
+	start, err := time.Parse(entity.DateLayout, from)
+	if err != nil {
+		return 0, fmt.Errorf("invalid attendance date %q: %w", from, err)
+	}
+	end, err := time.Parse(entity.DateLayout, to)
+	if err != nil {
+		return 0, fmt.Errorf("invalid attendance date %q: %w", to, err)
+	}
+	return int(end.Sub(start).Hours() / 24), nil
+}
+
+// Claim history
+
+func (s *service) ListClaims(query ClaimQuery) ([]*entity.Claim, error) {
+	claims, err := s.repo.ListClaims(repository.ClaimFilter{
+		UserID:     query.UserID,
+		CalendarID: query.CalendarID,
+		Limit:      query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,210 @@
+package attendance
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"fxserver/modules/attendance/entity"
+	"fxserver/modules/attendance/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/reward"
+	"fxserver/modules/reward/rewardtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID   = 1 // currency, takes no slot
+	potionID = 3 // stackable, takes one slot
+	userID   = 1
+)
+
+type fixture struct {
+	svc   Service
+	items *itemtest.Fixture
+	now   time.Time
+}
+
+func setupAttendanceService(t *testing.T) *fixture {
+	logger := zap.NewNop()
+	items := itemtest.New()
+
+	f := &fixture{
+		items: items,
+		now:   time.Date(2026, time.January, 30, 12, 0, 0, 0, time.UTC),
+	}
+	svc := NewService(ServiceParam{
+		Repository:    repository.NewMemoryRepository(),
+		RewardService: rewardtest.New(items).Service,
+		Logger:        logger,
+	})
+	svc.(*service).now = func() time.Time { return f.now }
+	f.svc = svc
+	return f
+}
+
+func goldDays(counts ...int) []entity.CalendarDay {
+	days := make([]entity.CalendarDay, len(counts))
+	for i, count := range counts {
+		days[i] = entity.CalendarDay{Items: []itemEntity.RewardItem{{ItemID: goldID, Count: count}}}
+	}
+	return days
+}
+
+func TestMonthlyCalendar(t *testing.T) {
+	f := setupAttendanceService(t)
+	calendar, err := f.svc.CreateCalendar(CreateCalendarRequest{
+		Name: "January login",
+		Mode: entity.CalendarModeMonthly,
+		Days: goldDays(10, 20),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", calendar.Timezone)
+
+	response, err := f.svc.Claim(userID, calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Claim.Day)
+	assert.Equal(t, 10, f.items.Balance(userID, goldID))
+
+	_, err = f.svc.Claim(userID, calendar.ID)
+	assert.ErrorIs(t, err, ErrAlreadyClaimed)
+
+	f.now = f.now.AddDate(0, 0, 1)
+	response, err = f.svc.Claim(userID, calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Claim.Day)
+	assert.Equal(t, 2, response.Attendance.Streak)
+	assert.Equal(t, 30, f.items.Balance(userID, goldID))
+
+	// The ledger records the daily source and points at the claim
+	entries, err := f.items.Items.GetInventoryTransactions(itemRepository.TransactionFilter{UserID: userID, Source: reward.RewardSourceDaily})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, ReferenceTypeAttendance, entries[0].ReferenceType)
+
+	// The month has no more days; February starts over
+	statuses, err := f.svc.GetStatus(userID)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].ClaimedToday)
+	assert.Equal(t, 1, statuses[0].NextDay)
+
+	f.now = f.now.AddDate(0, 0, 1)
+	response, err = f.svc.Claim(userID, calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Claim.Day)
+	assert.Equal(t, "2026-02", response.Attendance.Period)
+}
+
+func TestRollingCalendarCatchUp(t *testing.T) {
+	f := setupAttendanceService(t)
+	calendar, err := f.svc.CreateCalendar(CreateCalendarRequest{
+		Name:    "Weekly login",
+		Mode:    entity.CalendarModeRolling,
+		Days:    goldDays(10, 20, 30),
+		CatchUp: entity.CatchUpRule{GraceDays: 1, ResetProgress: true},
+	})
+	require.NoError(t, err)
+
+	claimAfter := func(days int) *ClaimResponse {
+		f.now = f.now.AddDate(0, 0, days)
+		response, err := f.svc.Claim(userID, calendar.ID)
+		require.NoError(t, err)
+		return response
+	}
+
+	claimAfter(0)
+	// One missed day is within the grace period
+	response := claimAfter(2)
+	assert.Equal(t, 2, response.Claim.Day)
+	assert.Equal(t, 2, response.Attendance.Streak)
+
+	response = claimAfter(1)
+	assert.Equal(t, 3, response.Claim.Day)
+	response = claimAfter(1)
+	assert.Equal(t, 1, response.Claim.Day, "rolling calendars repeat")
+	assert.Equal(t, 4, response.Attendance.Streak)
+
+	// Two missed days break the streak and restart the cycle
+	response = claimAfter(3)
+	assert.Equal(t, 1, response.Claim.Day)
+	assert.Equal(t, 1, response.Attendance.Streak)
+	assert.Equal(t, 4, response.Attendance.LongestStreak)
+}
+
+func TestClaimResetsInCalendarTimezone(t *testing.T) {
+	f := setupAttendanceService(t)
+	calendar, err := f.svc.CreateCalendar(CreateCalendarRequest{
+		Name:     "Seoul login",
+		Mode:     entity.CalendarModeRolling,
+		Days:     goldDays(10),
+		Timezone: "Asia/Seoul",
+	})
+	require.NoError(t, err)
+
+	// 23:30 and 00:30 in Seoul are different days even though both are the same UTC date
+	f.now = time.Date(2026, time.January, 30, 14, 30, 0, 0, time.UTC)
+	_, err = f.svc.Claim(userID, calendar.ID)
+	require.NoError(t, err)
+	f.now = f.now.Add(time.Hour)
+	response, err := f.svc.Claim(userID, calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-31", response.Claim.Date)
+
+	_, err = f.svc.CreateCalendar(CreateCalendarRequest{Name: "Bad", Mode: entity.CalendarModeRolling, Days: goldDays(10), Timezone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, ErrInvalidCalendar)
+}
+
+func TestConcurrentClaims(t *testing.T) {
+	f := setupAttendanceService(t)
+	calendar, err := f.svc.CreateCalendar(CreateCalendarRequest{Name: "Daily", Mode: entity.CalendarModeRolling, Days: goldDays(100)})
+	require.NoError(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.svc.Claim(userID, calendar.ID); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 100, f.items.Balance(userID, goldID))
+}
+
+func TestFailedGrantCanBeRetried(t *testing.T) {
+	f := setupAttendanceService(t)
+	require.NoError(t, f.items.Items.SetSlotCapacity(userID, 0))
+	calendar, err := f.svc.CreateCalendar(CreateCalendarRequest{
+		Name: "Potion login",
+		Mode: entity.CalendarModeRolling,
+		Days: []entity.CalendarDay{{Items: []itemEntity.RewardItem{{ItemID: potionID, Count: 1}}}},
+	})
+	require.NoError(t, err)
+
+	// With the default reject policy nothing fits, so the day stays unclaimed
+	_, err = f.svc.Claim(userID, calendar.ID)
+	assert.ErrorIs(t, err, item.ErrInventoryFull)
+
+	require.NoError(t, f.items.Items.SetSlotCapacity(userID, 10))
+	response, err := f.svc.Claim(userID, calendar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Attendance.TotalClaims)
+	assert.Equal(t, 1, f.items.Balance(userID, potionID))
+}
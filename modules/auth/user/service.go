@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"fxserver/pkg/events"
 	"fxserver/pkg/jwt"
 
 	"go.uber.org/fx"
@@ -14,6 +15,11 @@ var (
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 )
 
+// Domain events published by user authentication
+const (
+	EventUserLogin = "user.login" // 로그인 성공
+)
+
 // PasswordVerifier is an interface to break circular dependency
 type PasswordVerifier interface {
 	VerifyUserPassword(email, password string) (UserInfo, error)
@@ -31,6 +37,7 @@ type Param struct {
 	AccessTokenService  jwt.Service `name:"access_token"`
 	RefreshTokenService jwt.Service `name:"refresh_token"`
 	PasswordVerifier    PasswordVerifier
+	Events              *events.Bus `optional:"true"`
 	Logger              *zap.Logger
 }
 
@@ -45,6 +52,7 @@ type service struct {
 	accessTokenService  jwt.Service
 	refreshTokenService jwt.Service
 	passwordVerifier    PasswordVerifier
+	events              *events.Bus
 	logger              *zap.Logger
 }
 
@@ -53,6 +61,7 @@ func NewService(p Param) Service {
 		accessTokenService:  p.AccessTokenService,
 		refreshTokenService: p.RefreshTokenService,
 		passwordVerifier:    p.PasswordVerifier,
+		events:              p.Events,
 		logger:              p.Logger,
 	}
 }
@@ -83,6 +92,7 @@ func (s *service) Login(email, password string) (*LoginResponse, error) {
 	}
 
 	s.logger.Info("User logged in successfully", zap.Int("user_id", userInfo.ID), zap.String("email", email))
+	s.events.Publish(events.Event{Type: EventUserLogin, UserID: userInfo.ID})
 	return response, nil
 }
 
@@ -0,0 +1,66 @@
+package campaign
+
+import (
+	"time"
+
+	"fxserver/modules/campaign/entity"
+	itemEntity "fxserver/modules/item/entity"
+	rewardEntity "fxserver/modules/reward/entity"
+)
+
+// Campaign management DTOs (Admin only)
+type CreateCampaignRequest struct {
+	Name              string                  `json:"name" validate:"required,min=2,max=100"`
+	Description       string                  `json:"description" validate:"required,min=5,max=500"`
+	StartAt           time.Time               `json:"start_at" validate:"required"`
+	EndAt             time.Time               `json:"end_at" validate:"required"`
+	Audience          entity.Audience         `json:"audience"`
+	Items             []itemEntity.RewardItem `json:"items" validate:"required,min=1,dive"`
+	Source            string                  `json:"source,omitempty" validate:"omitempty,min=2,max=50"` // 기본값: event
+	Delivery          entity.DeliveryMode     `json:"delivery" validate:"required,oneof=grant mailbox claim"`
+	MailTitle         string                  `json:"mail_title,omitempty" validate:"omitempty,max=100"`
+	MailExpiresInDays int                     `json:"mail_expires_in_days,omitempty" validate:"omitempty,gt=0,lte=365"`
+	CreatedBy         int                     `json:"-"` // 생성한 관리자 ID (핸들러에서 설정)
+}
+
+// UpdateCampaignRequest changes a campaign that has not started yet; omitted fields are kept
+type UpdateCampaignRequest struct {
+	Name              string                  `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description       string                  `json:"description,omitempty" validate:"omitempty,min=5,max=500"`
+	StartAt           *time.Time              `json:"start_at,omitempty"`
+	EndAt             *time.Time              `json:"end_at,omitempty"`
+	Audience          *entity.Audience        `json:"audience,omitempty"`
+	Items             []itemEntity.RewardItem `json:"items,omitempty" validate:"omitempty,min=1,dive"` // 지정 시 전체 교체
+	Delivery          entity.DeliveryMode     `json:"delivery,omitempty" validate:"omitempty,oneof=grant mailbox claim"`
+	MailTitle         *string                 `json:"mail_title,omitempty" validate:"omitempty,max=100"`
+	MailExpiresInDays int                     `json:"mail_expires_in_days,omitempty" validate:"omitempty,gt=0,lte=365"`
+	UpdatedBy         int                     `json:"-"` // 수정한 관리자 ID (핸들러에서 설정)
+}
+
+// Response DTOs
+type CampaignResponse struct {
+	*entity.Campaign
+	Job *rewardEntity.BulkGrantJob `json:"job,omitempty"` // grant, mailbox: 일괄 지급 진행 상황
+}
+
+type AvailableCampaign struct {
+	*entity.Campaign
+	Claimed bool `json:"claimed"`
+}
+
+type ClaimResponse struct {
+	Claim       *entity.Claim           `json:"claim"`
+	GrantResult *itemEntity.GrantResult `json:"grant_result,omitempty"`
+}
+
+// Query DTOs
+type CampaignQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending_approval scheduled active ended cancelled"`
+	Limit  int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type ClaimQuery struct {
+	CampaignID int `query:"campaign_id" validate:"omitempty,gt=0"`
+	UserID     int `query:"user_id" validate:"omitempty,gt=0"`
+	Limit      int `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
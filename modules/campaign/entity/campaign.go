@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// DeliveryMode decides how a campaign hands out its rewards
+type DeliveryMode string
+
+const (
+	DeliveryGrant   DeliveryMode = "grant"   // 시작 시 대상자 전원에게 바로 지급
+	DeliveryMailbox DeliveryMode = "mailbox" // 시작 시 대상자 전원에게 우편 발송
+	DeliveryClaim   DeliveryMode = "claim"   // 기간 중 대상자가 직접 한 번 수령
+)
+
+// CampaignStatus is the lifecycle state of a campaign
+type CampaignStatus string
+
+const (
+	CampaignStatusPendingApproval CampaignStatus = "pending_approval" // 다른 관리자의 승인 대기
+	CampaignStatusScheduled       CampaignStatus = "scheduled"        // 시작 대기
+	CampaignStatusActive          CampaignStatus = "active"           // 진행 중
+	CampaignStatusEnded           CampaignStatus = "ended"            // 기간 종료
+	CampaignStatusCancelled       CampaignStatus = "cancelled"        // 관리자 취소
+)
+
+// Audience selects the users a campaign is for by their account attributes
+type Audience struct {
+	AllUsers    bool   `json:"all_users,omitempty"`
+	CreatedFrom string `json:"created_from,omitempty"` // YYYY-MM-DD format
+	CreatedTo   string `json:"created_to,omitempty"`   // YYYY-MM-DD format (inclusive)
+	MinAge      int    `json:"min_age,omitempty" validate:"omitempty,gte=0"`
+	MaxAge      int    `json:"max_age,omitempty" validate:"omitempty,gte=0"`
+}
+
+// Campaign is a live-ops reward scheduled for a time window
+type Campaign struct {
+	ID                int                     `json:"id"`
+	Name              string                  `json:"name"`
+	Description       string                  `json:"description"` // 지급 내역에 기록되는 설명
+	StartAt           time.Time               `json:"start_at"`
+	EndAt             time.Time               `json:"end_at"`
+	Audience          Audience                `json:"audience"`
+	Items             []itemEntity.RewardItem `json:"items"`
+	Source            string                  `json:"source"` // 보상 출처 (기본값: event)
+	Delivery          DeliveryMode            `json:"delivery"`
+	MailTitle         string                  `json:"mail_title,omitempty"`
+	MailExpiresInDays int                     `json:"mail_expires_in_days,omitempty"`
+	Status            CampaignStatus          `json:"status"`
+	JobID             int                     `json:"job_id,omitempty"`           // grant, mailbox: 일괄 지급 작업 ID
+	DeliveredAt       *time.Time              `json:"delivered_at,omitempty"`     // grant, mailbox: 일괄 지급 작업을 만든 시각
+	DeliveryError     string                  `json:"delivery_error,omitempty"`   // 마지막 일괄 지급 작업 생성 실패 사유
+	ClaimCount        int                     `json:"claim_count"`                // claim: 수령한 사용자 수
+	ApprovalReasons   []string                `json:"approval_reasons,omitempty"` // grant, mailbox: 승인이 필요한 사유
+	ApprovedBy        int                     `json:"approved_by,omitempty"`
+	ApprovedAt        *time.Time              `json:"approved_at,omitempty"`
+	CreatedBy         int                     `json:"created_by"`
+	UpdatedBy         int                     `json:"updated_by,omitempty"` // 마지막으로 수정한 관리자 ID
+	ActivatedAt       *time.Time              `json:"activated_at,omitempty"`
+	EndedAt           *time.Time              `json:"ended_at,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+// IsEditable reports whether the campaign can still be changed; it has not started yet
+func (c *Campaign) IsEditable() bool {
+	return c.Status == CampaignStatusScheduled || c.Status == CampaignStatusPendingApproval
+}
+
+// IsOpen reports whether players can claim from the campaign at t
+func (c *Campaign) IsOpen(t time.Time) bool {
+	return c.Status == CampaignStatusActive && c.Delivery == DeliveryClaim && !t.Before(c.StartAt) && t.Before(c.EndAt)
+}
+
+// Claim is one player's reward taken from a claim-mode campaign
+type Claim struct {
+	ID         int                     `json:"id"`
+	CampaignID int                     `json:"campaign_id"`
+	UserID     int                     `json:"user_id"`
+	Items      []itemEntity.RewardItem `json:"items"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
@@ -0,0 +1,268 @@
+package campaign
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/campaign/entity"
+	"fxserver/modules/item"
+	"fxserver/modules/reward"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// GetAvailableCampaigns returns the open claim campaigns the authenticated user can take part in
+func (h *Handler) GetAvailableCampaigns(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	campaigns, err := h.service.ListAvailable(userID)
+	if err != nil {
+		h.logger.Error("Failed to list available campaigns", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get campaigns"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(campaigns))
+}
+
+// Claim takes the rewards of a claim campaign once
+func (h *Handler) Claim(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	campaignID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid campaign ID", "invalid_request_error"))
+	}
+
+	response, err := h.service.Claim(userID, campaignID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCampaignNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Campaign"))
+		case errors.Is(err, ErrNotEligible):
+			return c.JSON(http.StatusForbidden, dto.NewError(err.Error(), "permission_error"))
+		case errors.Is(err, ErrAlreadyClaimed):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, ErrCampaignClosed) || errors.Is(err, reward.ErrSourceInactive) || errors.Is(err, reward.ErrSourceCapReached) ||
+			errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to claim campaign", zap.Error(err), zap.Int("user_id", userID), zap.Int("campaign_id", campaignID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to claim campaign"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Admin APIs
+
+// GetCampaigns returns campaigns ordered by start time (Admin only)
+func (h *Handler) GetCampaigns(c echo.Context) error {
+	var query CampaignQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	campaigns, err := h.service.ListCampaigns(query)
+	if err != nil {
+		h.logger.Error("Failed to list campaigns", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get campaigns"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(campaigns))
+}
+
+// GetCampaign returns a campaign with its delivery progress (Admin only)
+func (h *Handler) GetCampaign(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid campaign ID", "invalid_request_error"))
+	}
+
+	campaign, err := h.service.GetCampaign(id)
+	if err != nil {
+		if errors.Is(err, ErrCampaignNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Campaign"))
+		}
+		h.logger.Error("Failed to get campaign", zap.Error(err), zap.Int("campaign_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get campaign"))
+	}
+
+	return c.JSON(http.StatusOK, campaign)
+}
+
+// CreateCampaign schedules a reward campaign (Admin only)
+func (h *Handler) CreateCampaign(c echo.Context) error {
+	var req CreateCampaignRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	if adminID, ok := adminauth.GetAdminID(c); ok {
+		req.CreatedBy = adminID
+	}
+
+	campaign, err := h.service.CreateCampaign(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCampaign) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create campaign", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create campaign"))
+	}
+
+	// Campaigns above the reward approval thresholds wait for a second admin
+	if campaign.Status == entity.CampaignStatusPendingApproval {
+		return c.JSON(http.StatusAccepted, campaign)
+	}
+	return c.JSON(http.StatusCreated, campaign)
+}
+
+// UpdateCampaign changes a campaign that has not started yet (Admin only)
+func (h *Handler) UpdateCampaign(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid campaign ID", "invalid_request_error"))
+	}
+
+	var req UpdateCampaignRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	if adminID, ok := adminauth.GetAdminID(c); ok {
+		req.UpdatedBy = adminID
+	}
+
+	campaign, err := h.service.UpdateCampaign(id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCampaignNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Campaign"))
+		case errors.Is(err, ErrCampaignStarted):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, ErrInvalidCampaign):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update campaign", zap.Error(err), zap.Int("campaign_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update campaign"))
+	}
+
+	return c.JSON(http.StatusOK, campaign)
+}
+
+// CancelCampaign stops a scheduled or active campaign and its delivery (Admin only)
+func (h *Handler) CancelCampaign(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid campaign ID", "invalid_request_error"))
+	}
+
+	campaign, err := h.service.CancelCampaign(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCampaignNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Campaign"))
+		case errors.Is(err, ErrCampaignFinished):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to cancel campaign", zap.Error(err), zap.Int("campaign_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to cancel campaign"))
+	}
+
+	return c.JSON(http.StatusOK, campaign)
+}
+
+// ApproveCampaign schedules a campaign held for approval; it must be approved by another admin (Admin only)
+func (h *Handler) ApproveCampaign(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid campaign ID", "invalid_request_error"))
+	}
+
+	adminID, _ := adminauth.GetAdminID(c)
+	campaign, err := h.service.ApproveCampaign(id, adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrCampaignNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Campaign"))
+		case errors.Is(err, reward.ErrSelfApproval):
+			return c.JSON(http.StatusForbidden, dto.NewError(err.Error(), "permission_error"))
+		case errors.Is(err, ErrNotPending):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, ErrInvalidCampaign):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to approve campaign", zap.Error(err), zap.Int("campaign_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to approve campaign"))
+	}
+
+	return c.JSON(http.StatusOK, campaign)
+}
+
+// GetClaims returns claim campaign records with optional filters (Admin only)
+func (h *Handler) GetClaims(c echo.Context) error {
+	var query ClaimQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	claims, err := h.service.ListClaims(query)
+	if err != nil {
+		h.logger.Error("Failed to list campaign claims", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get campaign claims"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(claims))
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"fxserver/modules/campaign/entity"
+)
+
+var (
+	ErrCampaignNotFound = errors.New("campaign not found")
+	ErrStatusChanged    = errors.New("campaign status changed")
+	ErrClaimNotFound    = errors.New("claim not found")
+	ErrAlreadyClaimed   = errors.New("campaign already claimed")
+)
+
+// CampaignFilter narrows campaign queries; zero values are ignored
+type CampaignFilter struct {
+	Statuses []entity.CampaignStatus // any of
+	Limit    int
+}
+
+// ClaimFilter narrows claim queries; zero values are ignored
+type ClaimFilter struct {
+	CampaignID int
+	UserID     int
+	Limit      int
+}
+
+type CampaignRepository interface {
+	Create(campaign *entity.Campaign) error
+	// GetByID returns a copy of the stored campaign
+	GetByID(id int) (*entity.Campaign, error)
+	// Update stores the campaign only while it is still scheduled or pending approval; otherwise it fails with ErrStatusChanged
+	Update(campaign *entity.Campaign) error
+	// Approve schedules a campaign pending approval; otherwise it fails with ErrStatusChanged
+	Approve(id, adminID int, at time.Time) (*entity.Campaign, error)
+	// List returns matching campaigns ordered by start time
+	List(filter CampaignFilter) ([]*entity.Campaign, error)
+	// UpdateStatus moves the campaign to status only if it is currently in one of from;
+	// otherwise it fails with ErrStatusChanged
+	UpdateStatus(id int, from []entity.CampaignStatus, status entity.CampaignStatus, at time.Time) (*entity.Campaign, error)
+	// SaveDelivery records the bulk grant job created for the campaign, or why it could not be created
+	SaveDelivery(id, jobID int, deliveredAt *time.Time, deliveryError string) error
+}
+
+type ClaimRepository interface {
+	// SaveClaim stores the claim and counts it on the campaign.
+	// A second claim by the same user fails with ErrAlreadyClaimed.
+	SaveClaim(claim *entity.Claim) error
+	// RevertClaim removes a claim whose rewards could not be granted
+	RevertClaim(claimID int) error
+	// HasClaimed reports whether the user claimed the campaign
+	HasClaimed(campaignID, userID int) (bool, error)
+	// ListClaims returns matching claims, newest first
+	ListClaims(filter ClaimFilter) ([]*entity.Claim, error)
+}
+
+type Repository interface {
+	CampaignRepository
+	ClaimRepository
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"fxserver/modules/campaign/entity"
+)
+
+type memoryRepository struct {
+	mu          sync.RWMutex
+	campaigns   map[int]*entity.Campaign
+	claims      []*entity.Claim
+	claimed     map[string]int // key: "campaignID:userID", value: claim ID
+	nextID      int
+	nextClaimID int
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		campaigns:   make(map[int]*entity.Campaign),
+		claimed:     make(map[string]int),
+		nextID:      1,
+		nextClaimID: 1,
+	}
+}
+
+func claimedKey(campaignID, userID int) string {
+	return fmt.Sprintf("%d:%d", campaignID, userID)
+}
+
+// Campaign operations
+
+func (r *memoryRepository) Create(campaign *entity.Campaign) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	campaign.ID = r.nextID
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+	r.campaigns[campaign.ID] = copyCampaign(campaign)
+	r.nextID++
+	return nil
+}
+
+func (r *memoryRepository) GetByID(id int) (*entity.Campaign, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	campaign, exists := r.campaigns[id]
+	if !exists {
+		return nil, ErrCampaignNotFound
+	}
+	return copyCampaign(campaign), nil
+}
+
+func (r *memoryRepository) Update(campaign *entity.Campaign) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.campaigns[campaign.ID]
+	if !exists {
+		return ErrCampaignNotFound
+	}
+	// 시작된 캠페인은 스케줄러가 관리하므로 수정할 수 없음
+	if !stored.IsEditable() {
+		return ErrStatusChanged
+	}
+	campaign.UpdatedAt = time.Now()
+	r.campaigns[campaign.ID] = copyCampaign(campaign)
+	return nil
+}
+
+func (r *memoryRepository) Approve(id, adminID int, at time.Time) (*entity.Campaign, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	campaign, exists := r.campaigns[id]
+	if !exists {
+		return nil, ErrCampaignNotFound
+	}
+	if campaign.Status != entity.CampaignStatusPendingApproval {
+		return nil, ErrStatusChanged
+	}
+
+	campaign.Status = entity.CampaignStatusScheduled
+	campaign.ApprovedBy = adminID
+	campaign.ApprovedAt = &at
+	campaign.UpdatedAt = time.Now()
+	return copyCampaign(campaign), nil
+}
+
+func (r *memoryRepository) List(filter CampaignFilter) ([]*entity.Campaign, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var campaigns []*entity.Campaign
+	for _, campaign := range r.campaigns {
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, campaign.Status) {
+			continue
+		}
+		campaigns = append(campaigns, copyCampaign(campaign))
+	}
+	slices.SortFunc(campaigns, func(a, b *entity.Campaign) int {
+		if c := a.StartAt.Compare(b.StartAt); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	})
+
+	if filter.Limit > 0 && len(campaigns) > filter.Limit {
+		campaigns = campaigns[:filter.Limit]
+	}
+	return campaigns, nil
+}
+
+func (r *memoryRepository) UpdateStatus(id int, from []entity.CampaignStatus, status entity.CampaignStatus, at time.Time) (*entity.Campaign, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	campaign, exists := r.campaigns[id]
+	if !exists {
+		return nil, ErrCampaignNotFound
+	}
+	if !slices.Contains(from, campaign.Status) {
+		return nil, ErrStatusChanged
+	}
+
+	campaign.Status = status
+	switch status {
+	case entity.CampaignStatusActive:
+		campaign.ActivatedAt = &at
+	case entity.CampaignStatusEnded, entity.CampaignStatusCancelled:
+		campaign.EndedAt = &at
+	}
+	campaign.UpdatedAt = time.Now()
+	return copyCampaign(campaign), nil
+}
+
+func (r *memoryRepository) SaveDelivery(id, jobID int, deliveredAt *time.Time, deliveryError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	campaign, exists := r.campaigns[id]
+	if !exists {
+		return ErrCampaignNotFound
+	}
+	campaign.JobID = jobID
+	campaign.DeliveredAt = deliveredAt
+	campaign.DeliveryError = deliveryError
+	campaign.UpdatedAt = time.Now()
+	return nil
+}
+
+func copyCampaign(campaign *entity.Campaign) *entity.Campaign {
+	copied := *campaign
+	copied.Items = slices.Clone(campaign.Items)
+	copied.ApprovalReasons = slices.Clone(campaign.ApprovalReasons)
+	return &copied
+}
+
+// Claim operations
+
+func (r *memoryRepository) SaveClaim(claim *entity.Claim) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	campaign, exists := r.campaigns[claim.CampaignID]
+	if !exists {
+		return ErrCampaignNotFound
+	}
+	key := claimedKey(claim.CampaignID, claim.UserID)
+	if _, exists := r.claimed[key]; exists {
+		return ErrAlreadyClaimed
+	}
+
+	claim.ID = r.nextClaimID
+	claim.CreatedAt = time.Now()
+	r.claims = append(r.claims, claim)
+	r.claimed[key] = claim.ID
+	r.nextClaimID++
+	campaign.ClaimCount++
+	return nil
+}
+
+func (r *memoryRepository) RevertClaim(claimID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	index := slices.IndexFunc(r.claims, func(claim *entity.Claim) bool { return claim.ID == claimID })
+	if index < 0 {
+		return ErrClaimNotFound
+	}
+	claim := r.claims[index]
+	r.claims = slices.Delete(r.claims, index, index+1)
+	delete(r.claimed, claimedKey(claim.CampaignID, claim.UserID))
+	if campaign, exists := r.campaigns[claim.CampaignID]; exists {
+		campaign.ClaimCount--
+	}
+	return nil
+}
+
+func (r *memoryRepository) HasClaimed(campaignID, userID int) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.claimed[claimedKey(campaignID, userID)]
+	return exists, nil
+}
+
+func (r *memoryRepository) ListClaims(filter ClaimFilter) ([]*entity.Claim, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var claims []*entity.Claim
+	for i := len(r.claims) - 1; i >= 0; i-- {
+		claim := r.claims[i]
+		if filter.CampaignID != 0 && claim.CampaignID != filter.CampaignID {
+			continue
+		}
+		if filter.UserID != 0 && claim.UserID != filter.UserID {
+			continue
+		}
+		claims = append(claims, claim)
+		if filter.Limit > 0 && len(claims) >= filter.Limit {
+			break
+		}
+	}
+	return claims, nil
+}
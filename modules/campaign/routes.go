@@ -0,0 +1,51 @@
+package campaign
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// User campaign routes (user auth required)
+	campaigns := api.Group("/campaigns")
+	campaigns.GET("", r.handler.GetAvailableCampaigns, r.userMiddleware.VerifyAccessToken()) // Get open claim campaigns
+	campaigns.POST("/:id/claim", r.handler.Claim, r.userMiddleware.VerifyAccessToken())      // Claim campaign rewards
+
+	// Admin campaign management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminCampaigns := admin.Group("/campaigns")
+	adminCampaigns.GET("", r.handler.GetCampaigns, r.adminMiddleware.VerifyAdminToken())                 // List campaigns
+	adminCampaigns.POST("", r.handler.CreateCampaign, r.adminMiddleware.VerifyAdminToken())              // Schedule campaign
+	adminCampaigns.GET("/claims", r.handler.GetClaims, r.adminMiddleware.VerifyAdminToken())             // Get claim campaign records
+	adminCampaigns.GET("/:id", r.handler.GetCampaign, r.adminMiddleware.VerifyAdminToken())              // Get campaign with delivery progress
+	adminCampaigns.PUT("/:id", r.handler.UpdateCampaign, r.adminMiddleware.VerifyAdminToken())           // Update campaign before it starts
+	adminCampaigns.POST("/:id/approve", r.handler.ApproveCampaign, r.adminMiddleware.VerifyAdminToken()) // Approve campaign held for approval
+	adminCampaigns.POST("/:id/cancel", r.handler.CancelCampaign, r.adminMiddleware.VerifyAdminToken())   // Cancel campaign
+}
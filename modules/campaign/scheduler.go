@@ -0,0 +1,107 @@
+package campaign
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// DefaultSchedulerInterval is how often campaigns are checked when CAMPAIGN_SCHEDULER_INTERVAL is unset or invalid
+const DefaultSchedulerInterval = 30 * time.Second
+
+// Scheduler runs Service.RunSchedule periodically while the application is running.
+// Every transition is idempotent, so a restart just picks up from the stored campaign state.
+type Scheduler struct {
+	service  Service
+	interval time.Duration
+	logger   *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type SchedulerParam struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Service   Service
+	Logger    *zap.Logger
+}
+
+func NewScheduler(p SchedulerParam) *Scheduler {
+	s := &Scheduler{
+		service:  p.Service,
+		interval: schedulerIntervalFromEnv(p.Logger),
+		logger:   p.Logger,
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+
+	return s
+}
+
+// schedulerIntervalFromEnv reads CAMPAIGN_SCHEDULER_INTERVAL as a Go duration (e.g. 30s, 1m)
+func schedulerIntervalFromEnv(logger *zap.Logger) time.Duration {
+	value := os.Getenv("CAMPAIGN_SCHEDULER_INTERVAL")
+	if value == "" {
+		return DefaultSchedulerInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		logger.Warn("Invalid CAMPAIGN_SCHEDULER_INTERVAL, using default",
+			zap.String("value", value),
+			zap.Duration("default", DefaultSchedulerInterval))
+		return DefaultSchedulerInterval
+	}
+	return interval
+}
+
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.logger.Info("Starting campaign scheduler", zap.Duration("interval", s.interval))
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.loop()
+	return nil
+}
+
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping campaign scheduler")
+	close(s.stop)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// 재시작 중 시작/종료 시각이 지난 캠페인을 바로 처리
+	s.tick()
+	for {
+		select {
+		case <-ticker.C:
+			s.tick()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) tick() {
+	if err := s.service.RunSchedule(); err != nil {
+		s.logger.Error("Campaign schedule run failed", zap.Error(err))
+	}
+}
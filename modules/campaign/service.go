@@ -0,0 +1,627 @@
+package campaign
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fxserver/modules/campaign/entity"
+	"fxserver/modules/campaign/repository"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/reward"
+	"fxserver/modules/user"
+	"fxserver/pkg/lock"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCampaignNotFound = errors.New("campaign not found")
+	ErrInvalidCampaign  = errors.New("invalid campaign")
+	ErrCampaignStarted  = errors.New("campaign already started")
+	ErrCampaignFinished = errors.New("campaign already finished")
+	ErrCampaignClosed   = errors.New("campaign is not open for claims")
+	ErrNotEligible      = errors.New("user is not in the campaign audience")
+	ErrAlreadyClaimed   = errors.New("campaign already claimed")
+	ErrNotPending       = errors.New("campaign is not pending approval")
+)
+
+// Inventory ledger reference type for claim-mode campaigns.
+// grant and mailbox campaigns run as reward bulk grant jobs referenced by JobReference.
+const ReferenceTypeCampaign = "campaign"
+
+// JobReference is the bulk grant job reference of a campaign; the job service creates at most one job per reference
+func JobReference(campaignID int) string {
+	return ReferenceTypeCampaign + ":" + strconv.Itoa(campaignID)
+}
+
+type Service interface {
+	// Campaign management (Admin)
+	CreateCampaign(req CreateCampaignRequest) (*entity.Campaign, error)
+	UpdateCampaign(id int, req UpdateCampaignRequest) (*entity.Campaign, error)
+	CancelCampaign(id int) (*entity.Campaign, error)
+	// ApproveCampaign schedules a grant or mailbox campaign held for approval; the approver must not have created or last updated it
+	ApproveCampaign(id, adminID int) (*entity.Campaign, error)
+	GetCampaign(id int) (*CampaignResponse, error)
+	ListCampaigns(query CampaignQuery) ([]*entity.Campaign, error)
+
+	// Player operations (claim mode)
+	ListAvailable(userID int) ([]AvailableCampaign, error)
+	Claim(userID, campaignID int) (*ClaimResponse, error)
+	ListClaims(query ClaimQuery) ([]*entity.Claim, error)
+
+	// Scheduling
+	// RunSchedule activates campaigns whose start time has passed, starts their delivery
+	// and ends campaigns whose end time has passed. It is safe to run repeatedly.
+	RunSchedule() error
+}
+
+type service struct {
+	repo            repository.Repository
+	rewardService   reward.Service
+	jobService      reward.JobService
+	approvalService reward.ApprovalService
+	userService     user.Service
+	logger          *zap.Logger
+	now             func() time.Time
+
+	// scheduleLocks keeps scheduler ticks and admin cancellation from interleaving on the same campaign
+	scheduleLocks lock.Keyed[int]
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository      repository.Repository
+	RewardService   reward.Service
+	JobService      reward.JobService
+	ApprovalService reward.ApprovalService
+	UserService     user.Service
+	Logger          *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:            p.Repository,
+		rewardService:   p.RewardService,
+		jobService:      p.JobService,
+		approvalService: p.ApprovalService,
+		userService:     p.UserService,
+		logger:          p.Logger,
+		now:             time.Now,
+	}
+}
+
+func targetOf(audience entity.Audience) reward.BulkJobTarget {
+	return reward.BulkJobTarget{
+		AllUsers:    audience.AllUsers,
+		CreatedFrom: audience.CreatedFrom,
+		CreatedTo:   audience.CreatedTo,
+		MinAge:      audience.MinAge,
+		MaxAge:      audience.MaxAge,
+	}
+}
+
+// Campaign management
+
+func (s *service) CreateCampaign(req CreateCampaignRequest) (*entity.Campaign, error) {
+	source := req.Source
+	if source == "" {
+		source = reward.RewardSourceEvent
+	}
+
+	campaign := &entity.Campaign{
+		Name:              req.Name,
+		Description:       req.Description,
+		StartAt:           req.StartAt,
+		EndAt:             req.EndAt,
+		Audience:          req.Audience,
+		Items:             req.Items,
+		Source:            source,
+		Delivery:          req.Delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		Status:            entity.CampaignStatusScheduled,
+		CreatedBy:         req.CreatedBy,
+	}
+	if err := s.validateCampaign(campaign); err != nil {
+		return nil, err
+	}
+	if err := s.checkApproval(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(campaign); err != nil {
+		s.logger.Error("Failed to create campaign", zap.Error(err))
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	s.logger.Info("Campaign scheduled",
+		zap.Int("campaign_id", campaign.ID),
+		zap.String("delivery", string(campaign.Delivery)),
+		zap.String("status", string(campaign.Status)),
+		zap.Time("start_at", campaign.StartAt),
+		zap.Time("end_at", campaign.EndAt),
+		zap.Int("created_by", campaign.CreatedBy))
+
+	return campaign, nil
+}
+
+func (s *service) UpdateCampaign(id int, req UpdateCampaignRequest) (*entity.Campaign, error) {
+	campaign, err := s.getCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsEditable() {
+		return nil, ErrCampaignStarted
+	}
+
+	if req.Name != "" {
+		campaign.Name = req.Name
+	}
+	if req.Description != "" {
+		campaign.Description = req.Description
+	}
+	if req.StartAt != nil {
+		campaign.StartAt = *req.StartAt
+	}
+	if req.EndAt != nil {
+		campaign.EndAt = *req.EndAt
+	}
+	if req.Audience != nil {
+		campaign.Audience = *req.Audience
+	}
+	if req.Items != nil {
+		campaign.Items = req.Items
+	}
+	if req.Delivery != "" {
+		campaign.Delivery = req.Delivery
+	}
+	if req.MailTitle != nil {
+		campaign.MailTitle = *req.MailTitle
+	}
+	if req.MailExpiresInDays != 0 {
+		campaign.MailExpiresInDays = req.MailExpiresInDays
+	}
+	if err := s.validateCampaign(campaign); err != nil {
+		return nil, err
+	}
+	// 수정된 캠페인은 이전 승인과 관계없이 다시 검사
+	campaign.UpdatedBy = req.UpdatedBy
+	if err := s.checkApproval(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(campaign); err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return nil, ErrCampaignStarted
+		}
+		s.logger.Error("Failed to update campaign", zap.Error(err), zap.Int("campaign_id", id))
+		return nil, fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	s.logger.Info("Campaign updated",
+		zap.Int("campaign_id", campaign.ID),
+		zap.String("status", string(campaign.Status)),
+		zap.Time("start_at", campaign.StartAt),
+		zap.Time("end_at", campaign.EndAt))
+
+	return campaign, nil
+}
+
+func (s *service) validateCampaign(campaign *entity.Campaign) error {
+	if !campaign.EndAt.After(campaign.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", ErrInvalidCampaign)
+	}
+	if !campaign.EndAt.After(s.now()) {
+		return fmt.Errorf("%w: end_at must be in the future", ErrInvalidCampaign)
+	}
+	if _, err := targetOf(campaign.Audience).Matcher(); err != nil {
+		return audienceError(err)
+	}
+	if err := s.rewardService.ValidateSource(campaign.Source); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+	}
+	if err := s.rewardService.ValidateRewardItems(campaign.Items); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+	}
+	return nil
+}
+
+// checkApproval holds a campaign whose delivery exceeds the reward approval thresholds.
+// A claim campaign is checked as if every audience member claimed it.
+// It fails closed, so a campaign that cannot be checked is not scheduled.
+func (s *service) checkApproval(campaign *entity.Campaign) error {
+	campaign.Status = entity.CampaignStatusScheduled
+	campaign.ApprovalReasons = nil
+	campaign.ApprovedBy = 0
+	campaign.ApprovedAt = nil
+
+	reasons, err := s.approvalService.CheckBulkJob(s.jobRequest(campaign))
+	if err != nil {
+		switch {
+		case errors.Is(err, reward.ErrNoTargetUsers):
+			// 아직 대상자가 없으면 시작 시 다시 검사
+			return nil
+		case errors.Is(err, reward.ErrInvalidJob):
+			return audienceError(err)
+		case errors.Is(err, reward.ErrTooManyTargets) || errors.Is(err, reward.ErrInvalidGrantRequest):
+			return fmt.Errorf("%w: %v", ErrInvalidCampaign, err)
+		}
+		return fmt.Errorf("failed to check campaign approval: %w", err)
+	}
+	if len(reasons) > 0 {
+		campaign.Status = entity.CampaignStatusPendingApproval
+		campaign.ApprovalReasons = reasons
+	}
+	return nil
+}
+
+// audienceError reports a malformed audience as ErrInvalidCampaign instead of the bulk job error it is checked with
+func audienceError(err error) error {
+	return fmt.Errorf("%w: audience: %s", ErrInvalidCampaign, strings.TrimPrefix(err.Error(), reward.ErrInvalidJob.Error()+": "))
+}
+
+func (s *service) CancelCampaign(id int) (*entity.Campaign, error) {
+	defer s.scheduleLocks.Lock(id)()
+
+	campaign, err := s.repo.UpdateStatus(id,
+		[]entity.CampaignStatus{entity.CampaignStatusPendingApproval, entity.CampaignStatusScheduled, entity.CampaignStatusActive},
+		entity.CampaignStatusCancelled, s.now())
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCampaignNotFound):
+			return nil, ErrCampaignNotFound
+		case errors.Is(err, repository.ErrStatusChanged):
+			return nil, ErrCampaignFinished
+		}
+		return nil, fmt.Errorf("failed to cancel campaign: %w", err)
+	}
+
+	// 진행 중인 일괄 지급도 다음 배치부터 중단
+	if campaign.JobID != 0 {
+		if _, err := s.jobService.CancelJob(campaign.JobID); err != nil && !errors.Is(err, reward.ErrJobFinished) {
+			s.logger.Error("Failed to cancel campaign delivery job",
+				zap.Error(err),
+				zap.Int("campaign_id", id),
+				zap.Int("job_id", campaign.JobID))
+		}
+	}
+
+	s.logger.Info("Campaign cancelled",
+		zap.Int("campaign_id", id),
+		zap.Int("job_id", campaign.JobID))
+	return campaign, nil
+}
+
+func (s *service) ApproveCampaign(id, adminID int) (*entity.Campaign, error) {
+	campaign, err := s.getCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != entity.CampaignStatusPendingApproval {
+		return nil, ErrNotPending
+	}
+	if adminID == campaign.CreatedBy || adminID == campaign.UpdatedBy {
+		return nil, reward.ErrSelfApproval
+	}
+	if !campaign.EndAt.After(s.now()) {
+		return nil, fmt.Errorf("%w: end_at has passed", ErrInvalidCampaign)
+	}
+
+	approved, err := s.repo.Approve(id, adminID, s.now())
+	if err != nil {
+		if errors.Is(err, repository.ErrStatusChanged) {
+			return nil, ErrNotPending
+		}
+		return nil, fmt.Errorf("failed to approve campaign: %w", err)
+	}
+
+	s.logger.Info("Campaign approved",
+		zap.Int("campaign_id", id),
+		zap.Int("created_by", approved.CreatedBy),
+		zap.Int("approved_by", adminID))
+	return approved, nil
+}
+
+func (s *service) GetCampaign(id int) (*CampaignResponse, error) {
+	campaign, err := s.getCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CampaignResponse{Campaign: campaign}
+	if campaign.JobID != 0 {
+		job, err := s.jobService.GetJob(campaign.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get campaign delivery job: %w", err)
+		}
+		response.Job = job
+	}
+	return response, nil
+}
+
+func (s *service) ListCampaigns(query CampaignQuery) ([]*entity.Campaign, error) {
+	filter := repository.CampaignFilter{Limit: query.Limit}
+	if query.Status != "" {
+		filter.Statuses = []entity.CampaignStatus{entity.CampaignStatus(query.Status)}
+	}
+
+	campaigns, err := s.repo.List(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+func (s *service) getCampaign(id int) (*entity.Campaign, error) {
+	campaign, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+	return campaign, nil
+}
+
+// Player operations
+
+// ListAvailable returns the open claim-mode campaigns the user is in the audience of
+func (s *service) ListAvailable(userID int) ([]AvailableCampaign, error) {
+	campaigns, err := s.repo.List(repository.CampaignFilter{Statuses: []entity.CampaignStatus{entity.CampaignStatusActive}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	u, err := s.userService.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	now := s.now()
+	available := make([]AvailableCampaign, 0)
+	for _, campaign := range campaigns {
+		if !campaign.IsOpen(now) {
+			continue
+		}
+		matches, err := targetOf(campaign.Audience).Matcher()
+		if err != nil || !matches(u) {
+			continue
+		}
+		claimed, err := s.repo.HasClaimed(campaign.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check claim: %w", err)
+		}
+		available = append(available, AvailableCampaign{Campaign: campaign, Claimed: claimed})
+	}
+	return available, nil
+}
+
+func (s *service) Claim(userID, campaignID int) (*ClaimResponse, error) {
+	campaign, err := s.getCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Delivery != entity.DeliveryClaim {
+		return nil, ErrCampaignNotFound
+	}
+	if !campaign.IsOpen(s.now()) {
+		return nil, ErrCampaignClosed
+	}
+
+	u, err := s.userService.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	matches, err := targetOf(campaign.Audience).Matcher()
+	if err != nil {
+		return nil, audienceError(err)
+	}
+	if !matches(u) {
+		return nil, ErrNotEligible
+	}
+
+	// 지급 전에 수령을 먼저 기록하여 같은 사용자에게 두 번 지급되지 않도록 함
+	claim := &entity.Claim{
+		CampaignID: campaignID,
+		UserID:     userID,
+		Items:      campaign.Items,
+	}
+	if err := s.repo.SaveClaim(claim); err != nil {
+		if errors.Is(err, repository.ErrAlreadyClaimed) {
+			return nil, ErrAlreadyClaimed
+		}
+		return nil, fmt.Errorf("failed to save claim: %w", err)
+	}
+
+	result, err := s.rewardService.GrantItemsToUser(
+		userID,
+		campaign.Items,
+		"",
+		campaign.Source,
+		campaign.Description,
+		itemEntity.TransactionRef{
+			Type:  ReferenceTypeCampaign,
+			ID:    strconv.Itoa(campaignID),
+			Actor: itemEntity.UserActor(userID),
+		},
+	)
+	if err != nil {
+		if revertErr := s.repo.RevertClaim(claim.ID); revertErr != nil {
+			s.logger.Error("Failed to revert campaign claim after grant failure",
+				zap.Error(revertErr),
+				zap.Int("claim_id", claim.ID),
+				zap.Int("user_id", userID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Campaign claimed",
+		zap.Int("user_id", userID),
+		zap.Int("campaign_id", campaignID))
+
+	return &ClaimResponse{Claim: claim, GrantResult: result}, nil
+}
+
+func (s *service) ListClaims(query ClaimQuery) ([]*entity.Claim, error) {
+	claims, err := s.repo.ListClaims(repository.ClaimFilter{
+		CampaignID: query.CampaignID,
+		UserID:     query.UserID,
+		Limit:      query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	return claims, nil
+}
+
+// Scheduling
+
+func (s *service) RunSchedule() error {
+	now := s.now()
+	campaigns, err := s.repo.List(repository.CampaignFilter{
+		Statuses: []entity.CampaignStatus{entity.CampaignStatusScheduled, entity.CampaignStatusActive},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	var errs []error
+	for _, campaign := range campaigns {
+		if err := s.runCampaign(campaign.ID, now); err != nil {
+			errs = append(errs, fmt.Errorf("campaign %d: %w", campaign.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runCampaign starts, delivers and ends one campaign as its schedule requires
+func (s *service) runCampaign(id int, now time.Time) error {
+	defer s.scheduleLocks.Lock(id)()
+
+	// 목록 조회 후 취소되었을 수 있으므로 잠금을 잡은 뒤 다시 읽음
+	campaign, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	switch campaign.Status {
+	case entity.CampaignStatusScheduled:
+		if now.Before(campaign.StartAt) {
+			return nil
+		}
+		// 승인 없이 예약된 캠페인은 그사이 늘어난 대상자로 시작 직전에 다시 검사
+		if campaign.ApprovedBy == 0 {
+			held, err := s.holdForApproval(campaign)
+			if err != nil || held {
+				return err
+			}
+		}
+		// 서버가 기간 내내 꺼져 있었더라도 grant, mailbox 캠페인은 한 번 지급되도록 종료 전에 활성화
+		activated, err := s.repo.UpdateStatus(campaign.ID,
+			[]entity.CampaignStatus{entity.CampaignStatusScheduled}, entity.CampaignStatusActive, now)
+		if err != nil {
+			return fmt.Errorf("failed to activate: %w", err)
+		}
+		campaign = activated
+		s.logger.Info("Campaign activated",
+			zap.Int("campaign_id", campaign.ID),
+			zap.String("delivery", string(campaign.Delivery)))
+	case entity.CampaignStatusActive:
+		// 이미 시작된 캠페인은 지급과 종료만 확인
+	default:
+		return nil
+	}
+
+	// 재시작 등으로 일괄 지급 작업을 만들지 못한 캠페인도 여기서 다시 시도됨
+	var deliverErr error
+	if campaign.Delivery != entity.DeliveryClaim && campaign.DeliveredAt == nil {
+		deliverErr = s.deliver(campaign, now)
+	}
+
+	if !now.Before(campaign.EndAt) {
+		if _, err := s.repo.UpdateStatus(campaign.ID,
+			[]entity.CampaignStatus{entity.CampaignStatusActive}, entity.CampaignStatusEnded, now); err != nil {
+			return errors.Join(deliverErr, fmt.Errorf("failed to end: %w", err))
+		}
+		s.logger.Info("Campaign ended",
+			zap.Int("campaign_id", campaign.ID),
+			zap.Int("claim_count", campaign.ClaimCount))
+	}
+	return deliverErr
+}
+
+// holdForApproval moves a scheduled campaign to pending_approval when its delivery now exceeds the approval thresholds
+func (s *service) holdForApproval(campaign *entity.Campaign) (bool, error) {
+	if err := s.checkApproval(campaign); err != nil {
+		return false, err
+	}
+	if campaign.Status != entity.CampaignStatusPendingApproval {
+		return false, nil
+	}
+	if err := s.repo.Update(campaign); err != nil {
+		return false, fmt.Errorf("failed to hold for approval: %w", err)
+	}
+
+	s.logger.Warn("Campaign held for approval before starting",
+		zap.Int("campaign_id", campaign.ID),
+		zap.Strings("reasons", campaign.ApprovalReasons))
+	return true, nil
+}
+
+// jobRequest is the bulk grant job that delivers a grant or mailbox campaign.
+// For a claim campaign it describes every audience member claiming, which is what approval is checked against.
+func (s *service) jobRequest(campaign *entity.Campaign) reward.CreateBulkJobRequest {
+	delivery := reward.DeliveryDirect
+	if campaign.Delivery == entity.DeliveryMailbox {
+		delivery = reward.DeliveryMailbox
+	}
+	mailTitle := campaign.MailTitle
+	if mailTitle == "" {
+		mailTitle = campaign.Name
+	}
+	target := targetOf(campaign.Audience)
+
+	return reward.CreateBulkJobRequest{
+		Target:            &target,
+		Items:             campaign.Items,
+		Source:            campaign.Source,
+		Description:       campaign.Description,
+		Delivery:          delivery,
+		MailTitle:         mailTitle,
+		MailExpiresInDays: campaign.MailExpiresInDays,
+		GrantedBy:         campaign.CreatedBy,
+		Reference:         JobReference(campaign.ID),
+	}
+}
+
+// deliver hands a grant or mailbox campaign to the bulk grant job runner. The job is created with the
+// campaign's reference, so repeating this after a restart reuses the job and each user is granted once.
+func (s *service) deliver(campaign *entity.Campaign, now time.Time) error {
+	job, err := s.jobService.CreateJob(s.jobRequest(campaign))
+	if err != nil {
+		if errors.Is(err, reward.ErrNoTargetUsers) {
+			// 대상자가 없으면 지급할 것이 없으므로 완료로 처리
+			s.logger.Warn("Campaign has no target users", zap.Int("campaign_id", campaign.ID))
+			return s.repo.SaveDelivery(campaign.ID, 0, &now, err.Error())
+		}
+		if saveErr := s.repo.SaveDelivery(campaign.ID, 0, nil, err.Error()); saveErr != nil {
+			s.logger.Error("Failed to record campaign delivery error", zap.Error(saveErr), zap.Int("campaign_id", campaign.ID))
+		}
+		return fmt.Errorf("failed to create delivery job: %w", err)
+	}
+
+	if err := s.repo.SaveDelivery(campaign.ID, job.ID, &now, ""); err != nil {
+		return fmt.Errorf("failed to save delivery job: %w", err)
+	}
+	campaign.JobID = job.ID
+
+	s.logger.Info("Campaign delivery started",
+		zap.Int("campaign_id", campaign.ID),
+		zap.Int("job_id", job.ID),
+		zap.Int("total_users", job.Total))
+	return nil
+}
@@ -0,0 +1,344 @@
+package campaign
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/campaign/entity"
+	"fxserver/modules/campaign/repository"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	"fxserver/modules/reward"
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/rewardtest"
+	"fxserver/modules/user"
+	userEntity "fxserver/modules/user/entity"
+	userRepository "fxserver/modules/user/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+const potionID = 3 // stackable, takes one slot
+
+type fixture struct {
+	svc   *service
+	repo  repository.Repository
+	jobs  reward.JobService
+	items *itemtest.Fixture
+	users userRepository.UserRepository
+	now   time.Time
+}
+
+// setupCampaignService seeds users 1 (age 17), 2 (age 25) and 3 (age 40)
+func setupCampaignService(t *testing.T) *fixture {
+	logger := zap.NewNop()
+	items := itemtest.New()
+
+	users := userRepository.NewMemoryUserRepository()
+	for i, age := range []int{17, 25, 40} {
+		require.NoError(t, users.Create(&userEntity.User{Name: "player", Email: string(rune('a'+i)) + "@example.com", Age: age}))
+	}
+	userService := user.NewService(users, logger)
+	rewards := rewardtest.NewWithUsers(items, users)
+
+	lifecycle := fxtest.NewLifecycle(t)
+	jobs := reward.NewJobService(reward.JobServiceParam{
+		Lifecycle:   lifecycle,
+		Repository:  rewards.Repository,
+		Service:     rewards.Service,
+		UserService: userService,
+		Logger:      logger,
+	})
+	lifecycle.RequireStart()
+	t.Cleanup(lifecycle.RequireStop)
+
+	f := &fixture{
+		repo:  repository.NewMemoryRepository(),
+		jobs:  jobs,
+		items: items,
+		users: users,
+		now:   time.Now(),
+	}
+	f.svc = NewService(ServiceParam{
+		Repository:    f.repo,
+		RewardService: rewards.Service,
+		JobService:    jobs,
+		ApprovalService: reward.NewApprovalService(reward.ApprovalServiceParam{
+			Repository:  rewards.Repository,
+			Service:     rewards.Service,
+			JobService:  jobs,
+			ItemService: items.Service,
+			Logger:      logger,
+		}),
+		UserService: userService,
+		Logger:      logger,
+	}).(*service)
+	f.svc.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *fixture) waitForJob(t *testing.T, id int) *rewardEntity.BulkGrantJob {
+	var job *rewardEntity.BulkGrantJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = f.jobs.GetJob(id)
+		require.NoError(t, err)
+		return job.IsFinished()
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func campaignRequest(f *fixture, delivery entity.DeliveryMode) CreateCampaignRequest {
+	return CreateCampaignRequest{
+		Name:        "Spring festival",
+		Description: "Spring festival login reward",
+		StartAt:     f.now.Add(time.Hour),
+		EndAt:       f.now.Add(2 * time.Hour),
+		Audience:    entity.Audience{MinAge: 18},
+		Items:       []itemEntity.RewardItem{{ItemID: potionID, Count: 2}},
+		Delivery:    delivery,
+		CreatedBy:   1,
+	}
+}
+
+func TestGrantCampaignLifecycle(t *testing.T) {
+	f := setupCampaignService(t)
+	created, err := f.svc.CreateCampaign(campaignRequest(f, entity.DeliveryGrant))
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusScheduled, created.Status)
+	assert.Equal(t, reward.RewardSourceEvent, created.Source)
+
+	// Nothing happens before the start time
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err := f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusScheduled, campaign.Status)
+
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err = f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusActive, campaign.Status)
+	require.NotZero(t, campaign.JobID)
+
+	job := f.waitForJob(t, campaign.JobID)
+	assert.Equal(t, 2, job.SuccessCount)
+	assert.Equal(t, 0, f.items.Balance(1, potionID), "user 1 is not in the audience")
+	assert.Equal(t, 2, f.items.Balance(2, potionID))
+	assert.Equal(t, 2, f.items.Balance(3, potionID))
+
+	// Updating a started campaign is rejected
+	_, err = f.svc.UpdateCampaign(created.ID, UpdateCampaignRequest{Name: "Renamed"})
+	assert.ErrorIs(t, err, ErrCampaignStarted)
+
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err = f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusEnded, campaign.Status)
+	assert.Equal(t, 2, f.items.Balance(2, potionID))
+}
+
+func TestCampaignDeliveryIsIdempotent(t *testing.T) {
+	f := setupCampaignService(t)
+	created, err := f.svc.CreateCampaign(campaignRequest(f, entity.DeliveryGrant))
+	require.NoError(t, err)
+
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err := f.repo.GetByID(created.ID)
+	require.NoError(t, err)
+	f.waitForJob(t, campaign.JobID)
+
+	// A restart between creating the job and recording it makes the scheduler deliver again
+	require.NoError(t, f.repo.SaveDelivery(created.ID, 0, nil, ""))
+	require.NoError(t, f.svc.RunSchedule())
+	require.NoError(t, f.svc.RunSchedule())
+
+	redelivered, err := f.repo.GetByID(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, campaign.JobID, redelivered.JobID, "the campaign's job is reused")
+	jobs, err := f.jobs.ListJobs(reward.BulkJobQuery{})
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, 2, f.items.Balance(2, potionID))
+}
+
+func TestClaimCampaign(t *testing.T) {
+	f := setupCampaignService(t)
+	created, err := f.svc.CreateCampaign(campaignRequest(f, entity.DeliveryClaim))
+	require.NoError(t, err)
+
+	_, err = f.svc.Claim(2, created.ID)
+	assert.ErrorIs(t, err, ErrCampaignClosed)
+
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+
+	available, err := f.svc.ListAvailable(2)
+	require.NoError(t, err)
+	require.Len(t, available, 1)
+	assert.False(t, available[0].Claimed)
+
+	response, err := f.svc.Claim(2, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Claim.UserID)
+	assert.Equal(t, 2, f.items.Balance(2, potionID))
+
+	_, err = f.svc.Claim(2, created.ID)
+	assert.ErrorIs(t, err, ErrAlreadyClaimed)
+	_, err = f.svc.Claim(1, created.ID)
+	assert.ErrorIs(t, err, ErrNotEligible)
+
+	available, err = f.svc.ListAvailable(2)
+	require.NoError(t, err)
+	require.Len(t, available, 1)
+	assert.True(t, available[0].Claimed)
+	assert.Equal(t, 1, available[0].ClaimCount)
+
+	// Claims close with the campaign
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	_, err = f.svc.Claim(3, created.ID)
+	assert.ErrorIs(t, err, ErrCampaignClosed)
+	available, err = f.svc.ListAvailable(3)
+	require.NoError(t, err)
+	assert.Empty(t, available)
+}
+
+func TestCancelCampaign(t *testing.T) {
+	f := setupCampaignService(t)
+
+	req := campaignRequest(f, entity.DeliveryMailbox)
+	req.EndAt = req.StartAt
+	_, err := f.svc.CreateCampaign(req)
+	assert.ErrorIs(t, err, ErrInvalidCampaign)
+
+	req = campaignRequest(f, entity.DeliveryMailbox)
+	req.Audience = entity.Audience{}
+	_, err = f.svc.CreateCampaign(req)
+	assert.ErrorIs(t, err, ErrInvalidCampaign)
+
+	created, err := f.svc.CreateCampaign(campaignRequest(f, entity.DeliveryMailbox))
+	require.NoError(t, err)
+
+	cancelled, err := f.svc.CancelCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusCancelled, cancelled.Status)
+	_, err = f.svc.CancelCampaign(created.ID)
+	assert.ErrorIs(t, err, ErrCampaignFinished)
+
+	// A cancelled campaign never starts
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err := f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Zero(t, campaign.JobID)
+}
+
+func TestCampaignApproval(t *testing.T) {
+	t.Setenv("REWARD_APPROVAL_USER_THRESHOLD", "1")
+	f := setupCampaignService(t)
+
+	// Users 2 and 3 are in the audience, which is above the threshold
+	created, err := f.svc.CreateCampaign(campaignRequest(f, entity.DeliveryGrant))
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusPendingApproval, created.Status)
+	assert.Equal(t, []string{"user count 2 exceeds 1"}, created.ApprovalReasons)
+
+	// Held campaigns do not start
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err := f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusPendingApproval, campaign.Status)
+	assert.Zero(t, campaign.JobID)
+
+	_, err = f.svc.ApproveCampaign(created.ID, created.CreatedBy)
+	assert.ErrorIs(t, err, reward.ErrSelfApproval)
+
+	approved, err := f.svc.ApproveCampaign(created.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusScheduled, approved.Status)
+	assert.Equal(t, 2, approved.ApprovedBy)
+	_, err = f.svc.ApproveCampaign(created.ID, 2)
+	assert.ErrorIs(t, err, ErrNotPending)
+
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err = f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusActive, campaign.Status)
+	f.waitForJob(t, campaign.JobID)
+	assert.Equal(t, 2, f.items.Balance(2, potionID))
+}
+
+func TestClaimCampaignApproval(t *testing.T) {
+	t.Setenv("REWARD_APPROVAL_USER_THRESHOLD", "1")
+	f := setupCampaignService(t)
+
+	// Claim campaigns are checked as if the whole audience claimed
+	created, err := f.svc.CreateCampaign(campaignRequest(f, entity.DeliveryClaim))
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusPendingApproval, created.Status)
+	assert.Equal(t, []string{"user count 2 exceeds 1"}, created.ApprovalReasons)
+
+	// Held campaigns cannot be claimed
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	_, err = f.svc.Claim(2, created.ID)
+	assert.ErrorIs(t, err, ErrCampaignClosed)
+
+	_, err = f.svc.ApproveCampaign(created.ID, 2)
+	require.NoError(t, err)
+	require.NoError(t, f.svc.RunSchedule())
+	_, err = f.svc.Claim(2, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.items.Balance(2, potionID))
+
+	// A claim campaign scheduled under the threshold is rechecked on start like the others
+	req := campaignRequest(f, entity.DeliveryClaim)
+	req.Audience = entity.Audience{MinAge: 30}
+	scheduled, err := f.svc.CreateCampaign(req)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusScheduled, scheduled.Status)
+
+	require.NoError(t, f.users.Create(&userEntity.User{Name: "player", Email: "d@example.com", Age: 50}))
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+	campaign, err := f.svc.GetCampaign(scheduled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusPendingApproval, campaign.Status)
+}
+
+func TestCampaignApprovalRecheckedOnStart(t *testing.T) {
+	t.Setenv("REWARD_APPROVAL_USER_THRESHOLD", "1")
+	f := setupCampaignService(t)
+
+	req := campaignRequest(f, entity.DeliveryMailbox)
+	req.Audience = entity.Audience{MinAge: 30}
+	created, err := f.svc.CreateCampaign(req)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusScheduled, created.Status)
+
+	// The audience grows past the threshold before the campaign starts
+	require.NoError(t, f.users.Create(&userEntity.User{Name: "player", Email: "d@example.com", Age: 50}))
+	f.now = f.now.Add(time.Hour)
+	require.NoError(t, f.svc.RunSchedule())
+
+	campaign, err := f.svc.GetCampaign(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusPendingApproval, campaign.Status)
+	assert.Zero(t, campaign.JobID)
+
+	// Editing an approved campaign needs a new approval
+	_, err = f.svc.ApproveCampaign(created.ID, 2)
+	require.NoError(t, err)
+	updated, err := f.svc.UpdateCampaign(created.ID, UpdateCampaignRequest{Name: "Autumn festival", UpdatedBy: 2})
+	require.NoError(t, err)
+	assert.Equal(t, entity.CampaignStatusPendingApproval, updated.Status)
+	_, err = f.svc.ApproveCampaign(created.ID, 2)
+	assert.ErrorIs(t, err, reward.ErrSelfApproval)
+}
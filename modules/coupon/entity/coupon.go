@@ -62,6 +62,7 @@ type RedeemCouponResponse struct {
 	Code           string                `json:"code"`
 	DiscountAmount float64               `json:"discount_amount"`
 	RewardItems    []entity.RewardItem   `json:"reward_items,omitempty"`
+	GrantResult    *entity.GrantResult   `json:"grant_result,omitempty"` // 인벤토리 초과 정책 적용 결과
 	UsedAt         time.Time             `json:"used_at"`
 	Message        string                `json:"message"`
 }
@@ -8,6 +8,8 @@ import (
 
 	"fxserver/modules/coupon/entity"
 	"fxserver/modules/coupon/repository"
+	"fxserver/modules/item"
+	"fxserver/modules/reward"
 	"fxserver/pkg/dto"
 	"fxserver/pkg/validator"
 
@@ -192,6 +194,10 @@ func (h *Handler) RedeemCoupon(c echo.Context) error {
 		if strings.Contains(errorMsg, "order amount is required") {
 			return c.JSON(http.StatusBadRequest, dto.NewError(errorMsg, "invalid_request_error"))
 		}
+		if errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived) ||
+			errors.Is(err, reward.ErrSourceInactive) || errors.Is(err, reward.ErrSourceCapReached) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(errorMsg, "invalid_request_error"))
+		}
 		if strings.Contains(errorMsg, "failed to grant reward items") {
 			return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to grant reward items"))
 		}
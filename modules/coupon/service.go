@@ -3,11 +3,14 @@ package coupon
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"fxserver/modules/coupon/entity"
+	itemEntity "fxserver/modules/item/entity"
 	"fxserver/modules/coupon/repository"
 	"fxserver/modules/reward"
+	"fxserver/pkg/events"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -21,6 +24,11 @@ var (
 	ErrInvalidRewardType  = errors.New("invalid reward type")
 )
 
+// Domain events published by the coupon module
+const (
+	EventCouponRedeemed = "coupon.redeemed" // 쿠폰 사용 (Attributes: coupon_id)
+)
+
 type Service interface {
 	CreateCoupon(req CreateCouponRequest) (*entity.Coupon, error)
 	GetCoupon(id int) (*entity.Coupon, error)
@@ -35,6 +43,7 @@ type Service interface {
 type service struct {
 	repo          repository.CouponRepository
 	rewardService reward.Service
+	events        *events.Bus
 	logger        *zap.Logger
 }
 
@@ -42,6 +51,7 @@ type ServiceParam struct {
 	fx.In
 	Repository    repository.CouponRepository
 	RewardService reward.Service
+	Events        *events.Bus `optional:"true"`
 	Logger        *zap.Logger
 }
 
@@ -49,6 +59,7 @@ func NewService(p ServiceParam) Service {
 	return &service{
 		repo:          p.Repository,
 		rewardService: p.RewardService,
+		events:        p.Events,
 		logger:        p.Logger,
 	}
 }
@@ -267,13 +278,21 @@ func (s *service) RedeemCoupon(req RedeemCouponRequest) (*entity.RedeemCouponRes
 	}
 
 	// Handle item rewards
+	var grantResult *itemEntity.GrantResult
 	if coupon.HasRewardItems() {
-		// Grant reward items through reward service
-		if err := s.rewardService.GrantItemsToUser(
+		// Grant reward items through reward service (server default overflow policy)
+		var err error
+		if grantResult, err = s.rewardService.GrantItemsToUser(
 			req.UserID, 
 			coupon.RewardItems, 
+			"",
 			reward.RewardSourceCoupon,
 			fmt.Sprintf("Coupon redemption: %s", coupon.Name),
+			itemEntity.TransactionRef{
+				Type:  reward.RewardSourceCoupon,
+				ID:    strconv.Itoa(coupon.ID),
+				Actor: itemEntity.UserActor(req.UserID),
+			},
 		); err != nil {
 			s.logger.Error("Failed to grant reward items", 
 				zap.String("code", req.Code),
@@ -310,6 +329,7 @@ func (s *service) RedeemCoupon(req RedeemCouponRequest) (*entity.RedeemCouponRes
 		Code:           coupon.Code,
 		DiscountAmount: discountAmount,
 		RewardItems:    coupon.RewardItems,
+		GrantResult:    grantResult,
 		UsedAt:         now,
 		Message:        message,
 	}
@@ -320,5 +340,11 @@ func (s *service) RedeemCoupon(req RedeemCouponRequest) (*entity.RedeemCouponRes
 		zap.Float64("discount_amount", discountAmount),
 		zap.Int("reward_items_count", len(coupon.RewardItems)))
 
+	s.events.Publish(events.Event{
+		Type:       EventCouponRedeemed,
+		UserID:     req.UserID,
+		Attributes: map[string]string{"coupon_id": strconv.Itoa(coupon.ID)},
+	})
+
 	return response, nil
 }
\ No newline at end of file
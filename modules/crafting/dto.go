@@ -0,0 +1,45 @@
+package crafting
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Recipe management DTOs (Admin only)
+type CreateRecipeRequest struct {
+	Name           string                  `json:"name" validate:"required,min=2,max=100"`
+	Description    string                  `json:"description" validate:"omitempty,max=500"`
+	Inputs         []itemEntity.RewardItem `json:"inputs" validate:"required,min=1,dive"`
+	Outputs        []itemEntity.RewardItem `json:"outputs" validate:"required,min=1,dive"`
+	CurrencyItemID int                     `json:"currency_item_id" validate:"omitempty,gt=0"`
+	CurrencyAmount int                     `json:"currency_amount" validate:"omitempty,gt=0"`
+	StartsAt       *time.Time              `json:"starts_at,omitempty"`
+	EndsAt         *time.Time              `json:"ends_at,omitempty"`
+	IsActive       *bool                   `json:"is_active,omitempty"` // 미지정 시 활성
+}
+
+type UpdateRecipeRequest struct {
+	Name           string                  `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description    string                  `json:"description,omitempty" validate:"omitempty,max=500"`
+	Inputs         []itemEntity.RewardItem `json:"inputs,omitempty" validate:"omitempty,min=1,dive"`
+	Outputs        []itemEntity.RewardItem `json:"outputs,omitempty" validate:"omitempty,min=1,dive"`
+	CurrencyItemID *int                    `json:"currency_item_id,omitempty" validate:"omitempty,gte=0"` // 0: 화폐 비용 제거
+	CurrencyAmount *int                    `json:"currency_amount,omitempty" validate:"omitempty,gte=0"`
+	StartsAt       *time.Time              `json:"starts_at,omitempty"`
+	EndsAt         *time.Time              `json:"ends_at,omitempty"`
+	IsActive       *bool                   `json:"is_active,omitempty"`
+}
+
+// Craft query DTO
+type CraftQuery struct {
+	Times int `query:"times" validate:"omitempty,gt=0,lte=100"` // 미지정 시 1회
+}
+
+// Response DTOs
+type CraftResponse struct {
+	RecipeID int                     `json:"recipe_id"`
+	Times    int                     `json:"times"`
+	Consumed []itemEntity.RewardItem `json:"consumed"`
+	Produced []itemEntity.RewardItem `json:"produced"`
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Recipe converts a set of input items (plus optional currency) into output items
+type Recipe struct {
+	ID             int                     `json:"id"`
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description"`
+	Inputs         []itemEntity.RewardItem `json:"inputs"`           // 소모 재료
+	Outputs        []itemEntity.RewardItem `json:"outputs"`          // 제작 결과물
+	CurrencyItemID int                     `json:"currency_item_id"` // 제작 비용 화폐 (0: 없음)
+	CurrencyAmount int                     `json:"currency_amount"`
+	StartsAt       *time.Time              `json:"starts_at,omitempty"` // 제작 가능 기간 시작 (nil: 제한 없음)
+	EndsAt         *time.Time              `json:"ends_at,omitempty"`   // 제작 가능 기간 종료 (nil: 제한 없음)
+	IsActive       bool                    `json:"is_active"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// IsAvailable reports whether the recipe can be crafted at the given time
+func (r *Recipe) IsAvailable(now time.Time) bool {
+	if !r.IsActive {
+		return false
+	}
+	if r.StartsAt != nil && now.Before(*r.StartsAt) {
+		return false
+	}
+	if r.EndsAt != nil && !now.Before(*r.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Costs returns the inputs and currency consumed by crafting the recipe times times
+func (r *Recipe) Costs(times int) []itemEntity.RewardItem {
+	costs := multiply(r.Inputs, times)
+	if r.CurrencyItemID > 0 && r.CurrencyAmount > 0 {
+		costs = append(costs, itemEntity.RewardItem{ItemID: r.CurrencyItemID, Count: r.CurrencyAmount * times})
+	}
+	return costs
+}
+
+// Products returns the outputs granted by crafting the recipe times times
+func (r *Recipe) Products(times int) []itemEntity.RewardItem {
+	return multiply(r.Outputs, times)
+}
+
+func multiply(items []itemEntity.RewardItem, times int) []itemEntity.RewardItem {
+	result := make([]itemEntity.RewardItem, len(items))
+	for i, item := range items {
+		result[i] = itemEntity.RewardItem{ItemID: item.ItemID, Count: item.Count * times}
+	}
+	return result
+}
@@ -0,0 +1,197 @@
+package crafting
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// Craft crafts a recipe for the authenticated user
+func (h *Handler) Craft(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	recipeID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid recipe ID", "invalid_request_error"))
+	}
+
+	var query CraftQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	times := query.Times
+	if times == 0 {
+		times = 1
+	}
+
+	response, err := h.service.Craft(userID, recipeID, times)
+	if err != nil {
+		if errors.Is(err, ErrRecipeNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Recipe"))
+		}
+		if errors.Is(err, ErrRecipeNotAvailable) || errors.Is(err, ErrInvalidTimes) || errors.Is(err, item.ErrInsufficientItem) || errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to craft recipe", zap.Error(err), zap.Int("recipe_id", recipeID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to craft recipe"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Public APIs
+
+// GetRecipes returns the recipes currently available for crafting
+func (h *Handler) GetRecipes(c echo.Context) error {
+	recipes, err := h.service.ListRecipes(false)
+	if err != nil {
+		h.logger.Error("Failed to list recipes", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get recipes"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(recipes))
+}
+
+// GetRecipe returns a single recipe
+func (h *Handler) GetRecipe(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid recipe ID", "invalid_request_error"))
+	}
+
+	recipe, err := h.service.GetRecipe(id)
+	if err != nil {
+		if errors.Is(err, ErrRecipeNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Recipe"))
+		}
+		h.logger.Error("Failed to get recipe", zap.Error(err), zap.Int("recipe_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get recipe"))
+	}
+
+	return c.JSON(http.StatusOK, recipe)
+}
+
+// Admin APIs
+
+// GetAllRecipes returns every recipe including inactive ones (admin only)
+func (h *Handler) GetAllRecipes(c echo.Context) error {
+	recipes, err := h.service.ListRecipes(true)
+	if err != nil {
+		h.logger.Error("Failed to list recipes", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get recipes"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(recipes))
+}
+
+// CreateRecipe creates a new recipe (admin only)
+func (h *Handler) CreateRecipe(c echo.Context) error {
+	var req CreateRecipeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	recipe, err := h.service.CreateRecipe(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRecipe) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create recipe", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create recipe"))
+	}
+
+	return c.JSON(http.StatusCreated, recipe)
+}
+
+// UpdateRecipe updates an existing recipe (admin only)
+func (h *Handler) UpdateRecipe(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid recipe ID", "invalid_request_error"))
+	}
+
+	var req UpdateRecipeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	recipe, err := h.service.UpdateRecipe(id, req)
+	if err != nil {
+		if errors.Is(err, ErrRecipeNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Recipe"))
+		}
+		if errors.Is(err, ErrInvalidRecipe) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update recipe", zap.Error(err), zap.Int("recipe_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update recipe"))
+	}
+
+	return c.JSON(http.StatusOK, recipe)
+}
+
+// DeleteRecipe deletes a recipe (admin only)
+func (h *Handler) DeleteRecipe(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid recipe ID", "invalid_request_error"))
+	}
+
+	if err := h.service.DeleteRecipe(id); err != nil {
+		if errors.Is(err, ErrRecipeNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Recipe"))
+		}
+		h.logger.Error("Failed to delete recipe", zap.Error(err), zap.Int("recipe_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to delete recipe"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewEmpty(strconv.Itoa(id)))
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"errors"
+
+	"fxserver/modules/crafting/entity"
+)
+
+var (
+	ErrRecipeNotFound = errors.New("recipe not found")
+)
+
+type RecipeRepository interface {
+	Create(recipe *entity.Recipe) error
+	GetByID(id int) (*entity.Recipe, error)
+	Update(recipe *entity.Recipe) error
+	Delete(id int) error
+	List() ([]*entity.Recipe, error)
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"fxserver/modules/crafting/entity"
+)
+
+type memoryRecipeRepository struct {
+	recipes map[int]*entity.Recipe
+	nextID  int
+	mu      sync.RWMutex
+}
+
+func NewMemoryRecipeRepository() RecipeRepository {
+	return &memoryRecipeRepository{
+		recipes: make(map[int]*entity.Recipe),
+		nextID:  1,
+	}
+}
+
+func (r *memoryRecipeRepository) Create(recipe *entity.Recipe) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recipe.ID = r.nextID
+	recipe.CreatedAt = time.Now()
+	recipe.UpdatedAt = time.Now()
+	r.recipes[recipe.ID] = recipe
+	r.nextID++
+
+	return nil
+}
+
+func (r *memoryRecipeRepository) GetByID(id int) (*entity.Recipe, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recipe, exists := r.recipes[id]
+	if !exists {
+		return nil, ErrRecipeNotFound
+	}
+	return recipe, nil
+}
+
+func (r *memoryRecipeRepository) Update(recipe *entity.Recipe) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.recipes[recipe.ID]
+	if !exists {
+		return ErrRecipeNotFound
+	}
+
+	recipe.CreatedAt = existing.CreatedAt
+	recipe.UpdatedAt = time.Now()
+	r.recipes[recipe.ID] = recipe
+	return nil
+}
+
+func (r *memoryRecipeRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.recipes[id]; !exists {
+		return ErrRecipeNotFound
+	}
+	delete(r.recipes, id)
+	return nil
+}
+
+func (r *memoryRecipeRepository) List() ([]*entity.Recipe, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recipes := make([]*entity.Recipe, 0, len(r.recipes))
+	for _, recipe := range r.recipes {
+		recipes = append(recipes, recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		return recipes[i].ID < recipes[j].ID
+	})
+	return recipes, nil
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"go.uber.org/fx"
+)
+
+var Module = fx.Options(
+	fx.Provide(
+		fx.Annotate(
+			NewMemoryRecipeRepository,
+			fx.As(new(RecipeRepository)),
+		),
+	),
+)
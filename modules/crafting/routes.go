@@ -0,0 +1,51 @@
+package crafting
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// Public recipe routes (no auth required)
+	crafting := api.Group("/crafting")
+	crafting.GET("/recipes", r.handler.GetRecipes)    // Get available recipes
+	crafting.GET("/recipes/:id", r.handler.GetRecipe) // Get recipe by ID
+
+	// User crafting routes (user auth required)
+	crafting.POST("/recipes/:id/craft", r.handler.Craft, r.userMiddleware.VerifyAccessToken()) // Craft recipe (?times=N)
+
+	// Admin recipe management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminCrafting := admin.Group("/crafting")
+	adminCrafting.GET("/recipes", r.handler.GetAllRecipes, r.adminMiddleware.VerifyAdminToken())       // Get all recipes
+	adminCrafting.POST("/recipes", r.handler.CreateRecipe, r.adminMiddleware.VerifyAdminToken())       // Create recipe
+	adminCrafting.PUT("/recipes/:id", r.handler.UpdateRecipe, r.adminMiddleware.VerifyAdminToken())    // Update recipe
+	adminCrafting.DELETE("/recipes/:id", r.handler.DeleteRecipe, r.adminMiddleware.VerifyAdminToken()) // Delete recipe
+}
@@ -0,0 +1,275 @@
+package crafting
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fxserver/modules/crafting/entity"
+	"fxserver/modules/crafting/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRecipeNotFound     = errors.New("recipe not found")
+	ErrRecipeNotAvailable = errors.New("recipe is not available")
+	ErrInvalidRecipe      = errors.New("invalid recipe")
+	ErrInvalidTimes       = errors.New("times must be between 1 and 100")
+)
+
+// Inventory ledger source for crafting
+const SourceCrafting = "crafting"
+
+// MaxCraftTimes caps how many times a recipe can be crafted in one request
+const MaxCraftTimes = 100
+
+type Service interface {
+	// Player operations
+	Craft(userID, recipeID, times int) (*CraftResponse, error)
+
+	// Recipe queries
+	GetRecipe(id int) (*entity.Recipe, error)
+	ListRecipes(includeInactive bool) ([]*entity.Recipe, error)
+
+	// Recipe management (Admin)
+	CreateRecipe(req CreateRecipeRequest) (*entity.Recipe, error)
+	UpdateRecipe(id int, req UpdateRecipeRequest) (*entity.Recipe, error)
+	DeleteRecipe(id int) error
+}
+
+type service struct {
+	repo        repository.RecipeRepository
+	itemService item.Service
+	logger      *zap.Logger
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository  repository.RecipeRepository
+	ItemService item.Service
+	Logger      *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:        p.Repository,
+		itemService: p.ItemService,
+		logger:      p.Logger,
+	}
+}
+
+func (s *service) Craft(userID, recipeID, times int) (*CraftResponse, error) {
+	if times <= 0 || times > MaxCraftTimes {
+		return nil, ErrInvalidTimes
+	}
+
+	recipe, err := s.GetRecipe(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !recipe.IsAvailable(time.Now()) {
+		return nil, ErrRecipeNotAvailable
+	}
+
+	consumed := recipe.Costs(times)
+	produced := recipe.Products(times)
+	ref := itemEntity.TransactionRef{
+		Type:  SourceCrafting,
+		ID:    strconv.Itoa(recipe.ID),
+		Actor: itemEntity.UserActor(userID),
+	}
+
+	// Inputs and outputs are applied together; nothing is deducted if any input is short
+	if err := s.itemService.ExchangeItems(userID, consumed, produced, SourceCrafting, ref); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Recipe crafted",
+		zap.Int("user_id", userID),
+		zap.Int("recipe_id", recipe.ID),
+		zap.Int("times", times))
+
+	return &CraftResponse{
+		RecipeID: recipe.ID,
+		Times:    times,
+		Consumed: consumed,
+		Produced: produced,
+	}, nil
+}
+
+func (s *service) GetRecipe(id int) (*entity.Recipe, error) {
+	recipe, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecipeNotFound) {
+			return nil, ErrRecipeNotFound
+		}
+		return nil, fmt.Errorf("failed to get recipe: %w", err)
+	}
+	return recipe, nil
+}
+
+func (s *service) ListRecipes(includeInactive bool) ([]*entity.Recipe, error) {
+	recipes, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recipes: %w", err)
+	}
+	if includeInactive {
+		return recipes, nil
+	}
+
+	now := time.Now()
+	available := make([]*entity.Recipe, 0, len(recipes))
+	for _, recipe := range recipes {
+		if recipe.IsAvailable(now) {
+			available = append(available, recipe)
+		}
+	}
+	return available, nil
+}
+
+func (s *service) CreateRecipe(req CreateRecipeRequest) (*entity.Recipe, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	recipe := &entity.Recipe{
+		Name:           req.Name,
+		Description:    req.Description,
+		Inputs:         req.Inputs,
+		Outputs:        req.Outputs,
+		CurrencyItemID: req.CurrencyItemID,
+		CurrencyAmount: req.CurrencyAmount,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		IsActive:       isActive,
+	}
+
+	if err := s.validateRecipe(recipe); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(recipe); err != nil {
+		s.logger.Error("Failed to create recipe", zap.Error(err), zap.String("name", req.Name))
+		return nil, fmt.Errorf("failed to create recipe: %w", err)
+	}
+
+	s.logger.Info("Recipe created",
+		zap.Int("recipe_id", recipe.ID),
+		zap.String("name", recipe.Name))
+
+	return recipe, nil
+}
+
+func (s *service) UpdateRecipe(id int, req UpdateRecipeRequest) (*entity.Recipe, error) {
+	existing, err := s.GetRecipe(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Name != "" {
+		updated.Name = req.Name
+	}
+	if req.Description != "" {
+		updated.Description = req.Description
+	}
+	if req.Inputs != nil {
+		updated.Inputs = req.Inputs
+	}
+	if req.Outputs != nil {
+		updated.Outputs = req.Outputs
+	}
+	if req.CurrencyItemID != nil {
+		updated.CurrencyItemID = *req.CurrencyItemID
+	}
+	if req.CurrencyAmount != nil {
+		updated.CurrencyAmount = *req.CurrencyAmount
+	}
+	if req.StartsAt != nil {
+		updated.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updated.EndsAt = req.EndsAt
+	}
+	if req.IsActive != nil {
+		updated.IsActive = *req.IsActive
+	}
+
+	if err := s.validateRecipe(&updated); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		if errors.Is(err, repository.ErrRecipeNotFound) {
+			return nil, ErrRecipeNotFound
+		}
+		s.logger.Error("Failed to update recipe", zap.Error(err), zap.Int("recipe_id", id))
+		return nil, fmt.Errorf("failed to update recipe: %w", err)
+	}
+
+	s.logger.Info("Recipe updated", zap.Int("recipe_id", id))
+	return &updated, nil
+}
+
+func (s *service) DeleteRecipe(id int) error {
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, repository.ErrRecipeNotFound) {
+			return ErrRecipeNotFound
+		}
+		s.logger.Error("Failed to delete recipe", zap.Error(err), zap.Int("recipe_id", id))
+		return fmt.Errorf("failed to delete recipe: %w", err)
+	}
+
+	s.logger.Info("Recipe deleted", zap.Int("recipe_id", id))
+	return nil
+}
+
+func (s *service) validateRecipe(recipe *entity.Recipe) error {
+	if len(recipe.Inputs) == 0 && recipe.CurrencyAmount == 0 {
+		return fmt.Errorf("%w: recipe must consume inputs or currency", ErrInvalidRecipe)
+	}
+	if len(recipe.Outputs) == 0 {
+		return fmt.Errorf("%w: recipe must produce at least one item", ErrInvalidRecipe)
+	}
+
+	if (recipe.CurrencyItemID > 0) != (recipe.CurrencyAmount > 0) {
+		return fmt.Errorf("%w: currency item and amount must be set together", ErrInvalidRecipe)
+	}
+
+	if recipe.StartsAt != nil && recipe.EndsAt != nil && !recipe.EndsAt.After(*recipe.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidRecipe)
+	}
+
+	for _, reward := range append(append([]itemEntity.RewardItem{}, recipe.Inputs...), recipe.Outputs...) {
+		if reward.Count <= 0 {
+			return fmt.Errorf("%w: invalid count for item %d", ErrInvalidRecipe, reward.ItemID)
+		}
+		if _, err := s.itemService.GetItem(reward.ItemID); err != nil {
+			return fmt.Errorf("%w: item %d not found", ErrInvalidRecipe, reward.ItemID)
+		}
+	}
+	for _, output := range recipe.Outputs {
+		if template, err := s.itemService.GetItem(output.ItemID); err == nil && template.IsArchived() {
+			return fmt.Errorf("%w: output item %d is archived", ErrInvalidRecipe, output.ItemID)
+		}
+	}
+
+	if recipe.CurrencyItemID > 0 {
+		currency, err := s.itemService.GetItem(recipe.CurrencyItemID)
+		if err != nil {
+			return fmt.Errorf("%w: item %d not found", ErrInvalidRecipe, recipe.CurrencyItemID)
+		}
+		if currency.Type != itemEntity.ItemTypeCurrency {
+			return fmt.Errorf("%w: item %d is not a currency", ErrInvalidRecipe, recipe.CurrencyItemID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package crafting
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/crafting/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID    = 1
+	diamondID = 2
+	potionID  = 3
+)
+
+func setupCraftingService(t *testing.T) (Service, *itemtest.Fixture) {
+	items := itemtest.New()
+	svc := NewService(ServiceParam{
+		Repository:  repository.NewMemoryRecipeRepository(),
+		ItemService: items.Service,
+		Logger:      zap.NewNop(),
+	})
+	return svc, items
+}
+
+// potionRecipe turns 3 potions and 10 gold into 1 diamond
+func potionRecipe(t *testing.T, svc Service) int {
+	recipe, err := svc.CreateRecipe(CreateRecipeRequest{
+		Name:           "Potion Refinery",
+		Inputs:         []itemEntity.RewardItem{{ItemID: potionID, Count: 3}},
+		Outputs:        []itemEntity.RewardItem{{ItemID: diamondID, Count: 1}},
+		CurrencyItemID: goldID,
+		CurrencyAmount: 10,
+	})
+	require.NoError(t, err)
+	return recipe.ID
+}
+
+func TestCraftConsumesInputsAndGrantsOutputs(t *testing.T) {
+	svc, items := setupCraftingService(t)
+	recipeID := potionRecipe(t, svc)
+	require.NoError(t, items.Service.AddToInventory(1, potionID, 7, "admin", itemEntity.TransactionRef{}))
+	require.NoError(t, items.Service.AddToInventory(1, goldID, 25, "admin", itemEntity.TransactionRef{}))
+
+	response, err := svc.Craft(1, recipeID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Times)
+	assert.Equal(t, 1, items.Balance(1, potionID))
+	assert.Equal(t, 5, items.Balance(1, goldID))
+	assert.Equal(t, 2, items.Balance(1, diamondID))
+
+	transactions, err := items.Service.GetInventoryTransactions(1, item.InventoryTransactionQuery{Source: SourceCrafting})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 3)
+}
+
+func TestCraftInsufficientInputsDeductsNothing(t *testing.T) {
+	svc, items := setupCraftingService(t)
+	recipeID := potionRecipe(t, svc)
+	require.NoError(t, items.Service.AddToInventory(1, potionID, 6, "admin", itemEntity.TransactionRef{}))
+	require.NoError(t, items.Service.AddToInventory(1, goldID, 15, "admin", itemEntity.TransactionRef{}))
+
+	// Potions cover two crafts but gold only covers one
+	_, err := svc.Craft(1, recipeID, 2)
+	assert.ErrorIs(t, err, item.ErrInsufficientItem)
+	assert.Equal(t, 6, items.Balance(1, potionID))
+	assert.Equal(t, 15, items.Balance(1, goldID))
+	assert.Equal(t, 0, items.Balance(1, diamondID))
+}
+
+func TestCraftOutsideActiveWindow(t *testing.T) {
+	svc, items := setupCraftingService(t)
+	recipeID := potionRecipe(t, svc)
+	require.NoError(t, items.Service.AddToInventory(1, potionID, 3, "admin", itemEntity.TransactionRef{}))
+	require.NoError(t, items.Service.AddToInventory(1, goldID, 10, "admin", itemEntity.TransactionRef{}))
+
+	ended := time.Now().Add(-time.Hour)
+	_, err := svc.UpdateRecipe(recipeID, UpdateRecipeRequest{EndsAt: &ended})
+	require.NoError(t, err)
+
+	_, err = svc.Craft(1, recipeID, 1)
+	assert.ErrorIs(t, err, ErrRecipeNotAvailable)
+
+	recipes, err := svc.ListRecipes(false)
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+}
+
+func TestCreateRecipeValidation(t *testing.T) {
+	svc, _ := setupCraftingService(t)
+
+	_, err := svc.CreateRecipe(CreateRecipeRequest{
+		Name:    "Unknown Output",
+		Inputs:  []itemEntity.RewardItem{{ItemID: potionID, Count: 1}},
+		Outputs: []itemEntity.RewardItem{{ItemID: 999, Count: 1}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidRecipe)
+
+	_, err = svc.CreateRecipe(CreateRecipeRequest{
+		Name:           "Potion As Currency",
+		Inputs:         []itemEntity.RewardItem{{ItemID: goldID, Count: 1}},
+		Outputs:        []itemEntity.RewardItem{{ItemID: diamondID, Count: 1}},
+		CurrencyItemID: potionID,
+		CurrencyAmount: 1,
+	})
+	assert.ErrorIs(t, err, ErrInvalidRecipe)
+}
@@ -0,0 +1,35 @@
+package enhancement
+
+import (
+	"fxserver/modules/enhancement/entity"
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Enhancement table DTOs (Admin only)
+type UpsertLevelRequest struct {
+	Materials      []itemEntity.RewardItem `json:"materials" validate:"omitempty,dive"`
+	CurrencyItemID int                     `json:"currency_item_id" validate:"omitempty,gt=0"`
+	CurrencyAmount int                     `json:"currency_amount" validate:"omitempty,gt=0"`
+	SuccessRate    float64                 `json:"success_rate" validate:"required,gt=0,lte=1"`
+	FailureOutcome entity.FailureOutcome   `json:"failure_outcome" validate:"required"`
+}
+
+// Attempt query DTO (Admin only)
+type AttemptQuery struct {
+	UserID     int `query:"user_id" validate:"omitempty,gt=0"`
+	InstanceID int `query:"instance_id" validate:"omitempty,gt=0"`
+	Limit      int `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+// Response DTOs
+type EnhanceResponse struct {
+	InstanceID int                     `json:"instance_id"`
+	ItemID     int                     `json:"item_id"`
+	Success    bool                    `json:"success"`
+	FromLevel  int                     `json:"from_level"`
+	ToLevel    int                     `json:"to_level"`
+	Destroyed  bool                    `json:"destroyed"`
+	Outcome    entity.FailureOutcome   `json:"outcome,omitempty"`
+	Costs      []itemEntity.RewardItem `json:"costs"`
+	AttemptID  int                     `json:"attempt_id"`
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+type FailureOutcome string
+
+const (
+	FailureOutcomeKeep      FailureOutcome = "keep"      // 실패 시 레벨 유지
+	FailureOutcomeDowngrade FailureOutcome = "downgrade" // 실패 시 1레벨 하락
+	FailureOutcomeDestroy   FailureOutcome = "destroy"   // 실패 시 장비 파괴
+)
+
+type AttemptStatus string
+
+const (
+	AttemptStatusApplied  AttemptStatus = "applied"  // 비용 차감과 레벨 변경 완료
+	AttemptStatusRejected AttemptStatus = "rejected" // 비용 부족 등으로 아무것도 적용되지 않음
+)
+
+// EnhancementLevel is one row of the enhancement table: the cost and odds of
+// enhancing an instance from Level to Level+1
+type EnhancementLevel struct {
+	Level          int                     `json:"level"`
+	Materials      []itemEntity.RewardItem `json:"materials"`        // 소모 재료
+	CurrencyItemID int                     `json:"currency_item_id"` // 소모 화폐 아이템 (0: 없음)
+	CurrencyAmount int                     `json:"currency_amount"`
+	SuccessRate    float64                 `json:"success_rate"` // 0 < rate <= 1
+	FailureOutcome FailureOutcome          `json:"failure_outcome"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// EnhancementAttempt is the audit record of a single enhancement roll.
+// A rejected attempt keeps the roll but changed nothing, so the instance stays at FromLevel.
+type EnhancementAttempt struct {
+	ID          int                     `json:"id"`
+	UserID      int                     `json:"user_id"`
+	InstanceID  int                     `json:"instance_id"`
+	ItemID      int                     `json:"item_id"`
+	Status      AttemptStatus           `json:"status"`
+	Error       string                  `json:"error,omitempty"` // 적용되지 않은 사유
+	FromLevel   int                     `json:"from_level"`
+	ToLevel     int                     `json:"to_level"`
+	Success     bool                    `json:"success"`
+	Outcome     FailureOutcome          `json:"outcome,omitempty"` // 실패 시 적용된 결과
+	Destroyed   bool                    `json:"destroyed"`
+	SuccessRate float64                 `json:"success_rate"`
+	Roll        float64                 `json:"roll"`
+	Costs       []itemEntity.RewardItem `json:"costs"`
+	CreatedAt   time.Time               `json:"created_at"`
+}
+
+// Costs returns materials and currency consumed by one attempt at this level
+func (l *EnhancementLevel) Costs() []itemEntity.RewardItem {
+	costs := make([]itemEntity.RewardItem, 0, len(l.Materials)+1)
+	costs = append(costs, l.Materials...)
+	if l.CurrencyItemID > 0 && l.CurrencyAmount > 0 {
+		costs = append(costs, itemEntity.RewardItem{ItemID: l.CurrencyItemID, Count: l.CurrencyAmount})
+	}
+	return costs
+}
+
+// NextLevel returns the resulting level and whether the instance is destroyed
+func (l *EnhancementLevel) NextLevel(success bool) (int, bool) {
+	if success {
+		return l.Level + 1, false
+	}
+	switch l.FailureOutcome {
+	case FailureOutcomeDowngrade:
+		if l.Level > 1 {
+			return l.Level - 1, false
+		}
+		return l.Level, false
+	case FailureOutcomeDestroy:
+		return l.Level, true
+	default:
+		return l.Level, false
+	}
+}
+
+// IsValidFailureOutcome validates if the failure outcome is valid
+func IsValidFailureOutcome(outcome string) bool {
+	switch FailureOutcome(outcome) {
+	case FailureOutcomeKeep, FailureOutcomeDowngrade, FailureOutcomeDestroy:
+		return true
+	default:
+		return false
+	}
+}
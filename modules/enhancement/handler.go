@@ -0,0 +1,150 @@
+package enhancement
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// Enhance attempts to enhance one of the authenticated user's equipment instances
+func (h *Handler) Enhance(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	instanceID, err := strconv.Atoi(c.Param("instanceId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid instance ID", "invalid_request_error"))
+	}
+
+	response, err := h.service.Enhance(userID, instanceID)
+	if err != nil {
+		if errors.Is(err, item.ErrInstanceNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Item instance"))
+		}
+		if errors.Is(err, ErrNotEnhanceable) || errors.Is(err, ErrMaxLevel) || errors.Is(err, item.ErrInsufficientItem) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		if errors.Is(err, item.ErrInstanceChanged) {
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to enhance item instance", zap.Error(err), zap.Int("instance_id", instanceID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to enhance item"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Public APIs
+
+// GetLevels returns the enhancement table
+func (h *Handler) GetLevels(c echo.Context) error {
+	levels, err := h.service.ListLevels()
+	if err != nil {
+		h.logger.Error("Failed to list enhancement levels", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get enhancement levels"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(levels))
+}
+
+// Admin APIs
+
+// UpsertLevel creates or replaces an enhancement table row (admin only)
+func (h *Handler) UpsertLevel(c echo.Context) error {
+	level, err := strconv.Atoi(c.Param("level"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid level", "invalid_request_error"))
+	}
+
+	var req UpsertLevelRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	row, err := h.service.UpsertLevel(level, req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidFailureOutcome) || errors.Is(err, ErrInvalidCost) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to save enhancement level", zap.Error(err), zap.Int("level", level))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to save enhancement level"))
+	}
+
+	return c.JSON(http.StatusOK, row)
+}
+
+// DeleteLevel removes an enhancement table row (admin only)
+func (h *Handler) DeleteLevel(c echo.Context) error {
+	level, err := strconv.Atoi(c.Param("level"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid level", "invalid_request_error"))
+	}
+
+	if err := h.service.DeleteLevel(level); err != nil {
+		if errors.Is(err, ErrLevelNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Enhancement level"))
+		}
+		h.logger.Error("Failed to delete enhancement level", zap.Error(err), zap.Int("level", level))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to delete enhancement level"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewEmpty(strconv.Itoa(level)))
+}
+
+// GetAttempts returns the enhancement audit log (admin only)
+func (h *Handler) GetAttempts(c echo.Context) error {
+	var query AttemptQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	attempts, err := h.service.ListAttempts(query)
+	if err != nil {
+		h.logger.Error("Failed to list enhancement attempts", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get enhancement attempts"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(attempts))
+}
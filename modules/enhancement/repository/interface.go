@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"errors"
+
+	"fxserver/modules/enhancement/entity"
+)
+
+var (
+	ErrLevelNotFound = errors.New("enhancement level not found")
+)
+
+// AttemptFilter narrows down attempt queries; zero values are ignored
+type AttemptFilter struct {
+	UserID     int
+	InstanceID int
+	Limit      int
+}
+
+type EnhancementRepository interface {
+	// Enhancement table (Admin)
+	GetLevel(level int) (*entity.EnhancementLevel, error)
+	ListLevels() ([]*entity.EnhancementLevel, error)
+	UpsertLevel(level *entity.EnhancementLevel) error
+	DeleteLevel(level int) error
+
+	// Attempt audit log (append-only)
+	CreateAttempt(attempt *entity.EnhancementAttempt) error
+	ListAttempts(filter AttemptFilter) ([]*entity.EnhancementAttempt, error)
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"fxserver/modules/enhancement/entity"
+	itemEntity "fxserver/modules/item/entity"
+)
+
+type memoryEnhancementRepository struct {
+	levels   map[int]*entity.EnhancementLevel
+	attempts []*entity.EnhancementAttempt
+	nextID   int
+	mu       sync.RWMutex
+}
+
+func NewMemoryEnhancementRepository() EnhancementRepository {
+	repo := &memoryEnhancementRepository{
+		levels: make(map[int]*entity.EnhancementLevel),
+		nextID: 1,
+	}
+
+	// Initialize with a default table using gold (item 1)
+	repo.initializeDefaultLevels()
+
+	return repo
+}
+
+func (r *memoryEnhancementRepository) initializeDefaultLevels() {
+	defaultLevels := []*entity.EnhancementLevel{
+		{Level: 1, CurrencyItemID: 1, CurrencyAmount: 100, SuccessRate: 1.0, FailureOutcome: entity.FailureOutcomeKeep},
+		{Level: 2, CurrencyItemID: 1, CurrencyAmount: 300, SuccessRate: 0.8, FailureOutcome: entity.FailureOutcomeKeep},
+		{Level: 3, CurrencyItemID: 1, CurrencyAmount: 1000, SuccessRate: 0.5, FailureOutcome: entity.FailureOutcomeDowngrade},
+		{Level: 4, CurrencyItemID: 1, CurrencyAmount: 3000, SuccessRate: 0.3, FailureOutcome: entity.FailureOutcomeDestroy},
+	}
+
+	for _, level := range defaultLevels {
+		level.Materials = []itemEntity.RewardItem{}
+		level.UpdatedAt = time.Now()
+		r.levels[level.Level] = level
+	}
+}
+
+func (r *memoryEnhancementRepository) GetLevel(level int) (*entity.EnhancementLevel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, exists := r.levels[level]
+	if !exists {
+		return nil, ErrLevelNotFound
+	}
+	return row, nil
+}
+
+func (r *memoryEnhancementRepository) ListLevels() ([]*entity.EnhancementLevel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	levels := make([]*entity.EnhancementLevel, 0, len(r.levels))
+	for _, level := range r.levels {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Level < levels[j].Level
+	})
+	return levels, nil
+}
+
+func (r *memoryEnhancementRepository) UpsertLevel(level *entity.EnhancementLevel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	level.UpdatedAt = time.Now()
+	r.levels[level.Level] = level
+	return nil
+}
+
+func (r *memoryEnhancementRepository) DeleteLevel(level int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.levels[level]; !exists {
+		return ErrLevelNotFound
+	}
+	delete(r.levels, level)
+	return nil
+}
+
+func (r *memoryEnhancementRepository) CreateAttempt(attempt *entity.EnhancementAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt.ID = r.nextID
+	attempt.CreatedAt = time.Now()
+	r.attempts = append(r.attempts, attempt)
+	r.nextID++
+	return nil
+}
+
+func (r *memoryEnhancementRepository) ListAttempts(filter AttemptFilter) ([]*entity.EnhancementAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var attempts []*entity.EnhancementAttempt
+	for i := len(r.attempts) - 1; i >= 0; i-- {
+		attempt := r.attempts[i]
+		if filter.UserID != 0 && attempt.UserID != filter.UserID {
+			continue
+		}
+		if filter.InstanceID != 0 && attempt.InstanceID != filter.InstanceID {
+			continue
+		}
+		attempts = append(attempts, attempt)
+		if filter.Limit > 0 && len(attempts) >= filter.Limit {
+			break
+		}
+	}
+	return attempts, nil
+}
@@ -0,0 +1,50 @@
+package enhancement
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// Public enhancement info routes (no auth required)
+	enhancement := api.Group("/enhancement")
+	enhancement.GET("/levels", r.handler.GetLevels) // Get enhancement table
+
+	// User enhancement routes (user auth required)
+	users := api.Group("/users")
+	users.POST("/me/equipment/:instanceId/enhance", r.handler.Enhance, r.userMiddleware.VerifyAccessToken()) // Enhance equipment instance
+
+	// Admin enhancement management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminEnhancement := admin.Group("/enhancement")
+	adminEnhancement.PUT("/levels/:level", r.handler.UpsertLevel, r.adminMiddleware.VerifyAdminToken())    // Create or replace table row
+	adminEnhancement.DELETE("/levels/:level", r.handler.DeleteLevel, r.adminMiddleware.VerifyAdminToken()) // Delete table row
+	adminEnhancement.GET("/attempts", r.handler.GetAttempts, r.adminMiddleware.VerifyAdminToken())         // Get enhancement audit log
+}
@@ -0,0 +1,251 @@
+package enhancement
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"fxserver/modules/enhancement/entity"
+	"fxserver/modules/enhancement/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/pkg/random"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotEnhanceable        = errors.New("only equipment instances can be enhanced")
+	ErrMaxLevel              = errors.New("instance is already at the maximum enhancement level")
+	ErrLevelNotFound         = errors.New("enhancement level not found")
+	ErrInvalidFailureOutcome = errors.New("invalid failure outcome")
+	ErrInvalidCost           = errors.New("invalid enhancement cost")
+)
+
+// Inventory ledger source for enhancement costs
+const SourceEnhancement = "enhancement"
+
+type Service interface {
+	// Player operations
+	Enhance(userID, instanceID int) (*EnhanceResponse, error)
+
+	// Enhancement table (Admin)
+	ListLevels() ([]*entity.EnhancementLevel, error)
+	UpsertLevel(level int, req UpsertLevelRequest) (*entity.EnhancementLevel, error)
+	DeleteLevel(level int) error
+
+	// Audit (Admin)
+	ListAttempts(query AttemptQuery) ([]*entity.EnhancementAttempt, error)
+}
+
+type service struct {
+	repo        repository.EnhancementRepository
+	itemService item.Service
+	rng         random.Source
+	logger      *zap.Logger
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository  repository.EnhancementRepository
+	ItemService item.Service
+	Random      random.Source
+	Logger      *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:        p.Repository,
+		itemService: p.ItemService,
+		rng:         p.Random,
+		logger:      p.Logger,
+	}
+}
+
+func (s *service) Enhance(userID, instanceID int) (*EnhanceResponse, error) {
+	instance, err := s.itemService.GetUserInstance(userID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.itemService.GetItem(instance.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if template.Type != itemEntity.ItemTypeEquipment {
+		return nil, ErrNotEnhanceable
+	}
+
+	row, err := s.repo.GetLevel(instance.Level)
+	if err != nil {
+		return nil, ErrMaxLevel
+	}
+
+	fromLevel := instance.Level
+	roll := s.rng.Float64()
+	success := roll < row.SuccessRate
+	toLevel, destroyed := row.NextLevel(success)
+	costs := row.Costs()
+
+	change := itemEntity.InstanceLevelChange{
+		UserID:     userID,
+		InstanceID: instanceID,
+		FromLevel:  fromLevel,
+		ToLevel:    toLevel,
+		Destroy:    destroyed,
+	}
+	ref := itemEntity.TransactionRef{
+		Type:  SourceEnhancement,
+		ID:    strconv.Itoa(instanceID),
+		Actor: itemEntity.UserActor(userID),
+	}
+
+	// Costs and the level change are applied together; an attempt that cannot be paid changes
+	// nothing but is still recorded with the reason
+	attempt := &entity.EnhancementAttempt{
+		UserID:      userID,
+		InstanceID:  instanceID,
+		ItemID:      instance.ItemID,
+		FromLevel:   fromLevel,
+		ToLevel:     fromLevel,
+		SuccessRate: row.SuccessRate,
+		Roll:        roll,
+		Costs:       costs,
+	}
+	if err := s.itemService.ChangeInstanceLevel(change, costs, SourceEnhancement, ref); err != nil {
+		attempt.Status = entity.AttemptStatusRejected
+		attempt.Error = err.Error()
+		s.recordAttempt(attempt)
+		return nil, err
+	}
+
+	attempt.Status = entity.AttemptStatusApplied
+	attempt.ToLevel = toLevel
+	attempt.Success = success
+	attempt.Destroyed = destroyed
+	if !success {
+		attempt.Outcome = row.FailureOutcome
+	}
+	s.recordAttempt(attempt)
+
+	s.logger.Info("Enhancement attempted",
+		zap.Int("user_id", userID),
+		zap.Int("instance_id", instanceID),
+		zap.Int("from_level", fromLevel),
+		zap.Int("to_level", toLevel),
+		zap.Bool("success", success),
+		zap.Bool("destroyed", destroyed))
+
+	return &EnhanceResponse{
+		InstanceID: instanceID,
+		ItemID:     instance.ItemID,
+		Success:    success,
+		FromLevel:  fromLevel,
+		ToLevel:    toLevel,
+		Destroyed:  destroyed,
+		Outcome:    attempt.Outcome,
+		Costs:      costs,
+		AttemptID:  attempt.ID,
+	}, nil
+}
+
+// recordAttempt stores the audit record; a failure to record is logged but does not fail the attempt
+func (s *service) recordAttempt(attempt *entity.EnhancementAttempt) {
+	if err := s.repo.CreateAttempt(attempt); err != nil {
+		s.logger.Error("Failed to record enhancement attempt",
+			zap.Error(err),
+			zap.Int("user_id", attempt.UserID),
+			zap.Int("instance_id", attempt.InstanceID),
+			zap.String("status", string(attempt.Status)))
+	}
+}
+
+func (s *service) ListLevels() ([]*entity.EnhancementLevel, error) {
+	return s.repo.ListLevels()
+}
+
+func (s *service) UpsertLevel(level int, req UpsertLevelRequest) (*entity.EnhancementLevel, error) {
+	if level <= 0 {
+		return nil, fmt.Errorf("%w: level must be greater than 0", ErrInvalidCost)
+	}
+
+	if !entity.IsValidFailureOutcome(string(req.FailureOutcome)) {
+		return nil, ErrInvalidFailureOutcome
+	}
+
+	if (req.CurrencyItemID > 0) != (req.CurrencyAmount > 0) {
+		return nil, fmt.Errorf("%w: currency item and amount must be set together", ErrInvalidCost)
+	}
+
+	for _, material := range req.Materials {
+		if material.Count <= 0 {
+			return nil, fmt.Errorf("%w: invalid count for item %d", ErrInvalidCost, material.ItemID)
+		}
+		materialItem, err := s.itemService.GetItem(material.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %d not found", ErrInvalidCost, material.ItemID)
+		}
+		// Instanced items would be removed by count and could include the instance being enhanced
+		if materialItem.Type.IsInstanced() {
+			return nil, fmt.Errorf("%w: item %d cannot be used as a material", ErrInvalidCost, material.ItemID)
+		}
+	}
+
+	if req.CurrencyItemID > 0 {
+		currency, err := s.itemService.GetItem(req.CurrencyItemID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %d not found", ErrInvalidCost, req.CurrencyItemID)
+		}
+		if currency.Type != itemEntity.ItemTypeCurrency {
+			return nil, fmt.Errorf("%w: item %d is not a currency", ErrInvalidCost, req.CurrencyItemID)
+		}
+	}
+
+	materials := req.Materials
+	if materials == nil {
+		materials = []itemEntity.RewardItem{}
+	}
+
+	row := &entity.EnhancementLevel{
+		Level:          level,
+		Materials:      materials,
+		CurrencyItemID: req.CurrencyItemID,
+		CurrencyAmount: req.CurrencyAmount,
+		SuccessRate:    req.SuccessRate,
+		FailureOutcome: req.FailureOutcome,
+	}
+
+	if err := s.repo.UpsertLevel(row); err != nil {
+		s.logger.Error("Failed to save enhancement level", zap.Error(err), zap.Int("level", level))
+		return nil, fmt.Errorf("failed to save enhancement level: %w", err)
+	}
+
+	s.logger.Info("Enhancement level saved",
+		zap.Int("level", level),
+		zap.Float64("success_rate", req.SuccessRate),
+		zap.String("failure_outcome", string(req.FailureOutcome)))
+
+	return row, nil
+}
+
+func (s *service) DeleteLevel(level int) error {
+	if err := s.repo.DeleteLevel(level); err != nil {
+		if errors.Is(err, repository.ErrLevelNotFound) {
+			return ErrLevelNotFound
+		}
+		s.logger.Error("Failed to delete enhancement level", zap.Error(err), zap.Int("level", level))
+		return fmt.Errorf("failed to delete enhancement level: %w", err)
+	}
+
+	s.logger.Info("Enhancement level deleted", zap.Int("level", level))
+	return nil
+}
+
+func (s *service) ListAttempts(query AttemptQuery) ([]*entity.EnhancementAttempt, error) {
+	return s.repo.ListAttempts(repository.AttemptFilter{
+		UserID:     query.UserID,
+		InstanceID: query.InstanceID,
+		Limit:      query.Limit,
+	})
+}
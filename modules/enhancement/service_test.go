@@ -0,0 +1,180 @@
+package enhancement
+
+import (
+	"testing"
+
+	"fxserver/modules/enhancement/entity"
+	"fxserver/modules/enhancement/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	"fxserver/pkg/i18n"
+	"fxserver/pkg/random"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID  = 1
+	swordID = 4
+)
+
+// fixedSource always returns the same roll
+type fixedSource struct {
+	roll float64
+}
+
+func (f fixedSource) Float64() float64 { return f.roll }
+func (f fixedSource) Intn(n int) int   { return 0 }
+
+func setupEnhancementService(t *testing.T, rng random.Source) (Service, *itemtest.Fixture, repository.EnhancementRepository) {
+	items := itemtest.New()
+	repo := repository.NewMemoryEnhancementRepository()
+	svc := NewService(ServiceParam{
+		Repository:  repo,
+		ItemService: items.Service,
+		Random:      rng,
+		Logger:      zap.NewNop(),
+	})
+	return svc, items, repo
+}
+
+func grantSword(t *testing.T, items *itemtest.Fixture, userID int) int {
+	require.NoError(t, items.Service.AddToInventory(userID, swordID, 1, "admin", itemEntity.TransactionRef{}))
+	inventory, err := items.Service.GetUserInventory(userID, i18n.FallbackLocale)
+	require.NoError(t, err)
+	require.NotEmpty(t, inventory.Instances)
+	return inventory.Instances[len(inventory.Instances)-1].ID
+}
+
+func TestEnhanceSuccess(t *testing.T) {
+	svc, items, repo := setupEnhancementService(t, fixedSource{roll: 0.1})
+	instanceID := grantSword(t, items, 1)
+	require.NoError(t, items.Service.AddToInventory(1, goldID, 500, "admin", itemEntity.TransactionRef{}))
+
+	response, err := svc.Enhance(1, instanceID)
+	require.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, 1, response.FromLevel)
+	assert.Equal(t, 2, response.ToLevel)
+	assert.Equal(t, 400, items.Balance(1, goldID))
+
+	instance, err := items.Service.GetUserInstance(1, instanceID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, instance.Level)
+
+	attempts, err := repo.ListAttempts(repository.AttemptFilter{UserID: 1})
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.True(t, attempts[0].Success)
+	assert.Equal(t, entity.AttemptStatusApplied, attempts[0].Status)
+	assert.Empty(t, attempts[0].Error)
+}
+
+func TestEnhanceFailureOutcomes(t *testing.T) {
+	tests := []struct {
+		name          string
+		outcome       entity.FailureOutcome
+		wantLevel     int
+		wantDestroyed bool
+	}{
+		{name: "keep level", outcome: entity.FailureOutcomeKeep, wantLevel: 3},
+		{name: "downgrade", outcome: entity.FailureOutcomeDowngrade, wantLevel: 2},
+		{name: "destroy", outcome: entity.FailureOutcomeDestroy, wantLevel: 3, wantDestroyed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, items, _ := setupEnhancementService(t, fixedSource{roll: 0.99})
+			instanceID := grantSword(t, items, 1)
+			require.NoError(t, items.Service.AddToInventory(1, goldID, 1000, "admin", itemEntity.TransactionRef{}))
+
+			instance, err := items.Service.GetUserInstance(1, instanceID)
+			require.NoError(t, err)
+			instance.Level = 3
+
+			_, err = svc.UpsertLevel(3, UpsertLevelRequest{
+				CurrencyItemID: goldID,
+				CurrencyAmount: 100,
+				SuccessRate:    0.5,
+				FailureOutcome: tt.outcome,
+			})
+			require.NoError(t, err)
+
+			response, err := svc.Enhance(1, instanceID)
+			require.NoError(t, err)
+			assert.False(t, response.Success)
+			assert.Equal(t, tt.wantLevel, response.ToLevel)
+			assert.Equal(t, tt.wantDestroyed, response.Destroyed)
+			assert.Equal(t, 900, items.Balance(1, goldID))
+
+			_, err = items.Service.GetUserInstance(1, instanceID)
+			if tt.wantDestroyed {
+				assert.ErrorIs(t, err, item.ErrInstanceNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEnhanceInsufficientMaterials(t *testing.T) {
+	svc, items, repo := setupEnhancementService(t, fixedSource{roll: 0.1})
+	instanceID := grantSword(t, items, 1)
+	require.NoError(t, items.Service.AddToInventory(1, goldID, 50, "admin", itemEntity.TransactionRef{}))
+
+	_, err := svc.Enhance(1, instanceID)
+	assert.ErrorIs(t, err, item.ErrInsufficientItem)
+
+	// Nothing deducted and the level unchanged, but the attempt is recorded with the reason
+	assert.Equal(t, 50, items.Balance(1, goldID))
+	instance, err := items.Service.GetUserInstance(1, instanceID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, instance.Level)
+	attempts, err := repo.ListAttempts(repository.AttemptFilter{})
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, entity.AttemptStatusRejected, attempts[0].Status)
+	assert.Contains(t, attempts[0].Error, item.ErrInsufficientItem.Error())
+	assert.Equal(t, 1, attempts[0].ToLevel)
+	assert.False(t, attempts[0].Success)
+	assert.Equal(t, 0.1, attempts[0].Roll)
+}
+
+func TestEnhanceRejectsOtherUsersAndMaxLevel(t *testing.T) {
+	svc, items, _ := setupEnhancementService(t, fixedSource{roll: 0.1})
+	instanceID := grantSword(t, items, 1)
+
+	_, err := svc.Enhance(2, instanceID)
+	assert.ErrorIs(t, err, item.ErrInstanceNotFound)
+
+	instance, err := items.Service.GetUserInstance(1, instanceID)
+	require.NoError(t, err)
+	instance.Level = 99
+	_, err = svc.Enhance(1, instanceID)
+	assert.ErrorIs(t, err, ErrMaxLevel)
+}
+
+func TestUpsertLevelValidation(t *testing.T) {
+	svc, _, _ := setupEnhancementService(t, random.New(1))
+
+	_, err := svc.UpsertLevel(1, UpsertLevelRequest{SuccessRate: 0.5, FailureOutcome: "explode"})
+	assert.ErrorIs(t, err, ErrInvalidFailureOutcome)
+
+	_, err = svc.UpsertLevel(1, UpsertLevelRequest{
+		Materials:      []itemEntity.RewardItem{{ItemID: swordID, Count: 1}},
+		SuccessRate:    0.5,
+		FailureOutcome: entity.FailureOutcomeKeep,
+	})
+	assert.ErrorIs(t, err, ErrInvalidCost)
+
+	_, err = svc.UpsertLevel(1, UpsertLevelRequest{
+		CurrencyItemID: 3, // 체력 포션 is not a currency
+		CurrencyAmount: 10,
+		SuccessRate:    0.5,
+		FailureOutcome: entity.FailureOutcomeKeep,
+	})
+	assert.ErrorIs(t, err, ErrInvalidCost)
+}
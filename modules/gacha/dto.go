@@ -0,0 +1,83 @@
+package gacha
+
+import (
+	"time"
+
+	"fxserver/modules/gacha/entity"
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Banner management DTOs (Admin only)
+type CreateBannerRequest struct {
+	Name          string              `json:"name" validate:"required,min=2,max=100"`
+	Description   string              `json:"description" validate:"omitempty,max=500"`
+	CostItemID    int                 `json:"cost_item_id" validate:"required,gt=0"`
+	CostAmount    int                 `json:"cost_amount" validate:"required,gt=0"`
+	RarityRates   []entity.RarityRate `json:"rarity_rates" validate:"required,min=1,dive"`
+	Items         []entity.BannerItem `json:"items" validate:"required,min=1,dive"`
+	RateUpShare   float64             `json:"rate_up_share" validate:"gte=0,lte=1"`
+	PityThreshold int                 `json:"pity_threshold" validate:"gte=0"`
+	PityRarity    string              `json:"pity_rarity,omitempty" validate:"omitempty,oneof=common rare epic legendary"`
+	StartsAt      *time.Time          `json:"starts_at,omitempty"`
+	EndsAt        *time.Time          `json:"ends_at,omitempty"`
+	IsActive      *bool               `json:"is_active,omitempty"` // 미지정 시 활성
+}
+
+type UpdateBannerRequest struct {
+	Name          string              `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description   string              `json:"description,omitempty" validate:"omitempty,max=500"`
+	CostItemID    int                 `json:"cost_item_id,omitempty" validate:"omitempty,gt=0"`
+	CostAmount    int                 `json:"cost_amount,omitempty" validate:"omitempty,gt=0"`
+	RarityRates   []entity.RarityRate `json:"rarity_rates,omitempty" validate:"omitempty,min=1,dive"`
+	Items         []entity.BannerItem `json:"items,omitempty" validate:"omitempty,min=1,dive"`
+	RateUpShare   *float64            `json:"rate_up_share,omitempty" validate:"omitempty,gte=0,lte=1"`
+	PityThreshold *int                `json:"pity_threshold,omitempty" validate:"omitempty,gte=0"`
+	PityRarity    string              `json:"pity_rarity,omitempty" validate:"omitempty,oneof=common rare epic legendary"`
+	StartsAt      *time.Time          `json:"starts_at,omitempty"`
+	EndsAt        *time.Time          `json:"ends_at,omitempty"`
+	IsActive      *bool               `json:"is_active,omitempty"`
+}
+
+// Draw DTOs
+type DrawRequest struct {
+	Count int `json:"count" validate:"required,oneof=1 10"` // 1회 또는 10회 뽑기
+}
+
+// Draw history query DTO (Admin only)
+type DrawQuery struct {
+	UserID   int `query:"user_id" validate:"omitempty,gt=0"`
+	BannerID int `query:"banner_id" validate:"omitempty,gt=0"`
+	Limit    int `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+// Response DTOs
+type DrawResponse struct {
+	DrawID   int                     `json:"draw_id"`
+	BannerID int                     `json:"banner_id"`
+	Pulls    int                     `json:"pulls"`
+	Cost     itemEntity.RewardItem   `json:"cost"`
+	Results  []entity.DrawResult     `json:"results"`
+	Granted  []itemEntity.RewardItem `json:"granted"`            // 결과를 아이템별로 합산한 지급 내역
+	Overflow []itemEntity.RewardItem `json:"overflow,omitempty"` // 인벤토리에 들어가지 않아 우편함으로 보낸 수량
+	Pity     entity.PityState        `json:"pity"`
+}
+
+// RatesResponse is the published drop rate table of a banner
+type RatesResponse struct {
+	BannerID      int                 `json:"banner_id"`
+	Name          string              `json:"name"`
+	RarityRates   []entity.RarityRate `json:"rarity_rates"`
+	Items         []ItemRate          `json:"items"`
+	RateUpShare   float64             `json:"rate_up_share"`
+	PityThreshold int                 `json:"pity_threshold"`
+	PityRarity    string              `json:"pity_rarity,omitempty"`
+}
+
+type ItemRate struct {
+	ItemID int     `json:"item_id"`
+	Name   string  `json:"name"`
+	Count  int     `json:"count"`
+	Rarity string  `json:"rarity"`
+	RateUp bool    `json:"rate_up"`
+	Rate   float64 `json:"rate"` // 1회 뽑기 기준 확률 (천장 미적용)
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Banner is an admin-defined gacha pool.
+// A pull first rolls a rarity from RarityRates, then picks an item of that rarity by weight.
+type Banner struct {
+	ID            int          `json:"id"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	CostItemID    int          `json:"cost_item_id"`   // 1회 뽑기 비용 아이템 (티켓 또는 화폐)
+	CostAmount    int          `json:"cost_amount"`    // 1회 뽑기 비용 수량
+	RarityRates   []RarityRate `json:"rarity_rates"`   // 등급별 확률 (합계 1)
+	Items         []BannerItem `json:"items"`          // 등장 아이템
+	RateUpShare   float64      `json:"rate_up_share"`  // 해당 등급 당첨 시 픽업 아이템이 나올 확률
+	PityThreshold int          `json:"pity_threshold"` // N회째 뽑기에 PityRarity 이상 확정 (0: 천장 없음)
+	PityRarity    string       `json:"pity_rarity,omitempty"`
+	StartsAt      *time.Time   `json:"starts_at,omitempty"`
+	EndsAt        *time.Time   `json:"ends_at,omitempty"`
+	IsActive      bool         `json:"is_active"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+type RarityRate struct {
+	Rarity string  `json:"rarity" validate:"required"`
+	Rate   float64 `json:"rate" validate:"gt=0,lte=1"`
+}
+
+type BannerItem struct {
+	ItemID int    `json:"item_id" validate:"required,gt=0"`
+	Count  int    `json:"count" validate:"omitempty,gt=0"`  // 1회 당첨 시 지급 수량 (기본 1)
+	Weight int    `json:"weight" validate:"omitempty,gt=0"` // 같은 그룹 내 가중치 (기본 1)
+	RateUp bool   `json:"rate_up"`                          // 픽업 대상 여부
+	Rarity string `json:"rarity"`                           // 등록 시점의 아이템 등급 (서버에서 설정)
+}
+
+// IsAvailable reports whether the banner can be drawn from at the given time
+func (b *Banner) IsAvailable(now time.Time) bool {
+	if !b.IsActive {
+		return false
+	}
+	if b.StartsAt != nil && now.Before(*b.StartsAt) {
+		return false
+	}
+	if b.EndsAt != nil && !now.Before(*b.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Cost returns the cost of drawing count times
+func (b *Banner) Cost(count int) itemEntity.RewardItem {
+	return itemEntity.RewardItem{ItemID: b.CostItemID, Count: b.CostAmount * count}
+}
+
+// IsPityRarity reports whether rarity satisfies the banner's pity guarantee
+func (b *Banner) IsPityRarity(rarity string) bool {
+	return b.PityThreshold > 0 && itemEntity.RarityRank(rarity) >= itemEntity.RarityRank(b.PityRarity)
+}
+
+// ItemsByRarity splits the items of a rarity into rate-up and regular groups
+func (b *Banner) ItemsByRarity(rarity string) (rateUp, regular []BannerItem) {
+	for _, item := range b.Items {
+		if item.Rarity != rarity {
+			continue
+		}
+		if item.RateUp {
+			rateUp = append(rateUp, item)
+		} else {
+			regular = append(regular, item)
+		}
+	}
+	return rateUp, regular
+}
+
+// PityState tracks a user's progress towards the pity guarantee on a banner
+type PityState struct {
+	UserID     int       `json:"user_id"`
+	BannerID   int       `json:"banner_id"`
+	Count      int       `json:"count"`       // 마지막 천장 등급 당첨 이후 뽑기 횟수
+	TotalPulls int       `json:"total_pulls"` // 누적 뽑기 횟수
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// DrawResult is a single pull outcome
+type DrawResult struct {
+	ItemID int    `json:"item_id"`
+	Count  int    `json:"count"`
+	Rarity string `json:"rarity"`
+	RateUp bool   `json:"rate_up"`
+	Pity   bool   `json:"pity"` // 천장으로 확정된 결과 여부
+}
+
+// DrawRecord is the audit log of one draw request
+type DrawRecord struct {
+	ID        int                   `json:"id"`
+	UserID    int                   `json:"user_id"`
+	BannerID  int                   `json:"banner_id"`
+	Pulls     int                   `json:"pulls"`
+	Cost      itemEntity.RewardItem `json:"cost"`
+	Results   []DrawResult          `json:"results"`
+	CreatedAt time.Time             `json:"created_at"`
+}
@@ -0,0 +1,258 @@
+package gacha
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/modules/reward"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/i18n"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// Draw performs 1 or 10 pulls on a banner for the authenticated user
+func (h *Handler) Draw(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	bannerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid banner ID", "invalid_request_error"))
+	}
+
+	var req DrawRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	response, err := h.service.Draw(userID, bannerID, req.Count)
+	if err != nil {
+		if errors.Is(err, ErrBannerNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Banner"))
+		}
+		if errors.Is(err, ErrBannerNotAvailable) || errors.Is(err, ErrInvalidDrawCount) || errors.Is(err, item.ErrInsufficientItem) || errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived) ||
+			errors.Is(err, reward.ErrSourceInactive) || errors.Is(err, reward.ErrSourceCapReached) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to draw from banner", zap.Error(err), zap.Int("banner_id", bannerID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to draw"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetPity returns the authenticated user's pity progress on a banner
+func (h *Handler) GetPity(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	bannerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid banner ID", "invalid_request_error"))
+	}
+
+	pity, err := h.service.GetPity(userID, bannerID)
+	if err != nil {
+		if errors.Is(err, ErrBannerNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Banner"))
+		}
+		h.logger.Error("Failed to get pity state", zap.Error(err), zap.Int("banner_id", bannerID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get pity state"))
+	}
+
+	return c.JSON(http.StatusOK, pity)
+}
+
+// Public APIs
+
+// GetBanners returns the banners currently open for drawing
+func (h *Handler) GetBanners(c echo.Context) error {
+	banners, err := h.service.ListBanners(false)
+	if err != nil {
+		h.logger.Error("Failed to list banners", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get banners"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(banners))
+}
+
+// GetBanner returns a single banner
+func (h *Handler) GetBanner(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid banner ID", "invalid_request_error"))
+	}
+
+	banner, err := h.service.GetBanner(id)
+	if err != nil {
+		if errors.Is(err, ErrBannerNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Banner"))
+		}
+		h.logger.Error("Failed to get banner", zap.Error(err), zap.Int("banner_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get banner"))
+	}
+
+	return c.JSON(http.StatusOK, banner)
+}
+
+// GetRates returns the published drop rates of a banner
+func (h *Handler) GetRates(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid banner ID", "invalid_request_error"))
+	}
+
+	rates, err := h.service.GetRates(id, i18n.FromRequest(c))
+	if err != nil {
+		if errors.Is(err, ErrBannerNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Banner"))
+		}
+		h.logger.Error("Failed to get banner rates", zap.Error(err), zap.Int("banner_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get banner rates"))
+	}
+
+	return c.JSON(http.StatusOK, rates)
+}
+
+// Admin APIs
+
+// GetAllBanners returns every banner including inactive ones (admin only)
+func (h *Handler) GetAllBanners(c echo.Context) error {
+	banners, err := h.service.ListBanners(true)
+	if err != nil {
+		h.logger.Error("Failed to list banners", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get banners"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(banners))
+}
+
+// CreateBanner creates a new banner (admin only)
+func (h *Handler) CreateBanner(c echo.Context) error {
+	var req CreateBannerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	banner, err := h.service.CreateBanner(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBanner) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create banner", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create banner"))
+	}
+
+	return c.JSON(http.StatusCreated, banner)
+}
+
+// UpdateBanner updates an existing banner (admin only)
+func (h *Handler) UpdateBanner(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid banner ID", "invalid_request_error"))
+	}
+
+	var req UpdateBannerRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	banner, err := h.service.UpdateBanner(id, req)
+	if err != nil {
+		if errors.Is(err, ErrBannerNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Banner"))
+		}
+		if errors.Is(err, ErrInvalidBanner) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update banner", zap.Error(err), zap.Int("banner_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update banner"))
+	}
+
+	return c.JSON(http.StatusOK, banner)
+}
+
+// DeleteBanner deletes a banner (admin only)
+func (h *Handler) DeleteBanner(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid banner ID", "invalid_request_error"))
+	}
+
+	if err := h.service.DeleteBanner(id); err != nil {
+		if errors.Is(err, ErrBannerNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Banner"))
+		}
+		h.logger.Error("Failed to delete banner", zap.Error(err), zap.Int("banner_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to delete banner"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewEmpty(strconv.Itoa(id)))
+}
+
+// GetDraws returns the gacha draw audit log (admin only)
+func (h *Handler) GetDraws(c echo.Context) error {
+	var query DrawQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	draws, err := h.service.ListDraws(query)
+	if err != nil {
+		h.logger.Error("Failed to list gacha draws", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get gacha draws"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(draws))
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+
+	"fxserver/modules/gacha/entity"
+)
+
+var (
+	ErrBannerNotFound = errors.New("banner not found")
+)
+
+// DrawFilter narrows draw record queries; zero values are ignored
+type DrawFilter struct {
+	UserID   int
+	BannerID int
+	Limit    int
+}
+
+type BannerRepository interface {
+	Create(banner *entity.Banner) error
+	GetByID(id int) (*entity.Banner, error)
+	Update(banner *entity.Banner) error
+	Delete(id int) error
+	List() ([]*entity.Banner, error)
+}
+
+type DrawRepository interface {
+	// GetPity returns the user's pity state, or a zero state if the user has never drawn
+	GetPity(userID, bannerID int) (*entity.PityState, error)
+	SavePity(state *entity.PityState) error
+	CreateDraw(record *entity.DrawRecord) error
+	ListDraws(filter DrawFilter) ([]*entity.DrawRecord, error)
+}
+
+type Repository interface {
+	BannerRepository
+	DrawRepository
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"fxserver/modules/gacha/entity"
+)
+
+type pityKey struct {
+	userID   int
+	bannerID int
+}
+
+type memoryRepository struct {
+	banners      map[int]*entity.Banner
+	pity         map[pityKey]*entity.PityState
+	draws        []*entity.DrawRecord
+	bannerNextID int
+	drawNextID   int
+	mu           sync.RWMutex
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		banners:      make(map[int]*entity.Banner),
+		pity:         make(map[pityKey]*entity.PityState),
+		draws:        make([]*entity.DrawRecord, 0),
+		bannerNextID: 1,
+		drawNextID:   1,
+	}
+}
+
+// Banner operations
+
+func (r *memoryRepository) Create(banner *entity.Banner) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	banner.ID = r.bannerNextID
+	banner.CreatedAt = time.Now()
+	banner.UpdatedAt = time.Now()
+	r.banners[banner.ID] = banner
+	r.bannerNextID++
+	return nil
+}
+
+func (r *memoryRepository) GetByID(id int) (*entity.Banner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	banner, exists := r.banners[id]
+	if !exists {
+		return nil, ErrBannerNotFound
+	}
+	return banner, nil
+}
+
+func (r *memoryRepository) Update(banner *entity.Banner) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.banners[banner.ID]
+	if !exists {
+		return ErrBannerNotFound
+	}
+
+	banner.CreatedAt = existing.CreatedAt
+	banner.UpdatedAt = time.Now()
+	r.banners[banner.ID] = banner
+	return nil
+}
+
+func (r *memoryRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.banners[id]; !exists {
+		return ErrBannerNotFound
+	}
+	delete(r.banners, id)
+	return nil
+}
+
+func (r *memoryRepository) List() ([]*entity.Banner, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	banners := make([]*entity.Banner, 0, len(r.banners))
+	for _, banner := range r.banners {
+		banners = append(banners, banner)
+	}
+	sort.Slice(banners, func(i, j int) bool {
+		return banners[i].ID < banners[j].ID
+	})
+	return banners, nil
+}
+
+// Draw operations
+
+func (r *memoryRepository) GetPity(userID, bannerID int) (*entity.PityState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if state, exists := r.pity[pityKey{userID, bannerID}]; exists {
+		copied := *state
+		return &copied, nil
+	}
+	return &entity.PityState{UserID: userID, BannerID: bannerID}, nil
+}
+
+func (r *memoryRepository) SavePity(state *entity.PityState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state.UpdatedAt = time.Now()
+	copied := *state
+	r.pity[pityKey{state.UserID, state.BannerID}] = &copied
+	return nil
+}
+
+func (r *memoryRepository) CreateDraw(record *entity.DrawRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record.ID = r.drawNextID
+	record.CreatedAt = time.Now()
+	r.draws = append(r.draws, record)
+	r.drawNextID++
+	return nil
+}
+
+func (r *memoryRepository) ListDraws(filter DrawFilter) ([]*entity.DrawRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var draws []*entity.DrawRecord
+	for i := len(r.draws) - 1; i >= 0; i-- {
+		draw := r.draws[i]
+		if filter.UserID != 0 && draw.UserID != filter.UserID {
+			continue
+		}
+		if filter.BannerID != 0 && draw.BannerID != filter.BannerID {
+			continue
+		}
+		draws = append(draws, draw)
+		if filter.Limit > 0 && len(draws) >= filter.Limit {
+			break
+		}
+	}
+	return draws, nil
+}
@@ -0,0 +1,54 @@
+package gacha
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// Public banner routes (no auth required)
+	gacha := api.Group("/gacha")
+	gacha.GET("/banners", r.handler.GetBanners)         // Get open banners
+	gacha.GET("/banners/:id", r.handler.GetBanner)      // Get banner by ID
+	gacha.GET("/banners/:id/rates", r.handler.GetRates) // Get published drop rates
+
+	// User gacha routes (user auth required)
+	gacha.POST("/banners/:id/draw", r.handler.Draw, r.userMiddleware.VerifyAccessToken())   // Draw 1 or 10 pulls
+	gacha.GET("/banners/:id/pity", r.handler.GetPity, r.userMiddleware.VerifyAccessToken()) // Get my pity progress
+
+	// Admin banner management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminGacha := admin.Group("/gacha")
+	adminGacha.GET("/banners", r.handler.GetAllBanners, r.adminMiddleware.VerifyAdminToken())       // Get all banners
+	adminGacha.POST("/banners", r.handler.CreateBanner, r.adminMiddleware.VerifyAdminToken())       // Create banner
+	adminGacha.PUT("/banners/:id", r.handler.UpdateBanner, r.adminMiddleware.VerifyAdminToken())    // Update banner
+	adminGacha.DELETE("/banners/:id", r.handler.DeleteBanner, r.adminMiddleware.VerifyAdminToken()) // Delete banner
+	adminGacha.GET("/draws", r.handler.GetDraws, r.adminMiddleware.VerifyAdminToken())              // Get draw audit log
+}
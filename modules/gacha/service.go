@@ -0,0 +1,570 @@
+package gacha
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"fxserver/modules/gacha/entity"
+	"fxserver/modules/gacha/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/reward"
+	"fxserver/pkg/i18n"
+	"fxserver/pkg/lock"
+	"fxserver/pkg/random"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBannerNotFound     = errors.New("banner not found")
+	ErrBannerNotAvailable = errors.New("banner is not available")
+	ErrInvalidBanner      = errors.New("invalid banner")
+	ErrInvalidDrawCount   = errors.New("draw count must be 1 or 10")
+)
+
+// Inventory ledger sources for gacha
+const (
+	SourceGacha       = reward.RewardSourceGacha // 뽑기 비용 차감 및 결과 지급
+	SourceGachaRefund = "gacha_refund"           // 결과 지급 실패로 인한 비용 환불
+)
+
+// rateTolerance absorbs float rounding when checking that rarity rates sum to 1
+const rateTolerance = 1e-6
+
+type Service interface {
+	// Player operations
+	Draw(userID, bannerID, count int) (*DrawResponse, error)
+	GetPity(userID, bannerID int) (*entity.PityState, error)
+
+	// Banner queries
+	GetBanner(id int) (*entity.Banner, error)
+	ListBanners(includeInactive bool) ([]*entity.Banner, error)
+	GetRates(bannerID int, locale i18n.Locale) (*RatesResponse, error)
+
+	// Banner management (Admin)
+	CreateBanner(req CreateBannerRequest) (*entity.Banner, error)
+	UpdateBanner(id int, req UpdateBannerRequest) (*entity.Banner, error)
+	DeleteBanner(id int) error
+
+	// Audit (Admin)
+	ListDraws(query DrawQuery) ([]*entity.DrawRecord, error)
+}
+
+type service struct {
+	repo          repository.Repository
+	itemService   item.Service
+	rewardService reward.Service
+	rng           random.Source
+	logger        *zap.Logger
+
+	// drawLocks serializes each player's draws so pity counters are read and written consistently
+	drawLocks lock.Keyed[int]
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository    repository.Repository
+	ItemService   item.Service
+	RewardService reward.Service
+	Random        random.Source
+	Logger        *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:          p.Repository,
+		itemService:   p.ItemService,
+		rewardService: p.RewardService,
+		rng:           p.Random,
+		logger:        p.Logger,
+	}
+}
+
+func (s *service) Draw(userID, bannerID, count int) (*DrawResponse, error) {
+	if count != 1 && count != 10 {
+		return nil, ErrInvalidDrawCount
+	}
+
+	banner, err := s.GetBanner(bannerID)
+	if err != nil {
+		return nil, err
+	}
+	if !banner.IsAvailable(time.Now()) {
+		return nil, ErrBannerNotAvailable
+	}
+
+	defer s.drawLocks.Lock(userID)()
+
+	pity, err := s.repo.GetPity(userID, bannerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pity state: %w", err)
+	}
+
+	results := make([]entity.DrawResult, count)
+	pulled := make([]itemEntity.RewardItem, count)
+	for i := range results {
+		results[i] = s.pull(banner, pity)
+		pulled[i] = itemEntity.RewardItem{ItemID: results[i].ItemID, Count: results[i].Count}
+	}
+
+	cost := banner.Cost(count)
+	// 같은 아이템은 합산해서 한 번에 지급
+	granted := itemEntity.MergeRewardItems(pulled)
+	ref := itemEntity.TransactionRef{
+		Type:  SourceGacha,
+		ID:    strconv.Itoa(banner.ID),
+		Actor: itemEntity.UserActor(userID),
+	}
+
+	if err := s.itemService.ExchangeItems(userID, []itemEntity.RewardItem{cost}, nil, SourceGacha, ref); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("%s %d회 뽑기", banner.Name, count)
+	// Paid results are never discarded; whatever does not fit is mailed to the player
+	grant, err := s.rewardService.GrantItemsToUser(userID, granted, itemEntity.OverflowMailbox, SourceGacha, description, ref)
+	if err != nil {
+		s.logger.Error("Failed to grant gacha results, refunding cost",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.Int("banner_id", bannerID))
+		if refundErr := s.itemService.AddToInventory(userID, cost.ItemID, cost.Count, SourceGachaRefund, ref); refundErr != nil {
+			s.logger.Error("Failed to refund gacha cost", zap.Error(refundErr), zap.Int("user_id", userID))
+		}
+		return nil, fmt.Errorf("failed to grant draw results: %w", err)
+	}
+
+	if err := s.repo.SavePity(pity); err != nil {
+		s.logger.Error("Failed to save pity state",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.Int("banner_id", bannerID))
+	}
+
+	record := &entity.DrawRecord{
+		UserID:   userID,
+		BannerID: bannerID,
+		Pulls:    count,
+		Cost:     cost,
+		Results:  results,
+	}
+	if err := s.repo.CreateDraw(record); err != nil {
+		s.logger.Error("Failed to record gacha draw",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.Int("banner_id", bannerID))
+	}
+
+	s.logger.Info("Gacha draw completed",
+		zap.Int("user_id", userID),
+		zap.Int("banner_id", bannerID),
+		zap.Int("pulls", count),
+		zap.Int("pity_count", pity.Count))
+
+	return &DrawResponse{
+		DrawID:   record.ID,
+		BannerID: bannerID,
+		Pulls:    count,
+		Cost:     cost,
+		Results:  results,
+		Granted:  grant.Granted,
+		Overflow: grant.Overflow,
+		Pity:     *pity,
+	}, nil
+}
+
+// pull performs a single roll and advances the pity state in place
+func (s *service) pull(banner *entity.Banner, pity *entity.PityState) entity.DrawResult {
+	guaranteed := banner.PityThreshold > 0 && pity.Count+1 >= banner.PityThreshold
+
+	rates := banner.RarityRates
+	if guaranteed {
+		rates = make([]entity.RarityRate, 0, len(banner.RarityRates))
+		for _, rate := range banner.RarityRates {
+			if banner.IsPityRarity(rate.Rarity) {
+				rates = append(rates, rate)
+			}
+		}
+	}
+	rarity := s.rollRarity(rates)
+
+	rateUp, regular := banner.ItemsByRarity(rarity)
+	group := regular
+	if len(rateUp) > 0 && (len(regular) == 0 || s.rng.Float64() < banner.RateUpShare) {
+		group = rateUp
+	}
+	picked := s.pickWeighted(group)
+
+	pity.TotalPulls++
+	if banner.IsPityRarity(rarity) {
+		pity.Count = 0
+	} else {
+		pity.Count++
+	}
+
+	return entity.DrawResult{
+		ItemID: picked.ItemID,
+		Count:  picked.Count,
+		Rarity: rarity,
+		RateUp: picked.RateUp,
+		Pity:   guaranteed,
+	}
+}
+
+func (s *service) rollRarity(rates []entity.RarityRate) string {
+	total := 0.0
+	for _, rate := range rates {
+		total += rate.Rate
+	}
+
+	roll := s.rng.Float64() * total
+	for _, rate := range rates {
+		if roll < rate.Rate {
+			return rate.Rarity
+		}
+		roll -= rate.Rate
+	}
+	return rates[len(rates)-1].Rarity
+}
+
+func (s *service) pickWeighted(items []entity.BannerItem) entity.BannerItem {
+	total := 0
+	for _, item := range items {
+		total += item.Weight
+	}
+
+	roll := s.rng.Intn(total)
+	for _, item := range items {
+		if roll < item.Weight {
+			return item
+		}
+		roll -= item.Weight
+	}
+	return items[len(items)-1]
+}
+
+func (s *service) GetPity(userID, bannerID int) (*entity.PityState, error) {
+	if _, err := s.GetBanner(bannerID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPity(userID, bannerID)
+}
+
+func (s *service) GetBanner(id int) (*entity.Banner, error) {
+	banner, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrBannerNotFound) {
+			return nil, ErrBannerNotFound
+		}
+		return nil, fmt.Errorf("failed to get banner: %w", err)
+	}
+	return banner, nil
+}
+
+func (s *service) ListBanners(includeInactive bool) ([]*entity.Banner, error) {
+	banners, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list banners: %w", err)
+	}
+	if includeInactive {
+		return banners, nil
+	}
+
+	now := time.Now()
+	available := make([]*entity.Banner, 0, len(banners))
+	for _, banner := range banners {
+		if banner.IsAvailable(now) {
+			available = append(available, banner)
+		}
+	}
+	return available, nil
+}
+
+func (s *service) GetRates(bannerID int, locale i18n.Locale) (*RatesResponse, error) {
+	banner, err := s.GetBanner(bannerID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]ItemRate, 0, len(banner.Items))
+	for _, rate := range banner.RarityRates {
+		rateUp, regular := banner.ItemsByRarity(rate.Rarity)
+
+		rateUpShare := banner.RateUpShare
+		if len(regular) == 0 {
+			rateUpShare = 1
+		} else if len(rateUp) == 0 {
+			rateUpShare = 0
+		}
+
+		items = append(items, s.itemRates(rateUp, rate.Rate*rateUpShare, locale)...)
+		items = append(items, s.itemRates(regular, rate.Rate*(1-rateUpShare), locale)...)
+	}
+
+	return &RatesResponse{
+		BannerID:      banner.ID,
+		Name:          banner.Name,
+		RarityRates:   banner.RarityRates,
+		Items:         items,
+		RateUpShare:   banner.RateUpShare,
+		PityThreshold: banner.PityThreshold,
+		PityRarity:    banner.PityRarity,
+	}, nil
+}
+
+// itemRates splits groupRate across items by weight, naming them in the locale
+func (s *service) itemRates(items []entity.BannerItem, groupRate float64, locale i18n.Locale) []ItemRate {
+	total := 0
+	for _, item := range items {
+		total += item.Weight
+	}
+
+	rates := make([]ItemRate, 0, len(items))
+	for _, bannerItem := range items {
+		name := ""
+		if template, err := s.itemService.GetItem(bannerItem.ItemID); err == nil {
+			name = template.LocalizedName(locale)
+		}
+		rates = append(rates, ItemRate{
+			ItemID: bannerItem.ItemID,
+			Name:   name,
+			Count:  bannerItem.Count,
+			Rarity: bannerItem.Rarity,
+			RateUp: bannerItem.RateUp,
+			Rate:   groupRate * float64(bannerItem.Weight) / float64(total),
+		})
+	}
+	return rates
+}
+
+func (s *service) CreateBanner(req CreateBannerRequest) (*entity.Banner, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	banner := &entity.Banner{
+		Name:          req.Name,
+		Description:   req.Description,
+		CostItemID:    req.CostItemID,
+		CostAmount:    req.CostAmount,
+		RarityRates:   req.RarityRates,
+		Items:         req.Items,
+		RateUpShare:   req.RateUpShare,
+		PityThreshold: req.PityThreshold,
+		PityRarity:    req.PityRarity,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+		IsActive:      isActive,
+	}
+
+	if err := s.validateBanner(banner); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(banner); err != nil {
+		s.logger.Error("Failed to create banner", zap.Error(err), zap.String("name", req.Name))
+		return nil, fmt.Errorf("failed to create banner: %w", err)
+	}
+
+	s.logger.Info("Banner created",
+		zap.Int("banner_id", banner.ID),
+		zap.String("name", banner.Name))
+
+	return banner, nil
+}
+
+func (s *service) UpdateBanner(id int, req UpdateBannerRequest) (*entity.Banner, error) {
+	existing, err := s.GetBanner(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Name != "" {
+		updated.Name = req.Name
+	}
+	if req.Description != "" {
+		updated.Description = req.Description
+	}
+	if req.CostItemID != 0 {
+		updated.CostItemID = req.CostItemID
+	}
+	if req.CostAmount != 0 {
+		updated.CostAmount = req.CostAmount
+	}
+	if req.RarityRates != nil {
+		updated.RarityRates = req.RarityRates
+	}
+	if req.Items != nil {
+		updated.Items = req.Items
+	} else {
+		updated.Items = append([]entity.BannerItem(nil), existing.Items...)
+	}
+	if req.RateUpShare != nil {
+		updated.RateUpShare = *req.RateUpShare
+	}
+	if req.PityThreshold != nil {
+		updated.PityThreshold = *req.PityThreshold
+	}
+	if req.PityRarity != "" {
+		updated.PityRarity = req.PityRarity
+	}
+	if req.StartsAt != nil {
+		updated.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updated.EndsAt = req.EndsAt
+	}
+	if req.IsActive != nil {
+		updated.IsActive = *req.IsActive
+	}
+
+	if err := s.validateBanner(&updated); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		if errors.Is(err, repository.ErrBannerNotFound) {
+			return nil, ErrBannerNotFound
+		}
+		s.logger.Error("Failed to update banner", zap.Error(err), zap.Int("banner_id", id))
+		return nil, fmt.Errorf("failed to update banner: %w", err)
+	}
+
+	s.logger.Info("Banner updated", zap.Int("banner_id", id))
+	return &updated, nil
+}
+
+func (s *service) DeleteBanner(id int) error {
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, repository.ErrBannerNotFound) {
+			return ErrBannerNotFound
+		}
+		s.logger.Error("Failed to delete banner", zap.Error(err), zap.Int("banner_id", id))
+		return fmt.Errorf("failed to delete banner: %w", err)
+	}
+
+	s.logger.Info("Banner deleted", zap.Int("banner_id", id))
+	return nil
+}
+
+func (s *service) ListDraws(query DrawQuery) ([]*entity.DrawRecord, error) {
+	return s.repo.ListDraws(repository.DrawFilter{
+		UserID:   query.UserID,
+		BannerID: query.BannerID,
+		Limit:    query.Limit,
+	})
+}
+
+// validateBanner checks the banner and fills in item defaults and rarity snapshots
+func (s *service) validateBanner(banner *entity.Banner) error {
+	costItem, err := s.itemService.GetItem(banner.CostItemID)
+	if err != nil {
+		return fmt.Errorf("%w: cost item %d not found", ErrInvalidBanner, banner.CostItemID)
+	}
+	if costItem.Type != itemEntity.ItemTypeTicket && costItem.Type != itemEntity.ItemTypeCurrency {
+		return fmt.Errorf("%w: cost item %d must be a ticket or currency", ErrInvalidBanner, banner.CostItemID)
+	}
+	if banner.CostAmount <= 0 {
+		return fmt.Errorf("%w: cost amount must be greater than 0", ErrInvalidBanner)
+	}
+
+	rates := make(map[string]bool)
+	total := 0.0
+	for _, rate := range banner.RarityRates {
+		if !itemEntity.IsValidRarity(rate.Rarity) {
+			return fmt.Errorf("%w: invalid rarity %q", ErrInvalidBanner, rate.Rarity)
+		}
+		if rates[rate.Rarity] {
+			return fmt.Errorf("%w: duplicate rarity %q", ErrInvalidBanner, rate.Rarity)
+		}
+		if rate.Rate <= 0 {
+			return fmt.Errorf("%w: rate for %q must be greater than 0", ErrInvalidBanner, rate.Rarity)
+		}
+		rates[rate.Rarity] = true
+		total += rate.Rate
+	}
+	if math.Abs(total-1) > rateTolerance {
+		return fmt.Errorf("%w: rarity rates must sum to 1 (got %g)", ErrInvalidBanner, total)
+	}
+
+	seen := make(map[int]bool)
+	stocked := make(map[string]bool)
+	hasRateUp := false
+	for i := range banner.Items {
+		bannerItem := &banner.Items[i]
+		if seen[bannerItem.ItemID] {
+			return fmt.Errorf("%w: duplicate item %d", ErrInvalidBanner, bannerItem.ItemID)
+		}
+		seen[bannerItem.ItemID] = true
+
+		template, err := s.itemService.GetItem(bannerItem.ItemID)
+		if err != nil {
+			return fmt.Errorf("%w: item %d not found", ErrInvalidBanner, bannerItem.ItemID)
+		}
+		if template.IsArchived() {
+			return fmt.Errorf("%w: item %d is archived", ErrInvalidBanner, bannerItem.ItemID)
+		}
+		if !rates[template.Rarity] {
+			return fmt.Errorf("%w: item %d has rarity %q which has no rate", ErrInvalidBanner, bannerItem.ItemID, template.Rarity)
+		}
+
+		if bannerItem.Count == 0 {
+			bannerItem.Count = 1
+		}
+		if bannerItem.Weight == 0 {
+			bannerItem.Weight = 1
+		}
+		if bannerItem.Count < 0 || bannerItem.Weight < 0 {
+			return fmt.Errorf("%w: invalid count or weight for item %d", ErrInvalidBanner, bannerItem.ItemID)
+		}
+		bannerItem.Rarity = template.Rarity
+		stocked[template.Rarity] = true
+		hasRateUp = hasRateUp || bannerItem.RateUp
+	}
+
+	for rarity := range rates {
+		if !stocked[rarity] {
+			return fmt.Errorf("%w: rarity %q has no items", ErrInvalidBanner, rarity)
+		}
+	}
+
+	if banner.RateUpShare < 0 || banner.RateUpShare > 1 {
+		return fmt.Errorf("%w: rate_up_share must be between 0 and 1", ErrInvalidBanner)
+	}
+	if hasRateUp && banner.RateUpShare == 0 {
+		return fmt.Errorf("%w: rate_up_share is required when rate-up items are set", ErrInvalidBanner)
+	}
+
+	if banner.PityThreshold < 0 {
+		return fmt.Errorf("%w: pity_threshold must not be negative", ErrInvalidBanner)
+	}
+	if banner.PityThreshold > 0 {
+		if !itemEntity.IsValidRarity(banner.PityRarity) {
+			return fmt.Errorf("%w: pity_rarity is required when pity_threshold is set", ErrInvalidBanner)
+		}
+		reachable := false
+		for _, rate := range banner.RarityRates {
+			if banner.IsPityRarity(rate.Rarity) {
+				reachable = true
+			}
+		}
+		if !reachable {
+			return fmt.Errorf("%w: no rarity satisfies pity_rarity %q", ErrInvalidBanner, banner.PityRarity)
+		}
+	} else {
+		banner.PityRarity = ""
+	}
+
+	if banner.StartsAt != nil && banner.EndsAt != nil && !banner.EndsAt.After(*banner.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidBanner)
+	}
+
+	return nil
+}
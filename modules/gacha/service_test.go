@@ -0,0 +1,224 @@
+package gacha
+
+import (
+	"testing"
+
+	"fxserver/modules/gacha/entity"
+	"fxserver/modules/gacha/repository"
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	"fxserver/modules/reward/rewardtest"
+	"fxserver/pkg/i18n"
+	"fxserver/pkg/random"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	diamondID = 2 // epic
+	potionID  = 3 // common
+	ticketID  = 5
+)
+
+// fixedSource always returns the same roll
+type fixedSource struct {
+	roll float64
+}
+
+func (f fixedSource) Float64() float64 { return f.roll }
+func (f fixedSource) Intn(n int) int   { return 0 }
+
+type fixture struct {
+	svc     Service
+	items   *itemtest.Fixture
+	rareIDs []int
+}
+
+func setupGachaService(t *testing.T, rng random.Source) fixture {
+	items := itemtest.New()
+
+	var rareIDs []int
+	for _, name := range []string{"Rare Ore", "Rare Gem"} {
+		rare, err := items.Service.CreateItem(item.CreateItemRequest{
+			Name:        name,
+			Description: "Rare crafting material",
+			Type:        itemEntity.ItemTypeMaterial,
+			Value:       1,
+			Rarity:      "rare",
+		})
+		require.NoError(t, err)
+		rareIDs = append(rareIDs, rare.ID)
+	}
+
+	svc := NewService(ServiceParam{
+		Repository:    repository.NewMemoryRepository(),
+		ItemService:   items.Service,
+		RewardService: rewardtest.New(items).Service,
+		Random:        rng,
+		Logger:        zap.NewNop(),
+	})
+	return fixture{svc: svc, items: items, rareIDs: rareIDs}
+}
+
+// standardBanner: common 70% / rare 25% / epic 5%, epic guaranteed on the 10th pull
+func (f fixture) standardBanner(t *testing.T, pityThreshold int) *entity.Banner {
+	banner, err := f.svc.CreateBanner(CreateBannerRequest{
+		Name:       "Standard Banner",
+		CostItemID: ticketID,
+		CostAmount: 1,
+		RarityRates: []entity.RarityRate{
+			{Rarity: "common", Rate: 0.70},
+			{Rarity: "rare", Rate: 0.25},
+			{Rarity: "epic", Rate: 0.05},
+		},
+		Items: []entity.BannerItem{
+			{ItemID: potionID},
+			{ItemID: f.rareIDs[0], RateUp: true},
+			{ItemID: f.rareIDs[1]},
+			{ItemID: diamondID, Count: 10},
+		},
+		RateUpShare:   0.5,
+		PityThreshold: pityThreshold,
+		PityRarity:    "epic",
+	})
+	require.NoError(t, err)
+	return banner
+}
+
+func TestDrawDistributionMatchesPublishedRates(t *testing.T) {
+	f := setupGachaService(t, random.New(42))
+	banner := f.standardBanner(t, 0)
+	svc := f.svc.(*service)
+
+	const pulls = 100000
+	pity := &entity.PityState{}
+	rarities := make(map[string]int)
+	items := make(map[int]int)
+	for i := 0; i < pulls; i++ {
+		result := svc.pull(banner, pity)
+		rarities[result.Rarity]++
+		items[result.ItemID]++
+	}
+
+	for _, rate := range banner.RarityRates {
+		assert.InDelta(t, rate.Rate, float64(rarities[rate.Rarity])/pulls, 0.01, rate.Rarity)
+	}
+
+	rates, err := f.svc.GetRates(banner.ID, i18n.FallbackLocale)
+	require.NoError(t, err)
+	for _, itemRate := range rates.Items {
+		assert.InDelta(t, itemRate.Rate, float64(items[itemRate.ItemID])/pulls, 0.01, "item %d", itemRate.ItemID)
+	}
+}
+
+func TestGetRatesSplitsRateUp(t *testing.T) {
+	f := setupGachaService(t, random.New(1))
+	banner := f.standardBanner(t, 10)
+
+	rates, err := f.svc.GetRates(banner.ID, i18n.English)
+	require.NoError(t, err)
+	assert.Equal(t, 10, rates.PityThreshold)
+
+	total := 0.0
+	byItem := make(map[int]float64)
+	for _, itemRate := range rates.Items {
+		total += itemRate.Rate
+		byItem[itemRate.ItemID] = itemRate.Rate
+	}
+	assert.InDelta(t, 1.0, total, 1e-9)
+	assert.InDelta(t, 0.125, byItem[f.rareIDs[0]], 1e-9)
+	assert.InDelta(t, 0.125, byItem[f.rareIDs[1]], 1e-9)
+	assert.InDelta(t, 0.05, byItem[diamondID], 1e-9)
+
+	// Item names follow the request language
+	for _, itemRate := range rates.Items {
+		if itemRate.ItemID == diamondID {
+			assert.Equal(t, "Diamond", itemRate.Name)
+		}
+	}
+}
+
+func TestDrawPityGuarantee(t *testing.T) {
+	// A roll of 0 always lands on the first (common) rarity unless pity forces otherwise
+	f := setupGachaService(t, fixedSource{roll: 0})
+	banner := f.standardBanner(t, 10)
+	require.NoError(t, f.items.Service.AddToInventory(1, ticketID, 10, "admin", itemEntity.TransactionRef{}))
+
+	response, err := f.svc.Draw(1, banner.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, response.Results, 10)
+	for _, result := range response.Results[:9] {
+		assert.Equal(t, "common", result.Rarity)
+		assert.False(t, result.Pity)
+	}
+	assert.Equal(t, "epic", response.Results[9].Rarity)
+	assert.True(t, response.Results[9].Pity)
+	assert.Equal(t, 0, response.Pity.Count)
+	assert.Equal(t, 10, response.Pity.TotalPulls)
+}
+
+func TestDrawConsumesCostAndGrantsResults(t *testing.T) {
+	f := setupGachaService(t, fixedSource{roll: 0})
+	banner := f.standardBanner(t, 10)
+	require.NoError(t, f.items.Service.AddToInventory(1, ticketID, 10, "admin", itemEntity.TransactionRef{}))
+
+	response, err := f.svc.Draw(1, banner.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, itemEntity.RewardItem{ItemID: ticketID, Count: 10}, response.Cost)
+	assert.Equal(t, 0, f.items.Balance(1, ticketID))
+	assert.Equal(t, 9, f.items.Balance(1, potionID))
+	assert.Equal(t, 10, f.items.Balance(1, diamondID))
+
+	transactions, err := f.items.Service.GetInventoryTransactions(1, item.InventoryTransactionQuery{Source: SourceGacha})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 3)
+}
+
+func TestDrawInsufficientCost(t *testing.T) {
+	f := setupGachaService(t, fixedSource{roll: 0})
+	banner := f.standardBanner(t, 10)
+	require.NoError(t, f.items.Service.AddToInventory(1, ticketID, 5, "admin", itemEntity.TransactionRef{}))
+
+	_, err := f.svc.Draw(1, banner.ID, 10)
+	assert.ErrorIs(t, err, item.ErrInsufficientItem)
+	assert.Equal(t, 5, f.items.Balance(1, ticketID))
+	assert.Equal(t, 0, f.items.Balance(1, potionID))
+
+	pity, err := f.svc.GetPity(1, banner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pity.TotalPulls)
+}
+
+func TestCreateBannerValidation(t *testing.T) {
+	f := setupGachaService(t, random.New(1))
+
+	_, err := f.svc.CreateBanner(CreateBannerRequest{
+		Name:        "Broken Rates",
+		CostItemID:  ticketID,
+		CostAmount:  1,
+		RarityRates: []entity.RarityRate{{Rarity: "common", Rate: 0.5}},
+		Items:       []entity.BannerItem{{ItemID: potionID}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidBanner)
+
+	_, err = f.svc.CreateBanner(CreateBannerRequest{
+		Name:        "Potion Cost",
+		CostItemID:  potionID,
+		CostAmount:  1,
+		RarityRates: []entity.RarityRate{{Rarity: "common", Rate: 1}},
+		Items:       []entity.BannerItem{{ItemID: potionID}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidBanner)
+
+	_, err = f.svc.CreateBanner(CreateBannerRequest{
+		Name:        "Missing Rarity",
+		CostItemID:  ticketID,
+		CostAmount:  1,
+		RarityRates: []entity.RarityRate{{Rarity: "common", Rate: 1}},
+		Items:       []entity.BannerItem{{ItemID: potionID}, {ItemID: diamondID}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidBanner)
+}
@@ -1,6 +1,9 @@
 package item
 
-import "fxserver/modules/item/entity"
+import (
+	"fxserver/modules/item/entity"
+	"fxserver/pkg/i18n"
+)
 
 // Item management DTOs (Admin only)
 type CreateItemRequest struct {
@@ -10,6 +13,11 @@ type CreateItemRequest struct {
 	Value       int              `json:"value" validate:"required,gt=0"`
 	Rarity      string           `json:"rarity" validate:"required,oneof=common rare epic legendary"`
 	IconURL     string           `json:"icon_url" validate:"omitempty,url"`
+	Bundle      *entity.BundleContents `json:"bundle,omitempty"` // bundle 타입인 경우 필수
+	Translations map[i18n.Locale]entity.ItemTranslation `json:"translations,omitempty"` // 언어별 이름/설명 (ko, en, ja)
+	LifetimeSeconds int            `json:"lifetime_seconds,omitempty" validate:"omitempty,gte=0"` // 기본 수명 (0: 무기한)
+	MaxStack    int              `json:"max_stack,omitempty" validate:"omitempty,gte=0"` // 최대 보유 수량 (0: 제한 없음)
+	Tradable    bool             `json:"tradable,omitempty"` // 선물/거래 가능 여부 (기본값: false)
 }
 
 type UpdateItemRequest struct {
@@ -19,13 +27,87 @@ type UpdateItemRequest struct {
 	Value       int              `json:"value,omitempty" validate:"omitempty,gt=0"`
 	Rarity      string           `json:"rarity,omitempty" validate:"omitempty,oneof=common rare epic legendary"`
 	IconURL     string           `json:"icon_url,omitempty" validate:"omitempty,url"`
+	Bundle      *entity.BundleContents `json:"bundle,omitempty"`
+	Translations map[i18n.Locale]entity.ItemTranslation `json:"translations,omitempty"` // 지정 시 전체 교체, 빈 객체로 전체 삭제
+	LifetimeSeconds *int           `json:"lifetime_seconds,omitempty" validate:"omitempty,gte=0"` // 0으로 설정 시 무기한
+	MaxStack    *int             `json:"max_stack,omitempty" validate:"omitempty,gte=0"` // 0으로 설정 시 제한 없음
+	Tradable    *bool            `json:"tradable,omitempty"`
 }
 
+// Item catalog query DTO
+// type and rarity accept repeated or comma-separated values (type=card,ticket).
+type ListItemsQuery struct {
+	Q               string   `query:"q" validate:"omitempty,max=100"` // 이름 부분 검색 (모든 언어, 대소문자 무시)
+	Types           []string `query:"type"`
+	Rarities        []string `query:"rarity"`
+	IsActive        *bool    `query:"is_active"`        // 관리자 전용
+	IncludeArchived bool     `query:"include_archived"` // 관리자 전용, 보관된 아이템 포함 여부
+	Sort            string   `query:"sort" validate:"omitempty,oneof=id name rarity created_at"` // 기본값: id (name: 요청 언어의 이름 순)
+	Order           string   `query:"order" validate:"omitempty,oneof=asc desc"`                 // 기본값: asc
+	Cursor          string   `query:"cursor"`                                                    // 이전 페이지의 next_cursor
+	Limit           int      `query:"limit" validate:"omitempty,gt=0,lte=200"`                   // 기본값: 50
+}
+
+// DefaultItemPageSize is used when a catalog query has no limit
+const DefaultItemPageSize = 50
+
 // Inventory DTOs
 type GetInventoryRequest struct {
 	UserID int `json:"user_id" validate:"required,gt=0"`
 }
 
+// Item use DTOs
+type UseItemRequest struct {
+	Count int `json:"count" validate:"omitempty,gt=0,lte=999"` // 기본값: 1
+}
+
+type UseItemResponse struct {
+	ItemID    int          `json:"item_id"`
+	Count     int          `json:"count"`
+	Remaining int          `json:"remaining"`
+	Result    EffectResult `json:"result"`
+}
+
+// Bundle open DTOs
+type OpenBundleRequest struct {
+	Count int `json:"count" validate:"omitempty,gt=0,lte=100"` // 기본값: 1
+}
+
+type OpenBundleResponse struct {
+	ItemID    int                 `json:"item_id"`
+	Count     int                 `json:"count"`
+	Remaining int                 `json:"remaining"`
+	Contents  []entity.RewardItem `json:"contents"`
+}
+
+// Inventory capacity DTOs
+type SetInventoryCapacityRequest struct {
+	Capacity int `json:"capacity" validate:"gte=0,lte=10000"`
+}
+
+type InventoryCapacityResponse struct {
+	UserID   int `json:"user_id"`
+	Capacity int `json:"capacity"` // 전체 슬롯 수
+	Used     int `json:"used"`     // 사용 중인 슬롯 수 (재화 제외)
+	Free     int `json:"free"`
+}
+
+// Item instance DTOs
+type LockInstanceRequest struct {
+	Locked bool `json:"locked"`
+}
+
+// Inventory ledger query DTO (Admin only)
+type InventoryTransactionQuery struct {
+	ItemID        int    `query:"item_id" validate:"omitempty,gt=0"`
+	Source        string `query:"source"`
+	ReferenceType string `query:"reference_type"`
+	ReferenceID   string `query:"reference_id"`
+	StartDate     string `query:"start_date"` // YYYY-MM-DD format
+	EndDate       string `query:"end_date"`   // YYYY-MM-DD format (inclusive)
+	Limit         int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
 // Response DTOs
 type ListItemsResponse struct {
 	Items []entity.ItemResponse `json:"items"`
@@ -43,37 +125,24 @@ type ItemTypeInfo struct {
 }
 
 // Helper function to get all item types with descriptions
-func GetItemTypes() []ItemTypeInfo {
-	return []ItemTypeInfo{
-		{
-			Type:        entity.ItemTypeCurrency,
-			Name:        "화폐",
-			Description: entity.ItemTypeCurrency.GetValueDescription(),
-		},
-		{
-			Type:        entity.ItemTypeEquipment,
-			Name:        "장비",
-			Description: entity.ItemTypeEquipment.GetValueDescription(),
-		},
-		{
-			Type:        entity.ItemTypeConsumable,
-			Name:        "소모품",
-			Description: entity.ItemTypeConsumable.GetValueDescription(),
-		},
-		{
-			Type:        entity.ItemTypeCard,
-			Name:        "카드",
-			Description: entity.ItemTypeCard.GetValueDescription(),
-		},
-		{
-			Type:        entity.ItemTypeMaterial,
-			Name:        "재료",
-			Description: entity.ItemTypeMaterial.GetValueDescription(),
-		},
-		{
-			Type:        entity.ItemTypeTicket,
-			Name:        "티켓",
-			Description: entity.ItemTypeTicket.GetValueDescription(),
-		},
+func GetItemTypes(locale i18n.Locale) []ItemTypeInfo {
+	types := []entity.ItemType{
+		entity.ItemTypeCurrency,
+		entity.ItemTypeEquipment,
+		entity.ItemTypeConsumable,
+		entity.ItemTypeCard,
+		entity.ItemTypeMaterial,
+		entity.ItemTypeTicket,
+		entity.ItemTypeBundle,
+	}
+
+	infos := make([]ItemTypeInfo, len(types))
+	for i, itemType := range types {
+		infos[i] = ItemTypeInfo{
+			Type:        itemType,
+			Name:        itemType.GetName(locale),
+			Description: itemType.GetValueDescription(locale),
+		}
 	}
+	return infos
 }
\ No newline at end of file
@@ -0,0 +1,135 @@
+package item
+
+import (
+	"fmt"
+	"sync"
+
+	"fxserver/modules/item/entity"
+	"fxserver/pkg/i18n"
+
+	"go.uber.org/fx"
+)
+
+// EffectKey identifies which items an ItemEffectHandler applies to.
+// A handler registered for a specific ItemID takes precedence over one registered for its ItemType.
+type EffectKey struct {
+	ItemType entity.ItemType
+	ItemID   int
+}
+
+// EffectResult describes what happened when an item was used
+type EffectResult struct {
+	Effect  string                 `json:"effect"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// ItemEffectHandler applies the in-game effect of using an item.
+// Handlers are contributed through the `group:"item_effects"` value group.
+// Apply writes EffectResult.Message in the player's locale.
+type ItemEffectHandler interface {
+	Key() EffectKey
+	Apply(userID int, item *entity.Item, count int, locale i18n.Locale) (*EffectResult, error)
+}
+
+// EffectRegistry resolves the effect handler for an item
+type EffectRegistry interface {
+	Register(handler ItemEffectHandler)
+	Resolve(item *entity.Item) (ItemEffectHandler, bool)
+}
+
+type effectRegistry struct {
+	byItemID map[int]ItemEffectHandler
+	byType   map[entity.ItemType]ItemEffectHandler
+	mu       sync.RWMutex
+}
+
+type EffectRegistryParam struct {
+	fx.In
+	Handlers []ItemEffectHandler `group:"item_effects"`
+}
+
+func NewEffectRegistry(p EffectRegistryParam) EffectRegistry {
+	registry := &effectRegistry{
+		byItemID: make(map[int]ItemEffectHandler),
+		byType:   make(map[entity.ItemType]ItemEffectHandler),
+	}
+	for _, handler := range p.Handlers {
+		registry.Register(handler)
+	}
+	return registry
+}
+
+func (r *effectRegistry) Register(handler ItemEffectHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := handler.Key()
+	if key.ItemID > 0 {
+		r.byItemID[key.ItemID] = handler
+		return
+	}
+	r.byType[key.ItemType] = handler
+}
+
+func (r *effectRegistry) Resolve(item *entity.Item) (ItemEffectHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if handler, exists := r.byItemID[item.ID]; exists {
+		return handler, true
+	}
+	handler, exists := r.byType[item.Type]
+	return handler, exists
+}
+
+// Default effect handlers
+// 소모와 메시지만 처리하는 플레이스홀더이며, 실제 효과는 게임별 핸들러로 교체합니다
+
+// Message formats of the default effects: count, then the item name
+var (
+	consumeMessage = i18n.Text{i18n.Korean: "%[2]s %[1]d개를 사용했습니다", i18n.English: "Used %d x %s", i18n.Japanese: "%[2]sを%[1]d個使用しました"}
+	ticketMessage  = i18n.Text{i18n.Korean: "%[2]s %[1]d개를 사용했습니다", i18n.English: "Redeemed %d x %s", i18n.Japanese: "%[2]sを%[1]d枚使用しました"}
+)
+
+// consumableEffect applies Value per unit (e.g. HP restored by a potion)
+type consumableEffect struct{}
+
+func NewConsumableEffect() ItemEffectHandler {
+	return &consumableEffect{}
+}
+
+func (e *consumableEffect) Key() EffectKey {
+	return EffectKey{ItemType: entity.ItemTypeConsumable}
+}
+
+func (e *consumableEffect) Apply(userID int, item *entity.Item, count int, locale i18n.Locale) (*EffectResult, error) {
+	return &EffectResult{
+		Effect:  "consume",
+		Message: fmt.Sprintf(consumeMessage.Get(locale), count, item.LocalizedName(locale)),
+		Details: map[string]interface{}{
+			"amount": item.Value * count,
+		},
+	}, nil
+}
+
+// ticketEffect grants one entry per ticket used
+type ticketEffect struct{}
+
+func NewTicketEffect() ItemEffectHandler {
+	return &ticketEffect{}
+}
+
+func (e *ticketEffect) Key() EffectKey {
+	return EffectKey{ItemType: entity.ItemTypeTicket}
+}
+
+func (e *ticketEffect) Apply(userID int, item *entity.Item, count int, locale i18n.Locale) (*EffectResult, error) {
+	return &EffectResult{
+		Effect:  "ticket",
+		Message: fmt.Sprintf(ticketMessage.Get(locale), count, item.LocalizedName(locale)),
+		Details: map[string]interface{}{
+			"entries": count,
+		},
+	}, nil
+}
@@ -0,0 +1,175 @@
+package item
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fxserver/modules/item/entity"
+	"fxserver/modules/item/repository"
+	"fxserver/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	effectPotionID = 3 // 체력 포션 (consumable)
+	effectSwordID  = 4 // 전설의 검 (equipment)
+	effectTicketID = 5 // 던전 입장권 (ticket)
+)
+
+// stubEffect records how many units it was applied to and fails when err is set
+type stubEffect struct {
+	key     EffectKey
+	name    string
+	err     error
+	applied int
+}
+
+func (e *stubEffect) Key() EffectKey {
+	return e.key
+}
+
+func (e *stubEffect) Apply(userID int, item *entity.Item, count int, locale i18n.Locale) (*EffectResult, error) {
+	e.applied += count
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &EffectResult{Effect: e.name}, nil
+}
+
+func setupEffectService(t *testing.T, handlers ...ItemEffectHandler) (Service, repository.Repository) {
+	items := repository.NewMemoryRepository()
+	svc := NewService(ServiceParam{
+		Repository: items,
+		Effects:    NewEffectRegistry(EffectRegistryParam{Handlers: handlers}),
+		Logger:     zap.NewNop(),
+	})
+	return svc, items
+}
+
+func TestEffectRegistryPrefersItemHandler(t *testing.T) {
+	byType := &stubEffect{key: EffectKey{ItemType: entity.ItemTypeConsumable}, name: "type"}
+	byID := &stubEffect{key: EffectKey{ItemID: effectPotionID}, name: "item"}
+	// Registration order does not matter
+	registry := NewEffectRegistry(EffectRegistryParam{Handlers: []ItemEffectHandler{byID, byType}})
+
+	handler, ok := registry.Resolve(&entity.Item{ID: effectPotionID, Type: entity.ItemTypeConsumable})
+	require.True(t, ok)
+	assert.Same(t, byID, handler)
+
+	handler, ok = registry.Resolve(&entity.Item{ID: 99, Type: entity.ItemTypeConsumable})
+	require.True(t, ok)
+	assert.Same(t, byType, handler)
+
+	_, ok = registry.Resolve(&entity.Item{ID: effectTicketID, Type: entity.ItemTypeTicket})
+	assert.False(t, ok)
+}
+
+func TestUseItem(t *testing.T) {
+	svc, items := setupEffectService(t, NewConsumableEffect(), NewTicketEffect())
+	require.NoError(t, items.AddToInventory(1, effectPotionID, 5, "admin", entity.TransactionRef{}))
+	require.NoError(t, items.AddToInventory(1, effectSwordID, 1, "admin", entity.TransactionRef{}))
+
+	response, err := svc.UseItem(1, effectPotionID, 2, i18n.English)
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.Remaining)
+	assert.Equal(t, "consume", response.Result.Effect)
+	assert.Equal(t, "Used 2 x Health Potion", response.Result.Message)
+
+	// Equipment is not usable even when held
+	_, err = svc.UseItem(1, effectSwordID, 1, i18n.English)
+	assert.ErrorIs(t, err, ErrItemNotUsable)
+
+	// Nothing is consumed when the user holds too few
+	_, err = svc.UseItem(1, effectPotionID, 4, i18n.English)
+	assert.ErrorIs(t, err, ErrInsufficientItem)
+	_, err = svc.UseItem(1, effectTicketID, 1, i18n.English)
+	assert.ErrorIs(t, err, ErrInsufficientItem)
+	potion, err := items.GetUserInventoryItem(1, effectPotionID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, potion.Count)
+}
+
+func TestUseItemWithoutHandler(t *testing.T) {
+	svc, items := setupEffectService(t, NewTicketEffect())
+	require.NoError(t, items.AddToInventory(1, effectPotionID, 1, "admin", entity.TransactionRef{}))
+
+	_, err := svc.UseItem(1, effectPotionID, 1, i18n.English)
+	assert.ErrorIs(t, err, ErrNoEffectHandler)
+	potion, err := items.GetUserInventoryItem(1, effectPotionID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, potion.Count)
+}
+
+func TestUseItemRestoresItemsWhenEffectFails(t *testing.T) {
+	failing := &stubEffect{key: EffectKey{ItemID: effectPotionID}, err: errors.New("effect server unavailable")}
+	svc, items := setupEffectService(t, NewConsumableEffect(), failing)
+	require.NoError(t, items.AddToInventory(1, effectPotionID, 5, "admin", entity.TransactionRef{}))
+
+	_, err := svc.UseItem(1, effectPotionID, 2, i18n.English)
+	require.Error(t, err)
+	assert.Equal(t, 2, failing.applied, "the item-specific handler runs instead of the type default")
+
+	potion, err := items.GetUserInventoryItem(1, effectPotionID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, potion.Count)
+
+	// The items were consumed before the effect ran and restored afterwards
+	ledger, err := svc.GetInventoryTransactions(1, InventoryTransactionQuery{ItemID: effectPotionID})
+	require.NoError(t, err)
+	sources := make(map[string]int)
+	for _, transaction := range ledger {
+		sources[transaction.Source] += transaction.Delta
+	}
+	assert.Equal(t, map[string]int{"admin": 5, SourceItemUse: -2, SourceItemUseRollback: 2}, sources)
+}
+
+func TestUseItemRestoresExactStacks(t *testing.T) {
+	items := repository.NewMemoryRepository()
+	soon := time.Now().Add(time.Hour).Truncate(time.Second)
+	later := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	_, err := items.AddMultipleToInventory(1, []entity.RewardItem{
+		{ItemID: effectPotionID, Count: 1, ExpiresAt: &soon},
+		{ItemID: effectPotionID, Count: 2, ExpiresAt: &later},
+	}, entity.OverflowReject, "admin", entity.TransactionRef{})
+	require.NoError(t, err)
+	require.NoError(t, items.SetSlotCapacity(1, 1))
+
+	// Using every potion frees the only slot; a grant lands in it before the effect fails
+	failing := &stubEffect{key: EffectKey{ItemID: effectPotionID}, err: errors.New("effect server unavailable")}
+	registry := NewEffectRegistry(EffectRegistryParam{Handlers: []ItemEffectHandler{&grantingEffect{stubEffect: failing, items: items}}})
+	svc := NewService(ServiceParam{Repository: items, Effects: registry, Logger: zap.NewNop()})
+
+	_, err = svc.UseItem(1, effectPotionID, 3, i18n.English)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInventoryFull)
+
+	// The potions come back in their original stacks even though the inventory is now full
+	inventory, err := items.GetUserInventory(1)
+	require.NoError(t, err)
+	stacks := make(map[time.Time]int)
+	for _, stack := range inventory {
+		if stack.ItemID == effectPotionID {
+			require.NotNil(t, stack.ExpiresAt)
+			stacks[*stack.ExpiresAt] += stack.Count
+		}
+	}
+	assert.Equal(t, map[time.Time]int{soon: 1, later: 2}, stacks)
+}
+
+// grantingEffect grants a ticket before failing, standing in for a grant that takes the slot
+// freed by the consumed items while the effect runs
+type grantingEffect struct {
+	*stubEffect
+	items repository.Repository
+}
+
+func (e *grantingEffect) Apply(userID int, item *entity.Item, count int, locale i18n.Locale) (*EffectResult, error) {
+	if err := e.items.AddToInventory(userID, effectTicketID, 1, "admin", entity.TransactionRef{}); err != nil {
+		return nil, err
+	}
+	return e.stubEffect.Apply(userID, item, count, locale)
+}
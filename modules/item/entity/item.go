@@ -1,6 +1,11 @@
 package entity
 
-import "time"
+import (
+	"math"
+	"time"
+
+	"fxserver/pkg/i18n"
+)
 
 type ItemType string
 
@@ -11,6 +16,7 @@ const (
 	ItemTypeCard       ItemType = "card"       // 수집형 카드/캐릭터
 	ItemTypeMaterial   ItemType = "material"   // 제작/강화 재료
 	ItemTypeTicket     ItemType = "ticket"     // 이용권 (던전 입장권, 가챠 티켓)
+	ItemTypeBundle     ItemType = "bundle"     // 개봉형 상자/패키지 (스타터 상자)
 )
 
 type Item struct {
@@ -22,8 +28,49 @@ type Item struct {
 	Rarity      string   `json:"rarity"`      // common, rare, epic, legendary
 	IconURL     string   `json:"icon_url"`    // 아이템 아이콘 이미지 URL
 	IsActive    bool     `json:"is_active"`   // 활성화 여부
+	Bundle      *BundleContents `json:"bundle,omitempty"` // 상자 구성품 (bundle 타입 전용)
+	LifetimeSeconds int `json:"lifetime_seconds,omitempty"` // 지급 후 만료까지의 기본 수명 (0: 무기한)
+	MaxStack    int      `json:"max_stack,omitempty"` // 최대 보유 수량 (0: MaxStackLimit)
+	Tradable    bool     `json:"tradable"`            // 선물/거래 가능 여부
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // 보관(삭제) 시간, 보관된 아이템은 조회만 가능
+	Translations map[i18n.Locale]ItemTranslation `json:"translations,omitempty"` // 언어별 이름/설명 (Name/Description은 기본 언어)
+}
+
+// ItemTranslation is an item's name and description in one locale; empty fields fall back
+type ItemTranslation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// LocalizedName returns the item name in the locale, falling back to Name
+func (i *Item) LocalizedName(locale i18n.Locale) string {
+	if translation := i.Translations[locale]; translation.Name != "" {
+		return translation.Name
+	}
+	return i.Name
+}
+
+// LocalizedDescription returns the item description in the locale, falling back to Description
+func (i *Item) LocalizedDescription(locale i18n.Locale) string {
+	if translation := i.Translations[locale]; translation.Description != "" {
+		return translation.Description
+	}
+	return i.Description
+}
+
+// ItemPage is one page of a catalog query
+type ItemPage struct {
+	Items      []*Item
+	NextCursor string // empty on the last page
+	Total      int    // matches across all pages
+}
+
+// IsArchived reports whether the item was deleted. Archived items still resolve for
+// existing inventories and history but are not listed or newly granted.
+func (i *Item) IsArchived() bool {
+	return i.DeletedAt != nil
 }
 
 type UserInventory struct {
@@ -33,12 +80,89 @@ type UserInventory struct {
 	Count      int       `json:"count"`       // 보유 수량
 	AcquiredAt time.Time `json:"acquired_at"` // 최초 획득 시간
 	Source     string    `json:"source"`      // 획득 경로 (coupon, payment, reward, admin)
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // 만료 시간 (nil: 무기한), 만료 시간별로 별도 스택
 	UpdatedAt  time.Time `json:"updated_at"`  // 수량 변경 시간
 }
 
+// ItemInstance is a uniquely identifiable copy of an equipment or card item.
+// Unlike stackable inventory, each instance carries its own level and attributes.
+type ItemInstance struct {
+	ID         int            `json:"id"`
+	UserID     int            `json:"user_id"`
+	ItemID     int            `json:"item_id"`               // 템플릿 아이템 ID
+	Level      int            `json:"level"`                 // 강화 레벨 / 카드 레벨
+	Attributes map[string]int `json:"attributes,omitempty"` // 개별 옵션 (공격력 등)
+	Locked     bool           `json:"locked"`                // 잠금 시 분해/거래/차감 불가
+	Source     string         `json:"source"`                // 획득 경로
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"`  // 만료 시간 (nil: 무기한)
+	AcquiredAt time.Time      `json:"acquired_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// InstanceLevelChange describes an atomic level update of an ItemInstance.
+// FromLevel guards against concurrent modifications; Destroy removes the instance instead.
+type InstanceLevelChange struct {
+	UserID     int
+	InstanceID int
+	FromLevel  int
+	ToLevel    int
+	Destroy    bool
+}
+
 type RewardItem struct {
+	ItemID    int        `json:"item_id"`
+	Count     int        `json:"count"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 지급분 만료 시간 (미지정 시 아이템 기본 수명)
+}
+
+// BundleContents defines what opening one bundle item grants.
+// Items are always granted; LootTable is rolled Rolls times, picking one entry by weight each roll.
+type BundleContents struct {
+	Items     []RewardItem `json:"items,omitempty"`      // 고정 구성품
+	LootTable []LootEntry  `json:"loot_table,omitempty"` // 무작위 구성품
+	Rolls     int          `json:"rolls,omitempty"`      // 무작위 추첨 횟수 (기본값: 1)
+}
+
+type LootEntry struct {
 	ItemID int `json:"item_id"`
 	Count  int `json:"count"`
+	Weight int `json:"weight"`
+}
+
+// ItemIDs returns every item referenced by the bundle
+func (b *BundleContents) ItemIDs() []int {
+	ids := make([]int, 0, len(b.Items)+len(b.LootTable))
+	for _, item := range b.Items {
+		ids = append(ids, item.ItemID)
+	}
+	for _, entry := range b.LootTable {
+		ids = append(ids, entry.ItemID)
+	}
+	return ids
+}
+
+// MergeRewardItems sums counts of entries with the same item ID and expiry, keeping first-seen order
+func MergeRewardItems(items []RewardItem) []RewardItem {
+	type mergeKey struct {
+		itemID    int
+		expiresAt int64
+	}
+
+	merged := make([]RewardItem, 0, len(items))
+	index := make(map[mergeKey]int)
+	for _, item := range items {
+		key := mergeKey{itemID: item.ItemID}
+		if item.ExpiresAt != nil {
+			key.expiresAt = item.ExpiresAt.UnixNano()
+		}
+		if i, exists := index[key]; exists {
+			merged[i].Count += item.Count
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
 }
 
 // Item Response DTOs
@@ -50,6 +174,12 @@ type ItemResponse struct {
 	Value       int      `json:"value"`
 	Rarity      string   `json:"rarity"`
 	IconURL     string   `json:"icon_url"`
+	Bundle      *BundleContents `json:"bundle,omitempty"`
+	LifetimeSeconds int `json:"lifetime_seconds,omitempty"`
+	MaxStack    int      `json:"max_stack,omitempty"`
+	Tradable    bool     `json:"tradable"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Translations map[i18n.Locale]ItemTranslation `json:"translations,omitempty"` // 관리자 응답에만 포함
 }
 
 type InventoryResponse struct {
@@ -58,70 +188,184 @@ type InventoryResponse struct {
 	Count      int          `json:"count"`
 	AcquiredAt time.Time    `json:"acquired_at"`
 	Source     string       `json:"source"`
+	ExpiresAt  *time.Time   `json:"expires_at,omitempty"`
 	UpdatedAt  time.Time    `json:"updated_at"`
 }
 
+type InstanceResponse struct {
+	ID         int            `json:"id"`
+	Item       ItemResponse   `json:"item"`
+	Level      int            `json:"level"`
+	Attributes map[string]int `json:"attributes,omitempty"`
+	Locked     bool           `json:"locked"`
+	AcquiredAt time.Time      `json:"acquired_at"`
+	Source     string         `json:"source"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
 type UserInventoryResponse struct {
-	UserID int                 `json:"user_id"`
-	Items  []InventoryResponse `json:"items"`
-	Total  int                 `json:"total"`
+	UserID    int                 `json:"user_id"`
+	Items     []InventoryResponse `json:"items"`     // 스택형 아이템
+	Instances []InstanceResponse  `json:"instances"` // 개별 인스턴스 (장비, 카드)
+	Total     int                 `json:"total"`
 }
 
 // Helper methods
-func (i *Item) ToResponse() ItemResponse {
+func (i *Item) ToResponse(locale i18n.Locale) ItemResponse {
 	return ItemResponse{
 		ID:          i.ID,
-		Name:        i.Name,
-		Description: i.Description,
+		Name:        i.LocalizedName(locale),
+		Description: i.LocalizedDescription(locale),
 		Type:        i.Type,
 		Value:       i.Value,
 		Rarity:      i.Rarity,
 		IconURL:     i.IconURL,
+		Bundle:      i.Bundle,
+		LifetimeSeconds: i.LifetimeSeconds,
+		MaxStack:    i.MaxStack,
+		Tradable:    i.Tradable,
+		DeletedAt:   i.DeletedAt,
 	}
 }
 
-func (ui *UserInventory) ToResponse(item *Item) InventoryResponse {
+// MaxStackLimit caps how many units of any item a user can hold, keeping counts far from int overflow
+const MaxStackLimit = math.MaxInt32
+
+// StackLimit returns how many units of the item a user can hold
+func (i *Item) StackLimit() int {
+	if i.MaxStack <= 0 || i.MaxStack > MaxStackLimit {
+		return MaxStackLimit
+	}
+	return i.MaxStack
+}
+
+// DefaultExpiry returns when a unit granted at now expires, or nil if the item never expires
+func (i *Item) DefaultExpiry(now time.Time) *time.Time {
+	if i.LifetimeSeconds <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(time.Duration(i.LifetimeSeconds) * time.Second).Truncate(time.Second)
+	return &expiresAt
+}
+
+// IsExpired reports whether the stack has expired at now
+func (ui *UserInventory) IsExpired(now time.Time) bool {
+	return ui.ExpiresAt != nil && !now.Before(*ui.ExpiresAt)
+}
+
+// IsExpired reports whether the instance has expired at now
+func (ii *ItemInstance) IsExpired(now time.Time) bool {
+	return ii.ExpiresAt != nil && !now.Before(*ii.ExpiresAt)
+}
+
+func (ui *UserInventory) ToResponse(item *Item, locale i18n.Locale) InventoryResponse {
 	return InventoryResponse{
 		ID:         ui.ID,
-		Item:       item.ToResponse(),
+		Item:       item.ToResponse(locale),
 		Count:      ui.Count,
 		AcquiredAt: ui.AcquiredAt,
 		Source:     ui.Source,
+		ExpiresAt:  ui.ExpiresAt,
 		UpdatedAt:  ui.UpdatedAt,
 	}
 }
 
-// GetTypeDescription returns description of what Value field means for each ItemType
-func (t ItemType) GetValueDescription() string {
-	switch t {
-	case ItemTypeCurrency:
-		return "지급할 화폐 수량"
-	case ItemTypeEquipment:
-		return "강화 레벨 또는 등급 (기본값: 1)"
-	case ItemTypeConsumable:
-		return "지급할 개수"
-	case ItemTypeCard:
-		return "카드 레벨 또는 등급 (기본값: 1)"
-	case ItemTypeMaterial:
-		return "지급할 재료 개수"
-	case ItemTypeTicket:
-		return "지급할 티켓 개수"
-	default:
-		return "알 수 없는 타입"
+func (ii *ItemInstance) ToResponse(item *Item, locale i18n.Locale) InstanceResponse {
+	return InstanceResponse{
+		ID:         ii.ID,
+		Item:       item.ToResponse(locale),
+		Level:      ii.Level,
+		Attributes: ii.Attributes,
+		Locked:     ii.Locked,
+		AcquiredAt: ii.AcquiredAt,
+		Source:     ii.Source,
+		ExpiresAt:  ii.ExpiresAt,
+		UpdatedAt:  ii.UpdatedAt,
 	}
 }
 
+var itemTypeNames = map[ItemType]i18n.Text{
+	ItemTypeCurrency:   {i18n.Korean: "화폐", i18n.English: "Currency", i18n.Japanese: "通貨"},
+	ItemTypeEquipment:  {i18n.Korean: "장비", i18n.English: "Equipment", i18n.Japanese: "装備"},
+	ItemTypeConsumable: {i18n.Korean: "소모품", i18n.English: "Consumable", i18n.Japanese: "消耗品"},
+	ItemTypeCard:       {i18n.Korean: "카드", i18n.English: "Card", i18n.Japanese: "カード"},
+	ItemTypeMaterial:   {i18n.Korean: "재료", i18n.English: "Material", i18n.Japanese: "素材"},
+	ItemTypeTicket:     {i18n.Korean: "티켓", i18n.English: "Ticket", i18n.Japanese: "チケット"},
+	ItemTypeBundle:     {i18n.Korean: "상자", i18n.English: "Bundle", i18n.Japanese: "ボックス"},
+}
+
+var itemTypeValueDescriptions = map[ItemType]i18n.Text{
+	ItemTypeCurrency:   {i18n.Korean: "지급할 화폐 수량", i18n.English: "Amount of currency to grant", i18n.Japanese: "付与する通貨の数量"},
+	ItemTypeEquipment:  {i18n.Korean: "강화 레벨 또는 등급 (기본값: 1)", i18n.English: "Enhancement level or grade (default: 1)", i18n.Japanese: "強化レベルまたは等級 (デフォルト: 1)"},
+	ItemTypeConsumable: {i18n.Korean: "지급할 개수", i18n.English: "Number of items to grant", i18n.Japanese: "付与する個数"},
+	ItemTypeCard:       {i18n.Korean: "카드 레벨 또는 등급 (기본값: 1)", i18n.English: "Card level or grade (default: 1)", i18n.Japanese: "カードレベルまたは等級 (デフォルト: 1)"},
+	ItemTypeMaterial:   {i18n.Korean: "지급할 재료 개수", i18n.English: "Number of materials to grant", i18n.Japanese: "付与する素材の個数"},
+	ItemTypeTicket:     {i18n.Korean: "지급할 티켓 개수", i18n.English: "Number of tickets to grant", i18n.Japanese: "付与するチケットの枚数"},
+	ItemTypeBundle:     {i18n.Korean: "지급할 상자 개수", i18n.English: "Number of bundles to grant", i18n.Japanese: "付与するボックスの個数"},
+}
+
+var unknownType = i18n.Text{i18n.Korean: "알 수 없는 타입", i18n.English: "Unknown type", i18n.Japanese: "不明なタイプ"}
+
+// GetName returns the display name of the item type in the locale
+func (t ItemType) GetName(locale i18n.Locale) string {
+	if name, ok := itemTypeNames[t]; ok {
+		return name.Get(locale)
+	}
+	return unknownType.Get(locale)
+}
+
+// GetValueDescription returns what the Value field means for the item type, in the locale
+func (t ItemType) GetValueDescription(locale i18n.Locale) string {
+	if description, ok := itemTypeValueDescriptions[t]; ok {
+		return description.Get(locale)
+	}
+	return unknownType.Get(locale)
+}
+
+// IsConsumable reports whether items of this type are used up when used
+func (t ItemType) IsConsumable() bool {
+	return t == ItemTypeConsumable || t == ItemTypeTicket
+}
+
+// UsesSlot reports whether holding items of this type occupies inventory slots.
+// Currencies are held in a wallet and never take a slot.
+func (t ItemType) UsesSlot() bool {
+	return t != ItemTypeCurrency
+}
+
+// IsInstanced reports whether grants of this type create unique ItemInstances instead of stacking
+func (t ItemType) IsInstanced() bool {
+	return t == ItemTypeEquipment || t == ItemTypeCard
+}
+
 // IsValidType validates if the item type is valid
 func IsValidItemType(itemType string) bool {
 	switch ItemType(itemType) {
 	case ItemTypeCurrency, ItemTypeEquipment, ItemTypeConsumable, 
-		 ItemTypeCard, ItemTypeMaterial, ItemTypeTicket:
+		 ItemTypeCard, ItemTypeMaterial, ItemTypeTicket, ItemTypeBundle:
 		return true
 	default:
 		return false
 	}
 }
 
+// RarityRank orders rarities from common (1) to legendary (4); unknown rarities rank 0
+func RarityRank(rarity string) int {
+	switch rarity {
+	case "common":
+		return 1
+	case "rare":
+		return 2
+	case "epic":
+		return 3
+	case "legendary":
+		return 4
+	default:
+		return 0
+	}
+}
+
 // IsValidRarity validates if the rarity is valid
 func IsValidRarity(rarity string) bool {
 	switch rarity {
@@ -0,0 +1,31 @@
+package entity
+
+// DefaultSlotCapacity is the number of inventory slots a user has unless overridden.
+// Each stackable item (except currency) takes one slot; each ItemInstance takes one slot.
+const DefaultSlotCapacity = 200
+
+// OverflowPolicy decides what happens to units of a grant that do not fit in the inventory
+type OverflowPolicy string
+
+const (
+	OverflowReject   OverflowPolicy = "reject"   // 전체 지급 거부
+	OverflowTruncate OverflowPolicy = "truncate" // 들어가는 만큼만 지급, 초과분 폐기
+	OverflowMailbox  OverflowPolicy = "mailbox"  // 들어가는 만큼 지급, 초과분은 우편함으로 발송
+)
+
+// IsValidOverflowPolicy validates if the overflow policy is valid
+func IsValidOverflowPolicy(policy string) bool {
+	switch OverflowPolicy(policy) {
+	case OverflowReject, OverflowTruncate, OverflowMailbox:
+		return true
+	default:
+		return false
+	}
+}
+
+// GrantResult reports how a grant was applied under its overflow policy
+type GrantResult struct {
+	Policy   OverflowPolicy `json:"policy"`
+	Granted  []RewardItem   `json:"granted"`            // 인벤토리에 지급된 수량
+	Overflow []RewardItem   `json:"overflow,omitempty"` // 초과분 (truncate: 폐기, mailbox: 우편 발송)
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// InventoryTransaction is an append-only ledger entry written for every inventory count change
+type InventoryTransaction struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	ItemID        int       `json:"item_id"`
+	Delta         int       `json:"delta"`         // 증감 수량 (지급: +, 차감: -)
+	BalanceAfter  int       `json:"balance_after"` // 변경 후 보유 수량
+	Source        string    `json:"source"`        // 변경 경로 (coupon, payment, reward, admin)
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   string    `json:"reference_id,omitempty"`
+	Actor         string    `json:"actor,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TransactionRef describes what caused an inventory change and who triggered it
+type TransactionRef struct {
+	Type  string `json:"reference_type,omitempty"` // payment, coupon, reward_grant 등
+	ID    string `json:"reference_id,omitempty"`   // 참조 대상 ID
+	Actor string `json:"actor,omitempty"`          // user:1, admin:2, system
+}
+
+// ItemTransfer moves items from one user's inventory to another's
+type ItemTransfer struct {
+	FromUserID int          `json:"from_user_id"`
+	ToUserID   int          `json:"to_user_id"`
+	Items      []RewardItem `json:"items"`
+}
+
+// InventoryDelta is a signed change to one item in a user's inventory
+type InventoryDelta struct {
+	ItemID    int        `json:"item_id"`
+	Count     int        `json:"count"`                // 지급: +, 차감: -
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 지급 시에만 적용
+}
+
+// SplitInventoryDeltas separates deltas into removals and grants, both with positive counts
+func SplitInventoryDeltas(deltas []InventoryDelta) (remove, add []RewardItem) {
+	for _, delta := range deltas {
+		switch {
+		case delta.Count < 0:
+			remove = append(remove, RewardItem{ItemID: delta.ItemID, Count: -delta.Count})
+		case delta.Count > 0:
+			add = append(add, RewardItem{ItemID: delta.ItemID, Count: delta.Count, ExpiresAt: delta.ExpiresAt})
+		}
+	}
+	return remove, add
+}
+
+// ExchangeDeltas builds the deltas that remove one set of items and grant another
+func ExchangeDeltas(remove, add []RewardItem) []InventoryDelta {
+	deltas := make([]InventoryDelta, 0, len(remove)+len(add))
+	for _, item := range remove {
+		deltas = append(deltas, InventoryDelta{ItemID: item.ItemID, Count: -item.Count})
+	}
+	for _, item := range add {
+		deltas = append(deltas, InventoryDelta{ItemID: item.ItemID, Count: item.Count, ExpiresAt: item.ExpiresAt})
+	}
+	return deltas
+}
+
+// Actor helpers keep the ledger actor format consistent across modules
+const ActorSystem = "system"
+
+func UserActor(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+func AdminActor(adminID int) string {
+	return "admin:" + strconv.Itoa(adminID)
+}
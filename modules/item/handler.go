@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"strconv"
 
+	userauth "fxserver/modules/auth/user"
 	"fxserver/modules/item/entity"
 	"fxserver/pkg/dto"
+	"fxserver/pkg/i18n"
 	"fxserver/pkg/validator"
 
 	"github.com/labstack/echo/v4"
@@ -37,33 +39,47 @@ func NewHandler(p HandlerParam) *Handler {
 
 // Public APIs
 
-// GetItems returns all items, optionally filtered by type
+// GetItems searches the public catalog (active, unarchived items) with cursor pagination
 func (h *Handler) GetItems(c echo.Context) error {
-	itemType := c.QueryParam("type")
-	
-	var items []*entity.Item
-	var err error
-
-	if itemType != "" {
-		if !entity.IsValidItemType(itemType) {
-			return c.JSON(http.StatusBadRequest, dto.NewError("Invalid item type", "invalid_request_error"))
-		}
-		items, err = h.service.GetItemsByType(entity.ItemType(itemType))
-	} else {
-		items, err = h.service.GetItems()
+	var query ListItemsQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
 	}
 
+	// Visibility filters are admin only
+	active := true
+	query.IsActive = &active
+	query.IncludeArchived = false
+
+	return h.listItems(c, query, false)
+}
+
+// listItems runs a catalog query and writes one page of results; admin listings include translations
+func (h *Handler) listItems(c echo.Context, query ListItemsQuery, admin bool) error {
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	locale := i18n.FromRequest(c)
+	page, err := h.service.ListItems(query, locale)
 	if err != nil {
-		h.logger.Error("Failed to get items", zap.Error(err))
+		if errors.Is(err, ErrInvalidItemType) || errors.Is(err, ErrInvalidRarity) || errors.Is(err, ErrInvalidCursor) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to list items", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get items"))
 	}
 
-	itemResponses := make([]entity.ItemResponse, len(items))
-	for i, item := range items {
-		itemResponses[i] = item.ToResponse()
+	itemResponses := make([]entity.ItemResponse, len(page.Items))
+	for i, item := range page.Items {
+		if admin {
+			itemResponses[i] = adminItemResponse(item, locale)
+		} else {
+			itemResponses[i] = item.ToResponse(locale)
+		}
 	}
 
-	return c.JSON(http.StatusOK, dto.NewList(itemResponses))
+	return c.JSON(http.StatusOK, dto.NewCursorList(itemResponses, page.NextCursor, page.Total))
 }
 
 // GetItem returns a specific item by ID
@@ -83,13 +99,13 @@ func (h *Handler) GetItem(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get item"))
 	}
 
-	return c.JSON(http.StatusOK, item.ToResponse())
+	return c.JSON(http.StatusOK, item.ToResponse(i18n.FromRequest(c)))
 }
 
 // GetItemTypes returns all available item types
 func (h *Handler) GetItemTypes(c echo.Context) error {
 	return c.JSON(http.StatusOK, ItemTypesResponse{
-		Types: h.service.GetItemTypes(),
+		Types: h.service.GetItemTypes(i18n.FromRequest(c)),
 	})
 }
 
@@ -101,7 +117,7 @@ func (h *Handler) GetUserInventory(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid user ID", "invalid_request_error"))
 	}
 
-	inventory, err := h.service.GetUserInventory(userID)
+	inventory, err := h.service.GetUserInventory(userID, i18n.FromRequest(c))
 	if err != nil {
 		h.logger.Error("Failed to get user inventory", zap.Error(err), zap.Int("user_id", userID))
 		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get user inventory"))
@@ -110,8 +126,187 @@ func (h *Handler) GetUserInventory(c echo.Context) error {
 	return c.JSON(http.StatusOK, inventory)
 }
 
+// UseItem consumes an item from the authenticated user's inventory and applies its effect
+func (h *Handler) UseItem(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	itemID, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid item ID", "invalid_request_error"))
+	}
+
+	var req UseItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	if req.Count == 0 {
+		req.Count = 1
+	}
+
+	response, err := h.service.UseItem(userID, itemID, req.Count, i18n.FromRequest(c))
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Item"))
+		}
+		if errors.Is(err, ErrItemNotUsable) || errors.Is(err, ErrInsufficientItem) || errors.Is(err, ErrNoEffectHandler) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to use item", zap.Error(err), zap.Int("user_id", userID), zap.Int("item_id", itemID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to use item"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// OpenBundle opens bundle items from the authenticated user's inventory and grants their contents
+func (h *Handler) OpenBundle(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	itemID, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid item ID", "invalid_request_error"))
+	}
+
+	var req OpenBundleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	if req.Count == 0 {
+		req.Count = 1
+	}
+
+	response, err := h.service.OpenBundle(userID, itemID, req.Count)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Item"))
+		}
+		if errors.Is(err, ErrNotBundle) || errors.Is(err, ErrInsufficientItem) || errors.Is(err, ErrInventoryFull) || errors.Is(err, ErrItemArchived) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to open bundle", zap.Error(err), zap.Int("user_id", userID), zap.Int("item_id", itemID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to open bundle"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// LockInstance locks or unlocks one of the authenticated user's item instances
+func (h *Handler) LockInstance(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	instanceID, err := strconv.Atoi(c.Param("instanceId"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid instance ID", "invalid_request_error"))
+	}
+
+	var req LockInstanceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	instance, err := h.service.SetInstanceLocked(userID, instanceID, req.Locked, i18n.FromRequest(c))
+	if err != nil {
+		if errors.Is(err, ErrInstanceNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Item instance"))
+		}
+		h.logger.Error("Failed to lock item instance", zap.Error(err), zap.Int("instance_id", instanceID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update item instance"))
+	}
+
+	return c.JSON(http.StatusOK, instance)
+}
+
+// GetInventoryCapacity returns the authenticated user's slot capacity and usage
+func (h *Handler) GetInventoryCapacity(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	capacity, err := h.service.GetInventoryCapacity(userID)
+	if err != nil {
+		h.logger.Error("Failed to get inventory capacity", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get inventory capacity"))
+	}
+
+	return c.JSON(http.StatusOK, capacity)
+}
+
 // Admin APIs
 
+// SetInventoryCapacity changes a user's inventory slot capacity (admin only)
+func (h *Handler) SetInventoryCapacity(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid user ID", "invalid_request_error"))
+	}
+
+	var req SetInventoryCapacityRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	capacity, err := h.service.SetInventoryCapacity(userID, req.Capacity)
+	if err != nil {
+		h.logger.Error("Failed to set inventory capacity", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to set inventory capacity"))
+	}
+
+	return c.JSON(http.StatusOK, capacity)
+}
+
+// GetInventoryTransactions returns the inventory ledger for a specific user (admin only)
+func (h *Handler) GetInventoryTransactions(c echo.Context) error {
+	idParam := c.Param("id")
+	userID, err := strconv.Atoi(idParam)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid user ID", "invalid_request_error"))
+	}
+
+	var query InventoryTransactionQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	transactions, err := h.service.GetInventoryTransactions(userID, query)
+	if err != nil {
+		if errors.Is(err, ErrInvalidDateRange) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to get inventory transactions", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get inventory transactions"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(transactions))
+}
+
 // CreateItem creates a new item (admin only)
 func (h *Handler) CreateItem(c echo.Context) error {
 	var req CreateItemRequest
@@ -125,14 +320,14 @@ func (h *Handler) CreateItem(c echo.Context) error {
 
 	item, err := h.service.CreateItem(req)
 	if err != nil {
-		if errors.Is(err, ErrInvalidItemType) || errors.Is(err, ErrInvalidRarity) {
+		if errors.Is(err, ErrInvalidItemType) || errors.Is(err, ErrInvalidRarity) || errors.Is(err, ErrInvalidBundle) || errors.Is(err, ErrInvalidLocale) {
 			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error()))
 		}
 		h.logger.Error("Failed to create item", zap.Error(err))
 		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create item"))
 	}
 
-	return c.JSON(http.StatusCreated, item.ToResponse())
+	return c.JSON(http.StatusCreated, adminItemResponse(item, i18n.FromRequest(c)))
 }
 
 // UpdateItem updates an existing item (admin only)
@@ -157,14 +352,14 @@ func (h *Handler) UpdateItem(c echo.Context) error {
 		if errors.Is(err, ErrItemNotFound) {
 			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Item"))
 		}
-		if errors.Is(err, ErrInvalidItemType) || errors.Is(err, ErrInvalidRarity) {
+		if errors.Is(err, ErrInvalidItemType) || errors.Is(err, ErrInvalidRarity) || errors.Is(err, ErrInvalidBundle) || errors.Is(err, ErrInvalidLocale) {
 			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error()))
 		}
 		h.logger.Error("Failed to update item", zap.Error(err), zap.Int("item_id", id))
 		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update item"))
 	}
 
-	return c.JSON(http.StatusOK, item.ToResponse())
+	return c.JSON(http.StatusOK, adminItemResponse(item, i18n.FromRequest(c)))
 }
 
 // DeleteItem soft deletes an item (admin only)
@@ -186,3 +381,42 @@ func (h *Handler) DeleteItem(c echo.Context) error {
 	return c.JSON(http.StatusOK, dto.NewEmpty(strconv.Itoa(id)))
 }
 
+// ListItems searches the whole catalog, including inactive and (on request) archived items (admin only)
+func (h *Handler) ListItems(c echo.Context) error {
+	var query ListItemsQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	return h.listItems(c, query, true)
+}
+
+// RestoreItem brings an archived item back (admin only)
+func (h *Handler) RestoreItem(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid item ID", "invalid_request_error"))
+	}
+
+	item, err := h.service.RestoreItem(id)
+	if err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Item"))
+		}
+		if errors.Is(err, ErrItemNotArchived) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to restore item", zap.Error(err), zap.Int("item_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to restore item"))
+	}
+
+	return c.JSON(http.StatusOK, adminItemResponse(item, i18n.FromRequest(c)))
+}
+
+
+// adminItemResponse adds the full translation map so admins can review and edit it
+func adminItemResponse(item *entity.Item, locale i18n.Locale) entity.ItemResponse {
+	response := item.ToResponse(locale)
+	response.Translations = item.Translations
+	return response
+}
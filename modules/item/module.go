@@ -9,12 +9,23 @@ import (
 var Module = fx.Options(
 	repository.Module,
 	fx.Provide(
+		NewEffectRegistry,
 		NewService,
 		NewHandler,
+		NewExpirySweeper,
+		fx.Annotate(
+			NewConsumableEffect,
+			fx.ResultTags(`group:"item_effects"`),
+		),
+		fx.Annotate(
+			NewTicketEffect,
+			fx.ResultTags(`group:"item_effects"`),
+		),
 		fx.Annotate(
 			NewRoutes,
 			fx.As(new(router.RouteRegistrar)),
 			fx.ResultTags(`group:"routes"`),
 		),
 	),
+	fx.Invoke(func(*ExpirySweeper) {}),
 )
\ No newline at end of file
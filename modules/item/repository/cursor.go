@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fxserver/modules/item/entity"
+	"fxserver/pkg/i18n"
+)
+
+// itemCursor marks the last item of a catalog page. It carries the sort key value rather
+// than a position so pages stay consistent while items are added or removed.
+type itemCursor struct {
+	SortBy     ItemSortField `json:"s"`
+	Descending bool          `json:"d,omitempty"`
+	Locale     i18n.Locale   `json:"l,omitempty"` // name 정렬에서만 사용
+	Key        string        `json:"k,omitempty"`
+	ID         int           `json:"i"`
+}
+
+func encodeItemCursor(filter ItemFilter, item *entity.Item) string {
+	cursor := itemCursor{
+		SortBy:     filter.SortBy,
+		Descending: filter.Descending,
+		Key:        itemSortKey(item, filter),
+		ID:         item.ID,
+	}
+	if filter.SortBy == ItemSortName {
+		cursor.Locale = filter.Locale
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeItemCursor(filter ItemFilter) (*itemCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+
+	var cursor itemCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+	if cursor.SortBy != filter.SortBy || cursor.Descending != filter.Descending {
+		return nil, fmt.Errorf("%w: cursor belongs to a different sort order", ErrInvalidCursor)
+	}
+	// 언어가 바뀌면 이름 순서도 바뀌므로 이어서 조회할 수 없음
+	if cursor.SortBy == ItemSortName && cursor.Locale != filter.Locale {
+		return nil, fmt.Errorf("%w: cursor belongs to a different language", ErrInvalidCursor)
+	}
+	return &cursor, nil
+}
+
+// itemSortKey renders the sort field so that string comparison matches the intended order;
+// names sort in the filter's language
+func itemSortKey(item *entity.Item, filter ItemFilter) string {
+	switch filter.SortBy {
+	case ItemSortName:
+		return strings.ToLower(item.LocalizedName(filter.Locale))
+	case ItemSortRarity:
+		return fmt.Sprintf("%d", entity.RarityRank(item.Rarity))
+	case ItemSortCreatedAt:
+		return fmt.Sprintf("%020d", item.CreatedAt.UnixNano())
+	default:
+		return ""
+	}
+}
+
+// compareItemKeys orders (key, id) pairs ascending; the ID breaks ties so the order is total
+func compareItemKeys(keyA string, idA int, keyB string, idB int) int {
+	if c := strings.Compare(keyA, keyB); c != 0 {
+		return c
+	}
+	switch {
+	case idA < idB:
+		return -1
+	case idA > idB:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -1,30 +1,148 @@
 package repository
 
-import "fxserver/modules/item/entity"
+import (
+	"errors"
+	"time"
+
+	"fxserver/modules/item/entity"
+	"fxserver/pkg/i18n"
+)
+
+var (
+	ErrInventoryNotFound = errors.New("inventory item not found")
+	ErrInsufficientCount = errors.New("insufficient item count")
+	ErrInstanceNotFound  = errors.New("item instance not found")
+	ErrInstancedItem     = errors.New("operation not supported for instanced items")
+	ErrInstanceChanged   = errors.New("item instance was modified concurrently")
+	ErrCapacityExceeded  = errors.New("inventory capacity exceeded")
+	ErrItemNotArchived   = errors.New("item is not archived")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+)
 
 type ItemRepository interface {
 	// Item master data operations
+	// GetItem resolves archived items too; GetItems and GetItemsByType skip them.
 	GetItem(id int) (*entity.Item, error)
 	GetItems() ([]*entity.Item, error)
 	GetItemsByType(itemType entity.ItemType) ([]*entity.Item, error)
+	// ListItems searches the catalog in a stable order (sort key, then ID) with keyset
+	// pagination; it fails with ErrInvalidCursor for cursors from a different sort.
+	ListItems(filter ItemFilter) (*entity.ItemPage, error)
 	CreateItem(item *entity.Item) error
 	UpdateItem(item *entity.Item) error
+	// DeleteItem archives the item (soft delete); RestoreItem reverses it
 	DeleteItem(id int) error
+	RestoreItem(id int) error
+}
+
+// ItemSortField is a catalog sort key
+type ItemSortField string
+
+const (
+	ItemSortID        ItemSortField = "id"
+	ItemSortName      ItemSortField = "name"
+	ItemSortRarity    ItemSortField = "rarity"
+	ItemSortCreatedAt ItemSortField = "created_at"
+)
+
+// ItemFilter narrows down item master queries; zero values are ignored
+type ItemFilter struct {
+	Query           string            // case-insensitive substring of the name in any language
+	Types           []entity.ItemType // any of
+	Rarities        []string          // any of
+	IsActive        *bool
+	IncludeArchived bool
+	SortBy          ItemSortField // default: id
+	Locale          i18n.Locale   // name 정렬에 쓰는 언어 (번역이 없으면 기본 언어 이름)
+	Descending      bool
+	Cursor          string // NextCursor of the previous page
+	Limit           int    // 0: no limit
 }
 
 type InventoryRepository interface {
 	// User inventory operations
+	// Every count change appends an InventoryTransaction within the same lock.
+	// Grants of instanced item types (equipment, card) create one ItemInstance per unit,
+	// removals delete unlocked instances (soonest-expiring, lowest level, oldest first).
+	// Stackable units are kept in separate stacks per expiry; expired stacks are hidden
+	// and removals consume the soonest-expiring stack first.
 	GetUserInventory(userID int) ([]*entity.UserInventory, error)
 	GetUserInventoryItem(userID, itemID int) (*entity.UserInventory, error)
-	AddToInventory(userID, itemID int, count int, source string) error
-	UpdateInventoryCount(userID, itemID int, count int) error
-	RemoveFromInventory(userID, itemID int, count int) error
+	AddToInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error
+	UpdateInventoryCount(userID, itemID int, count int, source string, ref entity.TransactionRef) error
+	RemoveFromInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error
+	// TakeFromInventory removes stackable units like RemoveFromInventory and returns the stacks
+	// they came from (count and expiry) so they can be put back; instanced items return ErrInstancedItem
+	TakeFromInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) ([]entity.RewardItem, error)
+	// RestoreToInventory puts units returned by TakeFromInventory back into stacks with the same
+	// expiry. Capacity is not checked since the user held the units before they were taken.
+	RestoreToInventory(userID int, items []entity.RewardItem, source string, ref entity.TransactionRef) error
 	
 	// Batch operations for reward system
-	AddMultipleToInventory(userID int, items []entity.RewardItem, source string) error
+	// Units beyond an item's stack cap or the user's free slots are handled by policy:
+	// reject fails the whole grant with ErrCapacityExceeded, truncate drops the excess and
+	// mailbox grants what fits and returns the excess in GrantResult.Overflow for the caller
+	// to mail. An empty policy means reject.
+	AddMultipleToInventory(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source string, ref entity.TransactionRef) (*entity.GrantResult, error)
+	// PlanGrant reports what AddMultipleToInventory would grant and overflow without changing anything
+	PlanGrant(userID int, items []entity.RewardItem, policy entity.OverflowPolicy) (*entity.GrantResult, error)
+
+	// ApplyInventoryChanges applies signed deltas in one critical section; nothing changes if any
+	// removal is short (ErrInventoryNotFound, ErrInsufficientCount) or the grants do not fit
+	// (ErrCapacityExceeded), counting slots freed by the removals. Removals apply before grants.
+	ApplyInventoryChanges(userID int, deltas []entity.InventoryDelta, source string, ref entity.TransactionRef) error
+	// ExchangeItems is ApplyInventoryChanges with separate removal and grant lists
+	ExchangeItems(userID int, remove, add []entity.RewardItem, source string, ref entity.TransactionRef) error
+
+	// TransferItems applies every transfer in one critical section; nothing moves if any sender
+	// is short or any receiver lacks room (ErrCapacityExceeded), counting slots freed by what
+	// that user sends. Moved stacks keep their expiry and instances keep their level and attributes.
+	TransferItems(transfers []entity.ItemTransfer, source string, ref entity.TransactionRef) error
+
+	// PurgeExpired deletes stacks and instances that expired at or before now, recording a
+	// ledger entry per user and item. It returns the number of units removed.
+	PurgeExpired(now time.Time, source string, ref entity.TransactionRef) (int, error)
+}
+
+type CapacityRepository interface {
+	// Inventory slot capacity operations
+	// GetSlotCapacity returns the user's slot capacity and the slots currently in use.
+	GetSlotCapacity(userID int) (capacity int, used int, err error)
+	SetSlotCapacity(userID, capacity int) error
+}
+
+type InstanceRepository interface {
+	// Unique item instance operations (equipment, card)
+	GetUserInstances(userID int) ([]*entity.ItemInstance, error)
+	GetInstance(instanceID int) (*entity.ItemInstance, error)
+	UpdateInstance(instance *entity.ItemInstance) error
+
+	// ChangeInstanceLevel deducts costs and applies the level change (or destroys the instance)
+	// in a single critical section; nothing is changed if any cost cannot be paid.
+	ChangeInstanceLevel(change entity.InstanceLevelChange, costs []entity.RewardItem, source string, ref entity.TransactionRef) error
+}
+
+// TransactionFilter narrows down inventory ledger queries; zero values are ignored
+type TransactionFilter struct {
+	UserID        int
+	ItemID        int
+	Source        string
+	ReferenceType string
+	ReferenceID   string
+	From          time.Time
+	To            time.Time
+	Limit         int
+}
+
+type TransactionRepository interface {
+	// Inventory ledger operations (append-only)
+	GetInventoryTransactions(filter TransactionFilter) ([]*entity.InventoryTransaction, error)
 }
 
 type Repository interface {
 	ItemRepository
 	InventoryRepository
-}
\ No newline at end of file
+	InstanceRepository
+	CapacityRepository
+	TransactionRepository
+}
@@ -2,17 +2,26 @@ package repository
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"fxserver/modules/item/entity"
+	"fxserver/pkg/i18n"
 )
 
 type memoryRepository struct {
 	items        map[int]*entity.Item
 	inventories  map[string]*entity.UserInventory // key: "userID:itemID"
+	instances    map[int]*entity.ItemInstance     // key: instanceID
+	transactions []*entity.InventoryTransaction   // append-only ledger
+	capacities   map[int]int                      // key: userID, slot capacity overrides
 	itemCounter  int
 	invCounter   int
+	instCounter  int
+	txCounter    int
 	mu           sync.RWMutex
 }
 
@@ -20,6 +29,8 @@ func NewMemoryRepository() Repository {
 	repo := &memoryRepository{
 		items:       make(map[int]*entity.Item),
 		inventories: make(map[string]*entity.UserInventory),
+		instances:   make(map[int]*entity.ItemInstance),
+		capacities:  make(map[int]int),
 		itemCounter: 0,
 		invCounter:  0,
 	}
@@ -40,8 +51,13 @@ func (r *memoryRepository) initializeDefaultItems() {
 			Rarity:      "common",
 			IconURL:     "/icons/gold.png",
 			IsActive:    true,
+			Tradable:    true,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			Translations: map[i18n.Locale]entity.ItemTranslation{
+				i18n.English:  {Name: "Gold", Description: "Basic in-game currency"},
+				i18n.Japanese: {Name: "ゴールド", Description: "基本のゲーム通貨"},
+			},
 		},
 		{
 			Name:        "다이아몬드",
@@ -53,6 +69,10 @@ func (r *memoryRepository) initializeDefaultItems() {
 			IsActive:    true,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			Translations: map[i18n.Locale]entity.ItemTranslation{
+				i18n.English:  {Name: "Diamond", Description: "Premium currency"},
+				i18n.Japanese: {Name: "ダイヤモンド", Description: "プレミアム通貨"},
+			},
 		},
 		{
 			Name:        "체력 포션",
@@ -62,8 +82,13 @@ func (r *memoryRepository) initializeDefaultItems() {
 			Rarity:      "common",
 			IconURL:     "/icons/hp_potion.png",
 			IsActive:    true,
+			Tradable:    true,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			Translations: map[i18n.Locale]entity.ItemTranslation{
+				i18n.English:  {Name: "Health Potion", Description: "A potion that restores HP"},
+				i18n.Japanese: {Name: "体力ポーション", Description: "HPを回復するポーション"},
+			},
 		},
 		{
 			Name:        "전설의 검",
@@ -73,8 +98,13 @@ func (r *memoryRepository) initializeDefaultItems() {
 			Rarity:      "legendary",
 			IconURL:     "/icons/legendary_sword.png",
 			IsActive:    true,
+			Tradable:    true,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			Translations: map[i18n.Locale]entity.ItemTranslation{
+				i18n.English:  {Name: "Legendary Sword", Description: "A legendary sword with immense attack power"},
+				i18n.Japanese: {Name: "伝説の剣", Description: "強力な攻撃力を持つ伝説級の剣"},
+			},
 		},
 		{
 			Name:        "던전 입장권",
@@ -86,6 +116,10 @@ func (r *memoryRepository) initializeDefaultItems() {
 			IsActive:    true,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			Translations: map[i18n.Locale]entity.ItemTranslation{
+				i18n.English:  {Name: "Dungeon Ticket", Description: "A ticket that grants entry to a dungeon"},
+				i18n.Japanese: {Name: "ダンジョン入場券", Description: "ダンジョンに入場できるチケット"},
+			},
 		},
 	}
 
@@ -114,7 +148,7 @@ func (r *memoryRepository) GetItems() ([]*entity.Item, error) {
 
 	items := make([]*entity.Item, 0, len(r.items))
 	for _, item := range r.items {
-		if item.IsActive {
+		if !item.IsArchived() {
 			items = append(items, item)
 		}
 	}
@@ -127,13 +161,110 @@ func (r *memoryRepository) GetItemsByType(itemType entity.ItemType) ([]*entity.I
 
 	var items []*entity.Item
 	for _, item := range r.items {
-		if item.IsActive && item.Type == itemType {
+		if !item.IsArchived() && item.Type == itemType {
 			items = append(items, item)
 		}
 	}
 	return items, nil
 }
 
+func (r *memoryRepository) ListItems(filter ItemFilter) (*entity.ItemPage, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = ItemSortID
+	}
+	var after *itemCursor
+	if filter.Cursor != "" {
+		cursor, err := decodeItemCursor(filter)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Sort keys are computed once per match rather than on every comparison
+	type match struct {
+		item *entity.Item
+		key  string
+	}
+	query := strings.ToLower(filter.Query)
+	matches := make([]match, 0, len(r.items))
+	for _, item := range r.items {
+		if !itemMatches(item, filter, query) {
+			continue
+		}
+		matches = append(matches, match{item: item, key: itemSortKey(item, filter)})
+	}
+
+	direction := 1
+	if filter.Descending {
+		direction = -1
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return direction*compareItemKeys(matches[i].key, matches[i].item.ID, matches[j].key, matches[j].item.ID) < 0
+	})
+
+	// Skip everything up to and including the cursor position
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matches), func(i int) bool {
+			return direction*compareItemKeys(matches[i].key, matches[i].item.ID, after.Key, after.ID) > 0
+		})
+	}
+
+	end := len(matches)
+	if filter.Limit > 0 && start+filter.Limit < end {
+		end = start + filter.Limit
+	}
+
+	page := &entity.ItemPage{
+		Items: make([]*entity.Item, 0, end-start),
+		Total: len(matches),
+	}
+	for _, m := range matches[start:end] {
+		page.Items = append(page.Items, m.item)
+	}
+	if end < len(matches) {
+		page.NextCursor = encodeItemCursor(filter, matches[end-1].item)
+	}
+	return page, nil
+}
+
+// itemMatches applies every ItemFilter condition except sorting and paging; query is lower-cased
+func itemMatches(item *entity.Item, filter ItemFilter, query string) bool {
+	if item.IsArchived() && !filter.IncludeArchived {
+		return false
+	}
+	if filter.IsActive != nil && item.IsActive != *filter.IsActive {
+		return false
+	}
+	if query != "" && !nameMatches(item, query) {
+		return false
+	}
+	if len(filter.Types) > 0 && !slices.Contains(filter.Types, item.Type) {
+		return false
+	}
+	if len(filter.Rarities) > 0 && !slices.Contains(filter.Rarities, item.Rarity) {
+		return false
+	}
+	return true
+}
+
+// nameMatches reports whether the item's name in any language contains query (lower-cased)
+func nameMatches(item *entity.Item, query string) bool {
+	if strings.Contains(strings.ToLower(item.Name), query) {
+		return true
+	}
+	for _, translation := range item.Translations {
+		if strings.Contains(strings.ToLower(translation.Name), query) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *memoryRepository) CreateItem(item *entity.Item) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -168,7 +299,31 @@ func (r *memoryRepository) DeleteItem(id int) error {
 		return fmt.Errorf("item with id %d not found", id)
 	}
 
+	if item.IsArchived() {
+		return nil
+	}
+
+	now := time.Now()
 	item.IsActive = false
+	item.DeletedAt = &now
+	item.UpdatedAt = now
+	return nil
+}
+
+func (r *memoryRepository) RestoreItem(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, exists := r.items[id]
+	if !exists {
+		return fmt.Errorf("item with id %d not found", id)
+	}
+	if !item.IsArchived() {
+		return fmt.Errorf("%w: %d", ErrItemNotArchived, id)
+	}
+
+	item.IsActive = true
+	item.DeletedAt = nil
 	item.UpdatedAt = time.Now()
 	return nil
 }
@@ -178,124 +333,780 @@ func (r *memoryRepository) GetUserInventory(userID int) ([]*entity.UserInventory
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	now := time.Now()
 	var inventories []*entity.UserInventory
 	for _, inv := range r.inventories {
-		if inv.UserID == userID && inv.Count > 0 {
+		if inv.UserID == userID && inv.Count > 0 && !inv.IsExpired(now) {
 			inventories = append(inventories, inv)
 		}
 	}
 	return inventories, nil
 }
 
+// GetUserInventoryItem returns the combined unexpired balance of an item across its stacks.
+// ExpiresAt is the soonest expiry among those stacks.
 func (r *memoryRepository) GetUserInventoryItem(userID, itemID int) (*entity.UserInventory, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	key := fmt.Sprintf("%d:%d", userID, itemID)
-	inventory, exists := r.inventories[key]
-	if !exists {
-		return nil, fmt.Errorf("inventory item not found for user %d, item %d", userID, itemID)
+	stacks := r.stacksLocked(userID, itemID, time.Now())
+	if len(stacks) == 0 {
+		return nil, fmt.Errorf("%w for user %d, item %d", ErrInventoryNotFound, userID, itemID)
 	}
-	return inventory, nil
+
+	combined := *stacks[0]
+	combined.Count = 0
+	for _, stack := range stacks {
+		combined.Count += stack.Count
+		if stack.AcquiredAt.Before(combined.AcquiredAt) {
+			combined.AcquiredAt = stack.AcquiredAt
+		}
+		if stack.UpdatedAt.After(combined.UpdatedAt) {
+			combined.UpdatedAt = stack.UpdatedAt
+		}
+	}
+	return &combined, nil
 }
 
-func (r *memoryRepository) AddToInventory(userID, itemID int, count int, source string) error {
+func (r *memoryRepository) AddToInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Verify item exists
-	if _, exists := r.items[itemID]; !exists {
+	item, exists := r.items[itemID]
+	if !exists {
 		return fmt.Errorf("item with id %d not found", itemID)
 	}
 
-	key := fmt.Sprintf("%d:%d", userID, itemID)
-	
-	if existing, exists := r.inventories[key]; exists {
-		// Update existing inventory
-		existing.Count += count
-		existing.UpdatedAt = time.Now()
-	} else {
-		// Create new inventory entry
-		r.invCounter++
-		r.inventories[key] = &entity.UserInventory{
-			ID:         r.invCounter,
-			UserID:     userID,
-			ItemID:     itemID,
-			Count:      count,
-			AcquiredAt: time.Now(),
-			Source:     source,
-			UpdatedAt:  time.Now(),
-		}
+	if fits := r.planGrantLocked(userID, nil, []entity.RewardItem{{ItemID: itemID, Count: count}}); fits[0] < count {
+		return fmt.Errorf("%w: only %d of %d units of item %d fit", ErrCapacityExceeded, fits[0], count, itemID)
 	}
 
+	r.grantLocked(userID, item, count, nil, source, ref)
 	return nil
 }
 
-func (r *memoryRepository) UpdateInventoryCount(userID, itemID int, count int) error {
+// UpdateInventoryCount sets the count of the user's non-expiring stack of an item
+func (r *memoryRepository) UpdateInventoryCount(userID, itemID int, count int, source string, ref entity.TransactionRef) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := fmt.Sprintf("%d:%d", userID, itemID)
-	inventory, exists := r.inventories[key]
+	if item, exists := r.items[itemID]; exists && item.Type.IsInstanced() {
+		return fmt.Errorf("%w: cannot set count of item %d", ErrInstancedItem, itemID)
+	}
+
+	inventory, exists := r.inventories[stackKey(userID, itemID, nil)]
 	if !exists {
-		return fmt.Errorf("inventory item not found for user %d, item %d", userID, itemID)
+		return fmt.Errorf("%w for user %d, item %d", ErrInventoryNotFound, userID, itemID)
 	}
 
+	delta := count - inventory.Count
 	inventory.Count = count
 	inventory.UpdatedAt = time.Now()
+	r.recordTransactionLocked(userID, itemID, delta, r.balanceLocked(userID, itemID, time.Now()), source, ref)
 	return nil
 }
 
-func (r *memoryRepository) RemoveFromInventory(userID, itemID int, count int) error {
+func (r *memoryRepository) RemoveFromInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	key := fmt.Sprintf("%d:%d", userID, itemID)
-	inventory, exists := r.inventories[key]
-	if !exists {
-		return fmt.Errorf("inventory item not found for user %d, item %d", userID, itemID)
+	if err := r.checkAvailableLocked(userID, itemID, count); err != nil {
+		return err
 	}
 
-	if inventory.Count < count {
-		return fmt.Errorf("insufficient item count: have %d, trying to remove %d", inventory.Count, count)
+	r.removeLocked(userID, itemID, count, source, ref)
+	return nil
+}
+
+func (r *memoryRepository) TakeFromInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) ([]entity.RewardItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if item, exists := r.items[itemID]; exists && item.Type.IsInstanced() {
+		return nil, fmt.Errorf("%w: item %d", ErrInstancedItem, itemID)
+	}
+	if err := r.checkAvailableLocked(userID, itemID, count); err != nil {
+		return nil, err
 	}
 
-	inventory.Count -= count
-	inventory.UpdatedAt = time.Now()
+	return r.removeLocked(userID, itemID, count, source, ref), nil
+}
+
+func (r *memoryRepository) RestoreToInventory(userID int, items []entity.RewardItem, source string, ref entity.TransactionRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, item := range items {
+		if catalog, exists := r.items[item.ItemID]; !exists {
+			return fmt.Errorf("item with id %d not found", item.ItemID)
+		} else if catalog.Type.IsInstanced() {
+			return fmt.Errorf("%w: item %d", ErrInstancedItem, item.ItemID)
+		}
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		r.addToStackLocked(userID, item.ItemID, item.Count, item.ExpiresAt, source, now)
+		r.recordTransactionLocked(userID, item.ItemID, item.Count, r.balanceLocked(userID, item.ItemID, now), source, ref)
+	}
 	return nil
 }
 
-func (r *memoryRepository) AddMultipleToInventory(userID int, items []entity.RewardItem, source string) error {
+func (r *memoryRepository) AddMultipleToInventory(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source string, ref entity.TransactionRef) (*entity.GrantResult, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Verify all items exist first
 	for _, item := range items {
 		if _, exists := r.items[item.ItemID]; !exists {
-			return fmt.Errorf("item with id %d not found", item.ItemID)
+			return nil, fmt.Errorf("item with id %d not found", item.ItemID)
 		}
 	}
 
-	// Add all items
+	result, err := r.planResultLocked(userID, items, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add what fits; the caller decides what happens to the overflow
+	for _, granted := range result.Granted {
+		r.grantLocked(userID, r.items[granted.ItemID], granted.Count, granted.ExpiresAt, source, ref)
+	}
+
+	return result, nil
+}
+
+func (r *memoryRepository) PlanGrant(userID int, items []entity.RewardItem, policy entity.OverflowPolicy) (*entity.GrantResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	for _, item := range items {
-		key := fmt.Sprintf("%d:%d", userID, item.ItemID)
-		
-		if existing, exists := r.inventories[key]; exists {
-			existing.Count += item.Count
-			existing.UpdatedAt = time.Now()
-		} else {
-			r.invCounter++
-			r.inventories[key] = &entity.UserInventory{
-				ID:         r.invCounter,
+		if _, exists := r.items[item.ItemID]; !exists {
+			return nil, fmt.Errorf("item with id %d not found", item.ItemID)
+		}
+	}
+	return r.planResultLocked(userID, items, policy)
+}
+
+// planResultLocked splits a grant into what fits and the overflow, failing with
+// ErrCapacityExceeded when the policy rejects overflow. Caller must hold the lock.
+func (r *memoryRepository) planResultLocked(userID int, items []entity.RewardItem, policy entity.OverflowPolicy) (*entity.GrantResult, error) {
+	if policy == "" {
+		policy = entity.OverflowReject
+	}
+	result := &entity.GrantResult{
+		Policy:   policy,
+		Granted:  []entity.RewardItem{},
+		Overflow: []entity.RewardItem{},
+	}
+
+	fits := r.planGrantLocked(userID, nil, items)
+	for i, item := range items {
+		if fits[i] > 0 {
+			granted := item
+			granted.Count = fits[i]
+			result.Granted = append(result.Granted, granted)
+		}
+		if fits[i] < item.Count {
+			excess := item
+			excess.Count = item.Count - fits[i]
+			result.Overflow = append(result.Overflow, excess)
+		}
+	}
+	if len(result.Overflow) > 0 && policy == entity.OverflowReject {
+		return nil, fmt.Errorf("%w: %d of %d item entries do not fit", ErrCapacityExceeded, len(result.Overflow), len(items))
+	}
+	return result, nil
+}
+
+func (r *memoryRepository) ExchangeItems(userID int, remove, add []entity.RewardItem, source string, ref entity.TransactionRef) error {
+	return r.ApplyInventoryChanges(userID, entity.ExchangeDeltas(remove, add), source, ref)
+}
+
+func (r *memoryRepository) ApplyInventoryChanges(userID int, deltas []entity.InventoryDelta, source string, ref entity.TransactionRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Verify all items exist first
+	for _, delta := range deltas {
+		if delta.Count == 0 {
+			return fmt.Errorf("invalid count 0 for item %d", delta.ItemID)
+		}
+		if _, exists := r.items[delta.ItemID]; !exists {
+			return fmt.Errorf("item with id %d not found", delta.ItemID)
+		}
+	}
+
+	// Check every removal before changing anything
+	remove, add := entity.SplitInventoryDeltas(deltas)
+	remove = entity.MergeRewardItems(remove)
+	for _, item := range remove {
+		if err := r.checkAvailableLocked(userID, item.ItemID, item.Count); err != nil {
+			return err
+		}
+	}
+
+	fits := r.planGrantLocked(userID, remove, add)
+	for i, item := range add {
+		if fits[i] < item.Count {
+			return fmt.Errorf("%w: only %d of %d units of item %d fit", ErrCapacityExceeded, fits[i], item.Count, item.ItemID)
+		}
+	}
+
+	// Removals are applied before grants so freed slots can be reused
+	for _, item := range remove {
+		r.removeLocked(userID, item.ItemID, item.Count, source, ref)
+	}
+	for _, item := range add {
+		r.grantLocked(userID, r.items[item.ItemID], item.Count, item.ExpiresAt, source, ref)
+	}
+
+	return nil
+}
+
+func (r *memoryRepository) TransferItems(transfers []entity.ItemTransfer, source string, ref entity.TransactionRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Group each user's outgoing and incoming items; a user can be on both sides of a trade
+	sends := make(map[int][]entity.RewardItem)
+	receives := make(map[int][]entity.RewardItem)
+	for _, transfer := range transfers {
+		for _, item := range transfer.Items {
+			if _, exists := r.items[item.ItemID]; !exists {
+				return fmt.Errorf("item with id %d not found", item.ItemID)
+			}
+		}
+		sends[transfer.FromUserID] = append(sends[transfer.FromUserID], transfer.Items...)
+		receives[transfer.ToUserID] = append(receives[transfer.ToUserID], transfer.Items...)
+	}
+
+	// Check every removal and every receiver's room before moving anything
+	for userID, items := range sends {
+		sends[userID] = entity.MergeRewardItems(items)
+		for _, item := range sends[userID] {
+			if err := r.checkAvailableLocked(userID, item.ItemID, item.Count); err != nil {
+				return err
+			}
+		}
+	}
+	for userID, items := range receives {
+		fits := r.planGrantLocked(userID, sends[userID], items)
+		for i, item := range items {
+			if fits[i] < item.Count {
+				return fmt.Errorf("%w: only %d of %d units of item %d fit for user %d", ErrCapacityExceeded, fits[i], item.Count, item.ItemID, userID)
+			}
+		}
+	}
+
+	for _, transfer := range transfers {
+		for _, item := range transfer.Items {
+			r.moveLocked(transfer.FromUserID, transfer.ToUserID, item.ItemID, item.Count, source, ref)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) PurgeExpired(now time.Time, source string, ref entity.TransactionRef) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type holding struct {
+		userID int
+		itemID int
+	}
+	purged := make(map[holding]int)
+
+	for key, inventory := range r.inventories {
+		if !inventory.IsExpired(now) {
+			continue
+		}
+		if inventory.Count > 0 {
+			purged[holding{inventory.UserID, inventory.ItemID}] += inventory.Count
+		}
+		delete(r.inventories, key)
+	}
+	for id, instance := range r.instances {
+		if !instance.IsExpired(now) {
+			continue
+		}
+		purged[holding{instance.UserID, instance.ItemID}]++
+		delete(r.instances, id)
+	}
+
+	total := 0
+	for h, count := range purged {
+		r.recordTransactionLocked(h.userID, h.itemID, -count, r.balanceLocked(h.userID, h.itemID, now), source, ref)
+		total += count
+	}
+	return total, nil
+}
+
+// stackKey identifies an inventory stack; units with different expiries are kept in separate stacks
+func stackKey(userID, itemID int, expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return fmt.Sprintf("%d:%d", userID, itemID)
+	}
+	return fmt.Sprintf("%d:%d:%d", userID, itemID, expiresAt.Unix())
+}
+
+// stacksLocked returns a user's unexpired stacks of an item, soonest-expiring first
+// and the non-expiring stack last. Caller must hold the lock.
+func (r *memoryRepository) stacksLocked(userID, itemID int, now time.Time) []*entity.UserInventory {
+	var stacks []*entity.UserInventory
+	for _, inventory := range r.inventories {
+		if inventory.UserID == userID && inventory.ItemID == itemID && !inventory.IsExpired(now) {
+			stacks = append(stacks, inventory)
+		}
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		return expiresBefore(stacks[i].ExpiresAt, stacks[j].ExpiresAt)
+	})
+	return stacks
+}
+
+// expiresBefore orders expiry times soonest first, treating nil (never) as latest
+func expiresBefore(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+	return a.Before(*b)
+}
+
+// balanceLocked returns the user's unexpired holding of an item; caller must hold the lock
+func (r *memoryRepository) balanceLocked(userID, itemID int, now time.Time) int {
+	if item, exists := r.items[itemID]; exists && item.Type.IsInstanced() {
+		return r.countInstancesLocked(userID, itemID)
+	}
+
+	balance := 0
+	for _, stack := range r.stacksLocked(userID, itemID, now) {
+		balance += stack.Count
+	}
+	return balance
+}
+
+// grantLocked adds count units of item to a user's inventory and records the ledger entry.
+// Instanced item types get one ItemInstance per unit; others are stacked by expiry.
+// A nil expiresAt falls back to the item's default lifetime. Caller must hold the write lock.
+func (r *memoryRepository) grantLocked(userID int, item *entity.Item, count int, expiresAt *time.Time, source string, ref entity.TransactionRef) {
+	now := time.Now()
+	if expiresAt == nil {
+		expiresAt = item.DefaultExpiry(now)
+	}
+
+	if item.Type.IsInstanced() {
+		level := item.Value
+		if level <= 0 {
+			level = 1
+		}
+		for i := 0; i < count; i++ {
+			r.instCounter++
+			r.instances[r.instCounter] = &entity.ItemInstance{
+				ID:         r.instCounter,
 				UserID:     userID,
-				ItemID:     item.ItemID,
-				Count:      item.Count,
-				AcquiredAt: time.Now(),
+				ItemID:     item.ID,
+				Level:      level,
 				Source:     source,
-				UpdatedAt:  time.Now(),
+				ExpiresAt:  expiresAt,
+				AcquiredAt: now,
+				UpdatedAt:  now,
 			}
 		}
+		r.recordTransactionLocked(userID, item.ID, count, r.countInstancesLocked(userID, item.ID), source, ref)
+		return
+	}
+
+	r.addToStackLocked(userID, item.ID, count, expiresAt, source, now)
+	r.recordTransactionLocked(userID, item.ID, count, r.balanceLocked(userID, item.ID, now), source, ref)
+}
+
+// addToStackLocked adds units to the user's stack with the given expiry, creating it if needed.
+// It writes no ledger entry. Caller must hold the write lock.
+func (r *memoryRepository) addToStackLocked(userID, itemID int, count int, expiresAt *time.Time, source string, now time.Time) {
+	key := stackKey(userID, itemID, expiresAt)
+	if inventory, exists := r.inventories[key]; exists {
+		inventory.Count += count
+		inventory.UpdatedAt = now
+		return
+	}
+
+	r.invCounter++
+	r.inventories[key] = &entity.UserInventory{
+		ID:         r.invCounter,
+		UserID:     userID,
+		ItemID:     itemID,
+		Count:      count,
+		AcquiredAt: now,
+		Source:     source,
+		ExpiresAt:  expiresAt,
+		UpdatedAt:  now,
+	}
+}
+
+// checkAvailableLocked verifies a user holds at least count removable units of an item.
+// Locked and expired units are not removable. Caller must hold the lock.
+func (r *memoryRepository) checkAvailableLocked(userID, itemID int, count int) error {
+	if item, exists := r.items[itemID]; exists && item.Type.IsInstanced() {
+		available := len(r.removableInstancesLocked(userID, itemID))
+		if available == 0 {
+			return fmt.Errorf("%w for user %d, item %d", ErrInventoryNotFound, userID, itemID)
+		}
+		if available < count {
+			return fmt.Errorf("%w: have %d unlocked, trying to remove %d", ErrInsufficientCount, available, count)
+		}
+		return nil
 	}
 
+	now := time.Now()
+	if len(r.stacksLocked(userID, itemID, now)) == 0 {
+		return fmt.Errorf("%w for user %d, item %d", ErrInventoryNotFound, userID, itemID)
+	}
+	if available := r.balanceLocked(userID, itemID, now); available < count {
+		return fmt.Errorf("%w: have %d, trying to remove %d", ErrInsufficientCount, available, count)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// removeLocked deducts count units, soonest-expiring first, and records the ledger entry.
+// It returns the stackable units taken per stack (nil for instanced items);
+// callers must run checkAvailableLocked first and hold the write lock.
+func (r *memoryRepository) removeLocked(userID, itemID int, count int, source string, ref entity.TransactionRef) []entity.RewardItem {
+	if item, exists := r.items[itemID]; exists && item.Type.IsInstanced() {
+		for _, instance := range r.removableInstancesLocked(userID, itemID)[:count] {
+			delete(r.instances, instance.ID)
+		}
+		r.recordTransactionLocked(userID, itemID, -count, r.countInstancesLocked(userID, itemID), source, ref)
+		return nil
+	}
+
+	now := time.Now()
+	remaining := count
+	var removed []entity.RewardItem
+	for _, stack := range r.stacksLocked(userID, itemID, now) {
+		if remaining == 0 {
+			break
+		}
+		taken := stack.Count
+		if taken > remaining {
+			taken = remaining
+		}
+		stack.Count -= taken
+		stack.UpdatedAt = now
+		remaining -= taken
+		removed = append(removed, entity.RewardItem{ItemID: itemID, Count: taken, ExpiresAt: stack.ExpiresAt})
+	}
+	r.recordTransactionLocked(userID, itemID, -count, r.balanceLocked(userID, itemID, now), source, ref)
+	return removed
+}
+
+// moveLocked hands count units of an item from one user to another, soonest-expiring first,
+// and records a ledger entry on both sides. Stacks keep their expiry on the receiving side and
+// instances change owner as they are. Callers must run checkAvailableLocked first and hold the write lock.
+func (r *memoryRepository) moveLocked(fromUserID, toUserID, itemID int, count int, source string, ref entity.TransactionRef) {
+	now := time.Now()
+	if item, exists := r.items[itemID]; exists && item.Type.IsInstanced() {
+		for _, instance := range r.removableInstancesLocked(fromUserID, itemID)[:count] {
+			instance.UserID = toUserID
+			instance.Source = source
+			instance.AcquiredAt = now
+			instance.UpdatedAt = now
+		}
+	} else {
+		remaining := count
+		for _, stack := range r.stacksLocked(fromUserID, itemID, now) {
+			if remaining == 0 {
+				break
+			}
+			taken := min(stack.Count, remaining)
+			stack.Count -= taken
+			stack.UpdatedAt = now
+			remaining -= taken
+			r.addToStackLocked(toUserID, itemID, taken, stack.ExpiresAt, source, now)
+		}
+	}
+
+	r.recordTransactionLocked(fromUserID, itemID, -count, r.balanceLocked(fromUserID, itemID, now), source, ref)
+	r.recordTransactionLocked(toUserID, itemID, count, r.balanceLocked(toUserID, itemID, now), source, ref)
+}
+
+// removableInstancesLocked returns a user's unlocked, unexpired instances of an item:
+// soonest-expiring first, then lowest level and oldest
+func (r *memoryRepository) removableInstancesLocked(userID, itemID int) []*entity.ItemInstance {
+	now := time.Now()
+	var instances []*entity.ItemInstance
+	for _, instance := range r.instances {
+		if instance.UserID == userID && instance.ItemID == itemID && !instance.Locked && !instance.IsExpired(now) {
+			instances = append(instances, instance)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if expiresBefore(a.ExpiresAt, b.ExpiresAt) {
+			return true
+		}
+		if expiresBefore(b.ExpiresAt, a.ExpiresAt) {
+			return false
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		return a.ID < b.ID
+	})
+	return instances
+}
+
+func (r *memoryRepository) countInstancesLocked(userID, itemID int) int {
+	now := time.Now()
+	count := 0
+	for _, instance := range r.instances {
+		if instance.UserID == userID && instance.ItemID == itemID && !instance.IsExpired(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// recordTransactionLocked appends a ledger entry; caller must hold the write lock
+func (r *memoryRepository) recordTransactionLocked(userID, itemID int, delta, balanceAfter int, source string, ref entity.TransactionRef) {
+	r.txCounter++
+	r.transactions = append(r.transactions, &entity.InventoryTransaction{
+		ID:            r.txCounter,
+		UserID:        userID,
+		ItemID:        itemID,
+		Delta:         delta,
+		BalanceAfter:  balanceAfter,
+		Source:        source,
+		ReferenceType: ref.Type,
+		ReferenceID:   ref.ID,
+		Actor:         ref.Actor,
+		CreatedAt:     time.Now(),
+	})
+}
+
+// CapacityRepository implementation
+func (r *memoryRepository) GetSlotCapacity(userID int) (int, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.slotCapacityLocked(userID), r.usedSlotsLocked(userID, time.Now()), nil
+}
+
+func (r *memoryRepository) SetSlotCapacity(userID, capacity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.capacities[userID] = capacity
+	return nil
+}
+
+func (r *memoryRepository) slotCapacityLocked(userID int) int {
+	if capacity, exists := r.capacities[userID]; exists {
+		return capacity
+	}
+	return entity.DefaultSlotCapacity
+}
+
+// usedSlotsLocked counts one slot per held stackable item and one per instance;
+// currencies and expired units take no slots. Caller must hold the lock.
+func (r *memoryRepository) usedSlotsLocked(userID int, now time.Time) int {
+	stacked := make(map[int]bool)
+	for _, inventory := range r.inventories {
+		if inventory.UserID != userID || inventory.Count <= 0 || inventory.IsExpired(now) {
+			continue
+		}
+		if item, exists := r.items[inventory.ItemID]; exists && item.Type.UsesSlot() {
+			stacked[inventory.ItemID] = true
+		}
+	}
+
+	used := len(stacked)
+	for _, instance := range r.instances {
+		if instance.UserID != userID || instance.IsExpired(now) {
+			continue
+		}
+		if item, exists := r.items[instance.ItemID]; exists && item.Type.UsesSlot() {
+			used++
+		}
+	}
+	return used
+}
+
+// slotsFor returns how many slots holding count units of an item takes
+func slotsFor(item *entity.Item, count int) int {
+	if count <= 0 || !item.Type.UsesSlot() {
+		return 0
+	}
+	if item.Type.IsInstanced() {
+		return count
+	}
+	return 1
+}
+
+// planGrantLocked works out how many units of each entry in add fit under the items' stack
+// caps and the user's free slots, once the (already checked) removals are applied.
+// Entries are filled in order. Caller must hold the lock.
+func (r *memoryRepository) planGrantLocked(userID int, remove, add []entity.RewardItem) []int {
+	now := time.Now()
+	held := make(map[int]int)
+	holding := func(itemID int) int {
+		if count, exists := held[itemID]; exists {
+			return count
+		}
+		held[itemID] = r.balanceLocked(userID, itemID, now)
+		return held[itemID]
+	}
+
+	free := r.slotCapacityLocked(userID) - r.usedSlotsLocked(userID, now)
+	for _, item := range remove {
+		definition := r.items[item.ItemID]
+		before := holding(item.ItemID)
+		held[item.ItemID] = before - item.Count
+		free += slotsFor(definition, before) - slotsFor(definition, held[item.ItemID])
+	}
+
+	fits := make([]int, len(add))
+	for i, item := range add {
+		definition := r.items[item.ItemID]
+		before := holding(item.ItemID)
+
+		count := min(item.Count, definition.StackLimit()-before)
+		if slotsFor(definition, before+count)-slotsFor(definition, before) > free {
+			// Instances need a slot each; a new stack needs one slot for all its units
+			if definition.Type.IsInstanced() {
+				count = free
+			} else {
+				count = 0
+			}
+		}
+		count = max(count, 0)
+
+		held[item.ItemID] = before + count
+		free -= slotsFor(definition, before+count) - slotsFor(definition, before)
+		fits[i] = count
+	}
+	return fits
+}
+
+// InstanceRepository implementation
+func (r *memoryRepository) GetUserInstances(userID int) ([]*entity.ItemInstance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var instances []*entity.ItemInstance
+	for _, instance := range r.instances {
+		if instance.UserID == userID && !instance.IsExpired(now) {
+			instances = append(instances, instance)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+	return instances, nil
+}
+
+func (r *memoryRepository) GetInstance(instanceID int) (*entity.ItemInstance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	instance, exists := r.instances[instanceID]
+	if !exists || instance.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("%w: id %d", ErrInstanceNotFound, instanceID)
+	}
+	return instance, nil
+}
+
+func (r *memoryRepository) UpdateInstance(instance *entity.ItemInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.instances[instance.ID]; !exists {
+		return fmt.Errorf("%w: id %d", ErrInstanceNotFound, instance.ID)
+	}
+
+	instance.UpdatedAt = time.Now()
+	r.instances[instance.ID] = instance
+	return nil
+}
+
+func (r *memoryRepository) ChangeInstanceLevel(change entity.InstanceLevelChange, costs []entity.RewardItem, source string, ref entity.TransactionRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	instance, exists := r.instances[change.InstanceID]
+	if !exists || instance.UserID != change.UserID || instance.IsExpired(time.Now()) {
+		return fmt.Errorf("%w: id %d", ErrInstanceNotFound, change.InstanceID)
+	}
+	if instance.Level != change.FromLevel {
+		return fmt.Errorf("%w: expected level %d, found %d", ErrInstanceChanged, change.FromLevel, instance.Level)
+	}
+
+	// Check every cost before deducting anything
+	costs = entity.MergeRewardItems(costs)
+	for _, cost := range costs {
+		if _, exists := r.items[cost.ItemID]; !exists {
+			return fmt.Errorf("item with id %d not found", cost.ItemID)
+		}
+		if err := r.checkAvailableLocked(change.UserID, cost.ItemID, cost.Count); err != nil {
+			return err
+		}
+	}
+
+	for _, cost := range costs {
+		r.removeLocked(change.UserID, cost.ItemID, cost.Count, source, ref)
+	}
+
+	if change.Destroy {
+		delete(r.instances, instance.ID)
+		r.recordTransactionLocked(change.UserID, instance.ItemID, -1, r.countInstancesLocked(change.UserID, instance.ItemID), source, ref)
+		return nil
+	}
+
+	instance.Level = change.ToLevel
+	instance.UpdatedAt = time.Now()
+	return nil
+}
+
+// TransactionRepository implementation
+func (r *memoryRepository) GetInventoryTransactions(filter TransactionFilter) ([]*entity.InventoryTransaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var transactions []*entity.InventoryTransaction
+	for _, tx := range r.transactions {
+		if filter.UserID != 0 && tx.UserID != filter.UserID {
+			continue
+		}
+		if filter.ItemID != 0 && tx.ItemID != filter.ItemID {
+			continue
+		}
+		if filter.Source != "" && tx.Source != filter.Source {
+			continue
+		}
+		if filter.ReferenceType != "" && tx.ReferenceType != filter.ReferenceType {
+			continue
+		}
+		if filter.ReferenceID != "" && tx.ReferenceID != filter.ReferenceID {
+			continue
+		}
+		if !filter.From.IsZero() && tx.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !tx.CreatedAt.Before(filter.To) {
+			continue
+		}
+		transactions = append(transactions, tx)
+	}
+
+	// Newest first
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].ID > transactions[j].ID
+	})
+
+	if filter.Limit > 0 && len(transactions) > filter.Limit {
+		transactions = transactions[:filter.Limit]
+	}
+	return transactions, nil
+}
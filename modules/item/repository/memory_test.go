@@ -0,0 +1,565 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/item/entity"
+	"fxserver/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventoryTransactionLedger(t *testing.T) {
+	repo := NewMemoryRepository()
+	ref := entity.TransactionRef{Type: "coupon", ID: "7", Actor: entity.UserActor(1)}
+
+	require.NoError(t, repo.AddToInventory(1, 1, 100, "coupon", ref))
+	_, err := repo.AddMultipleToInventory(1, []entity.RewardItem{
+		{ItemID: 1, Count: 50},
+		{ItemID: 2, Count: 3},
+	}, entity.OverflowReject, "reward", entity.TransactionRef{Type: "reward_grant", Actor: entity.AdminActor(9)})
+	require.NoError(t, err)
+	require.NoError(t, repo.RemoveFromInventory(1, 1, 30, "shop", entity.TransactionRef{}))
+	require.NoError(t, repo.UpdateInventoryCount(1, 2, 1, "admin", entity.TransactionRef{}))
+
+	// Failed removals must not leave ledger entries behind
+	assert.Error(t, repo.RemoveFromInventory(1, 2, 5, "shop", entity.TransactionRef{}))
+
+	transactions, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1})
+	require.NoError(t, err)
+	require.Len(t, transactions, 5)
+
+	// Newest first
+	assert.Equal(t, -2, transactions[0].Delta)
+	assert.Equal(t, 1, transactions[0].BalanceAfter)
+	assert.Equal(t, -30, transactions[1].Delta)
+	assert.Equal(t, 120, transactions[1].BalanceAfter)
+	assert.Equal(t, "coupon", transactions[4].ReferenceType)
+	assert.Equal(t, "7", transactions[4].ReferenceID)
+	assert.Equal(t, "user:1", transactions[4].Actor)
+
+	gold, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, ItemID: 1, Source: "reward"})
+	require.NoError(t, err)
+	require.Len(t, gold, 1)
+	assert.Equal(t, 150, gold[0].BalanceAfter)
+	assert.Equal(t, "admin:9", gold[0].Actor)
+
+	limited, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, limited, 2)
+
+	other, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 2})
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestInstancedItemGrantsAndRemovals(t *testing.T) {
+	repo := NewMemoryRepository()
+	const swordID = 4 // 전설의 검 (equipment)
+
+	_, err := repo.AddMultipleToInventory(1, []entity.RewardItem{
+		{ItemID: swordID, Count: 3},
+		{ItemID: 1, Count: 10},
+	}, entity.OverflowReject, "reward", entity.TransactionRef{})
+	require.NoError(t, err)
+
+	instances, err := repo.GetUserInstances(1)
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+	assert.Equal(t, 1, instances[0].Level)
+
+	// Equipment never creates a stack
+	_, err = repo.GetUserInventoryItem(1, swordID)
+	assert.ErrorIs(t, err, ErrInventoryNotFound)
+	assert.ErrorIs(t, repo.UpdateInventoryCount(1, swordID, 5, "admin", entity.TransactionRef{}), ErrInstancedItem)
+
+	// Locked and higher-level instances are kept when removing by count
+	instances[0].Locked = true
+	require.NoError(t, repo.UpdateInstance(instances[0]))
+	instances[1].Level = 5
+	require.NoError(t, repo.UpdateInstance(instances[1]))
+
+	assert.ErrorIs(t, repo.RemoveFromInventory(1, swordID, 3, "admin", entity.TransactionRef{}), ErrInsufficientCount)
+	require.NoError(t, repo.RemoveFromInventory(1, swordID, 1, "admin", entity.TransactionRef{}))
+
+	remaining, err := repo.GetUserInstances(1)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, instances[0].ID, remaining[0].ID)
+	assert.Equal(t, instances[1].ID, remaining[1].ID)
+
+	transactions, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, ItemID: swordID})
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, -1, transactions[0].Delta)
+	assert.Equal(t, 2, transactions[0].BalanceAfter)
+}
+
+func TestExchangeItemsIsAllOrNothing(t *testing.T) {
+	repo := NewMemoryRepository()
+	const (
+		goldID    = 1
+		diamondID = 2
+		potionID  = 3
+	)
+	ref := entity.TransactionRef{Type: "bundle_open", ID: "3", Actor: entity.UserActor(1)}
+
+	require.NoError(t, repo.AddToInventory(1, potionID, 2, "admin", entity.TransactionRef{}))
+	require.NoError(t, repo.AddToInventory(1, goldID, 5, "admin", entity.TransactionRef{}))
+
+	// Gold is short, so the potions must not be removed either
+	err := repo.ExchangeItems(1,
+		[]entity.RewardItem{{ItemID: potionID, Count: 1}, {ItemID: goldID, Count: 10}},
+		[]entity.RewardItem{{ItemID: diamondID, Count: 1}},
+		"bundle_open", ref)
+	assert.ErrorIs(t, err, ErrInsufficientCount)
+
+	potions, err := repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, potions.Count)
+	_, err = repo.GetUserInventoryItem(1, diamondID)
+	assert.ErrorIs(t, err, ErrInventoryNotFound)
+
+	require.NoError(t, repo.ExchangeItems(1,
+		[]entity.RewardItem{{ItemID: potionID, Count: 1}, {ItemID: potionID, Count: 1}},
+		[]entity.RewardItem{{ItemID: diamondID, Count: 1}, {ItemID: goldID, Count: 5}},
+		"bundle_open", ref))
+
+	potions, err = repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, potions.Count)
+	gold, err := repo.GetUserInventoryItem(1, goldID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, gold.Count)
+
+	transactions, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, Source: "bundle_open"})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 3)
+}
+
+func TestApplyInventoryChanges(t *testing.T) {
+	repo := NewMemoryRepository()
+	const (
+		goldID   = 1
+		potionID = 3
+	)
+	ref := entity.TransactionRef{Type: "refund", ID: "7", Actor: entity.AdminActor(1)}
+
+	require.NoError(t, repo.AddToInventory(1, goldID, 100, "admin", entity.TransactionRef{}))
+	require.NoError(t, repo.AddToInventory(1, potionID, 3, "admin", entity.TransactionRef{}))
+
+	// Two removals of the same item are checked together, so 2+2 potions fail against a balance of 3
+	err := repo.ApplyInventoryChanges(1, []entity.InventoryDelta{
+		{ItemID: goldID, Count: -50},
+		{ItemID: potionID, Count: -2},
+		{ItemID: potionID, Count: -2},
+	}, "refund", ref)
+	assert.ErrorIs(t, err, ErrInsufficientCount)
+	assert.Error(t, repo.ApplyInventoryChanges(1, []entity.InventoryDelta{{ItemID: goldID, Count: 0}}, "refund", ref))
+
+	gold, err := repo.GetUserInventoryItem(1, goldID)
+	require.NoError(t, err)
+	assert.Equal(t, 100, gold.Count)
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, repo.ApplyInventoryChanges(1, []entity.InventoryDelta{
+		{ItemID: goldID, Count: -50},
+		{ItemID: potionID, Count: -3},
+		{ItemID: potionID, Count: 1, ExpiresAt: &expiresAt},
+	}, "refund", ref))
+
+	gold, err = repo.GetUserInventoryItem(1, goldID)
+	require.NoError(t, err)
+	assert.Equal(t, 50, gold.Count)
+	potions, err := repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, potions.Count)
+
+	transactions, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, ReferenceType: "refund"})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 3)
+}
+
+func TestExpiringStacks(t *testing.T) {
+	repo := NewMemoryRepository()
+	const (
+		potionID = 3
+		swordID  = 4
+	)
+	now := time.Now()
+	soon := now.Add(time.Hour)
+	later := now.Add(2 * time.Hour)
+	past := now.Add(-time.Minute)
+
+	_, err := repo.AddMultipleToInventory(1, []entity.RewardItem{
+		{ItemID: potionID, Count: 5},
+		{ItemID: potionID, Count: 2, ExpiresAt: &later},
+		{ItemID: potionID, Count: 3, ExpiresAt: &soon},
+		{ItemID: potionID, Count: 4, ExpiresAt: &past},
+		{ItemID: swordID, Count: 1, ExpiresAt: &past},
+	}, entity.OverflowReject, "event", entity.TransactionRef{})
+	require.NoError(t, err)
+
+	// Expired stacks and instances are hidden
+	stacks, err := repo.GetUserInventory(1)
+	require.NoError(t, err)
+	assert.Len(t, stacks, 3)
+	potions, err := repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, potions.Count)
+	assert.Equal(t, soon.Unix(), potions.ExpiresAt.Unix())
+	instances, err := repo.GetUserInstances(1)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+
+	// Expired units cannot be spent and the soonest-expiring stack is consumed first
+	assert.ErrorIs(t, repo.RemoveFromInventory(1, potionID, 11, "shop", entity.TransactionRef{}), ErrInsufficientCount)
+	require.NoError(t, repo.RemoveFromInventory(1, potionID, 4, "shop", entity.TransactionRef{}))
+	stacks, err = repo.GetUserInventory(1)
+	require.NoError(t, err)
+	remaining := make(map[int64]int)
+	for _, stack := range stacks {
+		var expiry int64
+		if stack.ExpiresAt != nil {
+			expiry = stack.ExpiresAt.Unix()
+		}
+		remaining[expiry] = stack.Count
+	}
+	assert.Equal(t, map[int64]int{0: 5, later.Unix(): 1}, remaining)
+
+	purged, err := repo.PurgeExpired(time.Now(), "expired", entity.TransactionRef{Type: "expired", Actor: entity.ActorSystem})
+	require.NoError(t, err)
+	assert.Equal(t, 5, purged)
+
+	transactions, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, ItemID: potionID, Source: "expired"})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, -4, transactions[0].Delta)
+	assert.Equal(t, 6, transactions[0].BalanceAfter)
+
+	swords, err := repo.GetInventoryTransactions(TransactionFilter{UserID: 1, ItemID: swordID, Source: "expired"})
+	require.NoError(t, err)
+	require.Len(t, swords, 1)
+	assert.Equal(t, 0, swords[0].BalanceAfter)
+
+	// Nothing left to purge
+	purged, err = repo.PurgeExpired(time.Now(), "expired", entity.TransactionRef{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, purged)
+}
+
+func TestCapacityAndOverflowPolicies(t *testing.T) {
+	repo := NewMemoryRepository()
+	const (
+		goldID   = 1 // 골드 (currency, no slot)
+		potionID = 3 // 체력 포션 (consumable)
+		swordID  = 4 // 전설의 검 (equipment, one slot per instance)
+		ticketID = 5 // 던전 입장권 (ticket)
+	)
+
+	potion, err := repo.GetItem(potionID)
+	require.NoError(t, err)
+	potion.MaxStack = 10
+	require.NoError(t, repo.UpdateItem(potion))
+	require.NoError(t, repo.SetSlotCapacity(1, 2))
+
+	// Currency takes no slot; the potion stack takes one and is capped at 10
+	require.NoError(t, repo.AddToInventory(1, goldID, 1000, "admin", entity.TransactionRef{}))
+	require.NoError(t, repo.AddToInventory(1, potionID, 8, "admin", entity.TransactionRef{}))
+	assert.ErrorIs(t, repo.AddToInventory(1, potionID, 3, "admin", entity.TransactionRef{}), ErrCapacityExceeded)
+
+	capacity, used, err := repo.GetSlotCapacity(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, capacity)
+	assert.Equal(t, 1, used)
+
+	grant := []entity.RewardItem{
+		{ItemID: potionID, Count: 5},
+		{ItemID: swordID, Count: 2},
+	}
+
+	// Reject changes nothing
+	_, err = repo.AddMultipleToInventory(1, grant, entity.OverflowReject, "event", entity.TransactionRef{})
+	assert.ErrorIs(t, err, ErrCapacityExceeded)
+	potions, err := repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 8, potions.Count)
+
+	// Truncate fills the stack to its cap and the one free slot with a sword
+	result, err := repo.AddMultipleToInventory(1, grant, entity.OverflowTruncate, "event", entity.TransactionRef{})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.RewardItem{{ItemID: potionID, Count: 2}, {ItemID: swordID, Count: 1}}, result.Granted)
+	assert.Equal(t, []entity.RewardItem{{ItemID: potionID, Count: 3}, {ItemID: swordID, Count: 1}}, result.Overflow)
+
+	// Mailbox grants nothing that does not fit and hands the excess back for mailing
+	result, err = repo.AddMultipleToInventory(1, []entity.RewardItem{{ItemID: ticketID, Count: 4}}, entity.OverflowMailbox, "event", entity.TransactionRef{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Granted)
+	assert.Equal(t, []entity.RewardItem{{ItemID: ticketID, Count: 4}}, result.Overflow)
+
+	// Exchanging the last potions for tickets frees the potion slot first
+	require.NoError(t, repo.ExchangeItems(1,
+		[]entity.RewardItem{{ItemID: potionID, Count: 10}},
+		[]entity.RewardItem{{ItemID: ticketID, Count: 1}}, "shop", entity.TransactionRef{}))
+	tickets, err := repo.GetUserInventoryItem(1, ticketID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tickets.Count)
+}
+
+func TestTransferItems(t *testing.T) {
+	repo := NewMemoryRepository()
+	const (
+		potionID = 3 // 체력 포션
+		swordID  = 4 // 전설의 검
+	)
+
+	soon := time.Now().Add(time.Hour).Truncate(time.Second)
+	_, err := repo.AddMultipleToInventory(1, []entity.RewardItem{
+		{ItemID: potionID, Count: 3, ExpiresAt: &soon},
+		{ItemID: potionID, Count: 5},
+	}, entity.OverflowReject, "event", entity.TransactionRef{})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddToInventory(2, swordID, 1, "admin", entity.TransactionRef{}))
+
+	// The soonest-expiring potions move first and keep their expiry
+	transfers := []entity.ItemTransfer{
+		{FromUserID: 1, ToUserID: 2, Items: []entity.RewardItem{{ItemID: potionID, Count: 4}}},
+		{FromUserID: 2, ToUserID: 1, Items: []entity.RewardItem{{ItemID: swordID, Count: 1}}},
+	}
+	require.NoError(t, repo.TransferItems(transfers, "trade", entity.TransactionRef{Type: "trade_offer", ID: "1"}))
+
+	received, err := repo.GetUserInventory(2)
+	require.NoError(t, err)
+	expiring := 0
+	for _, stack := range received {
+		if stack.ItemID == potionID && stack.ExpiresAt != nil {
+			assert.True(t, stack.ExpiresAt.Equal(soon))
+			expiring += stack.Count
+		}
+	}
+	assert.Equal(t, 3, expiring)
+	kept, err := repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, kept.Count)
+	swords, err := repo.GetUserInstances(1)
+	require.NoError(t, err)
+	assert.Len(t, swords, 1)
+
+	entries, err := repo.GetInventoryTransactions(TransactionFilter{Source: "trade"})
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	// Nothing moves when a receiver has no room or a sender is short
+	require.NoError(t, repo.SetSlotCapacity(2, 1))
+	err = repo.TransferItems([]entity.ItemTransfer{
+		{FromUserID: 1, ToUserID: 2, Items: []entity.RewardItem{{ItemID: swordID, Count: 1}}},
+	}, "gift", entity.TransactionRef{})
+	assert.ErrorIs(t, err, ErrCapacityExceeded)
+	err = repo.TransferItems([]entity.ItemTransfer{
+		{FromUserID: 1, ToUserID: 3, Items: []entity.RewardItem{{ItemID: potionID, Count: 5}}},
+	}, "gift", entity.TransactionRef{})
+	assert.ErrorIs(t, err, ErrInsufficientCount)
+	swords, err = repo.GetUserInstances(1)
+	require.NoError(t, err)
+	assert.Len(t, swords, 1)
+}
+
+func TestArchiveAndRestoreItem(t *testing.T) {
+	repo := NewMemoryRepository()
+	const potionID = 3 // 체력 포션
+
+	require.NoError(t, repo.AddToInventory(1, potionID, 5, "admin", entity.TransactionRef{}))
+	require.NoError(t, repo.DeleteItem(potionID))
+
+	// Archived items still resolve for existing holdings
+	item, err := repo.GetItem(potionID)
+	require.NoError(t, err)
+	assert.True(t, item.IsArchived())
+	assert.False(t, item.IsActive)
+	potions, err := repo.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, potions.Count)
+
+	// ...but are only listed on request
+	items, err := repo.GetItems()
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+	consumables, err := repo.GetItemsByType(entity.ItemTypeConsumable)
+	require.NoError(t, err)
+	assert.Empty(t, consumables)
+	all, err := repo.ListItems(ItemFilter{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Len(t, all.Items, 5)
+	archived, err := repo.ListItems(ItemFilter{Types: []entity.ItemType{entity.ItemTypeConsumable}, IncludeArchived: true})
+	require.NoError(t, err)
+	require.Len(t, archived.Items, 1)
+	assert.Equal(t, potionID, archived.Items[0].ID)
+
+	require.NoError(t, repo.RestoreItem(potionID))
+	item, err = repo.GetItem(potionID)
+	require.NoError(t, err)
+	assert.False(t, item.IsArchived())
+	assert.True(t, item.IsActive)
+	assert.ErrorIs(t, repo.RestoreItem(potionID), ErrItemNotArchived)
+}
+
+func TestLocalizedItemResponse(t *testing.T) {
+	repo := NewMemoryRepository()
+	const potionID = 3 // 체력 포션
+
+	item, err := repo.GetItem(potionID)
+	require.NoError(t, err)
+	assert.Equal(t, "체력 포션", item.ToResponse(i18n.Korean).Name)
+	assert.Equal(t, "Health Potion", item.ToResponse(i18n.English).Name)
+	assert.Equal(t, "体力ポーション", item.ToResponse(i18n.Japanese).Name)
+
+	// Missing translations fall back to the base fields
+	item.Translations = map[i18n.Locale]entity.ItemTranslation{
+		i18n.English: {Name: "Health Potion"},
+	}
+	require.NoError(t, repo.UpdateItem(item))
+	item, err = repo.GetItem(potionID)
+	require.NoError(t, err)
+	response := item.ToResponse(i18n.English)
+	assert.Equal(t, "Health Potion", response.Name)
+	assert.Equal(t, item.Description, response.Description)
+	assert.Equal(t, "체력 포션", item.ToResponse(i18n.Japanese).Name)
+}
+
+func TestListItemsSearchAndCursor(t *testing.T) {
+	repo := NewMemoryRepository()
+	for _, name := range []string{"Potion of Speed", "Mega Potion", "Iron Sword"} {
+		require.NoError(t, repo.CreateItem(&entity.Item{Name: name, Type: entity.ItemTypeConsumable, Rarity: "rare", IsActive: true}))
+	}
+
+	// The seeded 체력 포션 matches through its English name
+	search, err := repo.ListItems(ItemFilter{Query: "POTION"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, search.Total)
+
+	filtered, err := repo.ListItems(ItemFilter{
+		Types:    []entity.ItemType{entity.ItemTypeConsumable, entity.ItemTypeEquipment},
+		Rarities: []string{"common", "legendary"},
+	})
+	require.NoError(t, err)
+	require.Len(t, filtered.Items, 2)
+	assert.Equal(t, 3, filtered.Items[0].ID) // 체력 포션
+	assert.Equal(t, 4, filtered.Items[1].ID) // 전설의 검
+
+	// Walking the pages by rarity (descending) visits every item once in a stable order
+	filter := ItemFilter{SortBy: ItemSortRarity, Descending: true, Limit: 3}
+	var ids []int
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5)
+		page, err := repo.ListItems(filter)
+		require.NoError(t, err)
+		assert.Equal(t, 8, page.Total)
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	assert.Equal(t, []int{4, 2, 8, 7, 6, 5, 3, 1}, ids)
+
+	// A cursor only continues the sort it came from
+	first, err := repo.ListItems(ItemFilter{SortBy: ItemSortName, Limit: 1})
+	require.NoError(t, err)
+	_, err = repo.ListItems(ItemFilter{SortBy: ItemSortID, Cursor: first.NextCursor})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = repo.ListItems(ItemFilter{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestListItemsLocalized(t *testing.T) {
+	repo := NewMemoryRepository()
+	visible := true
+	seeded := ItemFilter{IsActive: &visible, Types: []entity.ItemType{entity.ItemTypeCurrency, entity.ItemTypeConsumable}}
+
+	// Any translation matches the query
+	for _, query := range []string{"포션", "health", "ポーション"} {
+		filter := seeded
+		filter.Query = query
+		page, err := repo.ListItems(filter)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1, query)
+		assert.Equal(t, 3, page.Items[0].ID, query)
+	}
+
+	// Names sort in the requested language, falling back to the Korean name
+	names := func(locale i18n.Locale) []int {
+		filter := seeded
+		filter.SortBy = ItemSortName
+		filter.Locale = locale
+		page, err := repo.ListItems(filter)
+		require.NoError(t, err)
+		ids := make([]int, len(page.Items))
+		for i, item := range page.Items {
+			ids[i] = item.ID
+		}
+		return ids
+	}
+	assert.Equal(t, []int{1, 2, 3}, names(i18n.Korean))   // 골드, 다이아몬드, 체력 포션
+	assert.Equal(t, []int{2, 1, 3}, names(i18n.English))  // Diamond, Gold, Health Potion
+	assert.Equal(t, []int{1, 2, 3}, names(i18n.Japanese)) // ゴールド, ダイヤモンド, 体力ポーション
+
+	// A name cursor only continues in the language it came from
+	filter := seeded
+	filter.SortBy = ItemSortName
+	filter.Locale = i18n.English
+	filter.Limit = 1
+	first, err := repo.ListItems(filter)
+	require.NoError(t, err)
+	filter.Cursor = first.NextCursor
+	_, err = repo.ListItems(filter)
+	require.NoError(t, err)
+	filter.Locale = i18n.Korean
+	_, err = repo.ListItems(filter)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestTakeAndRestoreInventory(t *testing.T) {
+	const (
+		potionID = 3 // 체력 포션 (consumable)
+		swordID  = 4 // 전설의 검 (equipment)
+		ticketID = 5 // 던전 입장권 (ticket)
+	)
+	repo := NewMemoryRepository()
+	soon := time.Now().Add(time.Hour)
+	_, err := repo.AddMultipleToInventory(1, []entity.RewardItem{
+		{ItemID: potionID, Count: 2, ExpiresAt: &soon},
+		{ItemID: potionID, Count: 3},
+	}, entity.OverflowReject, "admin", entity.TransactionRef{})
+	require.NoError(t, err)
+
+	// Soonest-expiring stack first
+	taken, err := repo.TakeFromInventory(1, potionID, 5, "use", entity.TransactionRef{})
+	require.NoError(t, err)
+	require.Len(t, taken, 2)
+	assert.Equal(t, 2, taken[0].Count)
+	assert.Equal(t, soon, *taken[0].ExpiresAt)
+	assert.Equal(t, 3, taken[1].Count)
+	assert.Nil(t, taken[1].ExpiresAt)
+
+	// Restoring ignores capacity: the freed slot was taken in the meantime
+	require.NoError(t, repo.SetSlotCapacity(1, 1))
+	require.NoError(t, repo.AddToInventory(1, ticketID, 1, "admin", entity.TransactionRef{}))
+	require.NoError(t, repo.RestoreToInventory(1, taken, "rollback", entity.TransactionRef{}))
+
+	inventory, err := repo.GetUserInventory(1)
+	require.NoError(t, err)
+	restored := make(map[bool]int)
+	for _, stack := range inventory {
+		if stack.ItemID == potionID {
+			restored[stack.ExpiresAt != nil] += stack.Count
+		}
+	}
+	assert.Equal(t, map[bool]int{true: 2, false: 3}, restored)
+
+	_, err = repo.TakeFromInventory(1, swordID, 1, "use", entity.TransactionRef{})
+	assert.ErrorIs(t, err, ErrInstancedItem)
+}
@@ -35,18 +35,29 @@ func (r *Routes) RegisterRoutes(e *echo.Echo) {
 
 	// Public item routes (no auth required)
 	items := api.Group("/items")
-	items.GET("", r.handler.GetItems)           // Get all items (with optional type filter)
+	items.GET("", r.handler.GetItems)           // Search catalog (q, type, rarity, sort, cursor)
 	items.GET("/types", r.handler.GetItemTypes) // Get item types info
 	items.GET("/:id", r.handler.GetItem)        // Get specific item
 
 	// User inventory routes (user auth required)
 	users := api.Group("/users")
 	users.GET("/:id/inventory", r.handler.GetUserInventory, r.userMiddleware.VerifyAccessToken()) // Get user inventory
+	users.POST("/me/inventory/:itemId/use", r.handler.UseItem, r.userMiddleware.VerifyAccessToken()) // Use consumable/ticket item
+	users.POST("/me/inventory/:itemId/open", r.handler.OpenBundle, r.userMiddleware.VerifyAccessToken()) // Open bundle item
+	users.GET("/me/inventory/capacity", r.handler.GetInventoryCapacity, r.userMiddleware.VerifyAccessToken()) // Get slot capacity and usage
+	users.PUT("/me/instances/:instanceId/lock", r.handler.LockInstance, r.userMiddleware.VerifyAccessToken()) // Lock/unlock equipment or card instance
 
 	// Admin item management routes (admin auth required)
 	admin := api.Group("/admin")
 	adminItems := admin.Group("/items")
+	adminItems.GET("", r.handler.ListItems, r.adminMiddleware.VerifyAdminToken())        // List items (include_archived=true for archived)
 	adminItems.POST("", r.handler.CreateItem, r.adminMiddleware.VerifyAdminToken())      // Create item
 	adminItems.PUT("/:id", r.handler.UpdateItem, r.adminMiddleware.VerifyAdminToken())  // Update item
-	adminItems.DELETE("/:id", r.handler.DeleteItem, r.adminMiddleware.VerifyAdminToken()) // Delete item
+	adminItems.DELETE("/:id", r.handler.DeleteItem, r.adminMiddleware.VerifyAdminToken()) // Archive item (soft delete)
+	adminItems.POST("/:id/restore", r.handler.RestoreItem, r.adminMiddleware.VerifyAdminToken()) // Restore archived item
+
+	// Admin inventory ledger routes (admin auth required)
+	adminUsers := admin.Group("/users")
+	adminUsers.GET("/:id/inventory/transactions", r.handler.GetInventoryTransactions, r.adminMiddleware.VerifyAdminToken()) // Get user inventory ledger
+	adminUsers.PUT("/:id/inventory/capacity", r.handler.SetInventoryCapacity, r.adminMiddleware.VerifyAdminToken()) // Set user inventory slot capacity
 }
\ No newline at end of file
@@ -3,9 +3,16 @@ package item
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"fxserver/modules/item/entity"
 	"fxserver/modules/item/repository"
+	"fxserver/pkg/events"
+	"fxserver/pkg/i18n"
+	"fxserver/pkg/random"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -16,6 +23,33 @@ var (
 	ErrInvalidItemType  = errors.New("invalid item type")
 	ErrInvalidRarity    = errors.New("invalid rarity")
 	ErrInsufficientItem = errors.New("insufficient item count")
+	ErrInvalidDateRange = errors.New("invalid date range")
+	ErrItemNotUsable    = errors.New("item cannot be used")
+	ErrNoEffectHandler  = errors.New("no effect handler registered for item")
+	ErrInstanceNotFound = errors.New("item instance not found")
+	ErrInstanceChanged  = errors.New("item instance was modified concurrently")
+	ErrInvalidBundle    = errors.New("invalid bundle contents")
+	ErrNotBundle        = errors.New("item is not a bundle")
+	ErrInvalidExpiry    = errors.New("expires_at must be in the future")
+	ErrInventoryFull    = errors.New("inventory is full")
+	ErrInvalidPolicy    = errors.New("invalid overflow policy")
+	ErrItemArchived     = errors.New("item is archived")
+	ErrItemNotArchived  = errors.New("item is not archived")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidLocale    = errors.New("unsupported translation locale")
+)
+
+// Inventory ledger sources owned by the item module
+const (
+	SourceItemUse         = "item_use"          // 아이템 사용
+	SourceItemUseRollback = "item_use_rollback" // 사용 효과 실패로 인한 복구
+	SourceBundleOpen      = "bundle_open"       // 상자 개봉
+	SourceExpired         = "expired"           // 만료로 인한 소멸
+)
+
+// Domain events published by the item module
+const (
+	EventItemUsed = "item.used" // 아이템 사용 (Value: 사용 개수, Attributes: item_id, effect)
 )
 
 type Service interface {
@@ -26,35 +60,95 @@ type Service interface {
 	GetItem(id int) (*entity.Item, error)
 	GetItems() ([]*entity.Item, error)
 	GetItemsByType(itemType entity.ItemType) ([]*entity.Item, error)
+	// ListItems matches q against every translated name and sorts names in the locale
+	ListItems(query ListItemsQuery, locale i18n.Locale) (*entity.ItemPage, error)
+	RestoreItem(id int) (*entity.Item, error)
 
 	// Inventory operations
-	GetUserInventory(userID int) (*entity.UserInventoryResponse, error)
-	AddToInventory(userID, itemID int, count int, source string) error
-	RemoveFromInventory(userID, itemID int, count int) error
-	AddMultipleToInventory(userID int, items []entity.RewardItem, source string) error
+	GetUserInventory(userID int, locale i18n.Locale) (*entity.UserInventoryResponse, error)
+	AddToInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error
+	RemoveFromInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error
+	// An empty policy uses the server default (INVENTORY_OVERFLOW_POLICY)
+	AddMultipleToInventory(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source string, ref entity.TransactionRef) (*entity.GrantResult, error)
+	// PlanGrant runs the checks of AddMultipleToInventory and reports what would be granted and overflow without changing anything
+	PlanGrant(userID int, items []entity.RewardItem, policy entity.OverflowPolicy) (*entity.GrantResult, error)
+	// ApplyInventoryChanges applies signed deltas atomically; removals must be covered by the
+	// user's current counts and archived items can only be removed
+	ApplyInventoryChanges(userID int, deltas []entity.InventoryDelta, source string, ref entity.TransactionRef) error
+	ExchangeItems(userID int, remove, add []entity.RewardItem, source string, ref entity.TransactionRef) error
+	// TransferItems moves items between users atomically; archived items cannot be moved
+	TransferItems(transfers []entity.ItemTransfer, source string, ref entity.TransactionRef) error
+	// UseItem consumes count units and applies the item's effect; the effect message is written in the locale
+	UseItem(userID, itemID int, count int, locale i18n.Locale) (*UseItemResponse, error)
+	OpenBundle(userID, itemID int, count int) (*OpenBundleResponse, error)
+
+	// Item instance operations (equipment, card)
+	GetUserInstance(userID, instanceID int) (*entity.ItemInstance, error)
+	SetInstanceLocked(userID, instanceID int, locked bool, locale i18n.Locale) (*entity.InstanceResponse, error)
+	ChangeInstanceLevel(change entity.InstanceLevelChange, costs []entity.RewardItem, source string, ref entity.TransactionRef) error
+
+	// Expiry
+	PurgeExpiredItems() (int, error)
+
+	// Capacity
+	GetInventoryCapacity(userID int) (*InventoryCapacityResponse, error)
+	SetInventoryCapacity(userID, capacity int) (*InventoryCapacityResponse, error)
+
+	// Inventory ledger (Admin)
+	GetInventoryTransactions(userID int, query InventoryTransactionQuery) ([]*entity.InventoryTransaction, error)
 
 	// Utility
-	GetItemTypes() []ItemTypeInfo
+	GetItemTypes(locale i18n.Locale) []ItemTypeInfo
 }
 
 type service struct {
 	repository repository.Repository
+	effects    EffectRegistry
+	rng        random.Source
+	policy     entity.OverflowPolicy
+	events     *events.Bus
 	logger     *zap.Logger
 }
 
 type ServiceParam struct {
 	fx.In
 	Repository repository.Repository
+	Effects    EffectRegistry
+	Random     random.Source `optional:"true"` // 상자 무작위 구성품 추첨
+	Events     *events.Bus   `optional:"true"`
 	Logger     *zap.Logger
 }
 
 func NewService(p ServiceParam) Service {
+	rng := p.Random
+	if rng == nil {
+		rng = random.New(time.Now().UnixNano())
+	}
 	return &service{
 		repository: p.Repository,
+		effects:    p.Effects,
+		rng:        rng,
+		policy:     overflowPolicyFromEnv(p.Logger),
+		events:     p.Events,
 		logger:     p.Logger,
 	}
 }
 
+// overflowPolicyFromEnv reads the default overflow policy from INVENTORY_OVERFLOW_POLICY (default: reject)
+func overflowPolicyFromEnv(logger *zap.Logger) entity.OverflowPolicy {
+	value := os.Getenv("INVENTORY_OVERFLOW_POLICY")
+	if value == "" {
+		return entity.OverflowReject
+	}
+	if !entity.IsValidOverflowPolicy(value) {
+		logger.Warn("Invalid INVENTORY_OVERFLOW_POLICY, using default",
+			zap.String("value", value),
+			zap.String("default", string(entity.OverflowReject)))
+		return entity.OverflowReject
+	}
+	return entity.OverflowPolicy(value)
+}
+
 // Item master operations
 func (s *service) CreateItem(req CreateItemRequest) (*entity.Item, error) {
 	// Validate item type
@@ -67,6 +161,13 @@ func (s *service) CreateItem(req CreateItemRequest) (*entity.Item, error) {
 		return nil, ErrInvalidRarity
 	}
 
+	if err := s.validateBundle(0, req.Type, req.Bundle); err != nil {
+		return nil, err
+	}
+	if err := validateTranslations(req.Translations); err != nil {
+		return nil, err
+	}
+
 	item := &entity.Item{
 		Name:        req.Name,
 		Description: req.Description,
@@ -75,6 +176,11 @@ func (s *service) CreateItem(req CreateItemRequest) (*entity.Item, error) {
 		Rarity:      req.Rarity,
 		IconURL:     req.IconURL,
 		IsActive:    true,
+		Bundle:      req.Bundle,
+		Translations: req.Translations,
+		LifetimeSeconds: req.LifetimeSeconds,
+		MaxStack:    req.MaxStack,
+		Tradable:    req.Tradable,
 	}
 
 	if err := s.repository.CreateItem(item); err != nil {
@@ -97,6 +203,25 @@ func (s *service) UpdateItem(id int, req UpdateItemRequest) (*entity.Item, error
 		return nil, ErrItemNotFound
 	}
 
+	// Validate bundle contents against the resulting type before changing anything.
+	// Contents are cleared when the type changes away from bundle.
+	itemType := item.Type
+	if req.Type != "" {
+		itemType = req.Type
+	}
+	bundle := item.Bundle
+	if req.Bundle != nil {
+		bundle = req.Bundle
+	} else if itemType != entity.ItemTypeBundle {
+		bundle = nil
+	}
+	if err := s.validateBundle(id, itemType, bundle); err != nil {
+		return nil, err
+	}
+	if err := validateTranslations(req.Translations); err != nil {
+		return nil, err
+	}
+
 	// Update fields if provided
 	if req.Name != "" {
 		item.Name = req.Name
@@ -122,6 +247,22 @@ func (s *service) UpdateItem(id int, req UpdateItemRequest) (*entity.Item, error
 	if req.IconURL != "" {
 		item.IconURL = req.IconURL
 	}
+	if req.LifetimeSeconds != nil {
+		item.LifetimeSeconds = *req.LifetimeSeconds
+	}
+	if req.MaxStack != nil {
+		item.MaxStack = *req.MaxStack
+	}
+	if req.Tradable != nil {
+		item.Tradable = *req.Tradable
+	}
+	if req.Translations != nil {
+		item.Translations = req.Translations
+		if len(item.Translations) == 0 {
+			item.Translations = nil
+		}
+	}
+	item.Bundle = bundle
 
 	if err := s.repository.UpdateItem(item); err != nil {
 		s.logger.Error("Failed to update item", zap.Error(err), zap.Int("item_id", id))
@@ -132,6 +273,7 @@ func (s *service) UpdateItem(id int, req UpdateItemRequest) (*entity.Item, error
 	return item, nil
 }
 
+// DeleteItem archives the item so existing inventories and history keep resolving it
 func (s *service) DeleteItem(id int) error {
 	// Check if item exists
 	_, err := s.repository.GetItem(id)
@@ -144,10 +286,27 @@ func (s *service) DeleteItem(id int) error {
 		return fmt.Errorf("failed to delete item: %w", err)
 	}
 
-	s.logger.Info("Item deleted successfully", zap.Int("item_id", id))
+	s.logger.Info("Item archived successfully", zap.Int("item_id", id))
 	return nil
 }
 
+func (s *service) RestoreItem(id int) (*entity.Item, error) {
+	if _, err := s.repository.GetItem(id); err != nil {
+		return nil, ErrItemNotFound
+	}
+
+	if err := s.repository.RestoreItem(id); err != nil {
+		if errors.Is(err, repository.ErrItemNotArchived) {
+			return nil, ErrItemNotArchived
+		}
+		s.logger.Error("Failed to restore item", zap.Error(err), zap.Int("item_id", id))
+		return nil, fmt.Errorf("failed to restore item: %w", err)
+	}
+
+	s.logger.Info("Item restored successfully", zap.Int("item_id", id))
+	return s.repository.GetItem(id)
+}
+
 func (s *service) GetItem(id int) (*entity.Item, error) {
 	item, err := s.repository.GetItem(id)
 	if err != nil {
@@ -167,8 +326,69 @@ func (s *service) GetItemsByType(itemType entity.ItemType) ([]*entity.Item, erro
 	return s.repository.GetItemsByType(itemType)
 }
 
+func (s *service) ListItems(query ListItemsQuery, locale i18n.Locale) (*entity.ItemPage, error) {
+	filter := repository.ItemFilter{
+		Query:           strings.TrimSpace(query.Q),
+		IsActive:        query.IsActive,
+		IncludeArchived: query.IncludeArchived,
+		SortBy:          repository.ItemSortField(query.Sort),
+		Locale:          locale,
+		Descending:      query.Order == "desc",
+		Cursor:          query.Cursor,
+		Limit:           query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultItemPageSize
+	}
+
+	for _, itemType := range splitQueryValues(query.Types) {
+		if !entity.IsValidItemType(itemType) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidItemType, itemType)
+		}
+		filter.Types = append(filter.Types, entity.ItemType(itemType))
+	}
+	for _, rarity := range splitQueryValues(query.Rarities) {
+		if !entity.IsValidRarity(rarity) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRarity, rarity)
+		}
+		filter.Rarities = append(filter.Rarities, rarity)
+	}
+
+	page, err := s.repository.ListItems(filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	return page, nil
+}
+
+// validateTranslations only accepts locales we ship
+func validateTranslations(translations map[i18n.Locale]entity.ItemTranslation) error {
+	for locale := range translations {
+		if !i18n.IsSupported(locale) {
+			return fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+		}
+	}
+	return nil
+}
+
+// splitQueryValues flattens repeated and comma-separated query values, dropping blanks
+func splitQueryValues(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
 // Inventory operations
-func (s *service) GetUserInventory(userID int) (*entity.UserInventoryResponse, error) {
+func (s *service) GetUserInventory(userID int, locale i18n.Locale) (*entity.UserInventoryResponse, error) {
 	inventories, err := s.repository.GetUserInventory(userID)
 	if err != nil {
 		s.logger.Error("Failed to get user inventory", zap.Error(err), zap.Int("user_id", userID))
@@ -184,28 +404,54 @@ func (s *service) GetUserInventory(userID int) (*entity.UserInventoryResponse, e
 				zap.Int("user_id", userID))
 			continue
 		}
-		inventoryResponses = append(inventoryResponses, inv.ToResponse(item))
+		inventoryResponses = append(inventoryResponses, inv.ToResponse(item, locale))
+	}
+
+	instances, err := s.repository.GetUserInstances(userID)
+	if err != nil {
+		s.logger.Error("Failed to get user item instances", zap.Error(err), zap.Int("user_id", userID))
+		return nil, fmt.Errorf("failed to get user item instances: %w", err)
+	}
+
+	instanceResponses := make([]entity.InstanceResponse, 0, len(instances))
+	for _, instance := range instances {
+		item, err := s.repository.GetItem(instance.ItemID)
+		if err != nil {
+			s.logger.Warn("Item not found for instance",
+				zap.Int("item_id", instance.ItemID),
+				zap.Int("instance_id", instance.ID),
+				zap.Int("user_id", userID))
+			continue
+		}
+		instanceResponses = append(instanceResponses, instance.ToResponse(item, locale))
 	}
 
 	return &entity.UserInventoryResponse{
-		UserID: userID,
-		Items:  inventoryResponses,
-		Total:  len(inventoryResponses),
+		UserID:    userID,
+		Items:     inventoryResponses,
+		Instances: instanceResponses,
+		Total:     len(inventoryResponses) + len(instanceResponses),
 	}, nil
 }
 
-func (s *service) AddToInventory(userID, itemID int, count int, source string) error {
-	// Verify item exists
-	_, err := s.repository.GetItem(itemID)
+func (s *service) AddToInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error {
+	// Verify item exists and can still be granted
+	item, err := s.repository.GetItem(itemID)
 	if err != nil {
 		return ErrItemNotFound
 	}
+	if item.IsArchived() {
+		return fmt.Errorf("%w: item %d", ErrItemArchived, itemID)
+	}
 
 	if count <= 0 {
 		return errors.New("count must be greater than 0")
 	}
 
-	if err := s.repository.AddToInventory(userID, itemID, count, source); err != nil {
+	if err := s.repository.AddToInventory(userID, itemID, count, source, ref); err != nil {
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			return fmt.Errorf("%w: %v", ErrInventoryFull, err)
+		}
 		s.logger.Error("Failed to add item to inventory", 
 			zap.Error(err), 
 			zap.Int("user_id", userID), 
@@ -223,12 +469,12 @@ func (s *service) AddToInventory(userID, itemID int, count int, source string) e
 	return nil
 }
 
-func (s *service) RemoveFromInventory(userID, itemID int, count int) error {
+func (s *service) RemoveFromInventory(userID, itemID int, count int, source string, ref entity.TransactionRef) error {
 	if count <= 0 {
 		return errors.New("count must be greater than 0")
 	}
 
-	if err := s.repository.RemoveFromInventory(userID, itemID, count); err != nil {
+	if err := s.repository.RemoveFromInventory(userID, itemID, count, source, ref); err != nil {
 		s.logger.Error("Failed to remove item from inventory", 
 			zap.Error(err), 
 			zap.Int("user_id", userID), 
@@ -240,43 +486,578 @@ func (s *service) RemoveFromInventory(userID, itemID int, count int) error {
 	s.logger.Info("Item removed from inventory", 
 		zap.Int("user_id", userID), 
 		zap.Int("item_id", itemID),
-		zap.Int("count", count))
+		zap.Int("count", count),
+		zap.String("source", source))
 
 	return nil
 }
 
-func (s *service) AddMultipleToInventory(userID int, items []entity.RewardItem, source string) error {
+func (s *service) AddMultipleToInventory(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source string, ref entity.TransactionRef) (*entity.GrantResult, error) {
+	policy, err := s.checkGrant(items, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repository.AddMultipleToInventory(userID, items, policy, source, ref)
+	if err != nil {
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			return nil, fmt.Errorf("%w: %v", ErrInventoryFull, err)
+		}
+		s.logger.Error("Failed to add multiple items to inventory", 
+			zap.Error(err), 
+			zap.Int("user_id", userID), 
+			zap.Int("item_count", len(items)))
+		return nil, fmt.Errorf("failed to add multiple items to inventory: %w", err)
+	}
+
+	s.logger.Info("Multiple items added to inventory", 
+		zap.Int("user_id", userID), 
+		zap.Int("item_count", len(items)),
+		zap.String("policy", string(result.Policy)),
+		zap.Int("overflow_count", len(result.Overflow)),
+		zap.String("source", source))
+
+	return result, nil
+}
+
+func (s *service) PlanGrant(userID int, items []entity.RewardItem, policy entity.OverflowPolicy) (*entity.GrantResult, error) {
+	policy, err := s.checkGrant(items, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repository.PlanGrant(userID, items, policy)
+	if err != nil {
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			return nil, fmt.Errorf("%w: %v", ErrInventoryFull, err)
+		}
+		return nil, fmt.Errorf("failed to plan grant: %w", err)
+	}
+	return result, nil
+}
+
+// checkGrant validates items to be granted and resolves an empty policy to the server default
+func (s *service) checkGrant(items []entity.RewardItem, policy entity.OverflowPolicy) (entity.OverflowPolicy, error) {
 	if len(items) == 0 {
-		return errors.New("no items to add")
+		return "", errors.New("no items to add")
+	}
+	if policy == "" {
+		policy = s.policy
+	}
+	if !entity.IsValidOverflowPolicy(string(policy)) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPolicy, policy)
 	}
 
 	// Validate all items and counts
+	now := time.Now()
 	for _, item := range items {
+		if item.Count <= 0 {
+			return "", fmt.Errorf("invalid count %d for item %d", item.Count, item.ItemID)
+		}
+		if item.ExpiresAt != nil && !item.ExpiresAt.After(now) {
+			return "", fmt.Errorf("%w: item %d", ErrInvalidExpiry, item.ItemID)
+		}
+		// Verify item exists and can still be granted
+		template, err := s.repository.GetItem(item.ItemID)
+		if err != nil {
+			return "", fmt.Errorf("item %d not found", item.ItemID)
+		}
+		if template.IsArchived() {
+			return "", fmt.Errorf("%w: item %d", ErrItemArchived, item.ItemID)
+		}
+	}
+	return policy, nil
+}
+
+func (s *service) ExchangeItems(userID int, remove, add []entity.RewardItem, source string, ref entity.TransactionRef) error {
+	if len(remove) == 0 && len(add) == 0 {
+		return errors.New("no items to exchange")
+	}
+
+	for _, item := range append(append([]entity.RewardItem{}, remove...), add...) {
 		if item.Count <= 0 {
 			return fmt.Errorf("invalid count %d for item %d", item.Count, item.ItemID)
 		}
-		// Verify item exists
-		if _, err := s.repository.GetItem(item.ItemID); err != nil {
-			return fmt.Errorf("item %d not found", item.ItemID)
+	}
+
+	return s.ApplyInventoryChanges(userID, entity.ExchangeDeltas(remove, add), source, ref)
+}
+
+func (s *service) ApplyInventoryChanges(userID int, deltas []entity.InventoryDelta, source string, ref entity.TransactionRef) error {
+	if len(deltas) == 0 {
+		return errors.New("no inventory changes")
+	}
+
+	for _, delta := range deltas {
+		if delta.Count == 0 {
+			return fmt.Errorf("invalid count 0 for item %d", delta.ItemID)
+		}
+		if delta.Count > 0 && delta.ExpiresAt != nil && !delta.ExpiresAt.After(time.Now()) {
+			return fmt.Errorf("%w: item %d", ErrInvalidExpiry, delta.ItemID)
+		}
+		template, err := s.repository.GetItem(delta.ItemID)
+		if err != nil {
+			return fmt.Errorf("item %d not found", delta.ItemID)
+		}
+		// Archived items can still be consumed but not handed out
+		if delta.Count > 0 && template.IsArchived() {
+			return fmt.Errorf("%w: item %d", ErrItemArchived, delta.ItemID)
 		}
 	}
 
-	if err := s.repository.AddMultipleToInventory(userID, items, source); err != nil {
-		s.logger.Error("Failed to add multiple items to inventory", 
-			zap.Error(err), 
-			zap.Int("user_id", userID), 
-			zap.Int("item_count", len(items)))
-		return fmt.Errorf("failed to add multiple items to inventory: %w", err)
+	if err := s.repository.ApplyInventoryChanges(userID, deltas, source, ref); err != nil {
+		if errors.Is(err, repository.ErrInventoryNotFound) || errors.Is(err, repository.ErrInsufficientCount) {
+			return fmt.Errorf("%w: %v", ErrInsufficientItem, err)
+		}
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			return fmt.Errorf("%w: %v", ErrInventoryFull, err)
+		}
+		s.logger.Error("Failed to apply inventory changes",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.String("source", source))
+		return fmt.Errorf("failed to apply inventory changes: %w", err)
 	}
 
-	s.logger.Info("Multiple items added to inventory", 
-		zap.Int("user_id", userID), 
-		zap.Int("item_count", len(items)),
+	s.logger.Info("Inventory changes applied",
+		zap.Int("user_id", userID),
+		zap.Int("delta_count", len(deltas)),
+		zap.String("source", source))
+
+	return nil
+}
+
+func (s *service) TransferItems(transfers []entity.ItemTransfer, source string, ref entity.TransactionRef) error {
+	if len(transfers) == 0 {
+		return errors.New("no items to transfer")
+	}
+
+	for _, transfer := range transfers {
+		if transfer.FromUserID == transfer.ToUserID {
+			return fmt.Errorf("cannot transfer items from user %d to themselves", transfer.FromUserID)
+		}
+		if len(transfer.Items) == 0 {
+			return errors.New("no items to transfer")
+		}
+		for _, item := range transfer.Items {
+			if item.Count <= 0 {
+				return fmt.Errorf("invalid count %d for item %d", item.Count, item.ItemID)
+			}
+			template, err := s.repository.GetItem(item.ItemID)
+			if err != nil {
+				return fmt.Errorf("item %d not found", item.ItemID)
+			}
+			if template.IsArchived() {
+				return fmt.Errorf("%w: item %d", ErrItemArchived, item.ItemID)
+			}
+		}
+	}
+
+	if err := s.repository.TransferItems(transfers, source, ref); err != nil {
+		if errors.Is(err, repository.ErrInventoryNotFound) || errors.Is(err, repository.ErrInsufficientCount) {
+			return fmt.Errorf("%w: %v", ErrInsufficientItem, err)
+		}
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			return fmt.Errorf("%w: %v", ErrInventoryFull, err)
+		}
+		s.logger.Error("Failed to transfer items",
+			zap.Error(err),
+			zap.Int("transfer_count", len(transfers)),
+			zap.String("source", source))
+		return fmt.Errorf("failed to transfer items: %w", err)
+	}
+
+	s.logger.Info("Items transferred",
+		zap.Int("transfer_count", len(transfers)),
+		zap.String("source", source),
+		zap.String("reference_id", ref.ID))
+
+	return nil
+}
+
+func (s *service) UseItem(userID, itemID int, count int, locale i18n.Locale) (*UseItemResponse, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	item, err := s.repository.GetItem(itemID)
+	if err != nil {
+		return nil, ErrItemNotFound
+	}
+
+	if !item.Type.IsConsumable() {
+		return nil, ErrItemNotUsable
+	}
+
+	handler, ok := s.effects.Resolve(item)
+	if !ok {
+		return nil, ErrNoEffectHandler
+	}
+
+	ref := entity.TransactionRef{
+		Type:  SourceItemUse,
+		ID:    fmt.Sprintf("%d", itemID),
+		Actor: entity.UserActor(userID),
+	}
+
+	// Consume first so an effect is never applied without paying for it.
+	// The taken stacks are kept so a failed effect puts back exactly what was consumed.
+	taken, err := s.repository.TakeFromInventory(userID, itemID, count, SourceItemUse, ref)
+	if err != nil {
+		if errors.Is(err, repository.ErrInventoryNotFound) || errors.Is(err, repository.ErrInsufficientCount) {
+			return nil, ErrInsufficientItem
+		}
+		return nil, fmt.Errorf("failed to consume item: %w", err)
+	}
+
+	result, err := handler.Apply(userID, item, count, locale)
+	if err != nil {
+		s.logger.Error("Item effect failed, restoring consumed items",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.Int("item_id", itemID),
+			zap.Int("count", count))
+		if rollbackErr := s.repository.RestoreToInventory(userID, taken, SourceItemUseRollback, ref); rollbackErr != nil {
+			s.logger.Error("Failed to restore consumed items", zap.Error(rollbackErr), zap.Int("user_id", userID))
+		}
+		return nil, fmt.Errorf("failed to apply item effect: %w", err)
+	}
+
+	remaining := 0
+	if inv, err := s.repository.GetUserInventoryItem(userID, itemID); err == nil {
+		remaining = inv.Count
+	}
+
+	s.logger.Info("Item used",
+		zap.Int("user_id", userID),
+		zap.Int("item_id", itemID),
+		zap.Int("count", count),
+		zap.String("effect", result.Effect))
+
+	s.events.Publish(events.Event{
+		Type:   EventItemUsed,
+		UserID: userID,
+		Value:  count,
+		Attributes: map[string]string{
+			"item_id": strconv.Itoa(itemID),
+			"effect":  result.Effect,
+		},
+	})
+
+	return &UseItemResponse{
+		ItemID:    itemID,
+		Count:     count,
+		Remaining: remaining,
+		Result:    *result,
+	}, nil
+}
+
+func (s *service) OpenBundle(userID, itemID int, count int) (*OpenBundleResponse, error) {
+	if count <= 0 {
+		return nil, errors.New("count must be greater than 0")
+	}
+
+	item, err := s.repository.GetItem(itemID)
+	if err != nil {
+		return nil, ErrItemNotFound
+	}
+	if item.Type != entity.ItemTypeBundle || item.Bundle == nil {
+		return nil, ErrNotBundle
+	}
+
+	contents := make([]entity.RewardItem, 0)
+	for i := 0; i < count; i++ {
+		contents = append(contents, s.rollBundle(item.Bundle)...)
+	}
+	contents = entity.MergeRewardItems(contents)
+	for _, content := range contents {
+		if template, err := s.repository.GetItem(content.ItemID); err == nil && template.IsArchived() {
+			return nil, fmt.Errorf("%w: bundle content %d", ErrItemArchived, content.ItemID)
+		}
+	}
+
+	ref := entity.TransactionRef{
+		Type:  SourceBundleOpen,
+		ID:    fmt.Sprintf("%d", itemID),
+		Actor: entity.UserActor(userID),
+	}
+
+	// The bundle is removed and its contents granted under one lock; nothing changes if the user lacks the bundle
+	remove := []entity.RewardItem{{ItemID: itemID, Count: count}}
+	if err := s.repository.ExchangeItems(userID, remove, contents, SourceBundleOpen, ref); err != nil {
+		if errors.Is(err, repository.ErrInventoryNotFound) || errors.Is(err, repository.ErrInsufficientCount) {
+			return nil, ErrInsufficientItem
+		}
+		if errors.Is(err, repository.ErrCapacityExceeded) {
+			return nil, fmt.Errorf("%w: %v", ErrInventoryFull, err)
+		}
+		s.logger.Error("Failed to open bundle",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.Int("item_id", itemID))
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+
+	remaining := 0
+	if inv, err := s.repository.GetUserInventoryItem(userID, itemID); err == nil {
+		remaining = inv.Count
+	}
+
+	s.logger.Info("Bundle opened",
+		zap.Int("user_id", userID),
+		zap.Int("item_id", itemID),
+		zap.Int("count", count),
+		zap.Int("content_count", len(contents)))
+
+	return &OpenBundleResponse{
+		ItemID:    itemID,
+		Count:     count,
+		Remaining: remaining,
+		Contents:  contents,
+	}, nil
+}
+
+// rollBundle returns the contents of opening a single bundle
+func (s *service) rollBundle(bundle *entity.BundleContents) []entity.RewardItem {
+	contents := append([]entity.RewardItem{}, bundle.Items...)
+	if len(bundle.LootTable) == 0 {
+		return contents
+	}
+
+	total := 0
+	for _, entry := range bundle.LootTable {
+		total += entry.Weight
+	}
+
+	rolls := bundle.Rolls
+	if rolls <= 0 {
+		rolls = 1
+	}
+	for i := 0; i < rolls; i++ {
+		roll := s.rng.Intn(total)
+		for _, entry := range bundle.LootTable {
+			if roll < entry.Weight {
+				contents = append(contents, entity.RewardItem{ItemID: entry.ItemID, Count: entry.Count})
+				break
+			}
+			roll -= entry.Weight
+		}
+	}
+	return contents
+}
+
+// validateBundle checks bundle contents for an item of the given type.
+// itemID is the item being saved (0 when creating); contents may not lead back to it through nested bundles.
+func (s *service) validateBundle(itemID int, itemType entity.ItemType, bundle *entity.BundleContents) error {
+	if itemType != entity.ItemTypeBundle {
+		if bundle != nil {
+			return fmt.Errorf("%w: only bundle items can define contents", ErrInvalidBundle)
+		}
+		return nil
+	}
+
+	if bundle == nil || (len(bundle.Items) == 0 && len(bundle.LootTable) == 0) {
+		return fmt.Errorf("%w: bundle must contain items or a loot table", ErrInvalidBundle)
+	}
+	if bundle.Rolls < 0 {
+		return fmt.Errorf("%w: rolls must not be negative", ErrInvalidBundle)
+	}
+	for _, item := range bundle.Items {
+		if item.Count <= 0 {
+			return fmt.Errorf("%w: invalid count for item %d", ErrInvalidBundle, item.ItemID)
+		}
+	}
+	for _, entry := range bundle.LootTable {
+		if entry.Count <= 0 || entry.Weight <= 0 {
+			return fmt.Errorf("%w: invalid count or weight for item %d", ErrInvalidBundle, entry.ItemID)
+		}
+	}
+
+	visited := make(map[int]bool)
+	var visit func(ids []int) error
+	visit = func(ids []int) error {
+		for _, id := range ids {
+			if itemID != 0 && id == itemID {
+				return fmt.Errorf("%w: bundle contains itself", ErrInvalidBundle)
+			}
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+
+			content, err := s.repository.GetItem(id)
+			if err != nil {
+				return fmt.Errorf("%w: item %d not found", ErrInvalidBundle, id)
+			}
+			if content.IsArchived() {
+				return fmt.Errorf("%w: item %d is archived", ErrInvalidBundle, id)
+			}
+			if content.Type == entity.ItemTypeBundle && content.Bundle != nil {
+				if err := visit(content.Bundle.ItemIDs()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return visit(bundle.ItemIDs())
+}
+
+func (s *service) GetUserInstance(userID, instanceID int) (*entity.ItemInstance, error) {
+	instance, err := s.repository.GetInstance(instanceID)
+	if err != nil || instance.UserID != userID {
+		return nil, ErrInstanceNotFound
+	}
+	return instance, nil
+}
+
+func (s *service) SetInstanceLocked(userID, instanceID int, locked bool, locale i18n.Locale) (*entity.InstanceResponse, error) {
+	instance, err := s.GetUserInstance(userID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.repository.GetItem(instance.ItemID)
+	if err != nil {
+		return nil, ErrItemNotFound
+	}
+
+	instance.Locked = locked
+	if err := s.repository.UpdateInstance(instance); err != nil {
+		s.logger.Error("Failed to update item instance", zap.Error(err), zap.Int("instance_id", instanceID))
+		return nil, fmt.Errorf("failed to update item instance: %w", err)
+	}
+
+	s.logger.Info("Item instance lock updated",
+		zap.Int("user_id", userID),
+		zap.Int("instance_id", instanceID),
+		zap.Bool("locked", locked))
+
+	response := instance.ToResponse(item, locale)
+	return &response, nil
+}
+
+func (s *service) ChangeInstanceLevel(change entity.InstanceLevelChange, costs []entity.RewardItem, source string, ref entity.TransactionRef) error {
+	for _, cost := range costs {
+		if cost.Count <= 0 {
+			return fmt.Errorf("invalid count %d for item %d", cost.Count, cost.ItemID)
+		}
+	}
+
+	if err := s.repository.ChangeInstanceLevel(change, costs, source, ref); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrInstanceNotFound):
+			return ErrInstanceNotFound
+		case errors.Is(err, repository.ErrInstanceChanged):
+			return ErrInstanceChanged
+		case errors.Is(err, repository.ErrInventoryNotFound), errors.Is(err, repository.ErrInsufficientCount):
+			return fmt.Errorf("%w: %v", ErrInsufficientItem, err)
+		}
+		s.logger.Error("Failed to change item instance level",
+			zap.Error(err),
+			zap.Int("user_id", change.UserID),
+			zap.Int("instance_id", change.InstanceID))
+		return fmt.Errorf("failed to change item instance level: %w", err)
+	}
+
+	s.logger.Info("Item instance level changed",
+		zap.Int("user_id", change.UserID),
+		zap.Int("instance_id", change.InstanceID),
+		zap.Int("from_level", change.FromLevel),
+		zap.Int("to_level", change.ToLevel),
+		zap.Bool("destroyed", change.Destroy),
 		zap.String("source", source))
 
 	return nil
 }
 
-func (s *service) GetItemTypes() []ItemTypeInfo {
-	return GetItemTypes()
+// PurgeExpiredItems removes expired stacks and instances, recording them in the ledger
+func (s *service) PurgeExpiredItems() (int, error) {
+	ref := entity.TransactionRef{
+		Type:  SourceExpired,
+		Actor: entity.ActorSystem,
+	}
+
+	purged, err := s.repository.PurgeExpired(time.Now(), SourceExpired, ref)
+	if err != nil {
+		s.logger.Error("Failed to purge expired items", zap.Error(err))
+		return 0, fmt.Errorf("failed to purge expired items: %w", err)
+	}
+
+	if purged > 0 {
+		s.logger.Info("Expired items purged", zap.Int("count", purged))
+	}
+	return purged, nil
+}
+
+// Capacity
+func (s *service) GetInventoryCapacity(userID int) (*InventoryCapacityResponse, error) {
+	capacity, used, err := s.repository.GetSlotCapacity(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory capacity: %w", err)
+	}
+	return &InventoryCapacityResponse{
+		UserID:   userID,
+		Capacity: capacity,
+		Used:     used,
+		Free:     max(capacity-used, 0),
+	}, nil
+}
+
+// SetInventoryCapacity changes a user's slot capacity. Lowering it below the slots in use
+// keeps what the user holds but blocks new slots until enough are freed.
+func (s *service) SetInventoryCapacity(userID, capacity int) (*InventoryCapacityResponse, error) {
+	if capacity < 0 {
+		return nil, errors.New("capacity must not be negative")
+	}
+
+	if err := s.repository.SetSlotCapacity(userID, capacity); err != nil {
+		s.logger.Error("Failed to set inventory capacity", zap.Error(err), zap.Int("user_id", userID))
+		return nil, fmt.Errorf("failed to set inventory capacity: %w", err)
+	}
+
+	s.logger.Info("Inventory capacity updated", zap.Int("user_id", userID), zap.Int("capacity", capacity))
+	return s.GetInventoryCapacity(userID)
+}
+
+func (s *service) GetInventoryTransactions(userID int, query InventoryTransactionQuery) ([]*entity.InventoryTransaction, error) {
+	filter := repository.TransactionFilter{
+		UserID:        userID,
+		ItemID:        query.ItemID,
+		Source:        query.Source,
+		ReferenceType: query.ReferenceType,
+		ReferenceID:   query.ReferenceID,
+		Limit:         query.Limit,
+	}
+
+	if query.StartDate != "" {
+		from, err := time.Parse("2006-01-02", query.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid start date format", ErrInvalidDateRange)
+		}
+		filter.From = from
+	}
+	if query.EndDate != "" {
+		to, err := time.Parse("2006-01-02", query.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end date format", ErrInvalidDateRange)
+		}
+		// End date is inclusive
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	transactions, err := s.repository.GetInventoryTransactions(filter)
+	if err != nil {
+		s.logger.Error("Failed to get inventory transactions", zap.Error(err), zap.Int("user_id", userID))
+		return nil, fmt.Errorf("failed to get inventory transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+func (s *service) GetItemTypes(locale i18n.Locale) []ItemTypeInfo {
+	return GetItemTypes(locale)
 }
\ No newline at end of file
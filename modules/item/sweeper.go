@@ -0,0 +1,98 @@
+package item
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// defaultSweepInterval is used when ITEM_EXPIRY_SWEEP_INTERVAL is unset or invalid
+const defaultSweepInterval = time.Minute
+
+// ExpirySweeper periodically purges expired inventory entries.
+// Expired entries are already hidden from reads; the sweeper removes them and writes ledger entries.
+type ExpirySweeper struct {
+	service  Service
+	interval time.Duration
+	logger   *zap.Logger
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+type ExpirySweeperParam struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Service   Service
+	Logger    *zap.Logger
+}
+
+func NewExpirySweeper(p ExpirySweeperParam) *ExpirySweeper {
+	interval := defaultSweepInterval
+	if value := os.Getenv("ITEM_EXPIRY_SWEEP_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			p.Logger.Warn("Invalid ITEM_EXPIRY_SWEEP_INTERVAL, using default",
+				zap.String("value", value),
+				zap.Duration("default", defaultSweepInterval))
+		} else {
+			interval = parsed
+		}
+	}
+
+	sweeper := &ExpirySweeper{
+		service:  p.Service,
+		interval: interval,
+		logger:   p.Logger,
+	}
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: sweeper.Start,
+		OnStop:  sweeper.Stop,
+	})
+
+	return sweeper
+}
+
+func (s *ExpirySweeper) Start(ctx context.Context) error {
+	s.logger.Info("Starting item expiry sweeper", zap.Duration("interval", s.interval))
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.Sweep()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *ExpirySweeper) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping item expiry sweeper")
+	close(s.stop)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Sweep runs a single purge pass
+func (s *ExpirySweeper) Sweep() {
+	if _, err := s.service.PurgeExpiredItems(); err != nil {
+		s.logger.Error("Item expiry sweep failed", zap.Error(err))
+	}
+}
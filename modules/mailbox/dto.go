@@ -0,0 +1,50 @@
+package mailbox
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/mailbox/entity"
+)
+
+// SendRequest delivers a mail to one user; used by other services such as reward
+type SendRequest struct {
+	UserID    int
+	Title     string
+	Body      string
+	Items     []itemEntity.RewardItem
+	Source    string
+	SentBy    string     // 원장 actor 형식 (admin:1, system)
+	ExpiresAt *time.Time // nil: 기본 보관 기간 (DefaultMailTTL)
+	NoExpiry  bool       // ExpiresAt 없이 지정 시 만료 없음 (유료 보상 등)
+}
+
+// Query DTOs
+type MailQuery struct {
+	IncludeClaimed bool `query:"include_claimed"` // 수령 완료 우편 포함 여부
+	Limit          int  `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type AdminMailQuery struct {
+	UserID         int  `query:"user_id" validate:"required,gt=0"`
+	IncludeClaimed bool `query:"include_claimed"`
+	Limit          int  `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+// Response DTOs
+type ClaimResponse struct {
+	Mail        *entity.Mail            `json:"mail"`
+	GrantResult *itemEntity.GrantResult `json:"grant_result,omitempty"`
+}
+
+type ClaimFailure struct {
+	MailID  int    `json:"mail_id"`
+	Message string `json:"message"`
+}
+
+type ClaimAllResponse struct {
+	ClaimedCount int                     `json:"claimed_count"`
+	Claimed      []*entity.Mail          `json:"claimed"`
+	Items        []itemEntity.RewardItem `json:"items"`            // 수령한 아이템 합계
+	Failed       []ClaimFailure          `json:"failed,omitempty"` // 인벤토리 공간 부족 등으로 남은 우편
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Mail is a message in a user's mailbox; attached items are granted when it is claimed
+type Mail struct {
+	ID        int                     `json:"id"`
+	UserID    int                     `json:"user_id"`
+	Title     string                  `json:"title"`
+	Body      string                  `json:"body"`
+	Items     []itemEntity.RewardItem `json:"items"`
+	Source    string                  `json:"source"`               // 지급 출처 (admin, event, compensation 등)
+	SentBy    string                  `json:"sent_by,omitempty"`    // admin:1, system
+	ExpiresAt *time.Time              `json:"expires_at,omitempty"` // nil: 만료 없음
+	ClaimedAt *time.Time              `json:"claimed_at,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// IsClaimed reports whether the attachments were already received
+func (m *Mail) IsClaimed() bool {
+	return m.ClaimedAt != nil
+}
+
+// IsExpired reports whether the mail can no longer be claimed at the given time
+func (m *Mail) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
+}
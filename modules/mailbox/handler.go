@@ -0,0 +1,154 @@
+package mailbox
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// GetMailbox returns the authenticated user's mail that has not expired
+func (h *Handler) GetMailbox(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query MailQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	mail, err := h.service.ListMail(userID, query)
+	if err != nil {
+		h.logger.Error("Failed to list mail", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get mailbox"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(mail))
+}
+
+// GetMail returns a single mail owned by the authenticated user
+func (h *Handler) GetMail(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	mailID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid mail ID", "invalid_request_error"))
+	}
+
+	mail, err := h.service.GetMail(userID, mailID)
+	if err != nil {
+		if errors.Is(err, ErrMailNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Mail"))
+		}
+		h.logger.Error("Failed to get mail", zap.Error(err), zap.Int("user_id", userID), zap.Int("mail_id", mailID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get mail"))
+	}
+
+	return c.JSON(http.StatusOK, mail)
+}
+
+// ClaimMail moves a mail's attached items into the authenticated user's inventory
+func (h *Handler) ClaimMail(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	mailID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid mail ID", "invalid_request_error"))
+	}
+
+	response, err := h.service.Claim(userID, mailID)
+	if err != nil {
+		if errors.Is(err, ErrMailNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Mail"))
+		}
+		if errors.Is(err, ErrMailClaimed) || errors.Is(err, ErrMailExpired) ||
+			errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to claim mail", zap.Error(err), zap.Int("user_id", userID), zap.Int("mail_id", mailID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to claim mail"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// ClaimAllMail claims every claimable mail of the authenticated user
+func (h *Handler) ClaimAllMail(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	response, err := h.service.ClaimAll(userID)
+	if err != nil {
+		h.logger.Error("Failed to claim mailbox", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to claim mailbox"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Admin APIs
+
+// GetUserMailbox returns a user's mail, including expired mail (admin only)
+func (h *Handler) GetUserMailbox(c echo.Context) error {
+	var query AdminMailQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	mail, err := h.service.ListUserMail(query)
+	if err != nil {
+		h.logger.Error("Failed to list mail", zap.Error(err), zap.Int("user_id", query.UserID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get mailbox"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(mail))
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"fxserver/modules/mailbox/entity"
+)
+
+var (
+	ErrMailNotFound       = errors.New("mail not found")
+	ErrMailAlreadyClaimed = errors.New("mail already claimed")
+)
+
+// MailFilter narrows mailbox queries; zero values are ignored
+type MailFilter struct {
+	UserID       int
+	Unclaimed    bool
+	NotExpiredAt time.Time // 지정 시 이 시각에 만료된 우편 제외
+	Limit        int
+}
+
+type Repository interface {
+	Create(mail *entity.Mail) error
+	// GetByID returns a copy of the stored mail
+	GetByID(id int) (*entity.Mail, error)
+	// MarkClaimed sets the claim time once; it fails with ErrMailAlreadyClaimed afterwards
+	MarkClaimed(id int, claimedAt time.Time) error
+	// List returns matching mail, newest first
+	List(filter MailFilter) ([]*entity.Mail, error)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"fxserver/modules/mailbox/entity"
+)
+
+type memoryRepository struct {
+	mu     sync.RWMutex
+	mail   []*entity.Mail
+	byID   map[int]*entity.Mail
+	nextID int
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		byID:   make(map[int]*entity.Mail),
+		nextID: 1,
+	}
+}
+
+func (r *memoryRepository) Create(mail *entity.Mail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mail.ID = r.nextID
+	mail.ClaimedAt = nil
+	mail.CreatedAt = time.Now()
+	r.mail = append(r.mail, mail)
+	r.byID[mail.ID] = mail
+	r.nextID++
+	return nil
+}
+
+func (r *memoryRepository) GetByID(id int) (*entity.Mail, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mail, exists := r.byID[id]
+	if !exists {
+		return nil, ErrMailNotFound
+	}
+	copied := *mail
+	return &copied, nil
+}
+
+func (r *memoryRepository) MarkClaimed(id int, claimedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mail, exists := r.byID[id]
+	if !exists {
+		return ErrMailNotFound
+	}
+	if mail.ClaimedAt != nil {
+		return ErrMailAlreadyClaimed
+	}
+	mail.ClaimedAt = &claimedAt
+	return nil
+}
+
+func (r *memoryRepository) List(filter MailFilter) ([]*entity.Mail, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entity.Mail
+	for i := len(r.mail) - 1; i >= 0; i-- {
+		mail := r.mail[i]
+		if filter.UserID != 0 && mail.UserID != filter.UserID {
+			continue
+		}
+		if filter.Unclaimed && mail.IsClaimed() {
+			continue
+		}
+		if !filter.NotExpiredAt.IsZero() && mail.IsExpired(filter.NotExpiredAt) {
+			continue
+		}
+		copied := *mail
+		result = append(result, &copied)
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
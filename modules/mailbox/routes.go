@@ -0,0 +1,46 @@
+package mailbox
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// User mailbox routes (user auth required)
+	mailbox := api.Group("/mailbox")
+	mailbox.GET("", r.handler.GetMailbox, r.userMiddleware.VerifyAccessToken())              // Get my mailbox
+	mailbox.POST("/claim-all", r.handler.ClaimAllMail, r.userMiddleware.VerifyAccessToken()) // Claim all mail
+	mailbox.GET("/:id", r.handler.GetMail, r.userMiddleware.VerifyAccessToken())             // Get mail by ID
+	mailbox.POST("/:id/claim", r.handler.ClaimMail, r.userMiddleware.VerifyAccessToken())    // Claim mail attachments
+
+	// Admin mailbox routes (admin auth required)
+	admin := api.Group("/admin")
+	admin.GET("/mailbox", r.handler.GetUserMailbox, r.adminMiddleware.VerifyAdminToken()) // Get a user's mailbox
+}
@@ -0,0 +1,252 @@
+package mailbox
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/mailbox/entity"
+	"fxserver/modules/mailbox/repository"
+	"fxserver/pkg/lock"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMailNotFound = errors.New("mail not found")
+	ErrMailClaimed  = errors.New("mail already claimed")
+	ErrMailExpired  = errors.New("mail has expired")
+	ErrInvalidMail  = errors.New("invalid mail")
+)
+
+// Inventory ledger reference type for mailbox claims; the ledger source is the mail's own source
+const ReferenceTypeMail = "mail"
+
+// DefaultMailTTL is how long mail stays claimable when the sender does not set an expiry
+const DefaultMailTTL = 30 * 24 * time.Hour
+
+type Service interface {
+	// Delivery (used by other services)
+	Send(req SendRequest) (*entity.Mail, error)
+
+	// Player operations
+	ListMail(userID int, query MailQuery) ([]*entity.Mail, error)
+	GetMail(userID, mailID int) (*entity.Mail, error)
+	Claim(userID, mailID int) (*ClaimResponse, error)
+	ClaimAll(userID int) (*ClaimAllResponse, error)
+
+	// Admin queries
+	ListUserMail(query AdminMailQuery) ([]*entity.Mail, error)
+}
+
+type service struct {
+	repo        repository.Repository
+	itemService item.Service
+	logger      *zap.Logger
+
+	// claimLocks serializes each player's claims so a mail's items are granted at most once
+	claimLocks lock.Keyed[int]
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository  repository.Repository
+	ItemService item.Service
+	Logger      *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:        p.Repository,
+		itemService: p.ItemService,
+		logger:      p.Logger,
+	}
+}
+
+func (s *service) Send(req SendRequest) (*entity.Mail, error) {
+	if req.UserID <= 0 {
+		return nil, fmt.Errorf("%w: invalid user ID %d", ErrInvalidMail, req.UserID)
+	}
+	if req.Title == "" || req.Source == "" {
+		return nil, fmt.Errorf("%w: title and source are required", ErrInvalidMail)
+	}
+	for _, reward := range req.Items {
+		if reward.Count <= 0 {
+			return nil, fmt.Errorf("%w: invalid count for item %d", ErrInvalidMail, reward.ItemID)
+		}
+		template, err := s.itemService.GetItem(reward.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %d not found", ErrInvalidMail, reward.ItemID)
+		}
+		if template.IsArchived() {
+			return nil, fmt.Errorf("%w: item %d", item.ErrItemArchived, reward.ItemID)
+		}
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidMail)
+	}
+	if expiresAt == nil && !req.NoExpiry {
+		defaultExpiry := time.Now().Add(DefaultMailTTL)
+		expiresAt = &defaultExpiry
+	}
+
+	mail := &entity.Mail{
+		UserID:    req.UserID,
+		Title:     req.Title,
+		Body:      req.Body,
+		Items:     req.Items,
+		Source:    req.Source,
+		SentBy:    req.SentBy,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(mail); err != nil {
+		s.logger.Error("Failed to send mail", zap.Error(err), zap.Int("user_id", req.UserID))
+		return nil, fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	s.logger.Info("Mail sent",
+		zap.Int("mail_id", mail.ID),
+		zap.Int("user_id", mail.UserID),
+		zap.String("source", mail.Source),
+		zap.Int("item_count", len(mail.Items)))
+
+	return mail, nil
+}
+
+func (s *service) ListMail(userID int, query MailQuery) ([]*entity.Mail, error) {
+	mail, err := s.repo.List(repository.MailFilter{
+		UserID:       userID,
+		Unclaimed:    !query.IncludeClaimed,
+		NotExpiredAt: time.Now(),
+		Limit:        query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mail: %w", err)
+	}
+	return mail, nil
+}
+
+func (s *service) GetMail(userID, mailID int) (*entity.Mail, error) {
+	mail, err := s.repo.GetByID(mailID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMailNotFound) {
+			return nil, ErrMailNotFound
+		}
+		return nil, fmt.Errorf("failed to get mail: %w", err)
+	}
+	// 다른 사용자의 우편은 존재 여부를 노출하지 않음
+	if mail.UserID != userID {
+		return nil, ErrMailNotFound
+	}
+	return mail, nil
+}
+
+func (s *service) Claim(userID, mailID int) (*ClaimResponse, error) {
+	defer s.claimLocks.Lock(userID)()
+
+	mail, err := s.GetMail(userID, mailID)
+	if err != nil {
+		return nil, err
+	}
+	return s.claimLocked(mail, time.Now())
+}
+
+func (s *service) ClaimAll(userID int) (*ClaimAllResponse, error) {
+	defer s.claimLocks.Lock(userID)()
+
+	now := time.Now()
+	pending, err := s.repo.List(repository.MailFilter{
+		UserID:       userID,
+		Unclaimed:    true,
+		NotExpiredAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mail: %w", err)
+	}
+
+	response := &ClaimAllResponse{
+		Claimed: []*entity.Mail{},
+		Items:   []itemEntity.RewardItem{},
+	}
+	// 오래된 우편부터 수령
+	for i := len(pending) - 1; i >= 0; i-- {
+		claimed, err := s.claimLocked(pending[i], now)
+		if err != nil {
+			response.Failed = append(response.Failed, ClaimFailure{MailID: pending[i].ID, Message: err.Error()})
+			continue
+		}
+		response.Claimed = append(response.Claimed, claimed.Mail)
+		if claimed.GrantResult != nil {
+			response.Items = append(response.Items, claimed.GrantResult.Granted...)
+		}
+	}
+	response.ClaimedCount = len(response.Claimed)
+	response.Items = itemEntity.MergeRewardItems(response.Items)
+
+	s.logger.Info("Mailbox claimed",
+		zap.Int("user_id", userID),
+		zap.Int("claimed_count", response.ClaimedCount),
+		zap.Int("failed_count", len(response.Failed)))
+
+	return response, nil
+}
+
+// claimLocked grants the mail's items and marks it claimed; the caller must hold the player's claim lock.
+// Nothing is granted unless every item fits, so a full inventory leaves the mail waiting.
+func (s *service) claimLocked(mail *entity.Mail, now time.Time) (*ClaimResponse, error) {
+	if mail.IsClaimed() {
+		return nil, ErrMailClaimed
+	}
+	if mail.IsExpired(now) {
+		return nil, ErrMailExpired
+	}
+
+	var result *itemEntity.GrantResult
+	if len(mail.Items) > 0 {
+		ref := itemEntity.TransactionRef{
+			Type:  ReferenceTypeMail,
+			ID:    strconv.Itoa(mail.ID),
+			Actor: itemEntity.UserActor(mail.UserID),
+		}
+		var err error
+		result, err = s.itemService.AddMultipleToInventory(mail.UserID, mail.Items, itemEntity.OverflowReject, mail.Source, ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.MarkClaimed(mail.ID, now); err != nil {
+		// 아이템은 이미 지급되었으므로 수동 확인이 필요함
+		s.logger.Error("Failed to mark mail claimed after granting items",
+			zap.Error(err),
+			zap.Int("mail_id", mail.ID),
+			zap.Int("user_id", mail.UserID))
+		return nil, fmt.Errorf("failed to mark mail claimed: %w", err)
+	}
+	mail.ClaimedAt = &now
+
+	s.logger.Info("Mail claimed",
+		zap.Int("mail_id", mail.ID),
+		zap.Int("user_id", mail.UserID),
+		zap.String("source", mail.Source))
+
+	return &ClaimResponse{Mail: mail, GrantResult: result}, nil
+}
+
+func (s *service) ListUserMail(query AdminMailQuery) ([]*entity.Mail, error) {
+	mail, err := s.repo.List(repository.MailFilter{
+		UserID:    query.UserID,
+		Unclaimed: !query.IncludeClaimed,
+		Limit:     query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mail: %w", err)
+	}
+	return mail, nil
+}
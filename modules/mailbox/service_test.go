@@ -0,0 +1,150 @@
+package mailbox
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/mailbox/entity"
+	"fxserver/modules/mailbox/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID   = 1 // currency, takes no slot
+	potionID = 3 // stackable, takes one slot
+	userID   = 1
+)
+
+func setupMailboxService(t *testing.T) (Service, *itemtest.Fixture) {
+	items := itemtest.New()
+	svc := NewService(ServiceParam{
+		Repository:  repository.NewMemoryRepository(),
+		ItemService: items.Service,
+		Logger:      zap.NewNop(),
+	})
+	return svc, items
+}
+
+func sendCompensation(t *testing.T, svc Service, userID int, items ...itemEntity.RewardItem) int {
+	mail, err := svc.Send(SendRequest{
+		UserID: userID,
+		Title:  "Server maintenance",
+		Body:   "You received compensation from the team",
+		Items:  items,
+		Source: "compensation",
+		SentBy: itemEntity.AdminActor(1),
+	})
+	require.NoError(t, err)
+	return mail.ID
+}
+
+func TestClaimMail(t *testing.T) {
+	svc, items := setupMailboxService(t)
+	mailID := sendCompensation(t, svc, userID, itemEntity.RewardItem{ItemID: goldID, Count: 500})
+
+	// Nothing is granted until the mail is claimed
+	assert.Equal(t, 0, items.Balance(userID, goldID))
+	mail, err := svc.GetMail(userID, mailID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(DefaultMailTTL), *mail.ExpiresAt, time.Minute)
+
+	// Other users cannot see or claim the mail
+	_, err = svc.Claim(userID+1, mailID)
+	assert.ErrorIs(t, err, ErrMailNotFound)
+
+	response, err := svc.Claim(userID, mailID)
+	require.NoError(t, err)
+	assert.True(t, response.Mail.IsClaimed())
+	assert.Equal(t, 500, items.Balance(userID, goldID))
+
+	// The ledger keeps the mail's source and points at the mail
+	entries, err := items.Items.GetInventoryTransactions(itemRepository.TransactionFilter{UserID: userID, Source: "compensation"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ReferenceTypeMail, entries[0].ReferenceType)
+
+	_, err = svc.Claim(userID, mailID)
+	assert.ErrorIs(t, err, ErrMailClaimed)
+	assert.Equal(t, 500, items.Balance(userID, goldID))
+
+	unclaimed, err := svc.ListMail(userID, MailQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, unclaimed)
+	all, err := svc.ListMail(userID, MailQuery{IncludeClaimed: true})
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestClaimMailWithFullInventory(t *testing.T) {
+	svc, items := setupMailboxService(t)
+	require.NoError(t, items.Items.SetSlotCapacity(userID, 0))
+	mailID := sendCompensation(t, svc, userID, itemEntity.RewardItem{ItemID: potionID, Count: 5})
+
+	// The mail stays in the mailbox until there is room
+	_, err := svc.Claim(userID, mailID)
+	assert.ErrorIs(t, err, item.ErrInventoryFull)
+	mail, err := svc.GetMail(userID, mailID)
+	require.NoError(t, err)
+	assert.False(t, mail.IsClaimed())
+
+	require.NoError(t, items.Items.SetSlotCapacity(userID, 10))
+	_, err = svc.Claim(userID, mailID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, items.Balance(userID, potionID))
+}
+
+func TestClaimAll(t *testing.T) {
+	svc, items := setupMailboxService(t)
+	require.NoError(t, items.Items.SetSlotCapacity(userID, 0))
+	sendCompensation(t, svc, userID, itemEntity.RewardItem{ItemID: goldID, Count: 100})
+	potionMail := sendCompensation(t, svc, userID, itemEntity.RewardItem{ItemID: potionID, Count: 2})
+	sendCompensation(t, svc, userID, itemEntity.RewardItem{ItemID: goldID, Count: 50})
+
+	// Mail that does not fit is reported and left for later
+	response, err := svc.ClaimAll(userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.ClaimedCount)
+	assert.Equal(t, []itemEntity.RewardItem{{ItemID: goldID, Count: 150}}, response.Items)
+	require.Len(t, response.Failed, 1)
+	assert.Equal(t, potionMail, response.Failed[0].MailID)
+	assert.Equal(t, 150, items.Balance(userID, goldID))
+
+	remaining, err := svc.ListMail(userID, MailQuery{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, potionMail, remaining[0].ID)
+}
+
+func TestExpiredMail(t *testing.T) {
+	svc, items := setupMailboxService(t)
+
+	// Store a mail that has already expired
+	past := time.Now().Add(-time.Minute)
+	expired := &entity.Mail{
+		UserID:    userID,
+		Title:     "Old event",
+		Items:     []itemEntity.RewardItem{{ItemID: goldID, Count: 100}},
+		Source:    "event",
+		ExpiresAt: &past,
+	}
+	require.NoError(t, svc.(*service).repo.Create(expired))
+
+	_, err := svc.Claim(userID, expired.ID)
+	assert.ErrorIs(t, err, ErrMailExpired)
+	assert.Equal(t, 0, items.Balance(userID, goldID))
+
+	mail, err := svc.ListMail(userID, MailQuery{IncludeClaimed: true})
+	require.NoError(t, err)
+	assert.Empty(t, mail)
+
+	// Senders cannot set an expiry in the past
+	_, err = svc.Send(SendRequest{UserID: userID, Title: "Late", Source: "event", ExpiresAt: &past})
+	assert.ErrorIs(t, err, ErrInvalidMail)
+}
@@ -1,6 +1,7 @@
 package payment
 
 import (
+	"fxserver/pkg/i18n"
 	itemEntity "fxserver/modules/item/entity"
 	"fxserver/modules/payment/entity"
 )
@@ -63,72 +64,44 @@ type PaymentStatusesResponse struct {
 }
 
 // Helper functions
-func GetPaymentMethods() []PaymentMethodInfo {
-	return []PaymentMethodInfo{
-		{
-			Method:      entity.PaymentMethodCard,
-			Name:        "신용카드",
-			Description: entity.PaymentMethodCard.GetDescription(),
-			IsActive:    true,
-		},
-		{
-			Method:      entity.PaymentMethodBank,
-			Name:        "계좌이체",
-			Description: entity.PaymentMethodBank.GetDescription(),
-			IsActive:    true,
-		},
-		{
-			Method:      entity.PaymentMethodPaypal,
-			Name:        "PayPal",
-			Description: entity.PaymentMethodPaypal.GetDescription(),
-			IsActive:    true,
-		},
-		{
-			Method:      entity.PaymentMethodApple,
-			Name:        "Apple Pay",
-			Description: entity.PaymentMethodApple.GetDescription(),
-			IsActive:    true,
-		},
-		{
-			Method:      entity.PaymentMethodGoogle,
-			Name:        "Google Pay",
-			Description: entity.PaymentMethodGoogle.GetDescription(),
+func GetPaymentMethods(locale i18n.Locale) []PaymentMethodInfo {
+	methods := []entity.PaymentMethod{
+		entity.PaymentMethodCard,
+		entity.PaymentMethodBank,
+		entity.PaymentMethodPaypal,
+		entity.PaymentMethodApple,
+		entity.PaymentMethodGoogle,
+	}
+
+	infos := make([]PaymentMethodInfo, 0, len(methods))
+	for _, method := range methods {
+		infos = append(infos, PaymentMethodInfo{
+			Method:      method,
+			Name:        method.GetDescription(locale),
+			Description: method.GetDescription(locale),
 			IsActive:    true,
-		},
+		})
 	}
+	return infos
 }
 
-func GetPaymentStatuses() []PaymentStatusInfo {
-	return []PaymentStatusInfo{
-		{
-			Status:      entity.PaymentStatusPending,
-			Name:        "대기중",
-			Description: entity.PaymentStatusPending.GetDescription(),
-		},
-		{
-			Status:      entity.PaymentStatusProcessing,
-			Name:        "처리중",
-			Description: entity.PaymentStatusProcessing.GetDescription(),
-		},
-		{
-			Status:      entity.PaymentStatusCompleted,
-			Name:        "완료",
-			Description: entity.PaymentStatusCompleted.GetDescription(),
-		},
-		{
-			Status:      entity.PaymentStatusFailed,
-			Name:        "실패",
-			Description: entity.PaymentStatusFailed.GetDescription(),
-		},
-		{
-			Status:      entity.PaymentStatusCancelled,
-			Name:        "취소",
-			Description: entity.PaymentStatusCancelled.GetDescription(),
-		},
-		{
-			Status:      entity.PaymentStatusRefunded,
-			Name:        "환불",
-			Description: entity.PaymentStatusRefunded.GetDescription(),
-		},
+func GetPaymentStatuses(locale i18n.Locale) []PaymentStatusInfo {
+	statuses := []entity.PaymentStatus{
+		entity.PaymentStatusPending,
+		entity.PaymentStatusProcessing,
+		entity.PaymentStatusCompleted,
+		entity.PaymentStatusFailed,
+		entity.PaymentStatusCancelled,
+		entity.PaymentStatusRefunded,
+	}
+
+	infos := make([]PaymentStatusInfo, 0, len(statuses))
+	for _, status := range statuses {
+		infos = append(infos, PaymentStatusInfo{
+			Status:      status,
+			Name:        status.GetName(locale),
+			Description: status.GetDescription(locale),
+		})
 	}
-}
\ No newline at end of file
+	return infos
+}
@@ -3,6 +3,7 @@ package entity
 import (
 	"time"
 
+	"fxserver/pkg/i18n"
 	itemEntity "fxserver/modules/item/entity"
 )
 
@@ -107,42 +108,59 @@ func (p *Payment) CanBeRefunded() bool {
 	return p.Status == PaymentStatusCompleted
 }
 
-// GetStatusDescription returns description of payment status
-func (s PaymentStatus) GetDescription() string {
-	switch s {
-	case PaymentStatusPending:
-		return "결제 대기 중"
-	case PaymentStatusProcessing:
-		return "결제 처리 중"
-	case PaymentStatusCompleted:
-		return "결제 완료"
-	case PaymentStatusFailed:
-		return "결제 실패"
-	case PaymentStatusCancelled:
-		return "결제 취소"
-	case PaymentStatusRefunded:
-		return "환불 완료"
-	default:
-		return "알 수 없는 상태"
+var paymentStatusNames = map[PaymentStatus]i18n.Text{
+	PaymentStatusPending:    {i18n.Korean: "대기중", i18n.English: "Pending", i18n.Japanese: "待機中"},
+	PaymentStatusProcessing: {i18n.Korean: "처리중", i18n.English: "Processing", i18n.Japanese: "処理中"},
+	PaymentStatusCompleted:  {i18n.Korean: "완료", i18n.English: "Completed", i18n.Japanese: "完了"},
+	PaymentStatusFailed:     {i18n.Korean: "실패", i18n.English: "Failed", i18n.Japanese: "失敗"},
+	PaymentStatusCancelled:  {i18n.Korean: "취소", i18n.English: "Cancelled", i18n.Japanese: "キャンセル"},
+	PaymentStatusRefunded:   {i18n.Korean: "환불", i18n.English: "Refunded", i18n.Japanese: "返金"},
+}
+
+var paymentStatusDescriptions = map[PaymentStatus]i18n.Text{
+	PaymentStatusPending:    {i18n.Korean: "결제 대기 중", i18n.English: "Awaiting payment", i18n.Japanese: "決済待ち"},
+	PaymentStatusProcessing: {i18n.Korean: "결제 처리 중", i18n.English: "Payment is being processed", i18n.Japanese: "決済処理中"},
+	PaymentStatusCompleted:  {i18n.Korean: "결제 완료", i18n.English: "Payment completed", i18n.Japanese: "決済完了"},
+	PaymentStatusFailed:     {i18n.Korean: "결제 실패", i18n.English: "Payment failed", i18n.Japanese: "決済失敗"},
+	PaymentStatusCancelled:  {i18n.Korean: "결제 취소", i18n.English: "Payment cancelled", i18n.Japanese: "決済キャンセル"},
+	PaymentStatusRefunded:   {i18n.Korean: "환불 완료", i18n.English: "Refund completed", i18n.Japanese: "返金完了"},
+}
+
+var paymentMethodNames = map[PaymentMethod]i18n.Text{
+	PaymentMethodCard:   {i18n.Korean: "신용카드", i18n.English: "Credit card", i18n.Japanese: "クレジットカード"},
+	PaymentMethodBank:   {i18n.Korean: "계좌이체", i18n.English: "Bank transfer", i18n.Japanese: "銀行振込"},
+	PaymentMethodPaypal: {i18n.Korean: "PayPal", i18n.English: "PayPal", i18n.Japanese: "PayPal"},
+	PaymentMethodApple:  {i18n.Korean: "Apple Pay", i18n.English: "Apple Pay", i18n.Japanese: "Apple Pay"},
+	PaymentMethodGoogle: {i18n.Korean: "Google Pay", i18n.English: "Google Pay", i18n.Japanese: "Google Pay"},
+}
+
+var (
+	unknownStatus = i18n.Text{i18n.Korean: "알 수 없는 상태", i18n.English: "Unknown status", i18n.Japanese: "不明な状態"}
+	unknownMethod = i18n.Text{i18n.Korean: "알 수 없는 결제 방법", i18n.English: "Unknown payment method", i18n.Japanese: "不明な決済方法"}
+)
+
+// GetName returns the short display name of payment status in the locale
+func (s PaymentStatus) GetName(locale i18n.Locale) string {
+	if name, ok := paymentStatusNames[s]; ok {
+		return name.Get(locale)
 	}
+	return unknownStatus.Get(locale)
 }
 
-// GetMethodDescription returns description of payment method
-func (m PaymentMethod) GetDescription() string {
-	switch m {
-	case PaymentMethodCard:
-		return "신용카드"
-	case PaymentMethodBank:
-		return "계좌이체"
-	case PaymentMethodPaypal:
-		return "PayPal"
-	case PaymentMethodApple:
-		return "Apple Pay"
-	case PaymentMethodGoogle:
-		return "Google Pay"
-	default:
-		return "알 수 없는 결제 방법"
+// GetDescription returns description of payment status in the locale
+func (s PaymentStatus) GetDescription(locale i18n.Locale) string {
+	if description, ok := paymentStatusDescriptions[s]; ok {
+		return description.Get(locale)
+	}
+	return unknownStatus.Get(locale)
+}
+
+// GetDescription returns description of payment method in the locale
+func (m PaymentMethod) GetDescription(locale i18n.Locale) string {
+	if name, ok := paymentMethodNames[m]; ok {
+		return name.Get(locale)
 	}
+	return unknownMethod.Get(locale)
 }
 
 // IsValidPaymentStatus validates if the payment status is valid
@@ -7,6 +7,7 @@ import (
 
 	"fxserver/modules/payment/entity"
 	"fxserver/pkg/dto"
+	"fxserver/pkg/i18n"
 	"fxserver/pkg/validator"
 
 	"github.com/labstack/echo/v4"
@@ -133,14 +134,14 @@ func (h *Handler) GetUserPaymentSummary(c echo.Context) error {
 // GetPaymentMethods returns all available payment methods
 func (h *Handler) GetPaymentMethods(c echo.Context) error {
 	return c.JSON(http.StatusOK, PaymentMethodsResponse{
-		Methods: h.service.GetPaymentMethods(),
+		Methods: h.service.GetPaymentMethods(i18n.FromRequest(c)),
 	})
 }
 
 // GetPaymentStatuses returns all payment status types with descriptions
 func (h *Handler) GetPaymentStatuses(c echo.Context) error {
 	return c.JSON(http.StatusOK, PaymentStatusesResponse{
-		Statuses: h.service.GetPaymentStatuses(),
+		Statuses: h.service.GetPaymentStatuses(i18n.FromRequest(c)),
 	})
 }
 
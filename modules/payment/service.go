@@ -3,10 +3,13 @@ package payment
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	paymentEntity "fxserver/modules/payment/entity"
 	"fxserver/modules/payment/repository"
+	"fxserver/pkg/events"
+	"fxserver/pkg/i18n"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -21,6 +24,11 @@ var (
 	ErrInvalidAmount        = errors.New("invalid payment amount")
 )
 
+// Domain events published by the payment module
+const (
+	EventPaymentCompleted = "payment.completed" // 결제 완료 (Attributes: payment_id, currency)
+)
+
 type Service interface {
 	// Payment processing
 	ProcessPayment(req CreatePaymentRequest) (*ProcessPaymentResponse, error)
@@ -43,24 +51,27 @@ type Service interface {
 	GetPaymentSummary() (*paymentEntity.PaymentSummaryResponse, error)
 
 	// Utility
-	GetPaymentMethods() []PaymentMethodInfo
-	GetPaymentStatuses() []PaymentStatusInfo
+	GetPaymentMethods(locale i18n.Locale) []PaymentMethodInfo
+	GetPaymentStatuses(locale i18n.Locale) []PaymentStatusInfo
 }
 
 type service struct {
 	repository repository.Repository
+	events     *events.Bus
 	logger     *zap.Logger
 }
 
 type ServiceParam struct {
 	fx.In
 	Repository repository.Repository
+	Events     *events.Bus `optional:"true"`
 	Logger     *zap.Logger
 }
 
 func NewService(p ServiceParam) Service {
 	return &service{
 		repository: p.Repository,
+		events:     p.Events,
 		logger:     p.Logger,
 	}
 }
@@ -136,6 +147,8 @@ func (s *service) UpdatePaymentStatus(paymentID int, req UpdatePaymentStatusRequ
 	if err != nil {
 		return nil, ErrPaymentNotFound
 	}
+	// 저장소가 같은 포인터를 돌려줄 수 있으므로 완료 이벤트 판단에 쓸 변경 전 상태를 따로 보관
+	previousStatus := payment.Status
 
 	// Update status
 	if err := s.repository.UpdatePaymentStatus(paymentID, req.Status, req.FailureReason); err != nil {
@@ -154,9 +167,20 @@ func (s *service) UpdatePaymentStatus(paymentID int, req UpdatePaymentStatusRequ
 
 	s.logger.Info("Payment status updated", 
 		zap.Int("payment_id", paymentID),
-		zap.String("old_status", string(payment.Status)),
+		zap.String("old_status", string(previousStatus)),
 		zap.String("new_status", string(req.Status)))
 
+	if req.Status == paymentEntity.PaymentStatusCompleted && previousStatus != paymentEntity.PaymentStatusCompleted {
+		s.events.Publish(events.Event{
+			Type:   EventPaymentCompleted,
+			UserID: updatedPayment.UserID,
+			Attributes: map[string]string{
+				"payment_id": strconv.Itoa(updatedPayment.ID),
+				"currency":   updatedPayment.Currency,
+			},
+		})
+	}
+
 	return updatedPayment, nil
 }
 
@@ -343,10 +367,10 @@ func (s *service) GetPaymentSummary() (*paymentEntity.PaymentSummaryResponse, er
 	return summary, nil
 }
 
-func (s *service) GetPaymentMethods() []PaymentMethodInfo {
-	return GetPaymentMethods()
+func (s *service) GetPaymentMethods(locale i18n.Locale) []PaymentMethodInfo {
+	return GetPaymentMethods(locale)
 }
 
-func (s *service) GetPaymentStatuses() []PaymentStatusInfo {
-	return GetPaymentStatuses()
+func (s *service) GetPaymentStatuses(locale i18n.Locale) []PaymentStatusInfo {
+	return GetPaymentStatuses(locale)
 }
\ No newline at end of file
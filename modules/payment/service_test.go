@@ -8,6 +8,7 @@ import (
 	itemEntity "fxserver/modules/item/entity"
 	"fxserver/modules/payment/entity"
 	"fxserver/modules/payment/repository"
+	"fxserver/pkg/i18n"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -631,7 +632,7 @@ func TestGetUserPaymentHistory(t *testing.T) {
 func TestGetPaymentMethods(t *testing.T) {
 	service := setupPaymentService(new(MockPaymentRepository))
 
-	methods := service.GetPaymentMethods()
+	methods := service.GetPaymentMethods(i18n.FallbackLocale)
 
 	assert.NotNil(t, methods)
 	assert.Len(t, methods.Methods, 5) // 5 payment methods defined
@@ -658,7 +659,7 @@ func TestGetPaymentMethods(t *testing.T) {
 func TestGetPaymentStatuses(t *testing.T) {
 	service := setupPaymentService(new(MockPaymentRepository))
 
-	statuses := service.GetPaymentStatuses()
+	statuses := service.GetPaymentStatuses(i18n.FallbackLocale)
 
 	assert.NotNil(t, statuses)
 	assert.Len(t, statuses.Statuses, 6) // 6 payment statuses defined
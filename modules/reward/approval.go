@@ -0,0 +1,512 @@
+package reward
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"fxserver/modules/item"
+	"fxserver/modules/item/entity"
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/repository"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrGrantRequestNotFound = errors.New("grant request not found")
+	ErrGrantRequestReviewed = errors.New("grant request already reviewed")
+	ErrSelfApproval         = errors.New("grant request must be reviewed by a different admin")
+	ErrInvalidGrantRequest  = errors.New("invalid grant request")
+)
+
+const (
+	// DefaultApprovalRarity holds grants containing items of this rarity or higher
+	DefaultApprovalRarity = "legendary"
+	// DefaultApprovalValueThreshold holds grants whose total item value exceeds it
+	DefaultApprovalValueThreshold = 1_000_000
+	// DefaultApprovalUserThreshold holds grants to more users than this
+	DefaultApprovalUserThreshold = 10_000
+)
+
+// ApprovalEventType is what happened to a grant request
+type ApprovalEventType string
+
+const (
+	ApprovalEventRequested ApprovalEventType = "requested"
+	ApprovalEventApproved  ApprovalEventType = "approved"
+	ApprovalEventRejected  ApprovalEventType = "rejected"
+	ApprovalEventFailed    ApprovalEventType = "failed"
+)
+
+// ApprovalEvent is delivered to every ApprovalHook when a grant request changes
+type ApprovalEvent struct {
+	Type    ApprovalEventType
+	Request *rewardEntity.GrantRequest
+}
+
+// ApprovalHook is notified about grant requests (e.g. to alert other admins).
+// Hooks are contributed through the `group:"reward_approval_hooks"` value group and run synchronously, so they should return quickly.
+type ApprovalHook interface {
+	OnApprovalEvent(event ApprovalEvent)
+}
+
+// ApprovalThresholds decide when a grant needs a second admin; zero values disable a check
+type ApprovalThresholds struct {
+	Rarity     string // 이 등급 이상의 아이템이 포함되면 승인 필요
+	TotalValue int    // 아이템 가치 합계 (value x 수량 x 사용자 수)
+	UserCount  int    // 대상 사용자 수
+}
+
+// ApprovalService holds large reward grants until a different admin approves them
+type ApprovalService interface {
+	// Hold* store the request for approval when it exceeds a threshold and return nil when it can run right away.
+	// They fail closed: a request that cannot be validated or evaluated returns an error instead of running unchecked.
+	HoldGrant(req GrantRewardRequest) (*rewardEntity.GrantRequest, error)
+	HoldBulkGrant(req BulkGrantRewardRequest) (*rewardEntity.GrantRequest, error)
+	HoldBulkJob(req CreateBulkJobRequest) (*rewardEntity.GrantRequest, error)
+	// HoldRuleGrant holds the per-user items of the preview, so approval grants what the requester reviewed
+	HoldRuleGrant(req RuleGrantRequest, preview *RuleGrantPreview) (*rewardEntity.GrantRequest, error)
+	// CheckBulkJob returns the thresholds a bulk job would exceed without holding it,
+	// for callers that keep their own approval state (e.g. scheduled campaigns)
+	CheckBulkJob(req CreateBulkJobRequest) ([]string, error)
+
+	ListRequests(query GrantRequestQuery) ([]*rewardEntity.GrantRequest, error)
+	GetRequest(id int) (*rewardEntity.GrantRequest, error)
+	Approve(id, adminID int, note string) (*rewardEntity.GrantRequest, error)
+	Reject(id, adminID int, note string) (*rewardEntity.GrantRequest, error)
+}
+
+type approvalService struct {
+	repo        repository.Repository
+	service     Service
+	jobService  JobService
+	itemService item.Service
+	hooks       []ApprovalHook
+	thresholds  ApprovalThresholds
+	logger      *zap.Logger
+}
+
+type ApprovalServiceParam struct {
+	fx.In
+	Repository  repository.Repository
+	Service     Service
+	JobService  JobService
+	ItemService item.Service
+	Hooks       []ApprovalHook `group:"reward_approval_hooks"`
+	Logger      *zap.Logger
+}
+
+func NewApprovalService(p ApprovalServiceParam) ApprovalService {
+	return &approvalService{
+		repo:        p.Repository,
+		service:     p.Service,
+		jobService:  p.JobService,
+		itemService: p.ItemService,
+		hooks:       p.Hooks,
+		thresholds:  approvalThresholdsFromEnv(p.Logger),
+		logger:      p.Logger,
+	}
+}
+
+// approvalThresholdsFromEnv reads REWARD_APPROVAL_RARITY (none: disabled),
+// REWARD_APPROVAL_VALUE_THRESHOLD and REWARD_APPROVAL_USER_THRESHOLD (0: disabled)
+func approvalThresholdsFromEnv(logger *zap.Logger) ApprovalThresholds {
+	thresholds := ApprovalThresholds{
+		Rarity:     DefaultApprovalRarity,
+		TotalValue: intFromEnv(logger, "REWARD_APPROVAL_VALUE_THRESHOLD", DefaultApprovalValueThreshold),
+		UserCount:  intFromEnv(logger, "REWARD_APPROVAL_USER_THRESHOLD", DefaultApprovalUserThreshold),
+	}
+
+	if value := os.Getenv("REWARD_APPROVAL_RARITY"); value != "" {
+		switch {
+		case value == "none":
+			thresholds.Rarity = ""
+		case entity.IsValidRarity(value):
+			thresholds.Rarity = value
+		default:
+			logger.Warn("Invalid REWARD_APPROVAL_RARITY, using default",
+				zap.String("value", value),
+				zap.String("default", DefaultApprovalRarity))
+		}
+	}
+	return thresholds
+}
+
+func intFromEnv(logger *zap.Logger, key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		logger.Warn("Invalid "+key+", using default",
+			zap.String("value", value),
+			zap.Int("default", fallback))
+		return fallback
+	}
+	return parsed
+}
+
+func (s *approvalService) HoldGrant(req GrantRewardRequest) (*rewardEntity.GrantRequest, error) {
+	return s.hold(&rewardEntity.GrantRequest{
+		Kind:              rewardEntity.GrantRequestKindGrant,
+		UserIDs:           []int{req.UserID},
+		Items:             req.Items,
+		Source:            req.Source,
+		Description:       req.Description,
+		OverflowPolicy:    req.OverflowPolicy,
+		Delivery:          req.Delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		RequestedBy:       req.GrantedBy,
+	})
+}
+
+func (s *approvalService) HoldBulkGrant(req BulkGrantRewardRequest) (*rewardEntity.GrantRequest, error) {
+	return s.hold(&rewardEntity.GrantRequest{
+		Kind:              rewardEntity.GrantRequestKindBulkGrant,
+		UserIDs:           req.UserIDs,
+		Items:             req.Items,
+		Source:            req.Source,
+		Description:       req.Description,
+		OverflowPolicy:    req.OverflowPolicy,
+		Delivery:          req.Delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		RequestedBy:       req.GrantedBy,
+	})
+}
+
+func (s *approvalService) HoldBulkJob(req CreateBulkJobRequest) (*rewardEntity.GrantRequest, error) {
+	request, err := s.bulkJobRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.hold(request)
+}
+
+func (s *approvalService) CheckBulkJob(req CreateBulkJobRequest) ([]string, error) {
+	request, err := s.bulkJobRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	_, reasons, err := s.check(request)
+	return reasons, err
+}
+
+func (s *approvalService) bulkJobRequest(req CreateBulkJobRequest) (*rewardEntity.GrantRequest, error) {
+	// 대상 조건은 요청 시점에 사용자 목록으로 확정하여 승인자가 실제 대상을 검토함
+	userIDs, err := s.jobService.ResolveTargets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rewardEntity.GrantRequest{
+		Kind:              rewardEntity.GrantRequestKindBulkJob,
+		UserIDs:           userIDs,
+		Items:             req.Items,
+		Source:            req.Source,
+		Description:       req.Description,
+		OverflowPolicy:    req.OverflowPolicy,
+		Delivery:          req.Delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		RequestedBy:       req.GrantedBy,
+	}, nil
+}
+
+func (s *approvalService) HoldRuleGrant(req RuleGrantRequest, preview *RuleGrantPreview) (*rewardEntity.GrantRequest, error) {
+	userItems := make(map[int][]entity.RewardItem, preview.EligibleCount)
+	var userIDs []int
+	for _, result := range preview.Users {
+		if result.Error == "" && len(result.Items) > 0 {
+			userIDs = append(userIDs, result.UserID)
+			userItems[result.UserID] = result.Items
+		}
+	}
+	if len(userIDs) == 0 {
+		// 지급 대상이 없으면 보류할 것도 없음
+		return nil, nil
+	}
+
+	return s.hold(&rewardEntity.GrantRequest{
+		Kind:              rewardEntity.GrantRequestKindRuleGrant,
+		UserIDs:           userIDs,
+		Items:             preview.Totals,
+		UserItems:         userItems,
+		Source:            req.Source,
+		Description:       req.Description,
+		OverflowPolicy:    req.OverflowPolicy,
+		Delivery:          req.Delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		RequestedBy:       req.GrantedBy,
+	})
+}
+
+func (s *approvalService) hold(request *rewardEntity.GrantRequest) (*rewardEntity.GrantRequest, error) {
+	totalValue, reasons, err := s.check(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	request.TotalValue = totalValue
+	request.Reasons = reasons
+	if err := s.repo.CreateGrantRequest(request); err != nil {
+		s.logger.Error("Failed to create grant request", zap.Error(err))
+		return nil, fmt.Errorf("failed to create grant request: %w", err)
+	}
+
+	s.logger.Info("Reward grant held for approval",
+		zap.Int("request_id", request.ID),
+		zap.String("kind", string(request.Kind)),
+		zap.Int("requested_by", request.RequestedBy),
+		zap.Int("user_count", request.UserCount),
+		zap.Strings("reasons", request.Reasons))
+
+	s.notify(ApprovalEventRequested, request)
+	return request, nil
+}
+
+// check validates the request and returns its total item value and the thresholds it exceeds
+func (s *approvalService) check(request *rewardEntity.GrantRequest) (int, []string, error) {
+	if err := s.service.ValidateSource(request.Source); err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrInvalidGrantRequest, err)
+	}
+	if err := s.service.ValidateRewardItems(request.Items); err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrInvalidGrantRequest, err)
+	}
+
+	totalValue, reasons, err := s.evaluate(request.TotalItems(), len(request.UserIDs))
+	if err != nil {
+		s.logger.Error("Failed to evaluate grant request", zap.Error(err))
+		return 0, nil, fmt.Errorf("failed to evaluate grant request: %w", err)
+	}
+	return totalValue, reasons, nil
+}
+
+// evaluate returns the total item value of the grant and the thresholds it exceeds.
+// totals are the item counts summed over all users.
+func (s *approvalService) evaluate(totals []entity.RewardItem, userCount int) (int, []string, error) {
+	var reasons []string
+
+	totalValue := 0
+	rarityRank := entity.RarityRank(s.thresholds.Rarity)
+	for _, reward := range totals {
+		template, err := s.itemService.GetItem(reward.ItemID)
+		if err != nil {
+			return 0, nil, err
+		}
+		// 수량이 큰 요청이 음수로 넘치며 임계값을 피하지 않도록 포화 연산 사용
+		totalValue = rewardEntity.AddSaturating(totalValue, rewardEntity.MulSaturating(template.Value, reward.Count))
+		if rarityRank > 0 && entity.RarityRank(template.Rarity) >= rarityRank {
+			reasons = append(reasons, fmt.Sprintf("item %d is %s", reward.ItemID, template.Rarity))
+		}
+	}
+
+	if s.thresholds.TotalValue > 0 && totalValue > s.thresholds.TotalValue {
+		reasons = append(reasons, fmt.Sprintf("total value %d exceeds %d", totalValue, s.thresholds.TotalValue))
+	}
+	if s.thresholds.UserCount > 0 && userCount > s.thresholds.UserCount {
+		reasons = append(reasons, fmt.Sprintf("user count %d exceeds %d", userCount, s.thresholds.UserCount))
+	}
+	return totalValue, reasons, nil
+}
+
+func (s *approvalService) ListRequests(query GrantRequestQuery) ([]*rewardEntity.GrantRequest, error) {
+	requests, err := s.repo.ListGrantRequests(repository.GrantRequestFilter{
+		Status:      rewardEntity.GrantRequestStatus(query.Status),
+		RequestedBy: query.RequestedBy,
+		Limit:       query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list grant requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (s *approvalService) GetRequest(id int) (*rewardEntity.GrantRequest, error) {
+	request, err := s.repo.GetGrantRequest(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrGrantRequestNotFound) {
+			return nil, ErrGrantRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get grant request: %w", err)
+	}
+	return request, nil
+}
+
+func (s *approvalService) Approve(id, adminID int, note string) (*rewardEntity.GrantRequest, error) {
+	request, err := s.review(id, adminID, rewardEntity.GrantRequestStatusApproved, note)
+	if err != nil {
+		return nil, err
+	}
+
+	// 승인 상태로 먼저 전환하여 같은 요청이 두 번 실행되지 않도록 함
+	result, execErr := s.execute(request)
+	errMessage := ""
+	if execErr != nil {
+		errMessage = execErr.Error()
+	}
+
+	completed, err := s.repo.CompleteGrantRequest(id, result, errMessage)
+	if err != nil {
+		s.logger.Error("Failed to store grant request result", zap.Error(err), zap.Int("request_id", id))
+		return nil, fmt.Errorf("failed to store grant request result: %w", err)
+	}
+
+	if execErr != nil {
+		s.logger.Warn("Approved reward grant failed",
+			zap.Error(execErr),
+			zap.Int("request_id", id),
+			zap.Int("approved_by", adminID))
+		s.notify(ApprovalEventFailed, completed)
+		return completed, nil
+	}
+
+	s.logger.Info("Reward grant approved",
+		zap.Int("request_id", id),
+		zap.String("kind", string(completed.Kind)),
+		zap.Int("requested_by", completed.RequestedBy),
+		zap.Int("approved_by", adminID))
+
+	s.notify(ApprovalEventApproved, completed)
+	return completed, nil
+}
+
+func (s *approvalService) Reject(id, adminID int, note string) (*rewardEntity.GrantRequest, error) {
+	request, err := s.review(id, adminID, rewardEntity.GrantRequestStatusRejected, note)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Reward grant rejected",
+		zap.Int("request_id", id),
+		zap.Int("requested_by", request.RequestedBy),
+		zap.Int("rejected_by", adminID))
+
+	s.notify(ApprovalEventRejected, request)
+	return request, nil
+}
+
+func (s *approvalService) review(id, adminID int, status rewardEntity.GrantRequestStatus, note string) (*rewardEntity.GrantRequest, error) {
+	request, err := s.GetRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsPending() {
+		return nil, ErrGrantRequestReviewed
+	}
+	if request.RequestedBy == adminID {
+		return nil, ErrSelfApproval
+	}
+
+	reviewed, err := s.repo.ReviewGrantRequest(id, status, adminID, note)
+	if err != nil {
+		if errors.Is(err, repository.ErrGrantRequestReviewed) {
+			return nil, ErrGrantRequestReviewed
+		}
+		return nil, fmt.Errorf("failed to review grant request: %w", err)
+	}
+	return reviewed, nil
+}
+
+// execute runs the held request as the requesting admin
+func (s *approvalService) execute(request *rewardEntity.GrantRequest) (interface{}, error) {
+	switch request.Kind {
+	case rewardEntity.GrantRequestKindGrant:
+		response, err := s.service.GrantRewards(GrantRewardRequest{
+			UserID:            request.UserIDs[0],
+			Items:             request.Items,
+			Source:            request.Source,
+			Description:       request.Description,
+			OverflowPolicy:    request.OverflowPolicy,
+			Delivery:          request.Delivery,
+			MailTitle:         request.MailTitle,
+			MailExpiresInDays: request.MailExpiresInDays,
+			GrantedBy:         request.RequestedBy,
+		})
+		if response == nil {
+			return nil, err
+		}
+		return response, err
+
+	case rewardEntity.GrantRequestKindBulkGrant:
+		response, err := s.service.BulkGrantRewards(BulkGrantRewardRequest{
+			UserIDs:           request.UserIDs,
+			Items:             request.Items,
+			Source:            request.Source,
+			Description:       request.Description,
+			OverflowPolicy:    request.OverflowPolicy,
+			Delivery:          request.Delivery,
+			MailTitle:         request.MailTitle,
+			MailExpiresInDays: request.MailExpiresInDays,
+			GrantedBy:         request.RequestedBy,
+		})
+		if response == nil {
+			return nil, err
+		}
+		return response, err
+
+	case rewardEntity.GrantRequestKindBulkJob:
+		job, err := s.jobService.CreateJob(CreateBulkJobRequest{
+			UserIDs:           request.UserIDs,
+			Items:             request.Items,
+			Source:            request.Source,
+			Description:       request.Description,
+			OverflowPolicy:    request.OverflowPolicy,
+			Delivery:          request.Delivery,
+			MailTitle:         request.MailTitle,
+			MailExpiresInDays: request.MailExpiresInDays,
+			GrantedBy:         request.RequestedBy,
+		})
+		if job == nil {
+			return nil, err
+		}
+		return job, err
+
+	case rewardEntity.GrantRequestKindRuleGrant:
+		return grantEach(s.service, GrantRewardRequest{
+			Source:            request.Source,
+			Description:       request.Description,
+			OverflowPolicy:    request.OverflowPolicy,
+			Delivery:          request.Delivery,
+			MailTitle:         request.MailTitle,
+			MailExpiresInDays: request.MailExpiresInDays,
+			GrantedBy:         request.RequestedBy,
+		}, request.UserIDs, request.UserItems), nil
+	}
+	return nil, fmt.Errorf("unknown grant request kind: %s", request.Kind)
+}
+
+func (s *approvalService) notify(eventType ApprovalEventType, request *rewardEntity.GrantRequest) {
+	for _, hook := range s.hooks {
+		hook.OnApprovalEvent(ApprovalEvent{Type: eventType, Request: request})
+	}
+}
+
+// Default approval hooks
+
+// logApprovalHook records approval events so they show up in the server log
+type logApprovalHook struct {
+	logger *zap.Logger
+}
+
+func NewLogApprovalHook(logger *zap.Logger) ApprovalHook {
+	return &logApprovalHook{logger: logger}
+}
+
+func (h *logApprovalHook) OnApprovalEvent(event ApprovalEvent) {
+	h.logger.Info("Reward approval event",
+		zap.String("event", string(event.Type)),
+		zap.Int("request_id", event.Request.ID),
+		zap.String("status", string(event.Request.Status)),
+		zap.Int("requested_by", event.Request.RequestedBy),
+		zap.Int("reviewed_by", event.Request.ReviewedBy))
+}
@@ -0,0 +1,179 @@
+package reward
+
+import (
+	"math"
+	"testing"
+
+	"fxserver/modules/item/entity"
+	itemRepository "fxserver/modules/item/repository"
+	rewardEntity "fxserver/modules/reward/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const swordID = 4 // legendary equipment, granted as instances
+
+type recordingHook struct {
+	events []ApprovalEventType
+}
+
+func (h *recordingHook) OnApprovalEvent(event ApprovalEvent) {
+	h.events = append(h.events, event.Type)
+}
+
+func setupApprovalService(t *testing.T) (ApprovalService, itemRepository.Repository, *recordingHook) {
+	jobs, items, _ := setupJobService(t)
+	hook := &recordingHook{}
+	logger := zap.NewNop()
+	approvals := NewApprovalService(ApprovalServiceParam{
+		Repository:  jobs.repo,
+		Service:     jobs.service,
+		JobService:  jobs,
+		ItemService: jobs.service.(*service).itemService,
+		Hooks:       []ApprovalHook{hook},
+		Logger:      logger,
+	})
+	return approvals, items, hook
+}
+
+func grantRequest(itemID, count int) GrantRewardRequest {
+	return GrantRewardRequest{
+		UserID:      1,
+		Items:       []entity.RewardItem{{ItemID: itemID, Count: count}},
+		Source:      RewardSourceEvent,
+		Description: "Tournament winner reward",
+		GrantedBy:   1,
+	}
+}
+
+func TestApproveHeldGrant(t *testing.T) {
+	approvals, items, hook := setupApprovalService(t)
+
+	// Small grants run right away
+	held, err := approvals.HoldGrant(grantRequest(goldID, 100))
+	require.NoError(t, err)
+	assert.Nil(t, held)
+
+	held, err = approvals.HoldGrant(grantRequest(swordID, 1))
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	assert.Equal(t, rewardEntity.GrantRequestStatusPending, held.Status)
+	assert.Equal(t, []string{"item 4 is legendary"}, held.Reasons)
+
+	// Nothing is granted while the request waits
+	instances, err := items.GetUserInstances(1)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+
+	_, err = approvals.Approve(held.ID, 1, "")
+	assert.ErrorIs(t, err, ErrSelfApproval)
+
+	approved, err := approvals.Approve(held.ID, 2, "Checked with the tournament team")
+	require.NoError(t, err)
+	assert.Equal(t, rewardEntity.GrantRequestStatusApproved, approved.Status)
+	assert.Equal(t, 2, approved.ReviewedBy)
+	assert.NotNil(t, approved.Result)
+
+	instances, err = items.GetUserInstances(1)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, swordID, instances[0].ItemID)
+
+	_, err = approvals.Approve(held.ID, 3, "")
+	assert.ErrorIs(t, err, ErrGrantRequestReviewed)
+	assert.Equal(t, []ApprovalEventType{ApprovalEventRequested, ApprovalEventApproved}, hook.events)
+}
+
+func TestRejectHeldGrant(t *testing.T) {
+	approvals, items, hook := setupApprovalService(t)
+
+	held, err := approvals.HoldGrant(grantRequest(swordID, 1))
+	require.NoError(t, err)
+	require.NotNil(t, held)
+
+	rejected, err := approvals.Reject(held.ID, 2, "Not announced")
+	require.NoError(t, err)
+	assert.Equal(t, rewardEntity.GrantRequestStatusRejected, rejected.Status)
+	assert.Equal(t, "Not announced", rejected.ReviewNote)
+
+	instances, err := items.GetUserInstances(1)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+	assert.Equal(t, []ApprovalEventType{ApprovalEventRequested, ApprovalEventRejected}, hook.events)
+
+	pending, err := approvals.ListRequests(GrantRequestQuery{Status: string(rewardEntity.GrantRequestStatusPending)})
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestApprovalThresholds(t *testing.T) {
+	t.Setenv("REWARD_APPROVAL_RARITY", "none")
+	t.Setenv("REWARD_APPROVAL_VALUE_THRESHOLD", "1000")
+	t.Setenv("REWARD_APPROVAL_USER_THRESHOLD", "2")
+	approvals, _, _ := setupApprovalService(t)
+
+	held, err := approvals.HoldGrant(grantRequest(swordID, 1))
+	require.NoError(t, err)
+	assert.Nil(t, held)
+
+	// Value counts every recipient
+	held, err = approvals.HoldBulkGrant(BulkGrantRewardRequest{
+		UserIDs:     []int{1, 2},
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: 600}},
+		Source:      RewardSourceEvent,
+		Description: "Tournament winner reward",
+		GrantedBy:   1,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	assert.Equal(t, 1200, held.TotalValue)
+	assert.Equal(t, []string{"total value 1200 exceeds 1000"}, held.Reasons)
+
+	job := jobRequest(1, 2, 3)
+	held, err = approvals.HoldBulkJob(job)
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	assert.Equal(t, rewardEntity.GrantRequestKindBulkJob, held.Kind)
+	assert.Equal(t, 3, held.UserCount)
+	assert.Equal(t, []string{"user count 3 exceeds 2"}, held.Reasons)
+
+	approved, err := approvals.Approve(held.ID, 2, "")
+	require.NoError(t, err)
+	created, ok := approved.Result.(*rewardEntity.BulkGrantJob)
+	require.True(t, ok)
+	assert.Equal(t, 3, created.Total)
+}
+
+func TestHoldFailsClosed(t *testing.T) {
+	t.Setenv("REWARD_APPROVAL_RARITY", "none")
+	t.Setenv("REWARD_APPROVAL_VALUE_THRESHOLD", "1000")
+	approvals, _, _ := setupApprovalService(t)
+
+	// Requests that cannot be checked are refused instead of running unchecked
+	req := grantRequest(goldID, 1)
+	req.Source = "unknown"
+	held, err := approvals.HoldGrant(req)
+	assert.ErrorIs(t, err, ErrInvalidGrantRequest)
+	assert.Nil(t, held)
+
+	held, err = approvals.HoldGrant(grantRequest(999, 1))
+	assert.ErrorIs(t, err, ErrInvalidGrantRequest)
+	assert.Nil(t, held)
+
+	_, err = approvals.HoldBulkJob(jobRequest())
+	assert.ErrorIs(t, err, ErrNoTargetUsers)
+
+	// A value that overflows int saturates and still exceeds the threshold
+	held, err = approvals.HoldBulkGrant(BulkGrantRewardRequest{
+		UserIDs:     []int{1, 2, 3},
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: math.MaxInt/2 + 1}},
+		Source:      RewardSourceEvent,
+		Description: "Tournament winner reward",
+		GrantedBy:   1,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	assert.Equal(t, math.MaxInt, held.TotalValue)
+}
@@ -1,6 +1,12 @@
 package reward
 
-import "fxserver/modules/item/entity"
+import (
+	"time"
+
+	"fxserver/modules/item/entity"
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/pkg/i18n"
+)
 
 // Grant rewards DTOs
 type GrantRewardRequest struct {
@@ -8,6 +14,13 @@ type GrantRewardRequest struct {
 	Items       []entity.RewardItem   `json:"items" validate:"required,min=1,dive"`
 	Source      string                `json:"source" validate:"required,min=2,max=50"` // admin, event, compensation, etc.
 	Description string                `json:"description" validate:"required,min=5,max=500"`
+	OverflowPolicy entity.OverflowPolicy `json:"overflow_policy,omitempty" validate:"omitempty,oneof=reject truncate mailbox"` // 미지정 시 서버 기본값
+	Delivery    string                `json:"delivery,omitempty" validate:"omitempty,oneof=direct mailbox"` // 미지정 시 direct
+	MailTitle   string                `json:"mail_title,omitempty" validate:"omitempty,max=100"`            // 우편 제목 (미지정 시 출처 설명)
+	MailExpiresInDays int             `json:"mail_expires_in_days,omitempty" validate:"omitempty,gt=0,lte=365"` // 기본값: 30
+	DryRun      bool                  `json:"dry_run,omitempty"` // true이면 검사만 하고 지급하지 않음
+	GrantedBy   int                   `json:"-"` // 지급한 관리자 ID (핸들러에서 설정)
+	JobID       int                   `json:"-"` // 일괄 지급 작업에서 호출한 경우
 }
 
 type BulkGrantRewardRequest struct {
@@ -15,6 +28,12 @@ type BulkGrantRewardRequest struct {
 	Items       []entity.RewardItem   `json:"items" validate:"required,min=1,dive"`
 	Source      string                `json:"source" validate:"required,min=2,max=50"`
 	Description string                `json:"description" validate:"required,min=5,max=500"`
+	OverflowPolicy entity.OverflowPolicy `json:"overflow_policy,omitempty" validate:"omitempty,oneof=reject truncate mailbox"` // 미지정 시 서버 기본값
+	Delivery    string                `json:"delivery,omitempty" validate:"omitempty,oneof=direct mailbox"` // 미지정 시 direct
+	MailTitle   string                `json:"mail_title,omitempty" validate:"omitempty,max=100"`            // 우편 제목 (미지정 시 출처 설명)
+	MailExpiresInDays int             `json:"mail_expires_in_days,omitempty" validate:"omitempty,gt=0,lte=365"` // 기본값: 30
+	DryRun      bool                  `json:"dry_run,omitempty"` // true이면 검사만 하고 지급하지 않음
+	GrantedBy   int                   `json:"-"` // 지급한 관리자 ID (핸들러에서 설정)
 }
 
 // Response DTOs
@@ -24,6 +43,8 @@ type GrantRewardResponse struct {
 	Source      string                `json:"source"`
 	Description string                `json:"description"`
 	GrantedAt   string                `json:"granted_at"`
+	GrantResult *entity.GrantResult   `json:"grant_result,omitempty"` // 적용된 초과 정책과 실제 지급/초과 수량
+	MailID      int                   `json:"mail_id,omitempty"`      // 우편함으로 발송한 경우 (mailbox 정책의 초과분 포함)
 	Success     bool                  `json:"success"`
 	Message     string                `json:"message,omitempty"`
 }
@@ -36,9 +57,50 @@ type BulkGrantRewardResponse struct {
 	Items         []entity.RewardItem   `json:"items"`
 	Source        string                `json:"source"`
 	Description   string                `json:"description"`
+	DryRun        bool                  `json:"dry_run,omitempty"`
+	Totals        []entity.RewardItem   `json:"totals,omitempty"` // dry_run: 지급 가능한 사용자 기준 아이템별 총 수량
+}
+
+// Grant history DTOs
+type GrantHistoryQuery struct {
+	UserID    int    `query:"user_id" validate:"omitempty,gt=0"`
+	Source    string `query:"source"`
+	Actor     string `query:"actor"` // admin:1, system
+	Status    string `query:"status" validate:"omitempty,oneof=success failed"`
+	StartDate string `query:"start_date"` // YYYY-MM-DD format
+	EndDate   string `query:"end_date"`   // YYYY-MM-DD format (inclusive)
+	Limit     int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type MyRewardsQuery struct {
+	Source    string `query:"source"`
+	StartDate string `query:"start_date"` // YYYY-MM-DD format
+	EndDate   string `query:"end_date"`   // YYYY-MM-DD format (inclusive)
+	Limit     int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+// UserRewardResponse is a successful grant as shown to the receiving player
+type UserRewardResponse struct {
+	ID                int                 `json:"id"`
+	Items             []entity.RewardItem `json:"items"`
+	Source            string              `json:"source"`
+	SourceDescription string              `json:"source_description"`
+	Description       string              `json:"description"`
+	Delivery          string              `json:"delivery"`
+	MailID            int                 `json:"mail_id,omitempty"` // 우편함으로 받은 경우
+	GrantedAt         time.Time           `json:"granted_at"`
 }
 
-// Reward source constants
+// Inventory ledger reference type for admin grants
+const ReferenceTypeRewardGrant = "reward_grant"
+
+// Reward delivery methods
+const (
+	DeliveryDirect  = "direct"  // 인벤토리에 바로 지급
+	DeliveryMailbox = "mailbox" // 우편함으로 발송, 사용자가 직접 수령
+)
+
+// Built-in reward sources, seeded into the source store; admins can add more at runtime
 const (
 	RewardSourceAdmin        = "admin"        // 관리자 직접 지급
 	RewardSourceCoupon       = "coupon"       // 쿠폰 사용
@@ -47,38 +109,116 @@ const (
 	RewardSourceCompensation = "compensation" // 보상/사과
 	RewardSourceDaily        = "daily"        // 일일 보상
 	RewardSourceAchievement  = "achievement"  // 업적 달성
+	RewardSourceGacha        = "gacha"        // 가챠 뽑기 결과
 )
 
-// IsValidRewardSource validates if the reward source is valid
-func IsValidRewardSource(source string) bool {
-	switch source {
-	case RewardSourceAdmin, RewardSourceCoupon, RewardSourcePayment,
-		 RewardSourceEvent, RewardSourceCompensation, RewardSourceDaily,
-		 RewardSourceAchievement:
-		return true
-	default:
-		return false
-	}
-}
-
-// GetRewardSourceDescription returns description for reward source
-func GetRewardSourceDescription(source string) string {
-	switch source {
-	case RewardSourceAdmin:
-		return "관리자 직접 지급"
-	case RewardSourceCoupon:
-		return "쿠폰 사용 보상"
-	case RewardSourcePayment:
-		return "결제 완료 보상"
-	case RewardSourceEvent:
-		return "이벤트 보상"
-	case RewardSourceCompensation:
-		return "보상/사과"
-	case RewardSourceDaily:
-		return "일일 보상"
-	case RewardSourceAchievement:
-		return "업적 달성 보상"
-	default:
-		return "알 수 없는 보상 출처"
-	}
-}
\ No newline at end of file
+// Bulk grant job DTOs
+type CreateBulkJobRequest struct {
+	UserIDs           []int                 `json:"user_ids,omitempty" validate:"omitempty,dive,gt=0"` // CSV 업로드 시 파일에서 채워짐
+	Target            *BulkJobTarget        `json:"target,omitempty"`                                   // user_ids 대신 조건으로 대상 지정
+	Items             []entity.RewardItem   `json:"items" validate:"required,min=1,dive"`
+	Source            string                `json:"source" validate:"required,min=2,max=50"`
+	Description       string                `json:"description" validate:"required,min=5,max=500"`
+	OverflowPolicy    entity.OverflowPolicy `json:"overflow_policy,omitempty" validate:"omitempty,oneof=reject truncate mailbox"`
+	Delivery          string                `json:"delivery,omitempty" validate:"omitempty,oneof=direct mailbox"`
+	MailTitle         string                `json:"mail_title,omitempty" validate:"omitempty,max=100"`
+	MailExpiresInDays int                   `json:"mail_expires_in_days,omitempty" validate:"omitempty,gt=0,lte=365"`
+	GrantedBy         int                   `json:"-"` // 지급한 관리자 ID (핸들러에서 설정)
+	Reference         string                `json:"-"` // 다른 모듈이 만든 작업의 식별자 (예: campaign:3), 같은 값으로 다시 요청하면 기존 작업을 반환
+}
+
+// BulkJobTarget selects users by their account attributes; the matching users are fixed when the job is created
+type BulkJobTarget struct {
+	AllUsers    bool   `json:"all_users,omitempty"`
+	CreatedFrom string `json:"created_from,omitempty"` // YYYY-MM-DD format
+	CreatedTo   string `json:"created_to,omitempty"`   // YYYY-MM-DD format (inclusive)
+	MinAge      int    `json:"min_age,omitempty" validate:"omitempty,gte=0"`
+	MaxAge      int    `json:"max_age,omitempty" validate:"omitempty,gte=0"`
+}
+
+type BulkJobQuery struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending running completed cancelled"`
+	Limit  int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type BulkJobResponse struct {
+	*rewardEntity.BulkGrantJob
+	Progress float64 `json:"progress"` // 처리율 (%)
+}
+
+// Rule grant DTOs
+type RuleGrantRequest struct {
+	UserIDs           []int                 `json:"user_ids,omitempty" validate:"omitempty,dive,gt=0"`
+	Target            *BulkJobTarget        `json:"target,omitempty"` // user_ids 대신 조건으로 대상 지정
+	Rules             []RewardRule          `json:"rules" validate:"required,min=1,max=20,dive"`
+	Params            map[string]float64    `json:"params,omitempty"` // 규칙에서 참조하는 입력값 (예: {"downtime_hours": 3})
+	Source            string                `json:"source" validate:"required,min=2,max=50"`
+	Description       string                `json:"description" validate:"required,min=5,max=500"`
+	OverflowPolicy    entity.OverflowPolicy `json:"overflow_policy,omitempty" validate:"omitempty,oneof=reject truncate mailbox"`
+	Delivery          string                `json:"delivery,omitempty" validate:"omitempty,oneof=direct mailbox"`
+	MailTitle         string                `json:"mail_title,omitempty" validate:"omitempty,max=100"`
+	MailExpiresInDays int                   `json:"mail_expires_in_days,omitempty" validate:"omitempty,gt=0,lte=365"`
+	DryRun            bool                  `json:"dry_run,omitempty"` // true이면 사용자별 계산 결과만 반환하고 지급하지 않음
+	GrantedBy         int                   `json:"-"`                 // 지급한 관리자 ID (핸들러에서 설정)
+}
+
+// RewardRule computes one item's count per user, e.g. "downtime_hours * 10 * (payment_count_30d > 0 ? 2 : 1)"
+type RewardRule struct {
+	ItemID    int    `json:"item_id" validate:"required,gt=0"`
+	Count     string `json:"count" validate:"required,max=1000"`               // 수량 계산식, 소수점 이하는 버리고 0 이하이면 지급하지 않음
+	Condition string `json:"condition,omitempty" validate:"omitempty,max=1000"` // 지정 시 참(0이 아님)인 사용자에게만 적용
+}
+
+type RuleGrantPreview struct {
+	TotalUsers    int                 `json:"total_users"`
+	EligibleCount int                 `json:"eligible_count"` // 지급할 아이템이 있는 사용자 수
+	SkippedCount  int                 `json:"skipped_count"`  // 계산 결과 지급할 아이템이 없는 사용자 수
+	ErrorCount    int                 `json:"error_count"`    // 변수 조회나 계산에 실패한 사용자 수
+	Totals        []entity.RewardItem `json:"totals"`         // 아이템별 총 지급 수량
+	Users         []RuleUserResult    `json:"users"`
+}
+
+type RuleUserResult struct {
+	UserID    int                 `json:"user_id"`
+	Variables map[string]float64  `json:"variables,omitempty"` // 규칙에서 참조한 변수 값
+	Items     []entity.RewardItem `json:"items"`
+	Error     string              `json:"error,omitempty"`
+}
+
+type GrantRequestQuery struct {
+	Status      string `query:"status" validate:"omitempty,oneof=pending approved rejected failed"`
+	RequestedBy int    `query:"requested_by" validate:"omitempty,gt=0"`
+	Limit       int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type ReviewGrantRequestRequest struct {
+	Note string `json:"note,omitempty" validate:"omitempty,max=500"` // 승인/반려 사유
+}
+
+type GrantRequestResponse struct {
+	*rewardEntity.GrantRequest
+	UserIDs   []int                       `json:"user_ids"`
+	UserItems map[int][]entity.RewardItem `json:"user_items,omitempty"` // 규칙 지급: 사용자별 지급 아이템
+}
+
+// Reward source management DTOs (Admin only)
+type CreateSourceRequest struct {
+	Key           string    `json:"key" validate:"required,min=2,max=50"` // 소문자, 숫자, _ (예: halloween_2026)
+	Descriptions  i18n.Text `json:"descriptions" validate:"required"`     // 언어별 설명 (ko 필수)
+	Active        *bool     `json:"active,omitempty"`                     // 기본값: true
+	DailyGrantCap int       `json:"daily_grant_cap,omitempty" validate:"omitempty,gte=0"`
+	DailyUserCap  int       `json:"daily_user_cap,omitempty" validate:"omitempty,gte=0"`
+	Paid          bool      `json:"paid,omitempty"` // 유료 보상 출처 (초과분 우편이 만료되지 않음)
+}
+
+type UpdateSourceRequest struct {
+	Descriptions  i18n.Text `json:"descriptions,omitempty"` // 지정 시 전체 교체
+	Active        *bool     `json:"active,omitempty"`
+	DailyGrantCap *int      `json:"daily_grant_cap,omitempty" validate:"omitempty,gte=0"` // 0으로 설정 시 무제한
+	DailyUserCap  *int      `json:"daily_user_cap,omitempty" validate:"omitempty,gte=0"`  // 0으로 설정 시 무제한
+	Paid          *bool     `json:"paid,omitempty"`
+}
+
+type SourceQuery struct {
+	IncludeInactive bool `query:"include_inactive"`
+}
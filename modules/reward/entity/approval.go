@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"math"
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// GrantRequestKind is the admin operation held for approval
+type GrantRequestKind string
+
+const (
+	GrantRequestKindGrant     GrantRequestKind = "grant"      // POST /admin/rewards/grant
+	GrantRequestKindBulkGrant GrantRequestKind = "bulk_grant" // POST /admin/rewards/bulk-grant
+	GrantRequestKindBulkJob   GrantRequestKind = "bulk_job"   // POST /admin/rewards/bulk-jobs
+	GrantRequestKindRuleGrant GrantRequestKind = "rule_grant" // POST /admin/rewards/rule-grant
+)
+
+// GrantRequestStatus is the review state of a grant request
+type GrantRequestStatus string
+
+const (
+	GrantRequestStatusPending  GrantRequestStatus = "pending"  // 승인 대기
+	GrantRequestStatusApproved GrantRequestStatus = "approved" // 승인 후 지급 완료
+	GrantRequestStatusRejected GrantRequestStatus = "rejected" // 반려
+	GrantRequestStatusFailed   GrantRequestStatus = "failed"   // 승인되었으나 지급 실패
+)
+
+// GrantRequest is a reward grant above the approval thresholds.
+// It keeps the original request and runs it only after a different admin approves it.
+type GrantRequest struct {
+	ID                int                             `json:"id"`
+	Kind              GrantRequestKind                `json:"kind"`
+	Status            GrantRequestStatus              `json:"status"`
+	UserIDs           []int                           `json:"-"` // 상세 조회에서만 노출
+	UserCount         int                             `json:"user_count"`
+	Items             []itemEntity.RewardItem         `json:"items"`
+	UserItems         map[int][]itemEntity.RewardItem `json:"-"` // 규칙 지급: 요청 시점에 계산한 사용자별 아이템 (Items는 합계)
+	Source            string                          `json:"source"`
+	Description       string                          `json:"description"`
+	OverflowPolicy    itemEntity.OverflowPolicy       `json:"overflow_policy,omitempty"`
+	Delivery          string                          `json:"delivery,omitempty"`
+	MailTitle         string                          `json:"mail_title,omitempty"`
+	MailExpiresInDays int                             `json:"mail_expires_in_days,omitempty"`
+	TotalValue        int                             `json:"total_value"`
+	Reasons           []string                        `json:"reasons"` // 승인이 필요한 이유
+	RequestedBy       int                             `json:"requested_by"`
+	ReviewedBy        int                             `json:"reviewed_by,omitempty"`
+	ReviewNote        string                          `json:"review_note,omitempty"`
+	Result            interface{}                     `json:"result,omitempty"` // 승인 후 실행 결과
+	Error             string                          `json:"error,omitempty"`  // 실행 실패 사유
+	CreatedAt         time.Time                       `json:"created_at"`
+	ReviewedAt        *time.Time                      `json:"reviewed_at,omitempty"`
+}
+
+// TotalItems returns the item counts summed over all target users
+func (r *GrantRequest) TotalItems() []itemEntity.RewardItem {
+	if r.UserItems != nil {
+		var items []itemEntity.RewardItem
+		for _, userID := range r.UserIDs {
+			items = append(items, r.UserItems[userID]...)
+		}
+		return itemEntity.MergeRewardItems(items)
+	}
+
+	totals := make([]itemEntity.RewardItem, len(r.Items))
+	for i, item := range r.Items {
+		totals[i] = itemEntity.RewardItem{ItemID: item.ItemID, Count: MulSaturating(item.Count, len(r.UserIDs))}
+	}
+	return totals
+}
+
+// IsPending reports whether the request still waits for review
+func (r *GrantRequest) IsPending() bool {
+	return r.Status == GrantRequestStatusPending
+}
+
+// MulSaturating multiplies non-negative counts, clamping at math.MaxInt instead of wrapping around
+func MulSaturating(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if a > math.MaxInt/b {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// AddSaturating adds non-negative counts, clamping at math.MaxInt instead of wrapping around
+func AddSaturating(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
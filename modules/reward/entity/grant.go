@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// GrantStatus is the outcome of a reward grant
+type GrantStatus string
+
+const (
+	GrantStatusSuccess GrantStatus = "success"
+	GrantStatusFailed  GrantStatus = "failed"
+)
+
+// RewardGrant records one reward grant to one user, whether it succeeded or not
+type RewardGrant struct {
+	ID          int                     `json:"id"`
+	UserID      int                     `json:"user_id"`
+	Items       []itemEntity.RewardItem `json:"items"`
+	Source      string                  `json:"source"`
+	Description string                  `json:"description"`
+	Actor       string                  `json:"actor"`                  // admin:1, user:5, system
+	GrantedBy   int                     `json:"granted_by,omitempty"`   // 지급한 관리자 ID
+	Delivery    string                  `json:"delivery"`               // direct, mailbox
+	MailID      int                     `json:"mail_id,omitempty"`      // 우편함으로 발송한 경우 (mailbox 정책의 초과분 포함)
+	JobID       int                     `json:"job_id,omitempty"`       // 일괄 지급 작업으로 지급한 경우
+	GrantResult *itemEntity.GrantResult `json:"grant_result,omitempty"` // 직접 지급한 경우
+	Status      GrantStatus             `json:"status"`
+	Message     string                  `json:"message,omitempty"` // 실패 사유
+	CreatedAt   time.Time               `json:"created_at"`
+}
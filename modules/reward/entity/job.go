@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// JobStatus is the lifecycle state of a bulk grant job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"   // 대기 중
+	JobStatusRunning   JobStatus = "running"   // 처리 중 (재시작 시 이어서 처리)
+	JobStatusCompleted JobStatus = "completed" // 완료
+	JobStatusCancelled JobStatus = "cancelled" // 취소됨
+)
+
+// BulkGrantJob grants the same rewards to a snapshot of users in the background.
+// Processed is the checkpoint: users before it are done and are skipped when the job resumes.
+type BulkGrantJob struct {
+	ID                int                       `json:"id"`
+	Status            JobStatus                 `json:"status"`
+	Items             []itemEntity.RewardItem   `json:"items"`
+	Source            string                    `json:"source"`
+	Description       string                    `json:"description"`
+	OverflowPolicy    itemEntity.OverflowPolicy `json:"overflow_policy,omitempty"`
+	Delivery          string                    `json:"delivery"`
+	MailTitle         string                    `json:"mail_title,omitempty"`
+	MailExpiresInDays int                       `json:"mail_expires_in_days,omitempty"`
+	GrantedBy         int                       `json:"granted_by,omitempty"`
+	Reference         string                    `json:"reference,omitempty"` // 작업을 만든 기능의 식별자 (예: campaign:3), 같은 값으로는 한 번만 생성됨
+	UserIDs           []int                     `json:"-"`                   // 대상 사용자 (생성 시점 스냅샷)
+	Total             int                       `json:"total"`
+	Processed         int                       `json:"processed"`
+	SuccessCount      int                       `json:"success_count"`
+	FailureCount      int                       `json:"failure_count"`
+	Failures          []JobFailure              `json:"-"` // 실패 목록은 별도 API로 조회
+	CreatedAt         time.Time                 `json:"created_at"`
+	StartedAt         *time.Time                `json:"started_at,omitempty"`
+	FinishedAt        *time.Time                `json:"finished_at,omitempty"`
+}
+
+// JobFailure is one user the job could not grant to
+type JobFailure struct {
+	UserID  int    `json:"user_id"`
+	Message string `json:"message"`
+}
+
+// IsFinished reports whether the job will not process any more users
+func (j *BulkGrantJob) IsFinished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusCancelled
+}
+
+// Progress returns the processed share of users as a percentage
+func (j *BulkGrantJob) Progress() float64 {
+	if j.Total == 0 {
+		return 100
+	}
+	return float64(j.Processed) * 100 / float64(j.Total)
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"fxserver/pkg/i18n"
+)
+
+// RewardSource is an admin-managed reason for granting rewards (e.g. "event", "halloween_2026").
+// Only active sources can be used for new grants; caps count successful grants per UTC day.
+type RewardSource struct {
+	Key           string    `json:"key"`
+	Descriptions  i18n.Text `json:"descriptions"` // 언어별 설명 (기본 언어 필수)
+	Active        bool      `json:"active"`
+	DailyGrantCap int       `json:"daily_grant_cap,omitempty"` // 하루 전체 지급 횟수 (0: 무제한)
+	DailyUserCap  int       `json:"daily_user_cap,omitempty"`  // 사용자별 하루 지급 횟수 (0: 무제한)
+	Paid          bool      `json:"paid,omitempty"`            // 유료로 얻은 보상 (초과분 우편이 만료되지 않음)
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// HasDailyCap reports whether grants under the source are capped
+func (s *RewardSource) HasDailyCap() bool {
+	return s.DailyGrantCap > 0 || s.DailyUserCap > 0
+}
@@ -1,9 +1,20 @@
 package reward
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	rewardEntity "fxserver/modules/reward/entity"
 	"fxserver/pkg/dto"
+	"fxserver/pkg/i18n"
 	"fxserver/pkg/validator"
 
 	"github.com/labstack/echo/v4"
@@ -12,23 +23,32 @@ import (
 )
 
 type Handler struct {
-	service   Service
-	validator validator.Validator
-	logger    *zap.Logger
+	service         Service
+	jobService      JobService
+	approvalService ApprovalService
+	ruleService     RuleService
+	validator       validator.Validator
+	logger          *zap.Logger
 }
 
 type HandlerParam struct {
 	fx.In
-	Service   Service
-	Validator validator.Validator
-	Logger    *zap.Logger
+	Service         Service
+	JobService      JobService
+	ApprovalService ApprovalService
+	RuleService     RuleService
+	Validator       validator.Validator
+	Logger          *zap.Logger
 }
 
 func NewHandler(p HandlerParam) *Handler {
 	return &Handler{
-		service:   p.Service,
-		validator: p.Validator,
-		logger:    p.Logger,
+		service:         p.Service,
+		jobService:      p.JobService,
+		approvalService: p.ApprovalService,
+		ruleService:     p.RuleService,
+		validator:       p.Validator,
+		logger:          p.Logger,
 	}
 }
 
@@ -43,6 +63,33 @@ func (h *Handler) GrantReward(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
 	}
 
+	if adminID, ok := adminauth.GetAdminID(c); ok {
+		req.GrantedBy = adminID
+	}
+
+	if req.DryRun {
+		return h.previewGrants(c, BulkGrantRewardRequest{
+			UserIDs:           []int{req.UserID},
+			Items:             req.Items,
+			Source:            req.Source,
+			Description:       req.Description,
+			OverflowPolicy:    req.OverflowPolicy,
+			Delivery:          req.Delivery,
+			MailTitle:         req.MailTitle,
+			MailExpiresInDays: req.MailExpiresInDays,
+			GrantedBy:         req.GrantedBy,
+		})
+	}
+
+	// Grants above the approval thresholds wait for a second admin
+	held, err := h.approvalService.HoldGrant(req)
+	if err != nil {
+		return h.holdError(c, err)
+	}
+	if held != nil {
+		return c.JSON(http.StatusAccepted, held)
+	}
+
 	response, err := h.service.GrantRewards(req)
 	if err != nil {
 		// Even if there's an error, we might have a partial response
@@ -73,6 +120,23 @@ func (h *Handler) BulkGrantReward(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, dto.NewError("Cannot grant rewards to more than 1000 users at once", "invalid_request_error"))
 	}
 
+	if adminID, ok := adminauth.GetAdminID(c); ok {
+		req.GrantedBy = adminID
+	}
+
+	if req.DryRun {
+		return h.previewGrants(c, req)
+	}
+
+	// Grants above the approval thresholds wait for a second admin
+	held, err := h.approvalService.HoldBulkGrant(req)
+	if err != nil {
+		return h.holdError(c, err)
+	}
+	if held != nil {
+		return c.JSON(http.StatusAccepted, held)
+	}
+
 	response, err := h.service.BulkGrantRewards(req)
 	if err != nil {
 		h.logger.Error("Failed to bulk grant rewards", zap.Error(err))
@@ -90,20 +154,496 @@ func (h *Handler) BulkGrantReward(c echo.Context) error {
 	return c.JSON(statusCode, response)
 }
 
-// GetRewardSources returns a list of all available reward sources with descriptions
+// holdError answers a failed approval check; invalid requests are rejected and anything else fails closed
+func (h *Handler) holdError(c echo.Context, err error) error {
+	if errors.Is(err, ErrInvalidGrantRequest) || errors.Is(err, ErrInvalidJob) || errors.Is(err, ErrNoTargetUsers) || errors.Is(err, ErrTooManyTargets) {
+		return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+	}
+	h.logger.Error("Failed to hold reward grant for approval", zap.Error(err))
+	return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create grant request"))
+}
+
+// previewGrants answers a dry_run grant; nothing is granted, recorded or held for approval
+func (h *Handler) previewGrants(c echo.Context, req BulkGrantRewardRequest) error {
+	response, err := h.service.PreviewGrants(req)
+	if err != nil {
+		if response != nil {
+			return c.JSON(http.StatusBadRequest, response)
+		}
+		h.logger.Error("Failed to preview reward grant", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to preview reward grant"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// RuleGrant computes each user's items from count expressions and grants them,
+// or only returns the per-user results when dry_run is set (Admin only)
+func (h *Handler) RuleGrant(c echo.Context) error {
+	var req RuleGrantRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	if adminID, ok := adminauth.GetAdminID(c); ok {
+		req.GrantedBy = adminID
+	}
+
+	preview, err := h.ruleService.Evaluate(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRule) || errors.Is(err, ErrInvalidJob) ||
+			errors.Is(err, ErrNoTargetUsers) || errors.Is(err, ErrTooManyTargets) ||
+			errors.Is(err, ErrInvalidSource) || errors.Is(err, ErrSourceInactive):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to evaluate reward rules", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to evaluate reward rules"))
+	}
+
+	if req.DryRun {
+		return c.JSON(http.StatusOK, preview)
+	}
+
+	// Grants above the approval thresholds wait for a second admin
+	held, err := h.approvalService.HoldRuleGrant(req, preview)
+	if err != nil {
+		return h.holdError(c, err)
+	}
+	if held != nil {
+		return c.JSON(http.StatusAccepted, held)
+	}
+
+	response, err := h.ruleService.Execute(req, preview)
+	if err != nil {
+		h.logger.Error("Failed to grant rule rewards", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to grant rule rewards"))
+	}
+
+	// Return 207 Multi-Status if there were partial failures
+	statusCode := http.StatusOK
+	if response.FailureCount > 0 && response.SuccessCount > 0 {
+		statusCode = http.StatusMultiStatus
+	} else if response.FailureCount > 0 && response.SuccessCount == 0 {
+		statusCode = http.StatusBadRequest
+	}
+
+	return c.JSON(statusCode, response)
+}
+
+// GetRewardSources returns the active reward sources with descriptions in the request locale
 func (h *Handler) GetRewardSources(c echo.Context) error {
-	sources := map[string]string{
-		RewardSourceAdmin:        GetRewardSourceDescription(RewardSourceAdmin),
-		RewardSourceCoupon:       GetRewardSourceDescription(RewardSourceCoupon),
-		RewardSourcePayment:      GetRewardSourceDescription(RewardSourcePayment),
-		RewardSourceEvent:        GetRewardSourceDescription(RewardSourceEvent),
-		RewardSourceCompensation: GetRewardSourceDescription(RewardSourceCompensation),
-		RewardSourceDaily:        GetRewardSourceDescription(RewardSourceDaily),
-		RewardSourceAchievement:  GetRewardSourceDescription(RewardSourceAchievement),
+	sources, err := h.service.ListSources(false)
+	if err != nil {
+		h.logger.Error("Failed to list reward sources", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get reward sources"))
+	}
+
+	locale := i18n.FromRequest(c)
+	descriptions := make(map[string]string, len(sources))
+	for _, source := range sources {
+		descriptions[source.Key] = h.service.DescribeSource(source.Key, locale)
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"sources": sources,
+		"sources": descriptions,
 	})
 }
 
+// ListRewardSources returns reward source records, including inactive ones on request (Admin only)
+func (h *Handler) ListRewardSources(c echo.Context) error {
+	var query SourceQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	sources, err := h.service.ListSources(query.IncludeInactive)
+	if err != nil {
+		h.logger.Error("Failed to list reward sources", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get reward sources"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(sources))
+}
+
+// CreateRewardSource registers a new reward source (Admin only)
+func (h *Handler) CreateRewardSource(c echo.Context) error {
+	var req CreateSourceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	source, err := h.service.CreateSource(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSourceExists):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, ErrInvalidSource):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create reward source", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create reward source"))
+	}
+
+	return c.JSON(http.StatusCreated, source)
+}
+
+// UpdateRewardSource changes a reward source's descriptions, active flag or caps (Admin only)
+func (h *Handler) UpdateRewardSource(c echo.Context) error {
+	var req UpdateSourceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	source, err := h.service.UpdateSource(c.Param("key"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrSourceNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Reward source"))
+		case errors.Is(err, ErrInvalidSource):
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update reward source", zap.Error(err), zap.String("key", c.Param("key")))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update reward source"))
+	}
+
+	return c.JSON(http.StatusOK, source)
+}
+
+// GetGrants returns the reward grant history with optional filters (Admin only)
+func (h *Handler) GetGrants(c echo.Context) error {
+	var query GrantHistoryQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	grants, err := h.service.ListGrants(query)
+	if err != nil {
+		if errors.Is(err, ErrInvalidDateRange) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to list reward grants", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get reward grants"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(grants))
+}
+
+// GetMyRewards returns the rewards granted to the authenticated user
+func (h *Handler) GetMyRewards(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query MyRewardsQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	rewards, err := h.service.ListUserRewards(userID, query, i18n.FromRequest(c))
+	if err != nil {
+		if errors.Is(err, ErrInvalidDateRange) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to list user rewards", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get rewards"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(rewards))
+}
+
+// CreateBulkJob queues a background reward grant for a large user list (Admin only).
+// Accepts a JSON body, or multipart form data with a JSON "request" field and a CSV "users" file.
+func (h *Handler) CreateBulkJob(c echo.Context) error {
+	var req CreateBulkJobRequest
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		if err := json.Unmarshal([]byte(c.FormValue("request")), &req); err != nil {
+			return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+		}
+
+		file, err := c.FormFile("users")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.NewError("users CSV file is required", "invalid_request_error"))
+		}
+		src, err := file.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.NewError("Failed to read users CSV file", "invalid_request_error"))
+		}
+		defer src.Close()
+
+		userIDs, err := ParseUserIDsCSV(src)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		req.UserIDs = append(req.UserIDs, userIDs...)
+	} else if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	if adminID, ok := adminauth.GetAdminID(c); ok {
+		req.GrantedBy = adminID
+	}
+
+	// Grants above the approval thresholds wait for a second admin
+	held, err := h.approvalService.HoldBulkJob(req)
+	if err != nil {
+		return h.holdError(c, err)
+	}
+	if held != nil {
+		return c.JSON(http.StatusAccepted, held)
+	}
+
+	job, err := h.jobService.CreateJob(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidJob) || errors.Is(err, ErrNoTargetUsers) || errors.Is(err, ErrTooManyTargets) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create bulk grant job", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create bulk grant job"))
+	}
+
+	return c.JSON(http.StatusAccepted, BulkJobResponse{BulkGrantJob: job, Progress: job.Progress()})
+}
+
+// GetBulkJobs returns bulk grant jobs, oldest first (Admin only)
+func (h *Handler) GetBulkJobs(c echo.Context) error {
+	var query BulkJobQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	jobs, err := h.jobService.ListJobs(query)
+	if err != nil {
+		h.logger.Error("Failed to list bulk grant jobs", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get bulk grant jobs"))
+	}
+
+	responses := make([]BulkJobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = BulkJobResponse{BulkGrantJob: job, Progress: job.Progress()}
+	}
+	return c.JSON(http.StatusOK, dto.NewList(responses))
+}
+
+// GetBulkJob returns the status and progress of a bulk grant job (Admin only)
+func (h *Handler) GetBulkJob(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid job ID", "invalid_request_error"))
+	}
+
+	job, err := h.jobService.GetJob(id)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Bulk grant job"))
+		}
+		h.logger.Error("Failed to get bulk grant job", zap.Error(err), zap.Int("job_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get bulk grant job"))
+	}
+
+	return c.JSON(http.StatusOK, BulkJobResponse{BulkGrantJob: job, Progress: job.Progress()})
+}
+
+// CancelBulkJob stops a pending or running bulk grant job (Admin only)
+func (h *Handler) CancelBulkJob(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid job ID", "invalid_request_error"))
+	}
+
+	job, err := h.jobService.CancelJob(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Bulk grant job"))
+		case errors.Is(err, ErrJobFinished):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to cancel bulk grant job", zap.Error(err), zap.Int("job_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to cancel bulk grant job"))
+	}
+
+	return c.JSON(http.StatusOK, BulkJobResponse{BulkGrantJob: job, Progress: job.Progress()})
+}
+
+// GetBulkJobFailures downloads the users a bulk grant job failed for as CSV, or as JSON with ?format=json (Admin only)
+func (h *Handler) GetBulkJobFailures(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid job ID", "invalid_request_error"))
+	}
+
+	failures, err := h.jobService.GetJobFailures(id)
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Bulk grant job"))
+		}
+		h.logger.Error("Failed to get bulk grant job failures", zap.Error(err), zap.Int("job_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get bulk grant job failures"))
+	}
+
+	if c.QueryParam("format") == "json" {
+		return c.JSON(http.StatusOK, dto.NewList(failures))
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="bulk-job-%d-failures.csv"`, id))
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(c.Response())
+	_ = writer.Write([]string{"user_id", "message"})
+	for _, failure := range failures {
+		_ = writer.Write([]string{strconv.Itoa(failure.UserID), failure.Message})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// GetGrantRequests returns grant requests held for approval, newest first (Admin only)
+func (h *Handler) GetGrantRequests(c echo.Context) error {
+	var query GrantRequestQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	requests, err := h.approvalService.ListRequests(query)
+	if err != nil {
+		h.logger.Error("Failed to list grant requests", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get grant requests"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(requests))
+}
+
+// GetGrantRequest returns a grant request with its target users (Admin only)
+func (h *Handler) GetGrantRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid grant request ID", "invalid_request_error"))
+	}
+
+	request, err := h.approvalService.GetRequest(id)
+	if err != nil {
+		if errors.Is(err, ErrGrantRequestNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Grant request"))
+		}
+		h.logger.Error("Failed to get grant request", zap.Error(err), zap.Int("request_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get grant request"))
+	}
+
+	return c.JSON(http.StatusOK, GrantRequestResponse{GrantRequest: request, UserIDs: request.UserIDs, UserItems: request.UserItems})
+}
+
+// ApproveGrantRequest approves a held grant and runs it (Admin only, not the requester)
+func (h *Handler) ApproveGrantRequest(c echo.Context) error {
+	return h.reviewGrantRequest(c, h.approvalService.Approve)
+}
+
+// RejectGrantRequest rejects a held grant (Admin only, not the requester)
+func (h *Handler) RejectGrantRequest(c echo.Context) error {
+	return h.reviewGrantRequest(c, h.approvalService.Reject)
+}
+
+func (h *Handler) reviewGrantRequest(c echo.Context, review func(id, adminID int, note string) (*rewardEntity.GrantRequest, error)) error {
+	adminID, ok := adminauth.GetAdminID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewError("Admin user ID not found in context"))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid grant request ID", "invalid_request_error"))
+	}
+
+	var req ReviewGrantRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	request, err := review(id, adminID, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrGrantRequestNotFound):
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Grant request"))
+		case errors.Is(err, ErrGrantRequestReviewed):
+			return c.JSON(http.StatusConflict, dto.NewError(err.Error(), "invalid_request_error"))
+		case errors.Is(err, ErrSelfApproval):
+			return c.JSON(http.StatusForbidden, dto.NewError(err.Error(), "permission_error"))
+		}
+		h.logger.Error("Failed to review grant request", zap.Error(err), zap.Int("request_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to review grant request"))
+	}
+
+	return c.JSON(http.StatusOK, request)
+}
+
+// ParseUserIDsCSV reads user IDs from the first column of a CSV file; a non-numeric header row is skipped
+func ParseUserIDsCSV(r io.Reader) ([]int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var userIDs []int
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+
+		value := strings.TrimSpace(record[0])
+		if value == "" {
+			continue
+		}
+		userID, err := strconv.Atoi(value)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("invalid user ID %q on line %d", value, line)
+		}
+		if userID <= 0 {
+			return nil, fmt.Errorf("invalid user ID %d on line %d", userID, line)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
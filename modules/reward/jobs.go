@@ -0,0 +1,504 @@
+package reward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/repository"
+	"fxserver/modules/user"
+	userEntity "fxserver/modules/user/entity"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrJobNotFound    = errors.New("bulk grant job not found")
+	ErrJobFinished    = errors.New("bulk grant job already finished")
+	ErrInvalidJob     = errors.New("invalid bulk grant job")
+	ErrNoTargetUsers  = errors.New("no target users")
+	ErrTooManyTargets = errors.New("too many target users")
+)
+
+const (
+	// MaxBulkJobUsers caps the user snapshot of a single job
+	MaxBulkJobUsers = 5_000_000
+	// bulkJobBatchSize is how many users are granted between checkpoints
+	bulkJobBatchSize = 500
+	// defaultJobConcurrency is used when REWARD_JOB_CONCURRENCY is unset or invalid
+	defaultJobConcurrency = 8
+)
+
+// JobService runs bulk reward grants in the background.
+// Jobs survive restarts through the repository: unfinished jobs resume from their last checkpoint.
+type JobService interface {
+	// CreateJob returns the existing job instead of creating another when req.Reference was used before
+	CreateJob(req CreateBulkJobRequest) (*rewardEntity.BulkGrantJob, error)
+	ResolveTargets(req CreateBulkJobRequest) ([]int, error)
+	GetJob(id int) (*rewardEntity.BulkGrantJob, error)
+	ListJobs(query BulkJobQuery) ([]*rewardEntity.BulkGrantJob, error)
+	CancelJob(id int) (*rewardEntity.BulkGrantJob, error)
+	GetJobFailures(id int) ([]rewardEntity.JobFailure, error)
+}
+
+type jobService struct {
+	repo        repository.Repository
+	service     Service
+	userService user.Service
+	logger      *zap.Logger
+	concurrency int
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+type JobServiceParam struct {
+	fx.In
+	Lifecycle   fx.Lifecycle
+	Repository  repository.Repository
+	Service     Service
+	UserService user.Service
+	Logger      *zap.Logger
+}
+
+func NewJobService(p JobServiceParam) JobService {
+	s := newJobService(p.Repository, p.Service, p.UserService, p.Logger)
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: s.Start,
+		OnStop:  s.Stop,
+	})
+
+	return s
+}
+
+func newJobService(repo repository.Repository, service Service, userService user.Service, logger *zap.Logger) *jobService {
+	concurrency := defaultJobConcurrency
+	if value := os.Getenv("REWARD_JOB_CONCURRENCY"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			logger.Warn("Invalid REWARD_JOB_CONCURRENCY, using default",
+				zap.String("value", value),
+				zap.Int("default", defaultJobConcurrency))
+		} else {
+			concurrency = parsed
+		}
+	}
+
+	return &jobService{
+		repo:        repo,
+		service:     service,
+		userService: userService,
+		logger:      logger,
+		concurrency: concurrency,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (s *jobService) CreateJob(req CreateBulkJobRequest) (*rewardEntity.BulkGrantJob, error) {
+	if req.Reference != "" {
+		if existing, err := s.repo.GetJobByReference(req.Reference); err == nil {
+			return existing, nil
+		}
+	}
+	if err := s.service.ValidateSource(req.Source); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJob, err)
+	}
+	if err := s.service.ValidateRewardItems(req.Items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJob, err)
+	}
+
+	userIDs, err := s.ResolveTargets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := req.Delivery
+	if delivery == "" {
+		delivery = DeliveryDirect
+	}
+
+	job := &rewardEntity.BulkGrantJob{
+		Items:             req.Items,
+		Source:            req.Source,
+		Description:       req.Description,
+		OverflowPolicy:    req.OverflowPolicy,
+		Delivery:          delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		GrantedBy:         req.GrantedBy,
+		Reference:         req.Reference,
+		UserIDs:           userIDs,
+	}
+	if err := s.repo.CreateJob(job); err != nil {
+		if errors.Is(err, repository.ErrJobReferenceExists) {
+			return s.repo.GetJobByReference(req.Reference)
+		}
+		s.logger.Error("Failed to create bulk grant job", zap.Error(err))
+		return nil, fmt.Errorf("failed to create bulk grant job: %w", err)
+	}
+
+	s.logger.Info("Bulk grant job created",
+		zap.Int("job_id", job.ID),
+		zap.Int("total_users", job.Total),
+		zap.String("source", job.Source),
+		zap.Int("granted_by", job.GrantedBy))
+
+	s.notify()
+	return job, nil
+}
+
+// ResolveTargets returns the deduplicated user IDs from the request's user list or target query
+func (s *jobService) ResolveTargets(req CreateBulkJobRequest) ([]int, error) {
+	if len(req.UserIDs) > 0 && req.Target != nil {
+		return nil, fmt.Errorf("%w: specify either user_ids or target, not both", ErrInvalidJob)
+	}
+
+	candidates := req.UserIDs
+	if req.Target != nil {
+		matched, err := s.matchTarget(*req.Target)
+		if err != nil {
+			return nil, err
+		}
+		candidates = matched
+	}
+
+	seen := make(map[int]bool, len(candidates))
+	userIDs := make([]int, 0, len(candidates))
+	for _, id := range candidates {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		userIDs = append(userIDs, id)
+	}
+
+	if len(userIDs) == 0 {
+		return nil, ErrNoTargetUsers
+	}
+	if len(userIDs) > MaxBulkJobUsers {
+		return nil, fmt.Errorf("%w: %d exceeds %d", ErrTooManyTargets, len(userIDs), MaxBulkJobUsers)
+	}
+	return userIDs, nil
+}
+
+// Matcher returns a predicate for the target's conditions; a malformed target fails with ErrInvalidJob
+func (target BulkJobTarget) Matcher() (func(u *userEntity.User) bool, error) {
+	var from, to time.Time
+	if target.CreatedFrom != "" {
+		parsed, err := time.Parse("2006-01-02", target.CreatedFrom)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid created_from format", ErrInvalidJob)
+		}
+		from = parsed
+	}
+	if target.CreatedTo != "" {
+		parsed, err := time.Parse("2006-01-02", target.CreatedTo)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid created_to format", ErrInvalidJob)
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	// 조건 없이 전체 사용자를 대상으로 하려면 all_users를 명시해야 함
+	if !target.AllUsers && from.IsZero() && to.IsZero() && target.MinAge == 0 && target.MaxAge == 0 {
+		return nil, fmt.Errorf("%w: target needs all_users or at least one condition", ErrInvalidJob)
+	}
+
+	return func(u *userEntity.User) bool {
+		if !from.IsZero() && u.CreatedAt.Before(from) {
+			return false
+		}
+		if !to.IsZero() && !u.CreatedAt.Before(to) {
+			return false
+		}
+		if target.MinAge > 0 && u.Age < target.MinAge {
+			return false
+		}
+		if target.MaxAge > 0 && u.Age > target.MaxAge {
+			return false
+		}
+		return true
+	}, nil
+}
+
+func (s *jobService) matchTarget(target BulkJobTarget) ([]int, error) {
+	matches, err := target.Matcher()
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userService.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var userIDs []int
+	for _, u := range users {
+		if matches(u) {
+			userIDs = append(userIDs, u.ID)
+		}
+	}
+	// 재개 시 같은 순서로 처리되도록 ID 순으로 고정
+	slices.Sort(userIDs)
+	return userIDs, nil
+}
+
+func (s *jobService) GetJob(id int) (*rewardEntity.BulkGrantJob, error) {
+	job, err := s.repo.GetJob(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get bulk grant job: %w", err)
+	}
+	return job, nil
+}
+
+func (s *jobService) ListJobs(query BulkJobQuery) ([]*rewardEntity.BulkGrantJob, error) {
+	filter := repository.JobFilter{Limit: query.Limit}
+	if query.Status != "" {
+		filter.Statuses = []rewardEntity.JobStatus{rewardEntity.JobStatus(query.Status)}
+	}
+
+	jobs, err := s.repo.ListJobs(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk grant jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s *jobService) CancelJob(id int) (*rewardEntity.BulkGrantJob, error) {
+	job, err := s.repo.UpdateJobStatus(id,
+		[]rewardEntity.JobStatus{rewardEntity.JobStatusPending, rewardEntity.JobStatusRunning},
+		rewardEntity.JobStatusCancelled)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrJobNotFound):
+			return nil, ErrJobNotFound
+		case errors.Is(err, repository.ErrJobStateChanged):
+			return nil, ErrJobFinished
+		}
+		return nil, fmt.Errorf("failed to cancel bulk grant job: %w", err)
+	}
+
+	// 처리 중인 배치는 끝까지 진행되고 다음 배치부터 중단됨
+	s.logger.Info("Bulk grant job cancelled",
+		zap.Int("job_id", id),
+		zap.Int("processed", job.Processed),
+		zap.Int("total", job.Total))
+	return job, nil
+}
+
+func (s *jobService) GetJobFailures(id int) ([]rewardEntity.JobFailure, error) {
+	job, err := s.GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	return job.Failures, nil
+}
+
+// Background processing
+
+func (s *jobService) Start(ctx context.Context) error {
+	s.logger.Info("Starting bulk grant job runner", zap.Int("concurrency", s.concurrency))
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.loop()
+	s.notify() // 재시작 전에 끝나지 않은 작업 재개
+	return nil
+}
+
+func (s *jobService) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping bulk grant job runner")
+	close(s.stop)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notify wakes the runner without blocking; one pending signal is enough
+func (s *jobService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *jobService) loop() {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+
+		// Drain every runnable job before waiting again
+		for {
+			job, err := s.nextJob()
+			if err != nil {
+				s.logger.Error("Failed to pick bulk grant job", zap.Error(err))
+				break
+			}
+			if job == nil || !s.run(job) {
+				break
+			}
+		}
+	}
+}
+
+// nextJob returns the oldest interrupted job first, then the oldest pending one
+func (s *jobService) nextJob() (*rewardEntity.BulkGrantJob, error) {
+	for _, status := range []rewardEntity.JobStatus{rewardEntity.JobStatusRunning, rewardEntity.JobStatusPending} {
+		jobs, err := s.repo.ListJobs(repository.JobFilter{Statuses: []rewardEntity.JobStatus{status}, Limit: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(jobs) > 0 {
+			return jobs[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// run processes a job batch by batch from its checkpoint. It returns false when the runner is stopping.
+func (s *jobService) run(job *rewardEntity.BulkGrantJob) bool {
+	resumed := job.Status == rewardEntity.JobStatusRunning
+	if !resumed {
+		started, err := s.repo.UpdateJobStatus(job.ID, []rewardEntity.JobStatus{rewardEntity.JobStatusPending}, rewardEntity.JobStatusRunning)
+		if err != nil {
+			// 시작 직전에 취소된 경우
+			return true
+		}
+		job = started
+	} else {
+		s.logger.Info("Resuming bulk grant job",
+			zap.Int("job_id", job.ID),
+			zap.Int("processed", job.Processed),
+			zap.Int("total", job.Total))
+	}
+
+	for processed := job.Processed; processed < job.Total; {
+		select {
+		case <-s.stop:
+			// 상태는 running으로 남아 다음 시작 시 재개됨
+			return false
+		default:
+		}
+
+		current, err := s.repo.GetJob(job.ID)
+		if err != nil {
+			s.logger.Error("Failed to reload bulk grant job", zap.Error(err), zap.Int("job_id", job.ID))
+			return true
+		}
+		if current.Status != rewardEntity.JobStatusRunning {
+			return true
+		}
+
+		end := min(processed+bulkJobBatchSize, job.Total)
+		batch := job.UserIDs[processed:end]
+		skipped := 0
+		if resumed {
+			// 중단 직전 배치에서 이미 지급된 사용자는 건너뛰고 성공으로 집계
+			batch, skipped = s.skipGranted(job.ID, batch)
+			resumed = false
+		}
+
+		succeeded, failures := s.grantBatch(job, batch)
+		succeeded += skipped
+		if err := s.repo.SaveJobProgress(job.ID, end, succeeded, failures); err != nil {
+			s.logger.Error("Failed to save bulk grant job progress", zap.Error(err), zap.Int("job_id", job.ID))
+			return true
+		}
+		processed = end
+	}
+
+	finished, err := s.repo.UpdateJobStatus(job.ID, []rewardEntity.JobStatus{rewardEntity.JobStatusRunning}, rewardEntity.JobStatusCompleted)
+	if err != nil {
+		return true
+	}
+
+	s.logger.Info("Bulk grant job completed",
+		zap.Int("job_id", finished.ID),
+		zap.Int("total", finished.Total),
+		zap.Int("success_count", finished.SuccessCount),
+		zap.Int("failure_count", finished.FailureCount))
+	return true
+}
+
+// skipGranted drops users that already have a successful grant recorded for the job and reports how many were dropped.
+// Users whose earlier attempt failed stay in the batch and are retried.
+func (s *jobService) skipGranted(jobID int, batch []int) ([]int, int) {
+	granted, err := s.repo.GrantedJobUsers(jobID, batch)
+	if err != nil {
+		s.logger.Warn("Failed to check granted users before resuming", zap.Error(err), zap.Int("job_id", jobID))
+		return batch, 0
+	}
+
+	remaining := make([]int, 0, len(batch))
+	for _, userID := range batch {
+		if !granted[userID] {
+			remaining = append(remaining, userID)
+		}
+	}
+	return remaining, len(batch) - len(remaining)
+}
+
+// grantBatch grants the job's rewards to each user with bounded concurrency
+func (s *jobService) grantBatch(job *rewardEntity.BulkGrantJob, batch []int) (int, []rewardEntity.JobFailure) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		succeeded int
+		failures  []rewardEntity.JobFailure
+	)
+	slots := make(chan struct{}, s.concurrency)
+
+	for _, userID := range batch {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(userID int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			_, err := s.service.GrantRewards(GrantRewardRequest{
+				UserID:            userID,
+				Items:             job.Items,
+				Source:            job.Source,
+				Description:       job.Description,
+				OverflowPolicy:    job.OverflowPolicy,
+				Delivery:          job.Delivery,
+				MailTitle:         job.MailTitle,
+				MailExpiresInDays: job.MailExpiresInDays,
+				GrantedBy:         job.GrantedBy,
+				JobID:             job.ID,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, rewardEntity.JobFailure{UserID: userID, Message: err.Error()})
+				return
+			}
+			succeeded++
+		}(userID)
+	}
+	wg.Wait()
+
+	return succeeded, failures
+}
@@ -0,0 +1,227 @@
+package reward
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"fxserver/modules/item/entity"
+	itemRepository "fxserver/modules/item/repository"
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/repository"
+	"fxserver/modules/user"
+	userRepository "fxserver/modules/user/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// setupJobService seeds users 1 (age 17), 2 (age 25) and 3 (age 40)
+func setupJobService(t *testing.T) (*jobService, itemRepository.Repository, userRepository.UserRepository) {
+	users := newUsers(t, 17, 25, 40)
+	svc, items, _ := setupRewardServiceWithUsers(t, users)
+	logger := zap.NewNop()
+	jobs := newJobService(svc.(*service).repo, svc, user.NewService(users, logger), logger)
+	return jobs, items, users
+}
+
+func jobRequest(userIDs ...int) CreateBulkJobRequest {
+	return CreateBulkJobRequest{
+		UserIDs:        userIDs,
+		Items:          []entity.RewardItem{{ItemID: potionID, Count: 1}},
+		Source:         RewardSourceCompensation,
+		Description:    "Server maintenance compensation",
+		OverflowPolicy: entity.OverflowReject,
+		GrantedBy:      1,
+	}
+}
+
+func waitForJob(t *testing.T, jobs *jobService, id int) *rewardEntity.BulkGrantJob {
+	var job *rewardEntity.BulkGrantJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = jobs.GetJob(id)
+		require.NoError(t, err)
+		return job.IsFinished()
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestBulkJobCompletes(t *testing.T) {
+	jobs, items, _ := setupJobService(t)
+	// User 3 has no room for the potion
+	require.NoError(t, items.SetSlotCapacity(3, 0))
+
+	require.NoError(t, jobs.Start(context.Background()))
+	defer jobs.Stop(context.Background())
+
+	// Duplicates are granted once
+	created, err := jobs.CreateJob(jobRequest(1, 2, 3, 2))
+	require.NoError(t, err)
+	assert.Equal(t, 3, created.Total)
+
+	job := waitForJob(t, jobs, created.ID)
+	assert.Equal(t, rewardEntity.JobStatusCompleted, job.Status)
+	assert.Equal(t, 3, job.Processed)
+	assert.Equal(t, 2, job.SuccessCount)
+	assert.Equal(t, 1, job.FailureCount)
+	assert.Equal(t, float64(100), job.Progress())
+
+	failures, err := jobs.GetJobFailures(created.ID)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	assert.Equal(t, 3, failures[0].UserID)
+
+	// Each grant is recorded against the job
+	grants, err := jobs.repo.ListGrants(repository.GrantFilter{JobID: created.ID})
+	require.NoError(t, err)
+	assert.Len(t, grants, 3)
+}
+
+func TestCancelBulkJob(t *testing.T) {
+	jobs, items, _ := setupJobService(t)
+
+	// The runner is not started, so the job is still pending
+	created, err := jobs.CreateJob(jobRequest(1, 2))
+	require.NoError(t, err)
+
+	cancelled, err := jobs.CancelJob(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rewardEntity.JobStatusCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.FinishedAt)
+
+	_, err = jobs.CancelJob(created.ID)
+	assert.ErrorIs(t, err, ErrJobFinished)
+	_, err = jobs.CancelJob(created.ID + 1)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	require.NoError(t, jobs.Start(context.Background()))
+	require.NoError(t, jobs.Stop(context.Background()))
+
+	_, err = items.GetUserInventoryItem(1, potionID)
+	assert.ErrorIs(t, err, itemRepository.ErrInventoryNotFound)
+}
+
+func TestResumeBulkJob(t *testing.T) {
+	jobs, items, _ := setupJobService(t)
+
+	created, err := jobs.CreateJob(jobRequest(1, 2))
+	require.NoError(t, err)
+
+	// Simulate a restart after user 1 was granted but before the checkpoint was saved
+	_, err = jobs.repo.UpdateJobStatus(created.ID, []rewardEntity.JobStatus{rewardEntity.JobStatusPending}, rewardEntity.JobStatusRunning)
+	require.NoError(t, err)
+	req := jobRequest()
+	_, err = jobs.service.GrantRewards(GrantRewardRequest{
+		UserID: 1, Items: req.Items, Source: req.Source, Description: req.Description, JobID: created.ID,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, jobs.Start(context.Background()))
+	defer jobs.Stop(context.Background())
+
+	job := waitForJob(t, jobs, created.ID)
+	assert.Equal(t, rewardEntity.JobStatusCompleted, job.Status)
+
+	for _, userID := range []int{1, 2} {
+		potion, err := items.GetUserInventoryItem(userID, potionID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, potion.Count)
+	}
+}
+
+func TestResumeBulkJobRetriesFailures(t *testing.T) {
+	jobs, items, _ := setupJobService(t)
+
+	created, err := jobs.CreateJob(jobRequest(1, 2, 3))
+	require.NoError(t, err)
+
+	// Before the restart user 1 was granted and user 2 failed for lack of room
+	_, err = jobs.repo.UpdateJobStatus(created.ID, []rewardEntity.JobStatus{rewardEntity.JobStatusPending}, rewardEntity.JobStatusRunning)
+	require.NoError(t, err)
+	req := jobRequest()
+	require.NoError(t, items.SetSlotCapacity(2, 0))
+	for _, userID := range []int{1, 2} {
+		_, _ = jobs.service.GrantRewards(GrantRewardRequest{
+			UserID: userID, Items: req.Items, Source: req.Source, Description: req.Description,
+			OverflowPolicy: req.OverflowPolicy, JobID: created.ID,
+		})
+	}
+	require.NoError(t, items.SetSlotCapacity(2, 10))
+
+	require.NoError(t, jobs.Start(context.Background()))
+	defer jobs.Stop(context.Background())
+
+	job := waitForJob(t, jobs, created.ID)
+	assert.Equal(t, rewardEntity.JobStatusCompleted, job.Status)
+	assert.Equal(t, 3, job.SuccessCount)
+	assert.Equal(t, 0, job.FailureCount)
+
+	// The failed user is retried; nobody is granted twice
+	for _, userID := range []int{1, 2, 3} {
+		potion, err := items.GetUserInventoryItem(userID, potionID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, potion.Count)
+	}
+}
+
+func TestBulkJobTarget(t *testing.T) {
+	jobs, _, _ := setupJobService(t)
+
+	req := jobRequest()
+	req.Target = &BulkJobTarget{MinAge: 18}
+	job, err := jobs.CreateJob(req)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, job.UserIDs)
+
+	today := time.Now().Format("2006-01-02")
+	req.Target = &BulkJobTarget{CreatedFrom: today, CreatedTo: today}
+	job, err = jobs.CreateJob(req)
+	require.NoError(t, err)
+	assert.Len(t, job.UserIDs, 3)
+
+	// An empty target must opt in to every user explicitly
+	req.Target = &BulkJobTarget{}
+	_, err = jobs.CreateJob(req)
+	assert.ErrorIs(t, err, ErrInvalidJob)
+
+	req.Target = &BulkJobTarget{MinAge: 100}
+	_, err = jobs.CreateJob(req)
+	assert.ErrorIs(t, err, ErrNoTargetUsers)
+
+	req = jobRequest(1)
+	req.Target = &BulkJobTarget{AllUsers: true}
+	_, err = jobs.CreateJob(req)
+	assert.ErrorIs(t, err, ErrInvalidJob)
+}
+
+func TestBulkJobReference(t *testing.T) {
+	jobs, _, _ := setupJobService(t)
+
+	req := jobRequest(1, 2)
+	req.Reference = "campaign:7"
+	first, err := jobs.CreateJob(req)
+	require.NoError(t, err)
+
+	// Creating again with the same reference returns the existing job
+	req.UserIDs = []int{1, 2, 3}
+	second, err := jobs.CreateJob(req)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 2, second.Total)
+
+	listed, err := jobs.ListJobs(BulkJobQuery{})
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+}
+
+func TestParseUserIDsCSV(t *testing.T) {
+	userIDs, err := ParseUserIDsCSV(strings.NewReader("user_id,name\n1,alice\n 2 \n\n3,carol\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, userIDs)
+
+	_, err = ParseUserIDsCSV(strings.NewReader("1\nabc\n"))
+	assert.Error(t, err)
+}
@@ -1,14 +1,23 @@
 package reward
 
 import (
+	"fxserver/modules/reward/repository"
 	"fxserver/pkg/router"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Options(
+	repository.Module,
 	fx.Provide(
 		NewService,
+		NewJobService,
+		NewApprovalService,
+		NewRuleService,
 		NewHandler,
+		fx.Annotate(
+			NewLogApprovalHook,
+			fx.ResultTags(`group:"reward_approval_hooks"`),
+		),
 		fx.Annotate(
 			NewRoutes,
 			fx.As(new(router.RouteRegistrar)),
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"fxserver/modules/reward/entity"
+)
+
+var (
+	ErrJobNotFound        = errors.New("bulk grant job not found")
+	ErrJobStateChanged    = errors.New("bulk grant job status changed")
+	ErrJobReferenceExists = errors.New("bulk grant job with reference already exists")
+
+	ErrGrantRequestNotFound = errors.New("grant request not found")
+	ErrGrantRequestReviewed = errors.New("grant request already reviewed")
+
+	ErrSourceNotFound = errors.New("reward source not found")
+	ErrSourceExists   = errors.New("reward source already exists")
+)
+
+// GrantFilter narrows reward grant queries; zero values are ignored
+type GrantFilter struct {
+	UserID int
+	Source string
+	Actor  string
+	Status entity.GrantStatus
+	JobID  int
+	From   time.Time
+	To     time.Time // exclusive
+	Limit  int
+}
+
+// JobFilter narrows bulk grant job queries; zero values are ignored
+type JobFilter struct {
+	Statuses []entity.JobStatus // any of
+	Limit    int
+}
+
+// GrantRequestFilter narrows grant request queries; zero values are ignored
+type GrantRequestFilter struct {
+	Status      entity.GrantRequestStatus
+	RequestedBy int
+	Limit       int
+}
+
+type GrantRepository interface {
+	CreateGrant(grant *entity.RewardGrant) error
+	// ListGrants returns matching grants, newest first
+	ListGrants(filter GrantFilter) ([]*entity.RewardGrant, error)
+	// CountDailyGrants returns the number of successful grants under the source on the UTC day
+	// of the given time, for one user or for everyone when userID is 0. It is served from an index
+	// kept by CreateGrant, so daily caps do not scan the grant history.
+	CountDailyGrants(source string, userID int, day time.Time) (int, error)
+	// GrantedJobUsers returns which of userIDs already have a successful grant recorded for the job,
+	// from an index kept by CreateGrant
+	GrantedJobUsers(jobID int, userIDs []int) (map[int]bool, error)
+}
+
+type SourceRepository interface {
+	CreateSource(source *entity.RewardSource) error
+	// GetSource returns a copy of the stored source
+	GetSource(key string) (*entity.RewardSource, error)
+	// ListSources returns sources ordered by key
+	ListSources(includeInactive bool) ([]*entity.RewardSource, error)
+	UpdateSource(source *entity.RewardSource) error
+}
+
+type JobRepository interface {
+	// CreateJob fails with ErrJobReferenceExists when a job with the same non-empty reference exists
+	CreateJob(job *entity.BulkGrantJob) error
+	// GetJob returns a copy of the stored job
+	GetJob(id int) (*entity.BulkGrantJob, error)
+	// GetJobByReference returns a copy of the job created with reference
+	GetJobByReference(reference string) (*entity.BulkGrantJob, error)
+	// ListJobs returns matching jobs, oldest first
+	ListJobs(filter JobFilter) ([]*entity.BulkGrantJob, error)
+	// UpdateJobStatus moves the job to status only if it is currently in one of from;
+	// otherwise it fails with ErrJobStateChanged
+	UpdateJobStatus(id int, from []entity.JobStatus, status entity.JobStatus) (*entity.BulkGrantJob, error)
+	// SaveJobProgress advances the checkpoint and adds the batch outcome to the counters
+	SaveJobProgress(id, processed, succeeded int, failures []entity.JobFailure) error
+}
+
+type GrantRequestRepository interface {
+	CreateGrantRequest(request *entity.GrantRequest) error
+	// GetGrantRequest returns a copy of the stored request
+	GetGrantRequest(id int) (*entity.GrantRequest, error)
+	// ListGrantRequests returns matching requests, newest first
+	ListGrantRequests(filter GrantRequestFilter) ([]*entity.GrantRequest, error)
+	// ReviewGrantRequest approves or rejects a pending request;
+	// a request that was already reviewed fails with ErrGrantRequestReviewed
+	ReviewGrantRequest(id int, status entity.GrantRequestStatus, reviewedBy int, note string) (*entity.GrantRequest, error)
+	// CompleteGrantRequest stores the outcome of an approved request; a non-empty errMessage marks it failed
+	CompleteGrantRequest(id int, result interface{}, errMessage string) (*entity.GrantRequest, error)
+}
+
+type Repository interface {
+	GrantRepository
+	JobRepository
+	GrantRequestRepository
+	SourceRepository
+}
@@ -0,0 +1,449 @@
+package repository
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"fxserver/modules/reward/entity"
+	"fxserver/pkg/i18n"
+)
+
+type memoryRepository struct {
+	mu        sync.RWMutex
+	grants    []*entity.RewardGrant
+	nextID    int
+	jobs      map[int]*entity.BulkGrantJob
+	nextJobID int
+
+	// 성공한 지급의 색인: 출처 일일 한도 집계와 일괄 지급 작업 재개용
+	dailyGrants map[dailyGrantKey]int
+	jobGrants   map[int]map[int]bool
+
+	requests      []*entity.GrantRequest
+	nextRequestID int
+
+	sources map[string]*entity.RewardSource
+}
+
+// dailyGrantKey counts successful grants per source, user (0: all users) and UTC day
+type dailyGrantKey struct {
+	source string
+	userID int
+	day    string
+}
+
+func grantDay(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func NewMemoryRepository() Repository {
+	repo := &memoryRepository{
+		nextID:    1,
+		jobs:      make(map[int]*entity.BulkGrantJob),
+		nextJobID: 1,
+
+		dailyGrants: make(map[dailyGrantKey]int),
+		jobGrants:   make(map[int]map[int]bool),
+
+		nextRequestID: 1,
+
+		sources: make(map[string]*entity.RewardSource),
+	}
+
+	// Initialize with the built-in reward sources
+	repo.initializeDefaultSources()
+
+	return repo
+}
+
+func (r *memoryRepository) initializeDefaultSources() {
+	defaultSources := []*entity.RewardSource{
+		{Key: "admin", Descriptions: i18n.Text{i18n.Korean: "관리자 직접 지급", i18n.English: "Granted directly by an admin", i18n.Japanese: "管理者による直接付与"}},
+		{Key: "coupon", Descriptions: i18n.Text{i18n.Korean: "쿠폰 사용 보상", i18n.English: "Coupon redemption reward", i18n.Japanese: "クーポン使用報酬"}},
+		{Key: "payment", Descriptions: i18n.Text{i18n.Korean: "결제 완료 보상", i18n.English: "Payment completion reward", i18n.Japanese: "決済完了報酬"}, Paid: true},
+		{Key: "event", Descriptions: i18n.Text{i18n.Korean: "이벤트 보상", i18n.English: "Event reward", i18n.Japanese: "イベント報酬"}},
+		{Key: "compensation", Descriptions: i18n.Text{i18n.Korean: "보상/사과", i18n.English: "Compensation", i18n.Japanese: "補填・お詫び"}},
+		{Key: "daily", Descriptions: i18n.Text{i18n.Korean: "일일 보상", i18n.English: "Daily reward", i18n.Japanese: "デイリー報酬"}},
+		{Key: "achievement", Descriptions: i18n.Text{i18n.Korean: "업적 달성 보상", i18n.English: "Achievement reward", i18n.Japanese: "実績達成報酬"}},
+		{Key: "gacha", Descriptions: i18n.Text{i18n.Korean: "가챠 뽑기 결과", i18n.English: "Gacha draw result", i18n.Japanese: "ガチャ結果"}, Paid: true},
+	}
+
+	now := time.Now()
+	for _, source := range defaultSources {
+		source.Active = true
+		source.CreatedAt = now
+		source.UpdatedAt = now
+		r.sources[source.Key] = source
+	}
+}
+
+// Grant operations
+
+func (r *memoryRepository) CreateGrant(grant *entity.RewardGrant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	grant.ID = r.nextID
+	grant.CreatedAt = time.Now()
+	r.grants = append(r.grants, grant)
+	r.nextID++
+
+	if grant.Status == entity.GrantStatusSuccess {
+		day := grantDay(grant.CreatedAt)
+		r.dailyGrants[dailyGrantKey{source: grant.Source, day: day}]++
+		r.dailyGrants[dailyGrantKey{source: grant.Source, userID: grant.UserID, day: day}]++
+		if grant.JobID != 0 {
+			if r.jobGrants[grant.JobID] == nil {
+				r.jobGrants[grant.JobID] = make(map[int]bool)
+			}
+			r.jobGrants[grant.JobID][grant.UserID] = true
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) ListGrants(filter GrantFilter) ([]*entity.RewardGrant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var grants []*entity.RewardGrant
+	for i := len(r.grants) - 1; i >= 0; i-- {
+		grant := r.grants[i]
+		if !matchGrant(grant, filter) {
+			continue
+		}
+		grants = append(grants, grant)
+		if filter.Limit > 0 && len(grants) >= filter.Limit {
+			break
+		}
+	}
+	return grants, nil
+}
+
+func (r *memoryRepository) CountDailyGrants(source string, userID int, day time.Time) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.dailyGrants[dailyGrantKey{source: source, userID: userID, day: grantDay(day)}], nil
+}
+
+func (r *memoryRepository) GrantedJobUsers(jobID int, userIDs []int) (map[int]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	granted := make(map[int]bool)
+	for _, userID := range userIDs {
+		if r.jobGrants[jobID][userID] {
+			granted[userID] = true
+		}
+	}
+	return granted, nil
+}
+
+func matchGrant(grant *entity.RewardGrant, filter GrantFilter) bool {
+	if filter.UserID != 0 && grant.UserID != filter.UserID {
+		return false
+	}
+	if filter.Source != "" && grant.Source != filter.Source {
+		return false
+	}
+	if filter.Actor != "" && grant.Actor != filter.Actor {
+		return false
+	}
+	if filter.Status != "" && grant.Status != filter.Status {
+		return false
+	}
+	if filter.JobID != 0 && grant.JobID != filter.JobID {
+		return false
+	}
+	if !filter.From.IsZero() && grant.CreatedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !grant.CreatedAt.Before(filter.To) {
+		return false
+	}
+	return true
+}
+
+// Bulk grant job operations
+
+func (r *memoryRepository) CreateJob(job *entity.BulkGrantJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if job.Reference != "" && r.findJobByReference(job.Reference) != nil {
+		return ErrJobReferenceExists
+	}
+
+	job.ID = r.nextJobID
+	job.Status = entity.JobStatusPending
+	job.Total = len(job.UserIDs)
+	job.CreatedAt = time.Now()
+	r.jobs[job.ID] = job
+	r.nextJobID++
+	return nil
+}
+
+func (r *memoryRepository) GetJob(id int) (*entity.BulkGrantJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, exists := r.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	return copyJob(job), nil
+}
+
+func (r *memoryRepository) GetJobByReference(reference string) (*entity.BulkGrantJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job := r.findJobByReference(reference)
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	return copyJob(job), nil
+}
+
+func (r *memoryRepository) findJobByReference(reference string) *entity.BulkGrantJob {
+	for _, job := range r.jobs {
+		if job.Reference == reference {
+			return job
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) ListJobs(filter JobFilter) ([]*entity.BulkGrantJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]int, 0, len(r.jobs))
+	for id, job := range r.jobs {
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, job.Status) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	if filter.Limit > 0 && len(ids) > filter.Limit {
+		ids = ids[:filter.Limit]
+	}
+
+	jobs := make([]*entity.BulkGrantJob, len(ids))
+	for i, id := range ids {
+		jobs[i] = copyJob(r.jobs[id])
+	}
+	return jobs, nil
+}
+
+func (r *memoryRepository) UpdateJobStatus(id int, from []entity.JobStatus, status entity.JobStatus) (*entity.BulkGrantJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, exists := r.jobs[id]
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+	if !slices.Contains(from, job.Status) {
+		return nil, ErrJobStateChanged
+	}
+
+	now := time.Now()
+	job.Status = status
+	if status == entity.JobStatusRunning && job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	if job.IsFinished() {
+		job.FinishedAt = &now
+	}
+	return copyJob(job), nil
+}
+
+func (r *memoryRepository) SaveJobProgress(id, processed, succeeded int, failures []entity.JobFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, exists := r.jobs[id]
+	if !exists {
+		return ErrJobNotFound
+	}
+	job.Processed = processed
+	job.SuccessCount += succeeded
+	job.FailureCount += len(failures)
+	job.Failures = append(job.Failures, failures...)
+	return nil
+}
+
+// copyJob copies the job so callers never see later progress updates mid-read
+func copyJob(job *entity.BulkGrantJob) *entity.BulkGrantJob {
+	copied := *job
+	copied.Failures = slices.Clone(job.Failures)
+	return &copied
+}
+
+// Grant request operations
+
+func (r *memoryRepository) CreateGrantRequest(request *entity.GrantRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request.ID = r.nextRequestID
+	request.Status = entity.GrantRequestStatusPending
+	request.UserCount = len(request.UserIDs)
+	request.CreatedAt = time.Now()
+	r.requests = append(r.requests, request)
+	r.nextRequestID++
+	return nil
+}
+
+func (r *memoryRepository) GetGrantRequest(id int) (*entity.GrantRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	request, err := r.findGrantRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	copied := *request
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListGrantRequests(filter GrantRequestFilter) ([]*entity.GrantRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var requests []*entity.GrantRequest
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		request := r.requests[i]
+		if filter.Status != "" && request.Status != filter.Status {
+			continue
+		}
+		if filter.RequestedBy != 0 && request.RequestedBy != filter.RequestedBy {
+			continue
+		}
+		copied := *request
+		requests = append(requests, &copied)
+		if filter.Limit > 0 && len(requests) >= filter.Limit {
+			break
+		}
+	}
+	return requests, nil
+}
+
+func (r *memoryRepository) ReviewGrantRequest(id int, status entity.GrantRequestStatus, reviewedBy int, note string) (*entity.GrantRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, err := r.findGrantRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsPending() {
+		return nil, ErrGrantRequestReviewed
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = reviewedBy
+	request.ReviewNote = note
+	request.ReviewedAt = &now
+
+	copied := *request
+	return &copied, nil
+}
+
+func (r *memoryRepository) CompleteGrantRequest(id int, result interface{}, errMessage string) (*entity.GrantRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, err := r.findGrantRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	request.Result = result
+	if errMessage != "" {
+		request.Status = entity.GrantRequestStatusFailed
+		request.Error = errMessage
+	}
+
+	copied := *request
+	return &copied, nil
+}
+
+func (r *memoryRepository) findGrantRequest(id int) (*entity.GrantRequest, error) {
+	for _, request := range r.requests {
+		if request.ID == id {
+			return request, nil
+		}
+	}
+	return nil, ErrGrantRequestNotFound
+}
+
+// Reward source operations
+
+func (r *memoryRepository) CreateSource(source *entity.RewardSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sources[source.Key]; exists {
+		return ErrSourceExists
+	}
+	now := time.Now()
+	source.CreatedAt = now
+	source.UpdatedAt = now
+	r.sources[source.Key] = copySource(source)
+	return nil
+}
+
+func (r *memoryRepository) GetSource(key string) (*entity.RewardSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	source, exists := r.sources[key]
+	if !exists {
+		return nil, ErrSourceNotFound
+	}
+	return copySource(source), nil
+}
+
+func (r *memoryRepository) ListSources(includeInactive bool) ([]*entity.RewardSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]string, 0, len(r.sources))
+	for key, source := range r.sources {
+		if !includeInactive && !source.Active {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	sources := make([]*entity.RewardSource, len(keys))
+	for i, key := range keys {
+		sources[i] = copySource(r.sources[key])
+	}
+	return sources, nil
+}
+
+func (r *memoryRepository) UpdateSource(source *entity.RewardSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.sources[source.Key]; !exists {
+		return ErrSourceNotFound
+	}
+	source.UpdatedAt = time.Now()
+	r.sources[source.Key] = copySource(source)
+	return nil
+}
+
+func copySource(source *entity.RewardSource) *entity.RewardSource {
+	copied := *source
+	copied.Descriptions = maps.Clone(source.Descriptions)
+	return &copied
+}
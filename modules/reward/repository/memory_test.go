@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/reward/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantIndexes(t *testing.T) {
+	repo := NewMemoryRepository()
+	for _, grant := range []*entity.RewardGrant{
+		{UserID: 1, Source: "event", Status: entity.GrantStatusSuccess, JobID: 7},
+		{UserID: 1, Source: "event", Status: entity.GrantStatusSuccess},
+		{UserID: 2, Source: "event", Status: entity.GrantStatusFailed, JobID: 7},
+		{UserID: 3, Source: "daily", Status: entity.GrantStatusSuccess, JobID: 8},
+	} {
+		require.NoError(t, repo.CreateGrant(grant))
+	}
+
+	// Only successful grants count, per source and UTC day
+	now := time.Now()
+	tests := []struct {
+		name   string
+		source string
+		userID int
+		day    time.Time
+		want   int
+	}{
+		{name: "all users", source: "event", day: now, want: 2},
+		{name: "one user", source: "event", userID: 1, day: now, want: 2},
+		{name: "failed grants are not counted", source: "event", userID: 2, day: now, want: 0},
+		{name: "other source", source: "daily", day: now, want: 1},
+		{name: "other day", source: "event", day: now.AddDate(0, 0, 1), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := repo.CountDailyGrants(tt.source, tt.userID, tt.day)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+
+	// Resuming job 7 skips user 1 but retries user 2, whose grant failed
+	granted, err := repo.GrantedJobUsers(7, []int{1, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{1: true}, granted)
+}
@@ -0,0 +1,62 @@
+// Package rewardtest builds the in-memory reward stack (rewards and mailbox over an
+// itemtest fixture) that tests of reward-granting modules run against.
+package rewardtest
+
+import (
+	"fmt"
+
+	"fxserver/modules/item/itemtest"
+	"fxserver/modules/mailbox"
+	mailboxRepository "fxserver/modules/mailbox/repository"
+	"fxserver/modules/reward"
+	"fxserver/modules/reward/repository"
+	"fxserver/modules/user"
+	userEntity "fxserver/modules/user/entity"
+	userRepository "fxserver/modules/user/repository"
+
+	"go.uber.org/zap"
+)
+
+// Players is how many players New seeds; they get IDs 1 through Players
+const Players = 5
+
+// Fixture is a reward service that delivers into the items of an itemtest fixture
+type Fixture struct {
+	Service    reward.Service
+	Repository repository.Repository
+	Users      userRepository.UserRepository
+}
+
+// New returns a reward service granting through items, with a mailbox for mailed rewards
+// and Players seeded players to grant to
+func New(items *itemtest.Fixture) *Fixture {
+	users := userRepository.NewMemoryUserRepository()
+	for i := 1; i <= Players; i++ {
+		player := &userEntity.User{Name: fmt.Sprintf("player%d", i), Email: fmt.Sprintf("player%d@example.com", i)}
+		if err := users.Create(player); err != nil {
+			panic(err)
+		}
+	}
+	return NewWithUsers(items, users)
+}
+
+// NewWithUsers returns a reward service like New that grants to the players in users
+func NewWithUsers(items *itemtest.Fixture, users userRepository.UserRepository) *Fixture {
+	logger := zap.NewNop()
+	repo := repository.NewMemoryRepository()
+	return &Fixture{
+		Service: reward.NewService(reward.ServiceParam{
+			Repository:  repo,
+			ItemService: items.Service,
+			MailboxService: mailbox.NewService(mailbox.ServiceParam{
+				Repository:  mailboxRepository.NewMemoryRepository(),
+				ItemService: items.Service,
+				Logger:      logger,
+			}),
+			UserService: user.NewService(users, logger),
+			Logger:      logger,
+		}),
+		Repository: repo,
+		Users:      users,
+	}
+}
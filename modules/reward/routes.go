@@ -2,6 +2,7 @@ package reward
 
 import (
 	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
 	"fxserver/pkg/router"
 
 	"github.com/labstack/echo/v4"
@@ -10,18 +11,21 @@ import (
 
 type Routes struct {
 	handler         *Handler
+	userMiddleware  *userauth.Middleware
 	adminMiddleware *adminauth.Middleware
 }
 
 type RoutesParam struct {
 	fx.In
 	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
 	AdminMiddleware *adminauth.Middleware
 }
 
 func NewRoutes(p RoutesParam) router.RouteRegistrar {
 	return &Routes{
 		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
 		adminMiddleware: p.AdminMiddleware,
 	}
 }
@@ -33,9 +37,33 @@ func (r *Routes) RegisterRoutes(e *echo.Echo) {
 	rewards := api.Group("/rewards")
 	rewards.GET("/sources", r.handler.GetRewardSources) // Get reward sources info
 
+	// User reward history routes (user auth required)
+	users := api.Group("/users")
+	users.GET("/me/rewards", r.handler.GetMyRewards, r.userMiddleware.VerifyAccessToken()) // Get my reward history
+
 	// Admin reward management routes (admin auth required)
 	admin := api.Group("/admin")
 	adminRewards := admin.Group("/rewards")
 	adminRewards.POST("/grant", r.handler.GrantReward, r.adminMiddleware.VerifyAdminToken())      // Grant reward to single user
 	adminRewards.POST("/bulk-grant", r.handler.BulkGrantReward, r.adminMiddleware.VerifyAdminToken()) // Grant rewards to multiple users
+	adminRewards.GET("/grants", r.handler.GetGrants, r.adminMiddleware.VerifyAdminToken())            // Get reward grant history
+	adminRewards.POST("/rule-grant", r.handler.RuleGrant, r.adminMiddleware.VerifyAdminToken())       // Grant per-user counts computed by rules (dry_run to preview)
+
+	// Reward source management
+	adminRewards.GET("/sources", r.handler.ListRewardSources, r.adminMiddleware.VerifyAdminToken())       // List sources (include_inactive=true for all)
+	adminRewards.POST("/sources", r.handler.CreateRewardSource, r.adminMiddleware.VerifyAdminToken())     // Register a source
+	adminRewards.PUT("/sources/:key", r.handler.UpdateRewardSource, r.adminMiddleware.VerifyAdminToken()) // Update descriptions, active flag or caps
+
+	// Background bulk grant jobs
+	adminRewards.POST("/bulk-jobs", r.handler.CreateBulkJob, r.adminMiddleware.VerifyAdminToken())                 // Queue a bulk grant job
+	adminRewards.GET("/bulk-jobs", r.handler.GetBulkJobs, r.adminMiddleware.VerifyAdminToken())                    // List bulk grant jobs
+	adminRewards.GET("/bulk-jobs/:id", r.handler.GetBulkJob, r.adminMiddleware.VerifyAdminToken())                 // Get job status and progress
+	adminRewards.POST("/bulk-jobs/:id/cancel", r.handler.CancelBulkJob, r.adminMiddleware.VerifyAdminToken())      // Cancel a job
+	adminRewards.GET("/bulk-jobs/:id/failures", r.handler.GetBulkJobFailures, r.adminMiddleware.VerifyAdminToken()) // Download failed users
+
+	// Two-person approval for grants above the thresholds
+	adminRewards.GET("/approvals", r.handler.GetGrantRequests, r.adminMiddleware.VerifyAdminToken())                 // List grant requests
+	adminRewards.GET("/approvals/:id", r.handler.GetGrantRequest, r.adminMiddleware.VerifyAdminToken())              // Get grant request
+	adminRewards.POST("/approvals/:id/approve", r.handler.ApproveGrantRequest, r.adminMiddleware.VerifyAdminToken()) // Approve and run the grant
+	adminRewards.POST("/approvals/:id/reject", r.handler.RejectGrantRequest, r.adminMiddleware.VerifyAdminToken())   // Reject the grant
 }
\ No newline at end of file
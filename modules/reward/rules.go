@@ -0,0 +1,417 @@
+package reward
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fxserver/modules/item"
+	"fxserver/modules/item/entity"
+	"fxserver/modules/payment"
+	paymentEntity "fxserver/modules/payment/entity"
+	"fxserver/modules/user"
+	"fxserver/pkg/expr"
+	"fxserver/pkg/i18n"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid reward rule")
+)
+
+const (
+	// MaxRuleGrantUsers caps the users of a rule grant, which runs synchronously like bulk-grant
+	MaxRuleGrantUsers = 1000
+	// MaxRuleItemCount caps the count a rule may compute for one item and user
+	MaxRuleItemCount = 1_000_000
+)
+
+// Per-user variables available to rule expressions
+const (
+	RuleVarAge             = "age"               // 사용자 나이
+	RuleVarAccountAgeDays  = "account_age_days"  // 가입 후 경과 일수
+	RuleVarPaymentTotal    = "payment_total"     // 완료된 결제 금액 합계 (통화 구분 없음)
+	RuleVarPaymentCount    = "payment_count"     // 완료된 결제 수
+	RuleVarPaymentTotal30d = "payment_total_30d" // 최근 30일 완료된 결제 금액 합계
+	RuleVarPaymentCount30d = "payment_count_30d" // 최근 30일 완료된 결제 수
+	RuleVarItemPrefix      = "item_"             // item_<아이템 ID>: 보유 수량 (스택 수량 + 인스턴스 수)
+)
+
+var ruleUserVariables = []string{RuleVarAge, RuleVarAccountAgeDays}
+var rulePaymentVariables = []string{RuleVarPaymentTotal, RuleVarPaymentCount, RuleVarPaymentTotal30d, RuleVarPaymentCount30d}
+
+// RuleService computes per-user grants from count expressions.
+// Evaluate never grants anything, so admins can preview the results before running Execute.
+type RuleService interface {
+	Evaluate(req RuleGrantRequest) (*RuleGrantPreview, error)
+	// Execute grants the items computed by Evaluate; users whose rules failed to evaluate are reported as failures
+	Execute(req RuleGrantRequest, preview *RuleGrantPreview) (*BulkGrantRewardResponse, error)
+}
+
+type ruleService struct {
+	service        Service
+	jobService     JobService
+	itemService    item.Service
+	paymentService payment.Service
+	userService    user.Service
+	logger         *zap.Logger
+
+	now func() time.Time
+}
+
+type RuleServiceParam struct {
+	fx.In
+	Service        Service
+	JobService     JobService
+	ItemService    item.Service
+	PaymentService payment.Service
+	UserService    user.Service
+	Logger         *zap.Logger
+}
+
+func NewRuleService(p RuleServiceParam) RuleService {
+	return &ruleService{
+		service:        p.Service,
+		jobService:     p.JobService,
+		itemService:    p.ItemService,
+		paymentService: p.PaymentService,
+		userService:    p.UserService,
+		logger:         p.Logger,
+		now:            time.Now,
+	}
+}
+
+// compiledRule is a RewardRule with its expressions parsed
+type compiledRule struct {
+	itemID    int
+	count     *expr.Expr
+	condition *expr.Expr // nil: 모든 사용자에게 적용
+}
+
+func (s *ruleService) Evaluate(req RuleGrantRequest) (*RuleGrantPreview, error) {
+	if err := s.service.ValidateSource(req.Source); err != nil {
+		return nil, err
+	}
+
+	rules, variables, err := s.compile(req)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs, err := s.jobService.ResolveTargets(CreateBulkJobRequest{UserIDs: req.UserIDs, Target: req.Target})
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) > MaxRuleGrantUsers {
+		return nil, fmt.Errorf("%w: %d exceeds %d", ErrTooManyTargets, len(userIDs), MaxRuleGrantUsers)
+	}
+
+	preview := &RuleGrantPreview{
+		TotalUsers: len(userIDs),
+		Users:      make([]RuleUserResult, 0, len(userIDs)),
+	}
+	var totals []entity.RewardItem
+	for _, userID := range userIDs {
+		result := s.evaluateUser(userID, rules, variables, req.Params)
+		switch {
+		case result.Error != "":
+			preview.ErrorCount++
+		case len(result.Items) == 0:
+			preview.SkippedCount++
+		default:
+			preview.EligibleCount++
+			totals = append(totals, result.Items...)
+		}
+		preview.Users = append(preview.Users, result)
+	}
+	preview.Totals = entity.MergeRewardItems(totals)
+	return preview, nil
+}
+
+// compile parses the rules and returns them with the variable names they read
+func (s *ruleService) compile(req RuleGrantRequest) ([]compiledRule, []string, error) {
+	for name := range req.Params {
+		if !isIdentifier(name) || isRuleVariable(name) {
+			return nil, nil, fmt.Errorf("%w: param %q must be an identifier that does not shadow a built-in variable", ErrInvalidRule, name)
+		}
+	}
+
+	// 같은 아이템을 여러 규칙이 지급하지 않도록 ValidateRewardItems로 중복과 존재 여부를 확인
+	items := make([]entity.RewardItem, len(req.Rules))
+	for i, rule := range req.Rules {
+		items[i] = entity.RewardItem{ItemID: rule.ItemID, Count: 1}
+	}
+	if err := s.service.ValidateRewardItems(items); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+
+	seen := make(map[string]bool)
+	rules := make([]compiledRule, len(req.Rules))
+	for i, rule := range req.Rules {
+		count, err := expr.Parse(rule.Count)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: count of item %d: %v", ErrInvalidRule, rule.ItemID, err)
+		}
+		rules[i] = compiledRule{itemID: rule.ItemID, count: count}
+		if rule.Condition != "" {
+			condition, err := expr.Parse(rule.Condition)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: condition of item %d: %v", ErrInvalidRule, rule.ItemID, err)
+			}
+			rules[i].condition = condition
+		}
+
+		for _, e := range []*expr.Expr{rules[i].count, rules[i].condition} {
+			if e == nil {
+				continue
+			}
+			for _, name := range e.Variables() {
+				if _, isParam := req.Params[name]; !isParam && !isRuleVariable(name) {
+					return nil, nil, fmt.Errorf("%w: unknown variable %q in rule for item %d", ErrInvalidRule, name, rule.ItemID)
+				}
+				seen[name] = true
+			}
+		}
+	}
+
+	variables := make([]string, 0, len(seen))
+	for name := range seen {
+		variables = append(variables, name)
+	}
+	slices.Sort(variables)
+	return rules, variables, nil
+}
+
+// evaluateUser resolves the variables the rules read for one user and computes the user's items
+func (s *ruleService) evaluateUser(userID int, rules []compiledRule, variables []string, params map[string]float64) RuleUserResult {
+	result := RuleUserResult{UserID: userID, Items: []entity.RewardItem{}}
+
+	vars, err := s.resolveVariables(userID, variables, params)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Variables = vars
+
+	// 계산에 실패한 사용자는 일부 아이템만 지급되지 않도록 결과를 비워 둠
+	var items []entity.RewardItem
+	for _, rule := range rules {
+		if rule.condition != nil {
+			matched, err := rule.condition.Eval(vars)
+			if err != nil {
+				result.Error = fmt.Sprintf("condition of item %d: %v", rule.itemID, err)
+				return result
+			}
+			if matched == 0 {
+				continue
+			}
+		}
+
+		value, err := rule.count.Eval(vars)
+		if err != nil {
+			result.Error = fmt.Sprintf("count of item %d: %v", rule.itemID, err)
+			return result
+		}
+		// 소수점 이하는 버리고 0 이하는 지급하지 않음
+		count := math.Floor(value)
+		if count > MaxRuleItemCount {
+			result.Error = fmt.Sprintf("count of item %d is %.0f, above the limit of %d", rule.itemID, count, MaxRuleItemCount)
+			return result
+		}
+		if count > 0 {
+			items = append(items, entity.RewardItem{ItemID: rule.itemID, Count: int(count)})
+		}
+	}
+	if items != nil {
+		result.Items = items
+	}
+	return result
+}
+
+// resolveVariables looks up only the variables the rules read, so rules without payment
+// variables never query payments
+func (s *ruleService) resolveVariables(userID int, variables []string, params map[string]float64) (map[string]float64, error) {
+	vars := make(map[string]float64)
+	for name, value := range params {
+		vars[name] = value
+	}
+
+	if containsAny(variables, ruleUserVariables) {
+		u, err := s.userService.GetUser(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		vars[RuleVarAge] = float64(u.Age)
+		vars[RuleVarAccountAgeDays] = math.Floor(s.now().Sub(u.CreatedAt).Hours() / 24)
+	}
+
+	if containsAny(variables, rulePaymentVariables) {
+		history, err := s.paymentService.GetUserPaymentsByStatus(userID, paymentEntity.PaymentStatusCompleted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payments: %w", err)
+		}
+		since := s.now().AddDate(0, 0, -30)
+		vars[RuleVarPaymentTotal], vars[RuleVarPaymentCount] = 0, 0
+		vars[RuleVarPaymentTotal30d], vars[RuleVarPaymentCount30d] = 0, 0
+		for _, p := range history.Payments {
+			vars[RuleVarPaymentTotal] += p.Amount
+			vars[RuleVarPaymentCount]++
+
+			paidAt := p.CreatedAt
+			if p.ProcessedAt != nil {
+				paidAt = *p.ProcessedAt
+			}
+			if !paidAt.Before(since) {
+				vars[RuleVarPaymentTotal30d] += p.Amount
+				vars[RuleVarPaymentCount30d]++
+			}
+		}
+	}
+
+	if slices.ContainsFunc(variables, isItemVariable) {
+		inventory, err := s.itemService.GetUserInventory(userID, i18n.FallbackLocale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inventory: %w", err)
+		}
+		counts := make(map[int]int)
+		for _, stack := range inventory.Items {
+			counts[stack.Item.ID] += stack.Count
+		}
+		for _, instance := range inventory.Instances {
+			counts[instance.Item.ID]++
+		}
+		for _, name := range variables {
+			if itemID, ok := itemVariableID(name); ok {
+				vars[name] = float64(counts[itemID])
+			}
+		}
+	}
+
+	// 미리보기에는 규칙이 참조한 변수만 노출
+	referenced := make(map[string]float64, len(variables))
+	for _, name := range variables {
+		referenced[name] = vars[name]
+	}
+	return referenced, nil
+}
+
+func (s *ruleService) Execute(req RuleGrantRequest, preview *RuleGrantPreview) (*BulkGrantRewardResponse, error) {
+	userItems := make(map[int][]entity.RewardItem, preview.EligibleCount)
+	var userIDs []int
+	var failed []GrantRewardResponse
+	for _, result := range preview.Users {
+		if result.Error != "" {
+			failed = append(failed, GrantRewardResponse{
+				UserID:      result.UserID,
+				Items:       result.Items,
+				Source:      req.Source,
+				Description: req.Description,
+				Success:     false,
+				Message:     "Rule evaluation failed: " + result.Error,
+			})
+			continue
+		}
+		if len(result.Items) > 0 {
+			userIDs = append(userIDs, result.UserID)
+			userItems[result.UserID] = result.Items
+		}
+	}
+
+	response := grantEach(s.service, GrantRewardRequest{
+		Source:            req.Source,
+		Description:       req.Description,
+		OverflowPolicy:    req.OverflowPolicy,
+		Delivery:          req.Delivery,
+		MailTitle:         req.MailTitle,
+		MailExpiresInDays: req.MailExpiresInDays,
+		GrantedBy:         req.GrantedBy,
+	}, userIDs, userItems)
+	response.Results = append(response.Results, failed...)
+	response.TotalUsers += len(failed)
+	response.FailureCount += len(failed)
+
+	s.logger.Info("Rule reward grant completed",
+		zap.String("source", req.Source),
+		zap.Int("granted_by", req.GrantedBy),
+		zap.Int("total_users", preview.TotalUsers),
+		zap.Int("success_count", response.SuccessCount),
+		zap.Int("failure_count", response.FailureCount),
+		zap.Int("skipped_count", preview.SkippedCount))
+
+	return response, nil
+}
+
+// grantEach grants each user their own items with the shared settings of base.
+// Items of the response are the totals over all users.
+func grantEach(service Service, base GrantRewardRequest, userIDs []int, userItems map[int][]entity.RewardItem) *BulkGrantRewardResponse {
+	response := &BulkGrantRewardResponse{
+		TotalUsers:  len(userIDs),
+		Results:     make([]GrantRewardResponse, 0, len(userIDs)),
+		Source:      base.Source,
+		Description: base.Description,
+	}
+
+	var totals []entity.RewardItem
+	for _, userID := range userIDs {
+		req := base
+		req.UserID = userID
+		req.Items = userItems[userID]
+		totals = append(totals, req.Items...)
+
+		result, err := service.GrantRewards(req)
+		if result == nil {
+			result = &GrantRewardResponse{UserID: userID, Items: req.Items, Source: req.Source, Message: err.Error()}
+		}
+		if err != nil {
+			response.FailureCount++
+		} else {
+			response.SuccessCount++
+		}
+		response.Results = append(response.Results, *result)
+	}
+	response.Items = entity.MergeRewardItems(totals)
+	return response
+}
+
+func isRuleVariable(name string) bool {
+	return slices.Contains(ruleUserVariables, name) || slices.Contains(rulePaymentVariables, name) || isItemVariable(name)
+}
+
+func isItemVariable(name string) bool {
+	_, ok := itemVariableID(name)
+	return ok
+}
+
+// itemVariableID parses the item ID of an item_<id> variable
+func itemVariableID(name string) (int, bool) {
+	if !strings.HasPrefix(name, RuleVarItemPrefix) {
+		return 0, false
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(name, RuleVarItemPrefix))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(names, candidates []string) bool {
+	return slices.ContainsFunc(names, func(name string) bool { return slices.Contains(candidates, name) })
+}
@@ -0,0 +1,193 @@
+package reward
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/item/entity"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/payment"
+	paymentEntity "fxserver/modules/payment/entity"
+	paymentRepository "fxserver/modules/payment/repository"
+	rewardEntity "fxserver/modules/reward/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const diamondID = 2
+
+// setupRuleService seeds users 1, 2 (paid 60 forty days ago) and 3 (paid 20 yesterday)
+func setupRuleService(t *testing.T) (*ruleService, *jobService, itemRepository.Repository) {
+	jobs, items, _ := setupJobService(t)
+
+	now := time.Now()
+	payments := paymentRepository.NewMemoryRepository()
+	for _, p := range []struct {
+		userID int
+		amount float64
+		paidAt time.Time
+	}{
+		{2, 60, now.AddDate(0, 0, -40)},
+		{3, 20, now.AddDate(0, 0, -1)},
+	} {
+		paidAt := p.paidAt
+		require.NoError(t, payments.CreatePayment(&paymentEntity.Payment{
+			UserID:      p.userID,
+			Amount:      p.amount,
+			Currency:    "USD",
+			Status:      paymentEntity.PaymentStatusCompleted,
+			ProcessedAt: &paidAt,
+		}))
+	}
+
+	logger := zap.NewNop()
+	rules := NewRuleService(RuleServiceParam{
+		Service:        jobs.service,
+		JobService:     jobs,
+		ItemService:    jobs.service.(*service).itemService,
+		PaymentService: payment.NewService(payment.ServiceParam{Repository: payments, Logger: logger}),
+		UserService:    jobs.userService,
+		Logger:         logger,
+	}).(*ruleService)
+	rules.now = func() time.Time { return now }
+	return rules, jobs, items
+}
+
+func compensationRules(userIDs ...int) RuleGrantRequest {
+	return RuleGrantRequest{
+		UserIDs: userIDs,
+		Rules: []RewardRule{
+			{ItemID: diamondID, Count: "downtime_hours * 10 * (payment_count_30d > 0 ? 2 : 1)"},
+			{ItemID: potionID, Count: "1", Condition: "payment_total >= 50"},
+		},
+		Params:      map[string]float64{"downtime_hours": 3},
+		Source:      RewardSourceCompensation,
+		Description: "Server downtime compensation",
+		GrantedBy:   1,
+	}
+}
+
+func TestRuleGrantPreviewAndExecute(t *testing.T) {
+	rules, _, items := setupRuleService(t)
+	req := compensationRules(1, 2, 3)
+
+	preview, err := rules.Evaluate(req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.TotalUsers)
+	assert.Equal(t, 3, preview.EligibleCount)
+	assert.Equal(t, []entity.RewardItem{{ItemID: diamondID, Count: 120}, {ItemID: potionID, Count: 1}}, preview.Totals)
+
+	require.Len(t, preview.Users, 3)
+	assert.Equal(t, []entity.RewardItem{{ItemID: diamondID, Count: 30}}, preview.Users[0].Items)
+	assert.Equal(t, []entity.RewardItem{{ItemID: diamondID, Count: 30}, {ItemID: potionID, Count: 1}}, preview.Users[1].Items)
+	assert.Equal(t, []entity.RewardItem{{ItemID: diamondID, Count: 60}}, preview.Users[2].Items)
+	assert.Equal(t, map[string]float64{"downtime_hours": 3, "payment_count_30d": 1, "payment_total": 20}, preview.Users[2].Variables)
+
+	// Previewing grants nothing
+	_, err = items.GetUserInventoryItem(3, diamondID)
+	assert.Error(t, err)
+
+	response, err := rules.Execute(req, preview)
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.SuccessCount)
+	assert.Equal(t, preview.Totals, response.Items)
+
+	inventory, err := items.GetUserInventoryItem(3, diamondID)
+	require.NoError(t, err)
+	assert.Equal(t, 60, inventory.Count)
+	inventory, err = items.GetUserInventoryItem(2, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, inventory.Count)
+}
+
+func TestRuleGrantInventoryVariables(t *testing.T) {
+	rules, jobs, _ := setupRuleService(t)
+	_, err := jobs.service.GrantRewards(GrantRewardRequest{
+		UserID:      1,
+		Items:       []entity.RewardItem{{ItemID: potionID, Count: 4}},
+		Source:      RewardSourceEvent,
+		Description: "Tournament winner reward",
+	})
+	require.NoError(t, err)
+
+	// Top up potions to 5; users who already have enough get nothing
+	preview, err := rules.Evaluate(RuleGrantRequest{
+		UserIDs:     []int{1, 2},
+		Rules:       []RewardRule{{ItemID: potionID, Count: "max(5 - item_3, 0)"}},
+		Source:      RewardSourceEvent,
+		Description: "Potion top-up event",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.RewardItem{{ItemID: potionID, Count: 1}}, preview.Users[0].Items)
+	assert.Equal(t, []entity.RewardItem{{ItemID: potionID, Count: 5}}, preview.Users[1].Items)
+	assert.Equal(t, map[string]float64{"item_3": 4}, preview.Users[0].Variables)
+}
+
+func TestRuleGrantValidation(t *testing.T) {
+	rules, _, _ := setupRuleService(t)
+
+	for name, mutate := range map[string]func(req *RuleGrantRequest){
+		"syntax error":     func(req *RuleGrantRequest) { req.Rules[0].Count = "downtime_hours *" },
+		"unknown variable": func(req *RuleGrantRequest) { req.Rules[0].Count = "level * 2" },
+		"shadowing param":  func(req *RuleGrantRequest) { req.Params["payment_total"] = 1 },
+		"duplicate item":   func(req *RuleGrantRequest) { req.Rules[1].ItemID = diamondID },
+		"unknown item":     func(req *RuleGrantRequest) { req.Rules[1].ItemID = 999 },
+	} {
+		req := compensationRules(1)
+		mutate(&req)
+		_, err := rules.Evaluate(req)
+		assert.ErrorIs(t, err, ErrInvalidRule, name)
+	}
+
+	// Evaluation errors are reported per user and count as failures when executed
+	req := compensationRules(1, 2)
+	req.Rules = []RewardRule{{ItemID: diamondID, Count: "60 / payment_total"}}
+	preview, err := rules.Evaluate(req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, preview.ErrorCount)
+	assert.Contains(t, preview.Users[0].Error, "division by zero")
+	assert.Equal(t, []entity.RewardItem{{ItemID: diamondID, Count: 1}}, preview.Users[1].Items)
+
+	response, err := rules.Execute(req, preview)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.TotalUsers)
+	assert.Equal(t, 1, response.SuccessCount)
+	assert.Equal(t, 1, response.FailureCount)
+}
+
+func TestHoldRuleGrant(t *testing.T) {
+	t.Setenv("REWARD_APPROVAL_VALUE_THRESHOLD", "100")
+	rules, jobs, items := setupRuleService(t)
+	approvals := NewApprovalService(ApprovalServiceParam{
+		Repository:  jobs.repo,
+		Service:     jobs.service,
+		JobService:  jobs,
+		ItemService: rules.itemService,
+		Logger:      zap.NewNop(),
+	})
+
+	req := compensationRules(1, 2, 3)
+	preview, err := rules.Evaluate(req)
+	require.NoError(t, err)
+
+	// The value counts each user's own items
+	held, err := approvals.HoldRuleGrant(req, preview)
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	assert.Equal(t, rewardEntity.GrantRequestKindRuleGrant, held.Kind)
+	assert.Equal(t, 121, held.TotalValue)
+	assert.Equal(t, preview.Totals, held.Items)
+
+	approved, err := approvals.Approve(held.ID, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, rewardEntity.GrantRequestStatusApproved, approved.Status)
+	result, ok := approved.Result.(*BulkGrantRewardResponse)
+	require.True(t, ok)
+	assert.Equal(t, 3, result.SuccessCount)
+
+	inventory, err := items.GetUserInventoryItem(2, diamondID)
+	require.NoError(t, err)
+	assert.Equal(t, 30, inventory.Count)
+}
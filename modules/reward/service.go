@@ -1,54 +1,105 @@
 package reward
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"fxserver/modules/item"
 	"fxserver/modules/item/entity"
+	"fxserver/modules/mailbox"
+	mailboxEntity "fxserver/modules/mailbox/entity"
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/repository"
+	"fxserver/modules/user"
+	userRepository "fxserver/modules/user/repository"
+	"fxserver/pkg/i18n"
+	"fxserver/pkg/lock"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrInvalidDateRange = errors.New("invalid date range")
+)
+
 type Service interface {
 	// Core reward granting
 	GrantRewards(req GrantRewardRequest) (*GrantRewardResponse, error)
 	BulkGrantRewards(req BulkGrantRewardRequest) (*BulkGrantRewardResponse, error)
+	// PreviewGrants runs the checks of BulkGrantRewards for each user without granting or recording anything
+	PreviewGrants(req BulkGrantRewardRequest) (*BulkGrantRewardResponse, error)
+
+	// Grant history
+	ListGrants(query GrantHistoryQuery) ([]*rewardEntity.RewardGrant, error)
+	ListUserRewards(userID int, query MyRewardsQuery, locale i18n.Locale) ([]UserRewardResponse, error)
 	
 	// Helper methods for other services
-	GrantItemsToUser(userID int, items []entity.RewardItem, source, description string) error
+	// GrantItemsToUser is the grant entry point for other modules. Like admin grants it requires an active source,
+	// enforces the source's daily caps and records the grant in the reward history.
+	// An empty policy uses the item module's default overflow policy
+	GrantItemsToUser(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source, description string, ref entity.TransactionRef) (*entity.GrantResult, error)
 	ValidateRewardItems(items []entity.RewardItem) error
+
+	// Reward sources
+	ListSources(includeInactive bool) ([]*rewardEntity.RewardSource, error)
+	CreateSource(req CreateSourceRequest) (*rewardEntity.RewardSource, error)
+	UpdateSource(key string, req UpdateSourceRequest) (*rewardEntity.RewardSource, error)
+	ValidateSource(key string) error
+	DescribeSource(key string, locale i18n.Locale) string
 }
 
 type service struct {
-	itemService item.Service
-	logger      *zap.Logger
+	repo           repository.Repository
+	itemService    item.Service
+	mailboxService mailbox.Service
+	userService    user.Service
+	logger         *zap.Logger
+
+	// capLocks serializes grants under each capped source so its daily count cannot be overshot
+	capLocks lock.Keyed[string]
 }
 
 type ServiceParam struct {
 	fx.In
-	ItemService item.Service
-	Logger      *zap.Logger
+	Repository     repository.Repository
+	ItemService    item.Service
+	MailboxService mailbox.Service
+	UserService    user.Service
+	Logger         *zap.Logger
 }
 
 func NewService(p ServiceParam) Service {
 	return &service{
-		itemService: p.ItemService,
-		logger:      p.Logger,
+		repo:           p.Repository,
+		itemService:    p.ItemService,
+		mailboxService: p.MailboxService,
+		userService:    p.UserService,
+		logger:         p.Logger,
 	}
 }
 
+// delivery describes how an admin grant reaches the user
+type delivery struct {
+	method        string
+	mailTitle     string
+	expiresInDays int
+	noExpiry      bool // expiresInDays가 없으면 만료 없는 우편으로 발송
+}
+
 func (s *service) GrantRewards(req GrantRewardRequest) (*GrantRewardResponse, error) {
 	// Validate reward source
-	if !IsValidRewardSource(req.Source) {
+	source, err := s.activeSource(req.Source)
+	if err != nil {
 		return &GrantRewardResponse{
 			UserID:  req.UserID,
 			Items:   req.Items,
 			Source:  req.Source,
 			Success: false,
-			Message: "Invalid reward source",
-		}, fmt.Errorf("invalid reward source: %s", req.Source)
+			Message: err.Error(),
+		}, err
 	}
 
 	// Validate reward items
@@ -63,7 +114,9 @@ func (s *service) GrantRewards(req GrantRewardRequest) (*GrantRewardResponse, er
 	}
 
 	// Grant items to user
-	if err := s.GrantItemsToUser(req.UserID, req.Items, req.Source, req.Description); err != nil {
+	target := delivery{method: req.Delivery, mailTitle: req.MailTitle, expiresInDays: req.MailExpiresInDays}
+	result, mailID, err := s.grantToUser(source, req.UserID, req.Items, req.OverflowPolicy, target, req.Description, adminGrantRef(req.GrantedBy), req.GrantedBy, req.JobID)
+	if err != nil {
 		s.logger.Error("Failed to grant rewards to user", 
 			zap.Error(err),
 			zap.Int("user_id", req.UserID),
@@ -90,15 +143,18 @@ func (s *service) GrantRewards(req GrantRewardRequest) (*GrantRewardResponse, er
 		Source:      req.Source,
 		Description: req.Description,
 		GrantedAt:   time.Now().Format(time.RFC3339),
+		GrantResult: result,
+		MailID:      mailID,
 		Success:     true,
-		Message:     "Rewards granted successfully",
+		Message:     grantMessage(result, mailID),
 	}, nil
 }
 
 func (s *service) BulkGrantRewards(req BulkGrantRewardRequest) (*BulkGrantRewardResponse, error) {
 	// Validate reward source
-	if !IsValidRewardSource(req.Source) {
-		return nil, fmt.Errorf("invalid reward source: %s", req.Source)
+	source, err := s.activeSource(req.Source)
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate reward items
@@ -111,8 +167,9 @@ func (s *service) BulkGrantRewards(req BulkGrantRewardRequest) (*BulkGrantReward
 	failureCount := 0
 
 	// Grant rewards to each user
+	target := delivery{method: req.Delivery, mailTitle: req.MailTitle, expiresInDays: req.MailExpiresInDays}
 	for i, userID := range req.UserIDs {
-		err := s.GrantItemsToUser(userID, req.Items, req.Source, req.Description)
+		result, mailID, err := s.grantToUser(source, userID, req.Items, req.OverflowPolicy, target, req.Description, adminGrantRef(req.GrantedBy), req.GrantedBy, 0)
 		
 		results[i] = GrantRewardResponse{
 			UserID:      userID,
@@ -120,6 +177,8 @@ func (s *service) BulkGrantRewards(req BulkGrantRewardRequest) (*BulkGrantReward
 			Source:      req.Source,
 			Description: req.Description,
 			GrantedAt:   time.Now().Format(time.RFC3339),
+			GrantResult: result,
+			MailID:      mailID,
 		}
 
 		if err != nil {
@@ -133,7 +192,7 @@ func (s *service) BulkGrantRewards(req BulkGrantRewardRequest) (*BulkGrantReward
 				zap.String("source", req.Source))
 		} else {
 			results[i].Success = true
-			results[i].Message = "Rewards granted successfully"
+			results[i].Message = grantMessage(result, mailID)
 			successCount++
 		}
 	}
@@ -156,33 +215,383 @@ func (s *service) BulkGrantRewards(req BulkGrantRewardRequest) (*BulkGrantReward
 	}, nil
 }
 
-func (s *service) GrantItemsToUser(userID int, items []entity.RewardItem, source, description string) error {
+func (s *service) PreviewGrants(req BulkGrantRewardRequest) (*BulkGrantRewardResponse, error) {
+	// 출처나 아이템이 잘못되면 모든 사용자가 실패하므로 사유와 함께 응답을 돌려줌
+	source, err := s.activeSource(req.Source)
+	if err == nil {
+		err = s.ValidateRewardItems(req.Items)
+	}
+	if err != nil {
+		return previewFailure(req, err), err
+	}
+
+	results := make([]GrantRewardResponse, len(req.UserIDs))
+	successCount := 0
+	failureCount := 0
+
+	// 미리보기에서 통과한 지급도 일일 한도에 포함하여 실제 지급과 같은 결과가 나오도록 함
+	pending := &capUsage{users: make(map[int]int)}
+	target := delivery{method: req.Delivery}
+	var received []entity.RewardItem
+	for i, userID := range req.UserIDs {
+		results[i] = GrantRewardResponse{
+			UserID:      userID,
+			Items:       req.Items,
+			Source:      req.Source,
+			Description: req.Description,
+		}
+
+		result, err := s.previewUser(source, userID, req.Items, req.OverflowPolicy, target, pending)
+		if err != nil {
+			if !errors.Is(err, userRepository.ErrUserNotFound) && !errors.Is(err, ErrSourceCapReached) &&
+				!errors.Is(err, item.ErrInventoryFull) {
+				return nil, err
+			}
+			results[i].Success = false
+			results[i].Message = err.Error()
+			failureCount++
+			continue
+		}
+
+		pending.total++
+		pending.users[userID]++
+		results[i].GrantResult = result
+		results[i].Success = true
+		results[i].Message = "Rewards can be granted"
+		successCount++
+		received = append(received, receivedItems(req.Items, result)...)
+	}
+
+	totals := entity.MergeRewardItems(received)
+
+	return &BulkGrantRewardResponse{
+		TotalUsers:   len(req.UserIDs),
+		SuccessCount: successCount,
+		FailureCount: failureCount,
+		Results:      results,
+		Items:        req.Items,
+		Source:       req.Source,
+		Description:  req.Description,
+		DryRun:       true,
+		Totals:       totals,
+	}, nil
+}
+
+func previewFailure(req BulkGrantRewardRequest, err error) *BulkGrantRewardResponse {
+	results := make([]GrantRewardResponse, len(req.UserIDs))
+	for i, userID := range req.UserIDs {
+		results[i] = GrantRewardResponse{
+			UserID:      userID,
+			Items:       req.Items,
+			Source:      req.Source,
+			Description: req.Description,
+			Success:     false,
+			Message:     err.Error(),
+		}
+	}
+	return &BulkGrantRewardResponse{
+		TotalUsers:   len(req.UserIDs),
+		FailureCount: len(req.UserIDs),
+		Results:      results,
+		Items:        req.Items,
+		Source:       req.Source,
+		Description:  req.Description,
+		DryRun:       true,
+		Totals:       []entity.RewardItem{},
+	}
+}
+
+// previewUser checks that the user exists and the grant fits the source's daily caps.
+// For direct delivery it also plans the grant against the user's inventory, returning what would fit and overflow.
+func (s *service) previewUser(source *rewardEntity.RewardSource, userID int, items []entity.RewardItem, policy entity.OverflowPolicy, target delivery, pending *capUsage) (*entity.GrantResult, error) {
+	if err := s.checkUser(userID); err != nil {
+		return nil, err
+	}
+	if err := s.checkDailyCap(source, userID, pending); err != nil {
+		return nil, err
+	}
+	if target.method == DeliveryMailbox {
+		return nil, nil
+	}
+	return s.itemService.PlanGrant(userID, items, policy)
+}
+
+// receivedItems is what a user ends up with: everything sent by mail, otherwise what fits plus the overflow the policy keeps
+func receivedItems(items []entity.RewardItem, result *entity.GrantResult) []entity.RewardItem {
+	if result == nil {
+		return items
+	}
+	if result.Policy == entity.OverflowMailbox {
+		return append(slices.Clone(result.Granted), result.Overflow...)
+	}
+	return result.Granted
+}
+
+func (s *service) GrantItemsToUser(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source, description string, ref entity.TransactionRef) (*entity.GrantResult, error) {
 	// Validate inputs
 	if userID <= 0 {
-		return fmt.Errorf("invalid user ID: %d", userID)
+		return nil, fmt.Errorf("invalid user ID: %d", userID)
 	}
 
 	if len(items) == 0 {
-		return fmt.Errorf("no items to grant")
+		return nil, fmt.Errorf("no items to grant")
 	}
 
 	if source == "" {
-		return fmt.Errorf("reward source is required")
+		return nil, fmt.Errorf("reward source is required")
 	}
 
 	// Validate all items exist before granting any
 	if err := s.ValidateRewardItems(items); err != nil {
-		return err
+		return nil, err
+	}
+
+	rewardSource, err := s.activeSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// 관리자 지급과 같은 경로로 일일 한도 확인과 지급 기록을 거침
+	result, _, err := s.grantToUser(rewardSource, userID, items, policy, delivery{}, description, ref, 0, 0)
+	return result, err
+}
+
+// addToInventory grants the items directly through the item service
+func (s *service) addToInventory(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, source string, ref entity.TransactionRef) (*entity.GrantResult, error) {
+	result, err := s.itemService.AddMultipleToInventory(userID, items, policy, source, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add items to inventory: %w", err)
+	}
+	return result, nil
+}
+
+// grantToUser is the single path every grant takes: it enforces the source's daily caps, delivers one user's grant and records the outcome
+func (s *service) grantToUser(source *rewardEntity.RewardSource, userID int, items []entity.RewardItem, policy entity.OverflowPolicy, target delivery, description string, ref entity.TransactionRef, grantedBy, jobID int) (*entity.GrantResult, int, error) {
+	if source.HasDailyCap() {
+		// 집계와 기록 사이에 다른 지급이 끼어들지 않도록 함
+		defer s.capLocks.Lock(source.Key)()
+	}
+
+	var (
+		result *entity.GrantResult
+		mailID int
+	)
+	err := s.checkUser(userID)
+	if err == nil {
+		err = s.checkDailyCap(source, userID, nil)
+	}
+	if err == nil {
+		result, mailID, err = s.deliver(userID, items, policy, target, source, description, ref)
+	}
+	s.recordGrant(userID, items, source.Key, description, grantedBy, jobID, ref, target, result, mailID, err)
+	return result, mailID, err
+}
+
+// checkUser fails for users that do not exist so no grant is made to a missing account
+func (s *service) checkUser(userID int) error {
+	if _, err := s.userService.GetUser(userID); err != nil {
+		if errors.Is(err, userRepository.ErrUserNotFound) {
+			return fmt.Errorf("%w: %d", err, userID)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
+
+// deliver grants the items directly or sends them to the user's mailbox. It returns the ID of
+// the mail it sent: the whole grant for mailbox delivery, or the overflow under the mailbox policy.
+func (s *service) deliver(userID int, items []entity.RewardItem, policy entity.OverflowPolicy, target delivery, source *rewardEntity.RewardSource, description string, ref entity.TransactionRef) (*entity.GrantResult, int, error) {
+	if target.method != DeliveryMailbox {
+		result, err := s.addToInventory(userID, items, policy, source.Key, ref)
+		if err != nil {
+			return nil, 0, err
+		}
+		return result, s.mailOverflow(userID, result, source, description, ref), nil
+	}
+
+	mail, err := s.sendMail(userID, items, target, source.Key, description, ref)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to send reward mail: %w", err)
+	}
+	return nil, mail.ID, nil
+}
+
+// mailOverflow sends the units that did not fit under the mailbox policy to the user's mailbox.
+// The rest of the grant has already been applied, so a failure is logged for manual
+// compensation instead of failing the grant; it returns the mail ID, or 0 if nothing was sent.
+// Overflow of a paid source never expires so nothing the player paid for is lost.
+func (s *service) mailOverflow(userID int, result *entity.GrantResult, source *rewardEntity.RewardSource, description string, ref entity.TransactionRef) int {
+	if result.Policy != entity.OverflowMailbox || len(result.Overflow) == 0 {
+		return 0
+	}
+
+	mail, err := s.sendMail(userID, result.Overflow, delivery{noExpiry: source.Paid}, source.Key, description, ref)
+	if err != nil {
+		s.logger.Error("Failed to mail inventory overflow",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.String("source", source.Key),
+			zap.Any("overflow", result.Overflow))
+		return 0
+	}
+	return mail.ID
+}
+
+// sendMail sends items to the user's mailbox, titled after the source unless the delivery sets a title
+func (s *service) sendMail(userID int, items []entity.RewardItem, target delivery, source, description string, ref entity.TransactionRef) (*mailboxEntity.Mail, error) {
+	title := target.mailTitle
+	if title == "" {
+		title = s.DescribeSource(source, i18n.FallbackLocale)
+	}
+	var expiresAt *time.Time
+	if target.expiresInDays > 0 {
+		expiry := time.Now().AddDate(0, 0, target.expiresInDays)
+		expiresAt = &expiry
+	}
+
+	return s.mailboxService.Send(mailbox.SendRequest{
+		UserID:    userID,
+		Title:     title,
+		Body:      description,
+		Items:     items,
+		Source:    source,
+		SentBy:    ref.Actor,
+		ExpiresAt: expiresAt,
+		NoExpiry:  target.noExpiry,
+	})
+}
+
+// recordGrant stores the outcome of a grant; a failure to record is logged but does not fail the grant
+func (s *service) recordGrant(userID int, items []entity.RewardItem, source, description string, grantedBy, jobID int, ref entity.TransactionRef, target delivery, result *entity.GrantResult, mailID int, grantErr error) {
+	method := target.method
+	if method == "" {
+		method = DeliveryDirect
 	}
 
-	// Grant all items using item service
-	if err := s.itemService.AddMultipleToInventory(userID, items, source); err != nil {
-		return fmt.Errorf("failed to add items to inventory: %w", err)
+	grant := &rewardEntity.RewardGrant{
+		UserID:      userID,
+		Items:       items,
+		Source:      source,
+		Description: description,
+		Actor:       ref.Actor,
+		GrantedBy:   grantedBy,
+		Delivery:    method,
+		MailID:      mailID,
+		JobID:       jobID,
+		GrantResult: result,
+		Status:      rewardEntity.GrantStatusSuccess,
 	}
+	if grantErr != nil {
+		grant.Status = rewardEntity.GrantStatusFailed
+		grant.Message = grantErr.Error()
+	}
+
+	if err := s.repo.CreateGrant(grant); err != nil {
+		s.logger.Error("Failed to record reward grant",
+			zap.Error(err),
+			zap.Int("user_id", userID),
+			zap.String("source", source))
+	}
+}
+
+func (s *service) ListGrants(query GrantHistoryQuery) ([]*rewardEntity.RewardGrant, error) {
+	filter := repository.GrantFilter{
+		UserID: query.UserID,
+		Source: query.Source,
+		Actor:  query.Actor,
+		Status: rewardEntity.GrantStatus(query.Status),
+		Limit:  query.Limit,
+	}
+	if err := applyDateRange(&filter, query.StartDate, query.EndDate); err != nil {
+		return nil, err
+	}
+
+	grants, err := s.repo.ListGrants(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reward grants: %w", err)
+	}
+	return grants, nil
+}
+
+func (s *service) ListUserRewards(userID int, query MyRewardsQuery, locale i18n.Locale) ([]UserRewardResponse, error) {
+	filter := repository.GrantFilter{
+		UserID: userID,
+		Source: query.Source,
+		Status: rewardEntity.GrantStatusSuccess,
+		Limit:  query.Limit,
+	}
+	if err := applyDateRange(&filter, query.StartDate, query.EndDate); err != nil {
+		return nil, err
+	}
+
+	grants, err := s.repo.ListGrants(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reward grants: %w", err)
+	}
+
+	rewards := make([]UserRewardResponse, len(grants))
+	for i, grant := range grants {
+		rewards[i] = UserRewardResponse{
+			ID:                grant.ID,
+			Items:             grant.Items,
+			Source:            grant.Source,
+			SourceDescription: s.DescribeSource(grant.Source, locale),
+			Description:       grant.Description,
+			Delivery:          grant.Delivery,
+			MailID:            grant.MailID,
+			GrantedAt:         grant.CreatedAt,
+		}
+	}
+	return rewards, nil
+}
 
+// applyDateRange parses YYYY-MM-DD bounds into the filter; the end date is inclusive
+func applyDateRange(filter *repository.GrantFilter, startDate, endDate string) error {
+	if startDate != "" {
+		from, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			return fmt.Errorf("%w: invalid start date format", ErrInvalidDateRange)
+		}
+		filter.From = from
+	}
+	if endDate != "" {
+		to, err := time.Parse("2006-01-02", endDate)
+		if err != nil {
+			return fmt.Errorf("%w: invalid end date format", ErrInvalidDateRange)
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return ErrInvalidDateRange
+	}
 	return nil
 }
 
+// grantMessage describes where the rewards went and what happened to units that did not fit
+func grantMessage(result *entity.GrantResult, mailID int) string {
+	switch {
+	case result == nil && mailID > 0:
+		return "Rewards sent to mailbox"
+	case result == nil || len(result.Overflow) == 0:
+		return "Rewards granted successfully"
+	case mailID > 0:
+		return "Rewards granted; items that did not fit were sent to the mailbox"
+	}
+	return "Rewards granted partially; items that did not fit were discarded"
+}
+
+// adminGrantRef builds the inventory ledger reference for admin-initiated grants
+func adminGrantRef(adminID int) entity.TransactionRef {
+	actor := entity.ActorSystem
+	if adminID > 0 {
+		actor = entity.AdminActor(adminID)
+	}
+	return entity.TransactionRef{
+		Type:  ReferenceTypeRewardGrant,
+		Actor: actor,
+	}
+}
+
 func (s *service) ValidateRewardItems(items []entity.RewardItem) error {
 	if len(items) == 0 {
 		return fmt.Errorf("no reward items provided")
@@ -191,25 +600,29 @@ func (s *service) ValidateRewardItems(items []entity.RewardItem) error {
 	// Check for duplicate item IDs
 	itemIDMap := make(map[int]bool)
 	
-	for i, item := range items {
+	for i, rewardItem := range items {
 		// Validate item fields
-		if item.ItemID <= 0 {
-			return fmt.Errorf("invalid item ID at index %d: %d", i, item.ItemID)
+		if rewardItem.ItemID <= 0 {
+			return fmt.Errorf("invalid item ID at index %d: %d", i, rewardItem.ItemID)
 		}
 		
-		if item.Count <= 0 {
-			return fmt.Errorf("invalid item count at index %d: %d", i, item.Count)
+		if rewardItem.Count <= 0 {
+			return fmt.Errorf("invalid item count at index %d: %d", i, rewardItem.Count)
 		}
 
 		// Check for duplicates
-		if itemIDMap[item.ItemID] {
-			return fmt.Errorf("duplicate item ID found: %d", item.ItemID)
+		if itemIDMap[rewardItem.ItemID] {
+			return fmt.Errorf("duplicate item ID found: %d", rewardItem.ItemID)
 		}
-		itemIDMap[item.ItemID] = true
+		itemIDMap[rewardItem.ItemID] = true
 
-		// Verify item exists in the system
-		if _, err := s.itemService.GetItem(item.ItemID); err != nil {
-			return fmt.Errorf("item with ID %d not found", item.ItemID)
+		// Verify item exists in the system and has not been archived
+		template, err := s.itemService.GetItem(rewardItem.ItemID)
+		if err != nil {
+			return fmt.Errorf("item with ID %d not found", rewardItem.ItemID)
+		}
+		if template.IsArchived() {
+			return fmt.Errorf("%w: item %d", item.ErrItemArchived, rewardItem.ItemID)
 		}
 	}
 
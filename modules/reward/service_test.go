@@ -0,0 +1,253 @@
+package reward
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/mailbox"
+	mailboxRepository "fxserver/modules/mailbox/repository"
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/repository"
+	"fxserver/modules/user"
+	userEntity "fxserver/modules/user/entity"
+	userRepository "fxserver/modules/user/repository"
+	"fxserver/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID   = 1
+	potionID = 3
+)
+
+func setupRewardService(t *testing.T) (Service, itemRepository.Repository, mailbox.Service) {
+	return setupRewardServiceWithUsers(t, newUsers(t, 20, 20, 20))
+}
+
+// newUsers seeds one player per age; they get IDs 1, 2, ... in order
+func newUsers(t *testing.T, ages ...int) userRepository.UserRepository {
+	users := userRepository.NewMemoryUserRepository()
+	for i, age := range ages {
+		require.NoError(t, users.Create(&userEntity.User{Name: "player", Email: string(rune('a'+i)) + "@example.com", Age: age}))
+	}
+	return users
+}
+
+func setupRewardServiceWithUsers(t *testing.T, users userRepository.UserRepository) (Service, itemRepository.Repository, mailbox.Service) {
+	logger := zap.NewNop()
+	items := itemtest.New()
+	mailboxService := mailbox.NewService(mailbox.ServiceParam{
+		Repository:  mailboxRepository.NewMemoryRepository(),
+		ItemService: items.Service,
+		Logger:      logger,
+	})
+	svc := NewService(ServiceParam{
+		Repository:     repository.NewMemoryRepository(),
+		ItemService:    items.Service,
+		MailboxService: mailboxService,
+		UserService:    user.NewService(users, logger),
+		Logger:         logger,
+	})
+	return svc, items.Items, mailboxService
+}
+
+func TestGrantRewardsRecordsHistory(t *testing.T) {
+	svc, _, _ := setupRewardService(t)
+
+	_, err := svc.GrantRewards(GrantRewardRequest{
+		UserID:      1,
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: 100}},
+		Source:      RewardSourceCompensation,
+		Description: "Server maintenance compensation",
+		GrantedBy:   7,
+	})
+	require.NoError(t, err)
+
+	grants, err := svc.ListGrants(GrantHistoryQuery{UserID: 1})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, rewardEntity.GrantStatusSuccess, grants[0].Status)
+	assert.Equal(t, entity.AdminActor(7), grants[0].Actor)
+	assert.Equal(t, 7, grants[0].GrantedBy)
+	assert.Equal(t, DeliveryDirect, grants[0].Delivery)
+	assert.Equal(t, "Server maintenance compensation", grants[0].Description)
+	assert.NotNil(t, grants[0].GrantResult)
+
+	byActor, err := svc.ListGrants(GrantHistoryQuery{Actor: entity.AdminActor(8)})
+	require.NoError(t, err)
+	assert.Empty(t, byActor)
+}
+
+func TestBulkGrantRecordsEachOutcome(t *testing.T) {
+	svc, items, _ := setupRewardService(t)
+	// User 2 has no room for the potions
+	require.NoError(t, items.SetSlotCapacity(2, 0))
+
+	response, err := svc.BulkGrantRewards(BulkGrantRewardRequest{
+		UserIDs:        []int{1, 2},
+		Items:          []entity.RewardItem{{ItemID: potionID, Count: 2}},
+		Source:         RewardSourceEvent,
+		Description:    "Weekend event reward",
+		OverflowPolicy: entity.OverflowReject,
+		GrantedBy:      1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.FailureCount)
+
+	succeeded, err := svc.ListGrants(GrantHistoryQuery{Source: RewardSourceEvent, Status: string(rewardEntity.GrantStatusSuccess)})
+	require.NoError(t, err)
+	require.Len(t, succeeded, 1)
+	assert.Equal(t, 1, succeeded[0].UserID)
+
+	failed, err := svc.ListGrants(GrantHistoryQuery{Status: string(rewardEntity.GrantStatusFailed)})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 2, failed[0].UserID)
+	assert.NotEmpty(t, failed[0].Message)
+}
+
+func TestGrantToMailbox(t *testing.T) {
+	svc, items, mailboxService := setupRewardService(t)
+
+	response, err := svc.GrantRewards(GrantRewardRequest{
+		UserID:            1,
+		Items:             []entity.RewardItem{{ItemID: goldID, Count: 300}},
+		Source:            RewardSourceCompensation,
+		Description:       "You received compensation from the team",
+		Delivery:          DeliveryMailbox,
+		MailExpiresInDays: 7,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, response.MailID)
+
+	// Nothing reaches the inventory until the mail is claimed
+	_, err = items.GetUserInventoryItem(1, goldID)
+	assert.ErrorIs(t, err, itemRepository.ErrInventoryNotFound)
+
+	mail, err := mailboxService.GetMail(1, response.MailID)
+	require.NoError(t, err)
+	assert.Equal(t, svc.DescribeSource(RewardSourceCompensation, i18n.FallbackLocale), mail.Title)
+	assert.Equal(t, "You received compensation from the team", mail.Body)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *mail.ExpiresAt, time.Minute)
+
+	_, err = mailboxService.Claim(1, response.MailID)
+	require.NoError(t, err)
+	gold, err := items.GetUserInventoryItem(1, goldID)
+	require.NoError(t, err)
+	assert.Equal(t, 300, gold.Count)
+}
+
+func TestOverflowIsMailed(t *testing.T) {
+	svc, items, mailboxService := setupRewardService(t)
+	// User 1 has no room for a new potion stack
+	require.NoError(t, items.SetSlotCapacity(1, 0))
+
+	response, err := svc.GrantRewards(GrantRewardRequest{
+		UserID:         1,
+		Items:          []entity.RewardItem{{ItemID: goldID, Count: 100}, {ItemID: potionID, Count: 2}},
+		Source:         RewardSourceEvent,
+		Description:    "Weekend event reward",
+		OverflowPolicy: entity.OverflowMailbox,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.RewardItem{{ItemID: goldID, Count: 100}}, response.GrantResult.Granted)
+	require.NotZero(t, response.MailID)
+
+	// The potions wait in the mailbox until there is room for them
+	mail, err := mailboxService.GetMail(1, response.MailID)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.RewardItem{{ItemID: potionID, Count: 2}}, mail.Items)
+	_, err = mailboxService.Claim(1, response.MailID)
+	assert.Error(t, err)
+
+	require.NoError(t, items.SetSlotCapacity(1, 1))
+	_, err = mailboxService.Claim(1, response.MailID)
+	require.NoError(t, err)
+	potion, err := items.GetUserInventoryItem(1, potionID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, potion.Count)
+}
+
+func TestPaidOverflowMailDoesNotExpire(t *testing.T) {
+	svc, items, mailboxService := setupRewardService(t)
+	require.NoError(t, items.SetSlotCapacity(1, 0))
+	ref := entity.TransactionRef{Type: "gacha", ID: "1", Actor: entity.UserActor(1)}
+	overflow := []entity.RewardItem{{ItemID: potionID, Count: 2}}
+
+	paid, err := svc.GrantItemsToUser(1, overflow, entity.OverflowMailbox, RewardSourceGacha, "Paid draw", ref)
+	require.NoError(t, err)
+	free, err := svc.GrantItemsToUser(1, overflow, entity.OverflowMailbox, RewardSourceEvent, "Event reward", ref)
+	require.NoError(t, err)
+	require.Equal(t, overflow, paid.Overflow)
+	require.Equal(t, overflow, free.Overflow)
+
+	// Only the free overflow is gone once the default retention has passed
+	mails, err := mailboxService.ListMail(1, mailbox.MailQuery{})
+	require.NoError(t, err)
+	require.Len(t, mails, 2)
+	afterRetention := time.Now().Add(mailbox.DefaultMailTTL + time.Hour)
+	for _, mail := range mails {
+		if mail.Source == RewardSourceGacha {
+			assert.Nil(t, mail.ExpiresAt)
+			assert.False(t, mail.IsExpired(afterRetention))
+		} else {
+			assert.True(t, mail.IsExpired(afterRetention))
+		}
+	}
+}
+
+func TestGrantItemsToUserRecordsHistory(t *testing.T) {
+	svc, _, _ := setupRewardService(t)
+
+	ref := entity.TransactionRef{Type: "achievement", ID: "7", Actor: entity.UserActor(1)}
+	_, err := svc.GrantItemsToUser(1, []entity.RewardItem{{ItemID: goldID, Count: 50}}, "", RewardSourceAchievement, "First win tier 1-1", ref)
+	require.NoError(t, err)
+
+	// Grants made by other modules show up in both the admin history and the player's rewards
+	grants, err := svc.ListGrants(GrantHistoryQuery{Actor: entity.UserActor(1)})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Equal(t, RewardSourceAchievement, grants[0].Source)
+	assert.Equal(t, rewardEntity.GrantStatusSuccess, grants[0].Status)
+	assert.Equal(t, DeliveryDirect, grants[0].Delivery)
+	assert.Zero(t, grants[0].GrantedBy)
+
+	rewards, err := svc.ListUserRewards(1, MyRewardsQuery{}, i18n.English)
+	require.NoError(t, err)
+	require.Len(t, rewards, 1)
+	assert.Equal(t, "First win tier 1-1", rewards[0].Description)
+}
+
+func TestListUserRewards(t *testing.T) {
+	svc, items, _ := setupRewardService(t)
+	require.NoError(t, items.SetSlotCapacity(1, 0))
+
+	for _, req := range []GrantRewardRequest{
+		{UserID: 1, Items: []entity.RewardItem{{ItemID: goldID, Count: 10}}, Source: RewardSourceDaily, Description: "Daily login"},
+		{UserID: 1, Items: []entity.RewardItem{{ItemID: potionID, Count: 1}}, Source: RewardSourceEvent, Description: "Does not fit", OverflowPolicy: entity.OverflowReject},
+		{UserID: 2, Items: []entity.RewardItem{{ItemID: goldID, Count: 10}}, Source: RewardSourceDaily, Description: "Daily login"},
+	} {
+		_, _ = svc.GrantRewards(req)
+	}
+
+	// Players only see their own successful grants, described in their language
+	rewards, err := svc.ListUserRewards(1, MyRewardsQuery{}, i18n.English)
+	require.NoError(t, err)
+	require.Len(t, rewards, 1)
+	assert.Equal(t, RewardSourceDaily, rewards[0].Source)
+	assert.Equal(t, "Daily reward", rewards[0].SourceDescription)
+
+	today := time.Now().Format("2006-01-02")
+	rewards, err = svc.ListUserRewards(1, MyRewardsQuery{StartDate: today, EndDate: today}, i18n.English)
+	require.NoError(t, err)
+	assert.Len(t, rewards, 1)
+
+	_, err = svc.ListUserRewards(1, MyRewardsQuery{StartDate: "2024-02-01", EndDate: "2024-01-01"}, i18n.English)
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+}
@@ -0,0 +1,195 @@
+package reward
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	rewardEntity "fxserver/modules/reward/entity"
+	"fxserver/modules/reward/repository"
+	"fxserver/pkg/i18n"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSourceNotFound   = errors.New("reward source not found")
+	ErrSourceExists     = errors.New("reward source already exists")
+	ErrInvalidSource    = errors.New("invalid reward source")
+	ErrSourceInactive   = errors.New("reward source is inactive")
+	ErrSourceCapReached = errors.New("reward source daily cap reached")
+)
+
+// sourceKeyPattern keeps keys safe to use in URLs and ledger entries (e.g. halloween_2026)
+var sourceKeyPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+var unknownRewardSource = i18n.Text{i18n.Korean: "알 수 없는 보상 출처", i18n.English: "Unknown reward source", i18n.Japanese: "不明な報酬元"}
+
+func (s *service) ListSources(includeInactive bool) ([]*rewardEntity.RewardSource, error) {
+	sources, err := s.repo.ListSources(includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reward sources: %w", err)
+	}
+	return sources, nil
+}
+
+func (s *service) CreateSource(req CreateSourceRequest) (*rewardEntity.RewardSource, error) {
+	if !sourceKeyPattern.MatchString(req.Key) {
+		return nil, fmt.Errorf("%w: key must contain only lowercase letters, digits and underscores", ErrInvalidSource)
+	}
+	if err := validateSourceDescriptions(req.Descriptions); err != nil {
+		return nil, err
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	source := &rewardEntity.RewardSource{
+		Key:           req.Key,
+		Descriptions:  req.Descriptions,
+		Active:        active,
+		DailyGrantCap: req.DailyGrantCap,
+		DailyUserCap:  req.DailyUserCap,
+		Paid:          req.Paid,
+	}
+	if err := s.repo.CreateSource(source); err != nil {
+		if errors.Is(err, repository.ErrSourceExists) {
+			return nil, ErrSourceExists
+		}
+		s.logger.Error("Failed to create reward source", zap.Error(err), zap.String("key", req.Key))
+		return nil, fmt.Errorf("failed to create reward source: %w", err)
+	}
+
+	s.logger.Info("Reward source created",
+		zap.String("key", source.Key),
+		zap.Bool("active", source.Active))
+
+	return source, nil
+}
+
+func (s *service) UpdateSource(key string, req UpdateSourceRequest) (*rewardEntity.RewardSource, error) {
+	source, err := s.repo.GetSource(key)
+	if err != nil {
+		if errors.Is(err, repository.ErrSourceNotFound) {
+			return nil, ErrSourceNotFound
+		}
+		return nil, fmt.Errorf("failed to get reward source: %w", err)
+	}
+
+	if req.Descriptions != nil {
+		if err := validateSourceDescriptions(req.Descriptions); err != nil {
+			return nil, err
+		}
+		source.Descriptions = req.Descriptions
+	}
+	if req.Active != nil {
+		source.Active = *req.Active
+	}
+	if req.DailyGrantCap != nil {
+		source.DailyGrantCap = *req.DailyGrantCap
+	}
+	if req.DailyUserCap != nil {
+		source.DailyUserCap = *req.DailyUserCap
+	}
+	if req.Paid != nil {
+		source.Paid = *req.Paid
+	}
+
+	if err := s.repo.UpdateSource(source); err != nil {
+		s.logger.Error("Failed to update reward source", zap.Error(err), zap.String("key", key))
+		return nil, fmt.Errorf("failed to update reward source: %w", err)
+	}
+
+	s.logger.Info("Reward source updated",
+		zap.String("key", source.Key),
+		zap.Bool("active", source.Active),
+		zap.Int("daily_grant_cap", source.DailyGrantCap),
+		zap.Int("daily_user_cap", source.DailyUserCap),
+		zap.Bool("paid", source.Paid))
+
+	return source, nil
+}
+
+func (s *service) ValidateSource(key string) error {
+	_, err := s.activeSource(key)
+	return err
+}
+
+// DescribeSource returns the source's description in the locale; inactive sources are still described for history
+func (s *service) DescribeSource(key string, locale i18n.Locale) string {
+	source, err := s.repo.GetSource(key)
+	if err != nil {
+		return unknownRewardSource.Get(locale)
+	}
+	if description := source.Descriptions.Get(locale); description != "" {
+		return description
+	}
+	return unknownRewardSource.Get(locale)
+}
+
+// activeSource returns the source if new grants may use it
+func (s *service) activeSource(key string) (*rewardEntity.RewardSource, error) {
+	source, err := s.repo.GetSource(key)
+	if err != nil {
+		if errors.Is(err, repository.ErrSourceNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSource, key)
+		}
+		return nil, fmt.Errorf("failed to get reward source: %w", err)
+	}
+	if !source.Active {
+		return nil, fmt.Errorf("%w: %s", ErrSourceInactive, key)
+	}
+	return source, nil
+}
+
+// capUsage counts the grants a dry run has already accepted but not recorded
+type capUsage struct {
+	total int
+	users map[int]int
+}
+
+// checkDailyCap counts today's successful grants (UTC) under the source plus the pending ones;
+// the caller must hold the source's cap lock for capped sources when it goes on to grant
+func (s *service) checkDailyCap(source *rewardEntity.RewardSource, userID int, pending *capUsage) error {
+	if !source.HasDailyCap() {
+		return nil
+	}
+	if pending == nil {
+		pending = &capUsage{}
+	}
+
+	now := time.Now()
+	if source.DailyGrantCap > 0 {
+		count, err := s.repo.CountDailyGrants(source.Key, 0, now)
+		if err != nil {
+			return fmt.Errorf("failed to count reward grants: %w", err)
+		}
+		if count+pending.total >= source.DailyGrantCap {
+			return fmt.Errorf("%w: %d grants per day for %s", ErrSourceCapReached, source.DailyGrantCap, source.Key)
+		}
+	}
+	if source.DailyUserCap > 0 {
+		count, err := s.repo.CountDailyGrants(source.Key, userID, now)
+		if err != nil {
+			return fmt.Errorf("failed to count reward grants: %w", err)
+		}
+		if count+pending.users[userID] >= source.DailyUserCap {
+			return fmt.Errorf("%w: %d grants per user per day for %s", ErrSourceCapReached, source.DailyUserCap, source.Key)
+		}
+	}
+	return nil
+}
+
+func validateSourceDescriptions(descriptions i18n.Text) error {
+	if descriptions[i18n.FallbackLocale] == "" {
+		return fmt.Errorf("%w: description in %s is required", ErrInvalidSource, i18n.FallbackLocale)
+	}
+	for locale := range descriptions {
+		if !i18n.IsSupported(locale) {
+			return fmt.Errorf("%w: unsupported locale %s", ErrInvalidSource, locale)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package reward
+
+import (
+	"testing"
+
+	"fxserver/modules/item/entity"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/pkg/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func halloweenGrant(userID int) GrantRewardRequest {
+	return GrantRewardRequest{
+		UserID:      userID,
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: 10}},
+		Source:      "halloween_2026",
+		Description: "Halloween login bonus",
+	}
+}
+
+func TestCustomRewardSource(t *testing.T) {
+	svc, _, _ := setupRewardService(t)
+
+	_, err := svc.GrantRewards(halloweenGrant(1))
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	source, err := svc.CreateSource(CreateSourceRequest{
+		Key:          "halloween_2026",
+		Descriptions: i18n.Text{i18n.Korean: "할로윈 이벤트 보상", i18n.English: "Halloween event reward"},
+	})
+	require.NoError(t, err)
+	assert.True(t, source.Active)
+	assert.Equal(t, "Halloween event reward", svc.DescribeSource(source.Key, i18n.English))
+
+	_, err = svc.GrantRewards(halloweenGrant(1))
+	require.NoError(t, err)
+
+	// Deactivated sources reject new grants but keep describing past ones
+	inactive := false
+	_, err = svc.UpdateSource(source.Key, UpdateSourceRequest{Active: &inactive})
+	require.NoError(t, err)
+	_, err = svc.GrantRewards(halloweenGrant(1))
+	assert.ErrorIs(t, err, ErrSourceInactive)
+	assert.Equal(t, "할로윈 이벤트 보상", svc.DescribeSource(source.Key, i18n.Korean))
+
+	active, err := svc.ListSources(false)
+	require.NoError(t, err)
+	assert.Len(t, active, 8)
+	all, err := svc.ListSources(true)
+	require.NoError(t, err)
+	assert.Len(t, all, 9)
+}
+
+func TestRewardSourceValidation(t *testing.T) {
+	svc, _, _ := setupRewardService(t)
+
+	_, err := svc.CreateSource(CreateSourceRequest{Key: "Halloween 2026", Descriptions: i18n.Text{i18n.Korean: "할로윈"}})
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	_, err = svc.CreateSource(CreateSourceRequest{Key: "halloween", Descriptions: i18n.Text{i18n.English: "Halloween"}})
+	assert.ErrorIs(t, err, ErrInvalidSource)
+
+	_, err = svc.CreateSource(CreateSourceRequest{Key: RewardSourceEvent, Descriptions: i18n.Text{i18n.Korean: "이벤트"}})
+	assert.ErrorIs(t, err, ErrSourceExists)
+
+	_, err = svc.UpdateSource("missing", UpdateSourceRequest{})
+	assert.ErrorIs(t, err, ErrSourceNotFound)
+}
+
+func TestRewardSourceDailyCaps(t *testing.T) {
+	svc, _, _ := setupRewardService(t)
+	_, err := svc.CreateSource(CreateSourceRequest{
+		Key:           "halloween_2026",
+		Descriptions:  i18n.Text{i18n.Korean: "할로윈 이벤트 보상"},
+		DailyGrantCap: 3,
+		DailyUserCap:  1,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.GrantRewards(halloweenGrant(1))
+	require.NoError(t, err)
+	_, err = svc.GrantRewards(halloweenGrant(1))
+	assert.ErrorIs(t, err, ErrSourceCapReached)
+
+	// Capped grants are recorded as failures and do not count toward the cap
+	response, err := svc.BulkGrantRewards(BulkGrantRewardRequest{
+		UserIDs:     []int{2, 3, 4},
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: 10}},
+		Source:      "halloween_2026",
+		Description: "Halloween login bonus",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.SuccessCount)
+	assert.Equal(t, 1, response.FailureCount)
+	assert.False(t, response.Results[2].Success)
+}
+
+func TestGrantItemsToUserChecksSource(t *testing.T) {
+	svc, _, _ := setupRewardService(t)
+	_, err := svc.CreateSource(CreateSourceRequest{
+		Key:          "halloween_2026",
+		Descriptions: i18n.Text{i18n.Korean: "할로윈 이벤트 보상"},
+		DailyUserCap: 1,
+	})
+	require.NoError(t, err)
+
+	items := []entity.RewardItem{{ItemID: goldID, Count: 10}}
+	grant := func(source string) error {
+		_, err := svc.GrantItemsToUser(1, items, "", source, "Halloween login bonus", entity.TransactionRef{Actor: entity.UserActor(1)})
+		return err
+	}
+
+	// Grants from other modules obey the same source rules as admin grants
+	require.NoError(t, grant("halloween_2026"))
+	assert.ErrorIs(t, grant("halloween_2026"), ErrSourceCapReached)
+	assert.ErrorIs(t, grant("unknown"), ErrInvalidSource)
+
+	inactive := false
+	_, err = svc.UpdateSource(RewardSourceDaily, UpdateSourceRequest{Active: &inactive})
+	require.NoError(t, err)
+	assert.ErrorIs(t, grant(RewardSourceDaily), ErrSourceInactive)
+}
+
+func TestPreviewGrants(t *testing.T) {
+	svc, items, _ := setupRewardServiceWithUsers(t, newUsers(t, 20, 20, 20))
+	_, err := svc.CreateSource(CreateSourceRequest{
+		Key:           "halloween_2026",
+		Descriptions:  i18n.Text{i18n.Korean: "할로윈 이벤트 보상"},
+		DailyGrantCap: 3,
+		DailyUserCap:  1,
+	})
+	require.NoError(t, err)
+	_, err = svc.GrantRewards(halloweenGrant(1))
+	require.NoError(t, err)
+
+	req := BulkGrantRewardRequest{
+		UserIDs:     []int{1, 2, 9, 3, 3},
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: 10}},
+		Source:      "halloween_2026",
+		Description: "Halloween login bonus",
+	}
+	response, err := svc.PreviewGrants(req)
+	require.NoError(t, err)
+	assert.True(t, response.DryRun)
+	assert.Equal(t, 5, response.TotalUsers)
+	assert.Equal(t, 2, response.SuccessCount)
+	assert.Equal(t, []entity.RewardItem{{ItemID: goldID, Count: 20}}, response.Totals)
+
+	assert.Contains(t, response.Results[0].Message, "per user per day", "user 1 was granted today")
+	assert.True(t, response.Results[1].Success)
+	assert.Contains(t, response.Results[2].Message, "not found")
+	assert.True(t, response.Results[3].Success)
+	// Users accepted earlier in the preview count toward the caps
+	assert.False(t, response.Results[4].Success)
+
+	// Nothing is granted or recorded
+	grants, err := svc.ListGrants(GrantHistoryQuery{Source: "halloween_2026"})
+	require.NoError(t, err)
+	assert.Len(t, grants, 1)
+	_, err = items.GetUserInventoryItem(2, goldID)
+	assert.Error(t, err)
+
+	req.Items = []entity.RewardItem{{ItemID: 999, Count: 1}}
+	response, err = svc.PreviewGrants(req)
+	require.Error(t, err)
+	assert.Equal(t, 5, response.FailureCount)
+	assert.Empty(t, response.Totals)
+}
+
+func TestPreviewMatchesExecution(t *testing.T) {
+	svc, items, _ := setupRewardService(t)
+	_, err := svc.CreateSource(CreateSourceRequest{
+		Key:          "halloween_2026",
+		Descriptions: i18n.Text{i18n.Korean: "할로윈 이벤트 보상"},
+		DailyUserCap: 1,
+	})
+	require.NoError(t, err)
+
+	// User 9 does not exist and user 3 is listed twice
+	req := BulkGrantRewardRequest{
+		UserIDs:     []int{1, 9, 3, 3},
+		Items:       []entity.RewardItem{{ItemID: goldID, Count: 10}},
+		Source:      "halloween_2026",
+		Description: "Halloween login bonus",
+	}
+	preview, err := svc.PreviewGrants(req)
+	require.NoError(t, err)
+	executed, err := svc.BulkGrantRewards(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, preview.SuccessCount, executed.SuccessCount)
+	require.Len(t, executed.Results, len(preview.Results))
+	for i, result := range preview.Results {
+		assert.Equal(t, result.Success, executed.Results[i].Success, "user %d", result.UserID)
+	}
+	assert.Contains(t, executed.Results[1].Message, "not found")
+	_, err = items.GetUserInventoryItem(9, goldID)
+	assert.Error(t, err, "nothing is granted to a missing user")
+}
+
+func TestPreviewGrantsChecksInventory(t *testing.T) {
+	svc, items, _ := setupRewardServiceWithUsers(t, newUsers(t, 20, 20))
+	// User 2 has no room for a new potion stack
+	require.NoError(t, items.SetSlotCapacity(2, 0))
+
+	req := BulkGrantRewardRequest{
+		UserIDs:        []int{1, 2},
+		Items:          []entity.RewardItem{{ItemID: goldID, Count: 10}, {ItemID: potionID, Count: 2}},
+		Source:         RewardSourceEvent,
+		Description:    "Tournament winner reward",
+		OverflowPolicy: entity.OverflowReject,
+	}
+	response, err := svc.PreviewGrants(req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.SuccessCount)
+	assert.Contains(t, response.Results[1].Message, "inventory")
+	assert.Equal(t, []entity.RewardItem{{ItemID: goldID, Count: 10}, {ItemID: potionID, Count: 2}}, response.Totals)
+
+	// Truncated units are not counted; mailed rewards always arrive
+	req.OverflowPolicy = entity.OverflowTruncate
+	response, err = svc.PreviewGrants(req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.SuccessCount)
+	assert.Equal(t, []entity.RewardItem{{ItemID: potionID, Count: 2}}, response.Results[1].GrantResult.Overflow)
+	assert.Equal(t, []entity.RewardItem{{ItemID: goldID, Count: 20}, {ItemID: potionID, Count: 2}}, response.Totals)
+
+	req.Delivery = DeliveryMailbox
+	response, err = svc.PreviewGrants(req)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.RewardItem{{ItemID: goldID, Count: 20}, {ItemID: potionID, Count: 4}}, response.Totals)
+
+	// Nothing was granted
+	_, err = items.GetUserInventoryItem(1, goldID)
+	assert.ErrorIs(t, err, itemRepository.ErrInventoryNotFound)
+}
@@ -0,0 +1,61 @@
+package shop
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Listing management DTOs (Admin only)
+type CreateListingRequest struct {
+	Name         string                  `json:"name" validate:"required,min=2,max=100"`
+	Description  string                  `json:"description" validate:"omitempty,max=500"`
+	Items        []itemEntity.RewardItem `json:"items" validate:"required,min=1,dive"`
+	PriceItemID  int                     `json:"price_item_id" validate:"required,gt=0"`
+	PriceAmount  int                     `json:"price_amount" validate:"required,gt=0"`
+	Stock        int                     `json:"stock" validate:"gte=0"`          // 0: 무제한
+	PerUserLimit int                     `json:"per_user_limit" validate:"gte=0"` // 0: 무제한
+	StartsAt     *time.Time              `json:"starts_at,omitempty"`
+	EndsAt       *time.Time              `json:"ends_at,omitempty"`
+	IsActive     *bool                   `json:"is_active,omitempty"` // 미지정 시 활성
+}
+
+type UpdateListingRequest struct {
+	Name         string                  `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description  string                  `json:"description,omitempty" validate:"omitempty,max=500"`
+	Items        []itemEntity.RewardItem `json:"items,omitempty" validate:"omitempty,min=1,dive"`
+	PriceItemID  int                     `json:"price_item_id,omitempty" validate:"omitempty,gt=0"`
+	PriceAmount  int                     `json:"price_amount,omitempty" validate:"omitempty,gt=0"`
+	Stock        *int                    `json:"stock,omitempty" validate:"omitempty,gte=0"`          // 0: 무제한
+	PerUserLimit *int                    `json:"per_user_limit,omitempty" validate:"omitempty,gte=0"` // 0: 무제한
+	StartsAt     *time.Time              `json:"starts_at,omitempty"`
+	EndsAt       *time.Time              `json:"ends_at,omitempty"`
+	IsActive     *bool                   `json:"is_active,omitempty"`
+}
+
+// Purchase DTOs
+type BuyRequest struct {
+	Quantity int `json:"quantity" validate:"omitempty,gt=0,lte=100"` // 기본값: 1
+}
+
+// Response DTOs
+type BuyResponse struct {
+	PurchaseID     int                     `json:"purchase_id"`
+	ListingID      int                     `json:"listing_id"`
+	Quantity       int                     `json:"quantity"`
+	Paid           itemEntity.RewardItem   `json:"paid"`
+	Granted        []itemEntity.RewardItem `json:"granted"`
+	RemainingStock *int                    `json:"remaining_stock,omitempty"` // 재고 제한이 있는 경우
+	UserRemaining  *int                    `json:"user_remaining,omitempty"`  // 사용자당 한도가 있는 경우
+}
+
+// Query DTOs
+type ListingQuery struct {
+	IncludeInactive bool `query:"include_inactive"` // 관리자 전용, 비활성/기간 외 상품 포함 여부
+}
+
+type PurchaseQuery struct {
+	UserID    int `query:"user_id" validate:"omitempty,gt=0"` // 관리자 전용
+	ListingID int `query:"listing_id" validate:"omitempty,gt=0"`
+	Limit     int `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Listing sells a fixed set of items for an amount of a currency item
+type Listing struct {
+	ID           int                     `json:"id"`
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	Items        []itemEntity.RewardItem `json:"items"`               // 구매 1회당 지급 아이템
+	PriceItemID  int                     `json:"price_item_id"`       // 가격 화폐 아이템 (currency 타입)
+	PriceAmount  int                     `json:"price_amount"`        // 구매 1회당 가격
+	Stock        int                     `json:"stock"`               // 전체 재고 (0: 무제한)
+	Sold         int                     `json:"sold"`                // 판매된 수량
+	PerUserLimit int                     `json:"per_user_limit"`      // 사용자당 구매 한도 (0: 무제한)
+	StartsAt     *time.Time              `json:"starts_at,omitempty"` // 판매 기간 시작 (nil: 제한 없음)
+	EndsAt       *time.Time              `json:"ends_at,omitempty"`   // 판매 기간 종료 (nil: 제한 없음)
+	IsActive     bool                    `json:"is_active"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// IsAvailable reports whether the listing is on sale at the given time
+func (l *Listing) IsAvailable(now time.Time) bool {
+	if !l.IsActive {
+		return false
+	}
+	if l.StartsAt != nil && now.Before(*l.StartsAt) {
+		return false
+	}
+	if l.EndsAt != nil && !now.Before(*l.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// RemainingStock returns how many more units can be sold, or -1 if stock is unlimited
+func (l *Listing) RemainingStock() int {
+	if l.Stock <= 0 {
+		return -1
+	}
+	return max(l.Stock-l.Sold, 0)
+}
+
+// Price returns the currency paid for buying the listing quantity times
+func (l *Listing) Price(quantity int) itemEntity.RewardItem {
+	return itemEntity.RewardItem{ItemID: l.PriceItemID, Count: l.PriceAmount * quantity}
+}
+
+// Products returns the items granted for buying the listing quantity times
+func (l *Listing) Products(quantity int) []itemEntity.RewardItem {
+	result := make([]itemEntity.RewardItem, len(l.Items))
+	for i, item := range l.Items {
+		result[i] = itemEntity.RewardItem{ItemID: item.ItemID, Count: item.Count * quantity}
+	}
+	return result
+}
+
+// Purchase records one buy of a listing
+type Purchase struct {
+	ID        int                     `json:"id"`
+	UserID    int                     `json:"user_id"`
+	ListingID int                     `json:"listing_id"`
+	Quantity  int                     `json:"quantity"`
+	Paid      itemEntity.RewardItem   `json:"paid"`
+	Granted   []itemEntity.RewardItem `json:"granted"`
+	CreatedAt time.Time               `json:"created_at"`
+}
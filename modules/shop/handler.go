@@ -0,0 +1,252 @@
+package shop
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// Public APIs
+
+// GetListings returns listings that are currently on sale
+func (h *Handler) GetListings(c echo.Context) error {
+	listings, err := h.service.ListListings(false)
+	if err != nil {
+		h.logger.Error("Failed to list shop listings", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get listings"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(listings))
+}
+
+// GetListing returns a single listing
+func (h *Handler) GetListing(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid listing ID", "invalid_request_error"))
+	}
+
+	listing, err := h.service.GetListing(id)
+	if err != nil {
+		if errors.Is(err, ErrListingNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Listing"))
+		}
+		h.logger.Error("Failed to get listing", zap.Error(err), zap.Int("listing_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get listing"))
+	}
+
+	return c.JSON(http.StatusOK, listing)
+}
+
+// User APIs
+
+// Buy purchases a listing with the authenticated user's currency
+func (h *Handler) Buy(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	listingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid listing ID", "invalid_request_error"))
+	}
+
+	var req BuyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	response, err := h.service.Buy(userID, listingID, quantity)
+	if err != nil {
+		if errors.Is(err, ErrListingNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Listing"))
+		}
+		if errors.Is(err, ErrInsufficientFunds) || errors.Is(err, ErrListingNotAvailable) ||
+			errors.Is(err, ErrOutOfStock) || errors.Is(err, ErrPurchaseLimitReached) || errors.Is(err, ErrInvalidQuantity) ||
+			errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to buy listing", zap.Error(err), zap.Int("user_id", userID), zap.Int("listing_id", listingID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to buy listing"))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetMyPurchases returns the authenticated user's purchase history
+func (h *Handler) GetMyPurchases(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query PurchaseQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	// 사용자는 본인 구매 내역만 조회 가능
+	query.UserID = userID
+
+	purchases, err := h.service.ListPurchases(query)
+	if err != nil {
+		h.logger.Error("Failed to list purchases", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get purchases"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(purchases))
+}
+
+// Admin APIs
+
+// GetAllListings returns listings, including inactive ones when requested (admin only)
+func (h *Handler) GetAllListings(c echo.Context) error {
+	var query ListingQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	listings, err := h.service.ListListings(query.IncludeInactive)
+	if err != nil {
+		h.logger.Error("Failed to list shop listings", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get listings"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(listings))
+}
+
+// CreateListing creates a new shop listing (admin only)
+func (h *Handler) CreateListing(c echo.Context) error {
+	var req CreateListingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	listing, err := h.service.CreateListing(req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidListing) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to create listing", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to create listing"))
+	}
+
+	return c.JSON(http.StatusCreated, listing)
+}
+
+// UpdateListing updates a shop listing (admin only)
+func (h *Handler) UpdateListing(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid listing ID", "invalid_request_error"))
+	}
+
+	var req UpdateListingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	listing, err := h.service.UpdateListing(id, req)
+	if err != nil {
+		if errors.Is(err, ErrListingNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Listing"))
+		}
+		if errors.Is(err, ErrInvalidListing) {
+			return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+		}
+		h.logger.Error("Failed to update listing", zap.Error(err), zap.Int("listing_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to update listing"))
+	}
+
+	return c.JSON(http.StatusOK, listing)
+}
+
+// DeleteListing removes a shop listing (admin only)
+func (h *Handler) DeleteListing(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid listing ID", "invalid_request_error"))
+	}
+
+	if err := h.service.DeleteListing(id); err != nil {
+		if errors.Is(err, ErrListingNotFound) {
+			return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Listing"))
+		}
+		h.logger.Error("Failed to delete listing", zap.Error(err), zap.Int("listing_id", id))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to delete listing"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewEmpty(strconv.Itoa(id)))
+}
+
+// GetAllPurchases returns the purchase log, optionally filtered by user or listing (admin only)
+func (h *Handler) GetAllPurchases(c echo.Context) error {
+	var query PurchaseQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	purchases, err := h.service.ListPurchases(query)
+	if err != nil {
+		h.logger.Error("Failed to list purchases", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get purchases"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(purchases))
+}
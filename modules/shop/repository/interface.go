@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"errors"
+
+	"fxserver/modules/shop/entity"
+)
+
+var (
+	ErrListingNotFound   = errors.New("listing not found")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// PurchaseFilter narrows purchase queries; zero values are ignored
+type PurchaseFilter struct {
+	UserID    int
+	ListingID int
+	Limit     int
+}
+
+type ListingRepository interface {
+	Create(listing *entity.Listing) error
+	GetByID(id int) (*entity.Listing, error)
+	// Update keeps the stored Sold count; sales are only recorded with AddSold
+	Update(listing *entity.Listing) error
+	// AddSold records quantity sales and returns the new sold count. For a listing with limited
+	// stock it fails with ErrInsufficientStock, changing nothing, unless quantity units are left;
+	// a negative quantity returns units to the stock.
+	AddSold(id, quantity int) (int, error)
+	Delete(id int) error
+	List() ([]*entity.Listing, error)
+}
+
+type PurchaseRepository interface {
+	CreatePurchase(purchase *entity.Purchase) error
+	// CountPurchased returns the total quantity a user has bought from a listing
+	CountPurchased(userID, listingID int) (int, error)
+	// ListPurchases returns matching purchases, newest first
+	ListPurchases(filter PurchaseFilter) ([]*entity.Purchase, error)
+}
+
+type Repository interface {
+	ListingRepository
+	PurchaseRepository
+}
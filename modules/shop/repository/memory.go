@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"fxserver/modules/shop/entity"
+)
+
+type purchaseKey struct {
+	userID    int
+	listingID int
+}
+
+type memoryRepository struct {
+	listings       map[int]*entity.Listing
+	purchases      []*entity.Purchase
+	purchased      map[purchaseKey]int // key: user, listing -> total quantity
+	listingNextID  int
+	purchaseNextID int
+	mu             sync.RWMutex
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		listings:       make(map[int]*entity.Listing),
+		purchases:      make([]*entity.Purchase, 0),
+		purchased:      make(map[purchaseKey]int),
+		listingNextID:  1,
+		purchaseNextID: 1,
+	}
+}
+
+// Listing operations
+
+func (r *memoryRepository) Create(listing *entity.Listing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	listing.ID = r.listingNextID
+	listing.Sold = 0
+	listing.CreatedAt = time.Now()
+	listing.UpdatedAt = time.Now()
+	r.listings[listing.ID] = listing
+	r.listingNextID++
+	return nil
+}
+
+func (r *memoryRepository) GetByID(id int) (*entity.Listing, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	listing, exists := r.listings[id]
+	if !exists {
+		return nil, ErrListingNotFound
+	}
+	// 호출자가 재고를 직접 수정하지 않도록 복사본 반환
+	copied := *listing
+	return &copied, nil
+}
+
+func (r *memoryRepository) Update(listing *entity.Listing) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.listings[listing.ID]
+	if !exists {
+		return ErrListingNotFound
+	}
+
+	listing.Sold = existing.Sold
+	listing.CreatedAt = existing.CreatedAt
+	listing.UpdatedAt = time.Now()
+	r.listings[listing.ID] = listing
+	return nil
+}
+
+func (r *memoryRepository) AddSold(id, quantity int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	listing, exists := r.listings[id]
+	if !exists {
+		return 0, ErrListingNotFound
+	}
+	if remaining := listing.RemainingStock(); remaining >= 0 && remaining < quantity {
+		return 0, fmt.Errorf("%w: %d left", ErrInsufficientStock, remaining)
+	}
+	listing.Sold += quantity
+	listing.UpdatedAt = time.Now()
+	return listing.Sold, nil
+}
+
+func (r *memoryRepository) Delete(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.listings[id]; !exists {
+		return ErrListingNotFound
+	}
+	delete(r.listings, id)
+	return nil
+}
+
+func (r *memoryRepository) List() ([]*entity.Listing, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	listings := make([]*entity.Listing, 0, len(r.listings))
+	for _, listing := range r.listings {
+		copied := *listing
+		listings = append(listings, &copied)
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].ID < listings[j].ID
+	})
+	return listings, nil
+}
+
+// Purchase operations
+
+func (r *memoryRepository) CreatePurchase(purchase *entity.Purchase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purchase.ID = r.purchaseNextID
+	purchase.CreatedAt = time.Now()
+	r.purchases = append(r.purchases, purchase)
+	r.purchased[purchaseKey{purchase.UserID, purchase.ListingID}] += purchase.Quantity
+	r.purchaseNextID++
+	return nil
+}
+
+func (r *memoryRepository) CountPurchased(userID, listingID int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.purchased[purchaseKey{userID, listingID}], nil
+}
+
+func (r *memoryRepository) ListPurchases(filter PurchaseFilter) ([]*entity.Purchase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var purchases []*entity.Purchase
+	for i := len(r.purchases) - 1; i >= 0; i-- {
+		purchase := r.purchases[i]
+		if filter.UserID != 0 && purchase.UserID != filter.UserID {
+			continue
+		}
+		if filter.ListingID != 0 && purchase.ListingID != filter.ListingID {
+			continue
+		}
+		purchases = append(purchases, purchase)
+		if filter.Limit > 0 && len(purchases) >= filter.Limit {
+			break
+		}
+	}
+	return purchases, nil
+}
@@ -0,0 +1,53 @@
+package shop
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// Public shop routes
+	shop := api.Group("/shop")
+	shop.GET("/listings", r.handler.GetListings)    // Get listings on sale
+	shop.GET("/listings/:id", r.handler.GetListing) // Get listing by ID
+
+	// User shop routes (user auth required)
+	shop.POST("/listings/:id/buy", r.handler.Buy, r.userMiddleware.VerifyAccessToken())    // Buy a listing
+	shop.GET("/purchases", r.handler.GetMyPurchases, r.userMiddleware.VerifyAccessToken()) // Get my purchase history
+
+	// Admin shop management routes (admin auth required)
+	admin := api.Group("/admin")
+	adminShop := admin.Group("/shop")
+	adminShop.GET("/listings", r.handler.GetAllListings, r.adminMiddleware.VerifyAdminToken())       // List listings (include_inactive=true for all)
+	adminShop.POST("/listings", r.handler.CreateListing, r.adminMiddleware.VerifyAdminToken())       // Create listing
+	adminShop.PUT("/listings/:id", r.handler.UpdateListing, r.adminMiddleware.VerifyAdminToken())    // Update listing
+	adminShop.DELETE("/listings/:id", r.handler.DeleteListing, r.adminMiddleware.VerifyAdminToken()) // Delete listing
+	adminShop.GET("/purchases", r.handler.GetAllPurchases, r.adminMiddleware.VerifyAdminToken())     // Get purchase log
+}
@@ -0,0 +1,353 @@
+package shop
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/shop/entity"
+	"fxserver/modules/shop/repository"
+	"fxserver/pkg/lock"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrListingNotFound      = errors.New("listing not found")
+	ErrListingNotAvailable  = errors.New("listing is not available")
+	ErrInvalidListing       = errors.New("invalid listing")
+	ErrInvalidQuantity      = errors.New("quantity must be between 1 and 100")
+	ErrOutOfStock           = errors.New("listing is out of stock")
+	ErrPurchaseLimitReached = errors.New("purchase limit reached")
+	ErrInsufficientFunds    = errors.New("insufficient funds")
+)
+
+// Inventory ledger source for shop purchases
+const SourceShop = "shop"
+
+// MaxBuyQuantity caps how many times a listing can be bought in one request
+const MaxBuyQuantity = 100
+
+type Service interface {
+	// Player operations
+	Buy(userID, listingID, quantity int) (*BuyResponse, error)
+	ListPurchases(query PurchaseQuery) ([]*entity.Purchase, error)
+
+	// Listing queries
+	GetListing(id int) (*entity.Listing, error)
+	ListListings(includeInactive bool) ([]*entity.Listing, error)
+
+	// Listing management (Admin)
+	CreateListing(req CreateListingRequest) (*entity.Listing, error)
+	UpdateListing(id int, req UpdateListingRequest) (*entity.Listing, error)
+	DeleteListing(id int) error
+}
+
+type service struct {
+	repo        repository.Repository
+	itemService item.Service
+	logger      *zap.Logger
+
+	// buyLocks serializes each player's purchases so per-user limits are checked and
+	// recorded consistently; shared stock is reserved atomically by the repository
+	buyLocks lock.Keyed[int]
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository  repository.Repository
+	ItemService item.Service
+	Logger      *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:        p.Repository,
+		itemService: p.ItemService,
+		logger:      p.Logger,
+	}
+}
+
+func (s *service) Buy(userID, listingID, quantity int) (*BuyResponse, error) {
+	if quantity <= 0 || quantity > MaxBuyQuantity {
+		return nil, ErrInvalidQuantity
+	}
+
+	defer s.buyLocks.Lock(userID)()
+
+	listing, err := s.GetListing(listingID)
+	if err != nil {
+		return nil, err
+	}
+	if !listing.IsAvailable(time.Now()) {
+		return nil, ErrListingNotAvailable
+	}
+
+	if remaining := listing.RemainingStock(); remaining >= 0 && remaining < quantity {
+		return nil, fmt.Errorf("%w: %d left", ErrOutOfStock, remaining)
+	}
+	bought, err := s.repo.CountPurchased(userID, listing.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count purchases: %w", err)
+	}
+	if listing.PerUserLimit > 0 && bought+quantity > listing.PerUserLimit {
+		return nil, fmt.Errorf("%w: %d of %d already bought", ErrPurchaseLimitReached, bought, listing.PerUserLimit)
+	}
+
+	price := listing.Price(quantity)
+	products := listing.Products(quantity)
+	ref := itemEntity.TransactionRef{
+		Type:  SourceShop,
+		ID:    strconv.Itoa(listing.ID),
+		Actor: itemEntity.UserActor(userID),
+	}
+
+	// Reserve the stock first so concurrent buyers cannot oversell it
+	sold, err := s.repo.AddSold(listing.ID, quantity)
+	if err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return nil, fmt.Errorf("%w: %v", ErrOutOfStock, err)
+		}
+		return nil, fmt.Errorf("failed to reserve listing stock: %w", err)
+	}
+	listing.Sold = sold
+
+	// The price is removed and the items granted together; nothing changes if the balance is too low
+	if err := s.itemService.ExchangeItems(userID, []itemEntity.RewardItem{price}, products, SourceShop, ref); err != nil {
+		s.releaseStock(listing.ID, quantity)
+		if errors.Is(err, item.ErrInsufficientItem) {
+			return nil, fmt.Errorf("%w: need %d of item %d", ErrInsufficientFunds, price.Count, price.ItemID)
+		}
+		return nil, err
+	}
+
+	purchase := &entity.Purchase{
+		UserID:    userID,
+		ListingID: listing.ID,
+		Quantity:  quantity,
+		Paid:      price,
+		Granted:   products,
+	}
+	if err := s.repo.CreatePurchase(purchase); err != nil {
+		s.logger.Error("Failed to record purchase", zap.Error(err), zap.Int("listing_id", listing.ID), zap.Int("user_id", userID))
+		return nil, fmt.Errorf("failed to record purchase: %w", err)
+	}
+
+	s.logger.Info("Listing purchased",
+		zap.Int("user_id", userID),
+		zap.Int("listing_id", listing.ID),
+		zap.Int("quantity", quantity))
+
+	response := &BuyResponse{
+		PurchaseID: purchase.ID,
+		ListingID:  listing.ID,
+		Quantity:   quantity,
+		Paid:       price,
+		Granted:    products,
+	}
+	if listing.Stock > 0 {
+		remaining := listing.RemainingStock()
+		response.RemainingStock = &remaining
+	}
+	if listing.PerUserLimit > 0 {
+		remaining := listing.PerUserLimit - bought - quantity
+		response.UserRemaining = &remaining
+	}
+	return response, nil
+}
+
+// releaseStock returns reserved units after a purchase fails
+func (s *service) releaseStock(listingID, quantity int) {
+	if _, err := s.repo.AddSold(listingID, -quantity); err != nil {
+		s.logger.Error("Failed to release listing stock",
+			zap.Error(err),
+			zap.Int("listing_id", listingID),
+			zap.Int("quantity", quantity))
+	}
+}
+
+func (s *service) ListPurchases(query PurchaseQuery) ([]*entity.Purchase, error) {
+	purchases, err := s.repo.ListPurchases(repository.PurchaseFilter{
+		UserID:    query.UserID,
+		ListingID: query.ListingID,
+		Limit:     query.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchases: %w", err)
+	}
+	return purchases, nil
+}
+
+func (s *service) GetListing(id int) (*entity.Listing, error) {
+	listing, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			return nil, ErrListingNotFound
+		}
+		return nil, fmt.Errorf("failed to get listing: %w", err)
+	}
+	return listing, nil
+}
+
+func (s *service) ListListings(includeInactive bool) ([]*entity.Listing, error) {
+	listings, err := s.repo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list listings: %w", err)
+	}
+	if includeInactive {
+		return listings, nil
+	}
+
+	now := time.Now()
+	available := make([]*entity.Listing, 0, len(listings))
+	for _, listing := range listings {
+		if listing.IsAvailable(now) {
+			available = append(available, listing)
+		}
+	}
+	return available, nil
+}
+
+func (s *service) CreateListing(req CreateListingRequest) (*entity.Listing, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	listing := &entity.Listing{
+		Name:         req.Name,
+		Description:  req.Description,
+		Items:        req.Items,
+		PriceItemID:  req.PriceItemID,
+		PriceAmount:  req.PriceAmount,
+		Stock:        req.Stock,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		IsActive:     isActive,
+	}
+
+	if err := s.validateListing(listing); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(listing); err != nil {
+		s.logger.Error("Failed to create listing", zap.Error(err), zap.String("name", req.Name))
+		return nil, fmt.Errorf("failed to create listing: %w", err)
+	}
+
+	s.logger.Info("Listing created",
+		zap.Int("listing_id", listing.ID),
+		zap.String("name", listing.Name))
+
+	return listing, nil
+}
+
+func (s *service) UpdateListing(id int, req UpdateListingRequest) (*entity.Listing, error) {
+	existing, err := s.GetListing(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Name != "" {
+		updated.Name = req.Name
+	}
+	if req.Description != "" {
+		updated.Description = req.Description
+	}
+	if req.Items != nil {
+		updated.Items = req.Items
+	}
+	if req.PriceItemID != 0 {
+		updated.PriceItemID = req.PriceItemID
+	}
+	if req.PriceAmount != 0 {
+		updated.PriceAmount = req.PriceAmount
+	}
+	if req.Stock != nil {
+		updated.Stock = *req.Stock
+	}
+	if req.PerUserLimit != nil {
+		updated.PerUserLimit = *req.PerUserLimit
+	}
+	if req.StartsAt != nil {
+		updated.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		updated.EndsAt = req.EndsAt
+	}
+	if req.IsActive != nil {
+		updated.IsActive = *req.IsActive
+	}
+
+	if err := s.validateListing(&updated); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(&updated); err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			return nil, ErrListingNotFound
+		}
+		s.logger.Error("Failed to update listing", zap.Error(err), zap.Int("listing_id", id))
+		return nil, fmt.Errorf("failed to update listing: %w", err)
+	}
+
+	s.logger.Info("Listing updated", zap.Int("listing_id", id))
+	return &updated, nil
+}
+
+func (s *service) DeleteListing(id int) error {
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, repository.ErrListingNotFound) {
+			return ErrListingNotFound
+		}
+		s.logger.Error("Failed to delete listing", zap.Error(err), zap.Int("listing_id", id))
+		return fmt.Errorf("failed to delete listing: %w", err)
+	}
+
+	s.logger.Info("Listing deleted", zap.Int("listing_id", id))
+	return nil
+}
+
+func (s *service) validateListing(listing *entity.Listing) error {
+	if len(listing.Items) == 0 {
+		return fmt.Errorf("%w: listing must sell at least one item", ErrInvalidListing)
+	}
+	if listing.PriceAmount <= 0 {
+		return fmt.Errorf("%w: price must be greater than 0", ErrInvalidListing)
+	}
+	if listing.Stock < 0 || listing.PerUserLimit < 0 {
+		return fmt.Errorf("%w: stock and per-user limit cannot be negative", ErrInvalidListing)
+	}
+	if listing.StartsAt != nil && listing.EndsAt != nil && !listing.EndsAt.After(*listing.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidListing)
+	}
+
+	currency, err := s.itemService.GetItem(listing.PriceItemID)
+	if err != nil {
+		return fmt.Errorf("%w: item %d not found", ErrInvalidListing, listing.PriceItemID)
+	}
+	if currency.Type != itemEntity.ItemTypeCurrency {
+		return fmt.Errorf("%w: item %d is not a currency", ErrInvalidListing, listing.PriceItemID)
+	}
+
+	for _, reward := range listing.Items {
+		if reward.Count <= 0 {
+			return fmt.Errorf("%w: invalid count for item %d", ErrInvalidListing, reward.ItemID)
+		}
+		template, err := s.itemService.GetItem(reward.ItemID)
+		if err != nil {
+			return fmt.Errorf("%w: item %d not found", ErrInvalidListing, reward.ItemID)
+		}
+		if template.IsArchived() {
+			return fmt.Errorf("%w: item %d is archived", ErrInvalidListing, reward.ItemID)
+		}
+	}
+
+	return nil
+}
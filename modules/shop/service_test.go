@@ -0,0 +1,230 @@
+package shop
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/shop/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID    = 1 // currency
+	diamondID = 2 // currency
+	potionID  = 3 // consumable
+	swordID   = 4 // equipment
+	userID    = 1
+)
+
+func setupShopService(t *testing.T) (Service, *itemtest.Fixture) {
+	items := itemtest.New()
+	svc := NewService(ServiceParam{
+		Repository:  repository.NewMemoryRepository(),
+		ItemService: items.Service,
+		Logger:      zap.NewNop(),
+	})
+	return svc, items
+}
+
+func TestBuyListing(t *testing.T) {
+	svc, items := setupShopService(t)
+	require.NoError(t, items.Items.AddToInventory(userID, goldID, 500, "admin", itemEntity.TransactionRef{}))
+
+	listing, err := svc.CreateListing(CreateListingRequest{
+		Name:        "Potion pack",
+		Items:       []itemEntity.RewardItem{{ItemID: potionID, Count: 5}},
+		PriceItemID: goldID,
+		PriceAmount: 100,
+	})
+	require.NoError(t, err)
+	assert.True(t, listing.IsActive)
+
+	response, err := svc.Buy(userID, listing.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, itemEntity.RewardItem{ItemID: goldID, Count: 200}, response.Paid)
+	assert.Nil(t, response.RemainingStock)
+	assert.Nil(t, response.UserRemaining)
+	assert.Equal(t, 300, items.Balance(userID, goldID))
+	assert.Equal(t, 10, items.Balance(userID, potionID))
+
+	// Both sides of the purchase are recorded in the ledger under the shop source
+	entries, err := items.Items.GetInventoryTransactions(itemRepository.TransactionFilter{UserID: userID, Source: SourceShop})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	purchases, err := svc.ListPurchases(PurchaseQuery{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, purchases, 1)
+	assert.Equal(t, 2, purchases[0].Quantity)
+}
+
+func TestBuyInsufficientFunds(t *testing.T) {
+	svc, items := setupShopService(t)
+	require.NoError(t, items.Items.AddToInventory(userID, goldID, 50, "admin", itemEntity.TransactionRef{}))
+
+	listing, err := svc.CreateListing(CreateListingRequest{
+		Name:        "Sword",
+		Items:       []itemEntity.RewardItem{{ItemID: swordID, Count: 1}},
+		PriceItemID: goldID,
+		PriceAmount: 100,
+		Stock:       3,
+	})
+	require.NoError(t, err)
+
+	// Nothing is deducted, granted or sold when the balance is too low
+	_, err = svc.Buy(userID, listing.ID, 1)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Equal(t, 50, items.Balance(userID, goldID))
+	assert.Equal(t, 0, items.Balance(userID, swordID))
+
+	stored, err := svc.GetListing(listing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.Sold)
+}
+
+func TestBuyStockAndPerUserLimits(t *testing.T) {
+	svc, items := setupShopService(t)
+	for _, id := range []int{userID, userID + 1} {
+		require.NoError(t, items.Items.AddToInventory(id, diamondID, 100, "admin", itemEntity.TransactionRef{}))
+	}
+
+	listing, err := svc.CreateListing(CreateListingRequest{
+		Name:         "Limited potion",
+		Items:        []itemEntity.RewardItem{{ItemID: potionID, Count: 1}},
+		PriceItemID:  diamondID,
+		PriceAmount:  10,
+		Stock:        3,
+		PerUserLimit: 2,
+	})
+	require.NoError(t, err)
+
+	response, err := svc.Buy(userID, listing.ID, 2)
+	require.NoError(t, err)
+	require.NotNil(t, response.RemainingStock)
+	assert.Equal(t, 1, *response.RemainingStock)
+	require.NotNil(t, response.UserRemaining)
+	assert.Equal(t, 0, *response.UserRemaining)
+
+	_, err = svc.Buy(userID, listing.ID, 1)
+	assert.ErrorIs(t, err, ErrPurchaseLimitReached)
+
+	_, err = svc.Buy(userID+1, listing.ID, 2)
+	assert.ErrorIs(t, err, ErrOutOfStock)
+	_, err = svc.Buy(userID+1, listing.ID, 1)
+	require.NoError(t, err)
+	_, err = svc.Buy(userID+1, listing.ID, 1)
+	assert.ErrorIs(t, err, ErrOutOfStock)
+
+	assert.Equal(t, 80, items.Balance(userID, diamondID))
+	assert.Equal(t, 90, items.Balance(userID+1, diamondID))
+}
+
+func TestConcurrentBuyersCannotOversell(t *testing.T) {
+	svc, items := setupShopService(t)
+	const buyers = 10
+	for id := 1; id <= buyers; id++ {
+		require.NoError(t, items.Items.AddToInventory(id, diamondID, 10, "admin", itemEntity.TransactionRef{}))
+	}
+
+	listing, err := svc.CreateListing(CreateListingRequest{
+		Name:        "Limited potion",
+		Items:       []itemEntity.RewardItem{{ItemID: potionID, Count: 1}},
+		PriceItemID: diamondID,
+		PriceAmount: 10,
+		Stock:       3,
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for id := 1; id <= buyers; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			_, _ = svc.Buy(id, listing.ID, 1)
+		}(id)
+	}
+	wg.Wait()
+
+	// Different players buy in parallel, but the stock is still sold exactly once
+	stored, err := svc.GetListing(listing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Sold)
+	paid := 0
+	for id := 1; id <= buyers; id++ {
+		paid += 10 - items.Balance(id, diamondID)
+	}
+	assert.Equal(t, 30, paid)
+}
+
+func TestListingSchedule(t *testing.T) {
+	svc, items := setupShopService(t)
+	require.NoError(t, items.Items.AddToInventory(userID, goldID, 100, "admin", itemEntity.TransactionRef{}))
+
+	future := time.Now().Add(time.Hour)
+	later := future.Add(time.Hour)
+	listing, err := svc.CreateListing(CreateListingRequest{
+		Name:        "Tomorrow's deal",
+		Items:       []itemEntity.RewardItem{{ItemID: potionID, Count: 1}},
+		PriceItemID: goldID,
+		PriceAmount: 10,
+		StartsAt:    &future,
+		EndsAt:      &later,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.Buy(userID, listing.ID, 1)
+	assert.ErrorIs(t, err, ErrListingNotAvailable)
+
+	onSale, err := svc.ListListings(false)
+	require.NoError(t, err)
+	assert.Empty(t, onSale)
+	all, err := svc.ListListings(true)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	// Moving the window to now puts the listing on sale
+	past := time.Now().Add(-time.Minute)
+	_, err = svc.UpdateListing(listing.ID, UpdateListingRequest{StartsAt: &past})
+	require.NoError(t, err)
+	_, err = svc.Buy(userID, listing.ID, 1)
+	assert.NoError(t, err)
+}
+
+func TestCreateListingValidation(t *testing.T) {
+	svc, _ := setupShopService(t)
+
+	// Prices must be in a currency item
+	_, err := svc.CreateListing(CreateListingRequest{
+		Name:        "Bad price",
+		Items:       []itemEntity.RewardItem{{ItemID: goldID, Count: 100}},
+		PriceItemID: potionID,
+		PriceAmount: 1,
+	})
+	assert.ErrorIs(t, err, ErrInvalidListing)
+
+	_, err = svc.CreateListing(CreateListingRequest{
+		Name:        "Unknown item",
+		Items:       []itemEntity.RewardItem{{ItemID: 999, Count: 1}},
+		PriceItemID: goldID,
+		PriceAmount: 1,
+	})
+	assert.ErrorIs(t, err, ErrInvalidListing)
+
+	start := time.Now()
+	_, err = svc.CreateListing(CreateListingRequest{
+		Name:        "Empty window",
+		Items:       []itemEntity.RewardItem{{ItemID: potionID, Count: 1}},
+		PriceItemID: goldID,
+		PriceAmount: 1,
+		StartsAt:    &start,
+		EndsAt:      &start,
+	})
+	assert.ErrorIs(t, err, ErrInvalidListing)
+}
@@ -0,0 +1,46 @@
+package trade
+
+import (
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/trade/entity"
+)
+
+// Gift DTOs
+type SendGiftRequest struct {
+	ReceiverID int                     `json:"receiver_id" validate:"required,gt=0"`
+	Items      []itemEntity.RewardItem `json:"items" validate:"required,min=1,max=10,dive"`
+	Message    string                  `json:"message,omitempty" validate:"omitempty,max=200"`
+}
+
+type SendGiftResponse struct {
+	Gift           *entity.Gift `json:"gift"`
+	GiftsSentToday int          `json:"gifts_sent_today"`
+	DailyLimit     int          `json:"daily_limit"` // 0: 제한 없음
+}
+
+// Trade offer DTOs
+type CreateOfferRequest struct {
+	RecipientID      int                     `json:"recipient_id" validate:"required,gt=0"`
+	Offered          []itemEntity.RewardItem `json:"offered" validate:"required,min=1,max=10,dive"`
+	Requested        []itemEntity.RewardItem `json:"requested,omitempty" validate:"omitempty,max=10,dive"`
+	Message          string                  `json:"message,omitempty" validate:"omitempty,max=200"`
+	ExpiresInMinutes int                     `json:"expires_in_minutes,omitempty" validate:"omitempty,gt=0,lte=10080"` // 기본값: 1440 (24시간)
+}
+
+// Query DTOs
+type GiftQuery struct {
+	Direction string `query:"direction" validate:"omitempty,oneof=sent received"` // 미지정 시 전체
+	Limit     int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type OfferQuery struct {
+	Role   string `query:"role" validate:"omitempty,oneof=sent received"` // 미지정 시 전체
+	Status string `query:"status" validate:"omitempty,oneof=pending accepted declined cancelled expired"`
+	Limit  int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
+
+type AdminTradeQuery struct {
+	UserID int    `query:"user_id" validate:"omitempty,gt=0"`
+	Status string `query:"status" validate:"omitempty,oneof=pending accepted declined cancelled expired"` // 거래 제안 전용
+	Limit  int    `query:"limit" validate:"omitempty,gt=0,lte=1000"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"time"
+
+	itemEntity "fxserver/modules/item/entity"
+)
+
+// Gift is a one-way transfer of items from one player to another
+type Gift struct {
+	ID         int                     `json:"id"`
+	SenderID   int                     `json:"sender_id"`
+	ReceiverID int                     `json:"receiver_id"`
+	Items      []itemEntity.RewardItem `json:"items"`
+	Message    string                  `json:"message,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
+
+type OfferStatus string
+
+const (
+	OfferStatusPending   OfferStatus = "pending"   // 수락 대기
+	OfferStatusAccepted  OfferStatus = "accepted"  // 수락되어 교환 완료
+	OfferStatusDeclined  OfferStatus = "declined"  // 상대방이 거절
+	OfferStatusCancelled OfferStatus = "cancelled" // 제안자가 취소
+	OfferStatusExpired   OfferStatus = "expired"   // 응답 없이 만료
+)
+
+// IsValidOfferStatus validates if the offer status is valid
+func IsValidOfferStatus(status string) bool {
+	switch OfferStatus(status) {
+	case OfferStatusPending, OfferStatusAccepted, OfferStatusDeclined,
+		OfferStatusCancelled, OfferStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// TradeOffer proposes swapping the proposer's offered items for the recipient's requested items.
+// Items are not held in escrow; both sides must still have them when the offer is accepted.
+type TradeOffer struct {
+	ID          int                     `json:"id"`
+	ProposerID  int                     `json:"proposer_id"`
+	RecipientID int                     `json:"recipient_id"`
+	Offered     []itemEntity.RewardItem `json:"offered"`   // 제안자가 주는 아이템
+	Requested   []itemEntity.RewardItem `json:"requested"` // 제안자가 받는 아이템 (비어 있으면 무상 제안)
+	Message     string                  `json:"message,omitempty"`
+	Status      OfferStatus             `json:"status"`
+	ExpiresAt   time.Time               `json:"expires_at"`
+	RespondedAt *time.Time              `json:"responded_at,omitempty"` // 수락/거절/취소/만료 처리 시간
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// IsExpired reports whether a pending offer has run past its expiry
+func (o *TradeOffer) IsExpired(now time.Time) bool {
+	return o.Status == OfferStatusPending && !now.Before(o.ExpiresAt)
+}
+
+// Involves reports whether the user is either side of the offer
+func (o *TradeOffer) Involves(userID int) bool {
+	return o.ProposerID == userID || o.RecipientID == userID
+}
+
+// Transfers returns the item moves that settle the offer
+func (o *TradeOffer) Transfers() []itemEntity.ItemTransfer {
+	transfers := []itemEntity.ItemTransfer{
+		{FromUserID: o.ProposerID, ToUserID: o.RecipientID, Items: o.Offered},
+	}
+	if len(o.Requested) > 0 {
+		transfers = append(transfers, itemEntity.ItemTransfer{
+			FromUserID: o.RecipientID,
+			ToUserID:   o.ProposerID,
+			Items:      o.Requested,
+		})
+	}
+	return transfers
+}
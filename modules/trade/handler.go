@@ -0,0 +1,243 @@
+package trade
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	userauth "fxserver/modules/auth/user"
+	"fxserver/modules/item"
+	"fxserver/modules/trade/entity"
+	"fxserver/pkg/dto"
+	"fxserver/pkg/validator"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	service   Service
+	validator validator.Validator
+	logger    *zap.Logger
+}
+
+type HandlerParam struct {
+	fx.In
+	Service   Service
+	Validator validator.Validator
+	Logger    *zap.Logger
+}
+
+func NewHandler(p HandlerParam) *Handler {
+	return &Handler{
+		service:   p.Service,
+		validator: p.Validator,
+		logger:    p.Logger,
+	}
+}
+
+// User APIs
+
+// SendGift transfers items from the authenticated user to another user
+func (h *Handler) SendGift(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var req SendGiftRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	response, err := h.service.SendGift(userID, req)
+	if err != nil {
+		return h.tradeError(c, err, "Failed to send gift", zap.Int("user_id", userID))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetGifts returns gifts the authenticated user sent or received
+func (h *Handler) GetGifts(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query GiftQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	gifts, err := h.service.ListGifts(userID, query)
+	if err != nil {
+		h.logger.Error("Failed to list gifts", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get gifts"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(gifts))
+}
+
+// CreateOffer proposes a trade to another user
+func (h *Handler) CreateOffer(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var req CreateOfferRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid request format", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	offer, err := h.service.CreateOffer(userID, req)
+	if err != nil {
+		return h.tradeError(c, err, "Failed to create trade offer", zap.Int("user_id", userID))
+	}
+
+	return c.JSON(http.StatusCreated, offer)
+}
+
+// GetOffers returns trade offers the authenticated user proposed or received
+func (h *Handler) GetOffers(c echo.Context) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	var query OfferQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	offers, err := h.service.ListOffers(userID, query)
+	if err != nil {
+		h.logger.Error("Failed to list trade offers", zap.Error(err), zap.Int("user_id", userID))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get trade offers"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(offers))
+}
+
+// GetOffer returns a trade offer the authenticated user is a party to
+func (h *Handler) GetOffer(c echo.Context) error {
+	return h.offerAction(c, "Failed to get trade offer", h.service.GetOffer)
+}
+
+// AcceptOffer accepts a received trade offer and swaps the items
+func (h *Handler) AcceptOffer(c echo.Context) error {
+	return h.offerAction(c, "Failed to accept trade offer", h.service.AcceptOffer)
+}
+
+// DeclineOffer declines a received trade offer
+func (h *Handler) DeclineOffer(c echo.Context) error {
+	return h.offerAction(c, "Failed to decline trade offer", h.service.DeclineOffer)
+}
+
+// CancelOffer withdraws a trade offer the authenticated user proposed
+func (h *Handler) CancelOffer(c echo.Context) error {
+	return h.offerAction(c, "Failed to cancel trade offer", h.service.CancelOffer)
+}
+
+// offerAction runs an operation on the offer in the :id path parameter for the authenticated user
+func (h *Handler) offerAction(c echo.Context, failure string, action func(userID, offerID int) (*entity.TradeOffer, error)) error {
+	userID, ok := userauth.GetUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, dto.NewAuthError("User not authenticated"))
+	}
+
+	offerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid trade offer ID", "invalid_request_error"))
+	}
+
+	offer, err := action(userID, offerID)
+	if err != nil {
+		return h.tradeError(c, err, failure, zap.Int("user_id", userID), zap.Int("offer_id", offerID))
+	}
+
+	return c.JSON(http.StatusOK, offer)
+}
+
+// Admin APIs
+
+// GetAllGifts returns the gift audit log (admin only)
+func (h *Handler) GetAllGifts(c echo.Context) error {
+	var query AdminTradeQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	gifts, err := h.service.ListAllGifts(query)
+	if err != nil {
+		h.logger.Error("Failed to list gifts", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get gifts"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(gifts))
+}
+
+// GetAllOffers returns every trade offer, optionally for one user (admin only)
+func (h *Handler) GetAllOffers(c echo.Context) error {
+	var query AdminTradeQuery
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewError("Invalid query parameters", "invalid_request_error"))
+	}
+
+	if err := h.validator.Validate(query); err != nil {
+		return c.JSON(http.StatusBadRequest, dto.NewValidationErrors(err))
+	}
+
+	offers, err := h.service.ListAllOffers(query)
+	if err != nil {
+		h.logger.Error("Failed to list trade offers", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, dto.NewError("Failed to get trade offers"))
+	}
+
+	return c.JSON(http.StatusOK, dto.NewList(offers))
+}
+
+// tradeError maps gift and trade offer errors to HTTP responses
+func (h *Handler) tradeError(c echo.Context, err error, failure string, fields ...zap.Field) error {
+	if errors.Is(err, ErrOfferNotFound) {
+		return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Trade offer"))
+	}
+	if errors.Is(err, ErrRecipientNotFound) {
+		return c.JSON(http.StatusNotFound, dto.NewNotFoundError("Recipient"))
+	}
+	if errors.Is(err, ErrNotOfferParty) {
+		return c.JSON(http.StatusForbidden, dto.NewError(err.Error(), "permission_error"))
+	}
+	if errors.Is(err, ErrDailyGiftLimit) {
+		return c.JSON(http.StatusTooManyRequests, dto.NewError(err.Error(), "rate_limit_error"))
+	}
+	if errors.Is(err, ErrInvalidTrade) || errors.Is(err, ErrItemNotTradable) ||
+		errors.Is(err, ErrOfferNotPending) || errors.Is(err, ErrOfferExpired) ||
+		errors.Is(err, item.ErrInsufficientItem) || errors.Is(err, item.ErrInventoryFull) || errors.Is(err, item.ErrItemArchived) {
+		return c.JSON(http.StatusBadRequest, dto.NewError(err.Error(), "invalid_request_error"))
+	}
+	h.logger.Error(failure, append(fields, zap.Error(err))...)
+	return c.JSON(http.StatusInternalServerError, dto.NewError(failure))
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"fxserver/modules/trade/entity"
+)
+
+var (
+	ErrGiftNotFound       = errors.New("gift not found")
+	ErrOfferNotFound      = errors.New("trade offer not found")
+	ErrOfferStatusChanged = errors.New("trade offer status changed")
+)
+
+// GiftFilter narrows gift queries; zero values are ignored
+type GiftFilter struct {
+	UserID     int // 보낸 사람 또는 받은 사람
+	SenderID   int
+	ReceiverID int
+	Since      time.Time
+	Limit      int
+}
+
+// OfferFilter narrows trade offer queries; zero values are ignored
+type OfferFilter struct {
+	UserID      int // 제안자 또는 상대방
+	ProposerID  int
+	RecipientID int
+	Status      entity.OfferStatus
+	Limit       int
+}
+
+type GiftRepository interface {
+	CreateGift(gift *entity.Gift) error
+	DeleteGift(id int) error
+	// ListGifts returns matching gifts, newest first
+	ListGifts(filter GiftFilter) ([]*entity.Gift, error)
+}
+
+type OfferRepository interface {
+	CreateOffer(offer *entity.TradeOffer) error
+	// GetOffer returns a copy; changes are saved with UpdateOffer
+	GetOffer(id int) (*entity.TradeOffer, error)
+	UpdateOffer(offer *entity.TradeOffer) error
+	// UpdateOfferStatus moves the offer to status and sets its response time only if it is
+	// currently in from; otherwise it fails with ErrOfferStatusChanged
+	UpdateOfferStatus(id int, from, status entity.OfferStatus, respondedAt *time.Time) (*entity.TradeOffer, error)
+	// ListOffers returns matching offers, newest first
+	ListOffers(filter OfferFilter) ([]*entity.TradeOffer, error)
+}
+
+type Repository interface {
+	GiftRepository
+	OfferRepository
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"fxserver/modules/trade/entity"
+)
+
+type memoryRepository struct {
+	gifts       []*entity.Gift
+	offers      map[int]*entity.TradeOffer
+	giftNextID  int
+	offerNextID int
+	mu          sync.RWMutex
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		gifts:       make([]*entity.Gift, 0),
+		offers:      make(map[int]*entity.TradeOffer),
+		giftNextID:  1,
+		offerNextID: 1,
+	}
+}
+
+// Gift operations
+
+func (r *memoryRepository) CreateGift(gift *entity.Gift) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	gift.ID = r.giftNextID
+	gift.CreatedAt = time.Now()
+	r.gifts = append(r.gifts, gift)
+	r.giftNextID++
+	return nil
+}
+
+func (r *memoryRepository) DeleteGift(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, gift := range r.gifts {
+		if gift.ID == id {
+			r.gifts = append(r.gifts[:i], r.gifts[i+1:]...)
+			return nil
+		}
+	}
+	return ErrGiftNotFound
+}
+
+func (r *memoryRepository) ListGifts(filter GiftFilter) ([]*entity.Gift, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var gifts []*entity.Gift
+	for i := len(r.gifts) - 1; i >= 0; i-- {
+		gift := r.gifts[i]
+		if !filter.Since.IsZero() && gift.CreatedAt.Before(filter.Since) {
+			break // gifts are stored in creation order
+		}
+		if filter.UserID != 0 && gift.SenderID != filter.UserID && gift.ReceiverID != filter.UserID {
+			continue
+		}
+		if filter.SenderID != 0 && gift.SenderID != filter.SenderID {
+			continue
+		}
+		if filter.ReceiverID != 0 && gift.ReceiverID != filter.ReceiverID {
+			continue
+		}
+		gifts = append(gifts, gift)
+		if filter.Limit > 0 && len(gifts) >= filter.Limit {
+			break
+		}
+	}
+	return gifts, nil
+}
+
+// Offer operations
+
+func (r *memoryRepository) CreateOffer(offer *entity.TradeOffer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offer.ID = r.offerNextID
+	offer.CreatedAt = time.Now()
+	offer.UpdatedAt = offer.CreatedAt
+	copied := *offer
+	r.offers[offer.ID] = &copied
+	r.offerNextID++
+	return nil
+}
+
+func (r *memoryRepository) GetOffer(id int) (*entity.TradeOffer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	offer, exists := r.offers[id]
+	if !exists {
+		return nil, ErrOfferNotFound
+	}
+	copied := *offer
+	return &copied, nil
+}
+
+func (r *memoryRepository) UpdateOffer(offer *entity.TradeOffer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.offers[offer.ID]
+	if !exists {
+		return ErrOfferNotFound
+	}
+
+	offer.CreatedAt = existing.CreatedAt
+	offer.UpdatedAt = time.Now()
+	copied := *offer
+	r.offers[offer.ID] = &copied
+	return nil
+}
+
+func (r *memoryRepository) UpdateOfferStatus(id int, from, status entity.OfferStatus, respondedAt *time.Time) (*entity.TradeOffer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offer, exists := r.offers[id]
+	if !exists {
+		return nil, ErrOfferNotFound
+	}
+	if offer.Status != from {
+		return nil, ErrOfferStatusChanged
+	}
+
+	offer.Status = status
+	offer.RespondedAt = respondedAt
+	offer.UpdatedAt = time.Now()
+	copied := *offer
+	return &copied, nil
+}
+
+func (r *memoryRepository) ListOffers(filter OfferFilter) ([]*entity.TradeOffer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var offers []*entity.TradeOffer
+	for id := r.offerNextID - 1; id >= 1; id-- {
+		offer, exists := r.offers[id]
+		if !exists {
+			continue
+		}
+		if filter.UserID != 0 && !offer.Involves(filter.UserID) {
+			continue
+		}
+		if filter.ProposerID != 0 && offer.ProposerID != filter.ProposerID {
+			continue
+		}
+		if filter.RecipientID != 0 && offer.RecipientID != filter.RecipientID {
+			continue
+		}
+		if filter.Status != "" && offer.Status != filter.Status {
+			continue
+		}
+		copied := *offer
+		offers = append(offers, &copied)
+		if filter.Limit > 0 && len(offers) >= filter.Limit {
+			break
+		}
+	}
+	return offers, nil
+}
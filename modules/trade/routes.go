@@ -0,0 +1,52 @@
+package trade
+
+import (
+	adminauth "fxserver/modules/auth/admin"
+	userauth "fxserver/modules/auth/user"
+	"fxserver/pkg/router"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+type Routes struct {
+	handler         *Handler
+	userMiddleware  *userauth.Middleware
+	adminMiddleware *adminauth.Middleware
+}
+
+type RoutesParam struct {
+	fx.In
+	Handler         *Handler
+	UserMiddleware  *userauth.Middleware
+	AdminMiddleware *adminauth.Middleware
+}
+
+func NewRoutes(p RoutesParam) router.RouteRegistrar {
+	return &Routes{
+		handler:         p.Handler,
+		userMiddleware:  p.UserMiddleware,
+		adminMiddleware: p.AdminMiddleware,
+	}
+}
+
+func (r *Routes) RegisterRoutes(e *echo.Echo) {
+	api := e.Group("/api/v1")
+
+	// User trade routes (user auth required)
+	trade := api.Group("/trade")
+	trade.POST("/gifts", r.handler.SendGift, r.userMiddleware.VerifyAccessToken())                  // Send a gift
+	trade.GET("/gifts", r.handler.GetGifts, r.userMiddleware.VerifyAccessToken())                   // Get my sent/received gifts
+	trade.POST("/offers", r.handler.CreateOffer, r.userMiddleware.VerifyAccessToken())              // Propose a trade
+	trade.GET("/offers", r.handler.GetOffers, r.userMiddleware.VerifyAccessToken())                 // Get my trade offers
+	trade.GET("/offers/:id", r.handler.GetOffer, r.userMiddleware.VerifyAccessToken())              // Get trade offer by ID
+	trade.POST("/offers/:id/accept", r.handler.AcceptOffer, r.userMiddleware.VerifyAccessToken())   // Accept a received offer
+	trade.POST("/offers/:id/decline", r.handler.DeclineOffer, r.userMiddleware.VerifyAccessToken()) // Decline a received offer
+	trade.POST("/offers/:id/cancel", r.handler.CancelOffer, r.userMiddleware.VerifyAccessToken())   // Cancel my offer
+
+	// Admin trade audit routes (admin auth required)
+	admin := api.Group("/admin")
+	adminTrade := admin.Group("/trade")
+	adminTrade.GET("/gifts", r.handler.GetAllGifts, r.adminMiddleware.VerifyAdminToken())   // Get gift audit log
+	adminTrade.GET("/offers", r.handler.GetAllOffers, r.adminMiddleware.VerifyAdminToken()) // Get trade offer audit log
+}
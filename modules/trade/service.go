@@ -0,0 +1,493 @@
+package trade
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/trade/entity"
+	"fxserver/modules/trade/repository"
+	"fxserver/modules/user"
+	userRepository "fxserver/modules/user/repository"
+	"fxserver/pkg/lock"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOfferNotFound     = errors.New("trade offer not found")
+	ErrInvalidTrade      = errors.New("invalid trade")
+	ErrItemNotTradable   = errors.New("item is not tradable")
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrDailyGiftLimit    = errors.New("daily gift limit reached")
+	ErrOfferNotPending   = errors.New("trade offer is no longer pending")
+	ErrOfferExpired      = errors.New("trade offer has expired")
+	ErrNotOfferParty     = errors.New("only the other side of the trade offer can do this")
+)
+
+// Inventory ledger sources for player-to-player moves
+const (
+	SourceGift  = "gift"  // 선물
+	SourceTrade = "trade" // 거래 제안 수락
+)
+
+// Ledger reference types
+const (
+	ReferenceTypeGift  = "gift"
+	ReferenceTypeOffer = "trade_offer"
+)
+
+const (
+	// DefaultGiftDailyLimit is the number of gifts a user can send per UTC day unless overridden
+	DefaultGiftDailyLimit = 10
+	// DefaultOfferTTL is how long a trade offer stays open when no expiry is requested
+	DefaultOfferTTL = 24 * time.Hour
+)
+
+type Service interface {
+	// Gifts
+	SendGift(senderID int, req SendGiftRequest) (*SendGiftResponse, error)
+	ListGifts(userID int, query GiftQuery) ([]*entity.Gift, error)
+
+	// Trade offers
+	CreateOffer(proposerID int, req CreateOfferRequest) (*entity.TradeOffer, error)
+	GetOffer(userID, offerID int) (*entity.TradeOffer, error)
+	ListOffers(userID int, query OfferQuery) ([]*entity.TradeOffer, error)
+	AcceptOffer(userID, offerID int) (*entity.TradeOffer, error)
+	DeclineOffer(userID, offerID int) (*entity.TradeOffer, error)
+	CancelOffer(userID, offerID int) (*entity.TradeOffer, error)
+
+	// Audit (Admin)
+	ListAllGifts(query AdminTradeQuery) ([]*entity.Gift, error)
+	ListAllOffers(query AdminTradeQuery) ([]*entity.TradeOffer, error)
+}
+
+type service struct {
+	repo        repository.Repository
+	itemService item.Service
+	userService user.Service
+	dailyLimit  int
+	logger      *zap.Logger
+
+	// giftLocks serializes each sender's gifts so the daily limit is checked and recorded
+	// consistently; offer status changes are conditional updates in the repository
+	giftLocks lock.Keyed[int]
+}
+
+type ServiceParam struct {
+	fx.In
+	Repository  repository.Repository
+	ItemService item.Service
+	UserService user.Service
+	Logger      *zap.Logger
+}
+
+func NewService(p ServiceParam) Service {
+	return &service{
+		repo:        p.Repository,
+		itemService: p.ItemService,
+		userService: p.UserService,
+		dailyLimit:  giftDailyLimitFromEnv(p.Logger),
+		logger:      p.Logger,
+	}
+}
+
+// giftDailyLimitFromEnv reads the per-user daily gift limit from TRADE_GIFT_DAILY_LIMIT (0: unlimited)
+func giftDailyLimitFromEnv(logger *zap.Logger) int {
+	value := os.Getenv("TRADE_GIFT_DAILY_LIMIT")
+	if value == "" {
+		return DefaultGiftDailyLimit
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		logger.Warn("Invalid TRADE_GIFT_DAILY_LIMIT, using default",
+			zap.String("value", value),
+			zap.Int("default", DefaultGiftDailyLimit))
+		return DefaultGiftDailyLimit
+	}
+	return limit
+}
+
+// Gifts
+
+func (s *service) SendGift(senderID int, req SendGiftRequest) (*SendGiftResponse, error) {
+	if err := s.validateCounterparty(senderID, req.ReceiverID); err != nil {
+		return nil, err
+	}
+	items, err := s.validateItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	defer s.giftLocks.Lock(senderID)()
+
+	sentToday, err := s.giftsSentToday(senderID)
+	if err != nil {
+		return nil, err
+	}
+	if s.dailyLimit > 0 && sentToday >= s.dailyLimit {
+		return nil, fmt.Errorf("%w: %d gifts per day", ErrDailyGiftLimit, s.dailyLimit)
+	}
+
+	// The gift is recorded first so the ledger entries can reference it
+	gift := &entity.Gift{
+		SenderID:   senderID,
+		ReceiverID: req.ReceiverID,
+		Items:      items,
+		Message:    req.Message,
+	}
+	if err := s.repo.CreateGift(gift); err != nil {
+		s.logger.Error("Failed to create gift", zap.Error(err), zap.Int("sender_id", senderID))
+		return nil, fmt.Errorf("failed to create gift: %w", err)
+	}
+
+	ref := itemEntity.TransactionRef{
+		Type:  ReferenceTypeGift,
+		ID:    strconv.Itoa(gift.ID),
+		Actor: itemEntity.UserActor(senderID),
+	}
+	transfer := itemEntity.ItemTransfer{FromUserID: senderID, ToUserID: req.ReceiverID, Items: items}
+	if err := s.itemService.TransferItems([]itemEntity.ItemTransfer{transfer}, SourceGift, ref); err != nil {
+		if deleteErr := s.repo.DeleteGift(gift.ID); deleteErr != nil {
+			s.logger.Error("Failed to remove unsent gift", zap.Error(deleteErr), zap.Int("gift_id", gift.ID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Gift sent",
+		zap.Int("gift_id", gift.ID),
+		zap.Int("sender_id", senderID),
+		zap.Int("receiver_id", req.ReceiverID))
+
+	return &SendGiftResponse{
+		Gift:           gift,
+		GiftsSentToday: sentToday + 1,
+		DailyLimit:     s.dailyLimit,
+	}, nil
+}
+
+// giftsSentToday counts the gifts a user has sent since the start of the UTC day
+func (s *service) giftsSentToday(senderID int) (int, error) {
+	since := time.Now().UTC().Truncate(24 * time.Hour)
+	gifts, err := s.repo.ListGifts(repository.GiftFilter{SenderID: senderID, Since: since})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count gifts: %w", err)
+	}
+	return len(gifts), nil
+}
+
+func (s *service) ListGifts(userID int, query GiftQuery) ([]*entity.Gift, error) {
+	filter := repository.GiftFilter{Limit: query.Limit}
+	switch query.Direction {
+	case "sent":
+		filter.SenderID = userID
+	case "received":
+		filter.ReceiverID = userID
+	default:
+		filter.UserID = userID
+	}
+
+	gifts, err := s.repo.ListGifts(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gifts: %w", err)
+	}
+	return gifts, nil
+}
+
+// Trade offers
+
+func (s *service) CreateOffer(proposerID int, req CreateOfferRequest) (*entity.TradeOffer, error) {
+	if err := s.validateCounterparty(proposerID, req.RecipientID); err != nil {
+		return nil, err
+	}
+	offered, err := s.validateItems(req.Offered)
+	if err != nil {
+		return nil, err
+	}
+	var requested []itemEntity.RewardItem
+	if len(req.Requested) > 0 {
+		if requested, err = s.validateItems(req.Requested); err != nil {
+			return nil, err
+		}
+	}
+
+	ttl := DefaultOfferTTL
+	if req.ExpiresInMinutes > 0 {
+		ttl = time.Duration(req.ExpiresInMinutes) * time.Minute
+	}
+
+	offer := &entity.TradeOffer{
+		ProposerID:  proposerID,
+		RecipientID: req.RecipientID,
+		Offered:     offered,
+		Requested:   requested,
+		Message:     req.Message,
+		Status:      entity.OfferStatusPending,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if err := s.repo.CreateOffer(offer); err != nil {
+		s.logger.Error("Failed to create trade offer", zap.Error(err), zap.Int("proposer_id", proposerID))
+		return nil, fmt.Errorf("failed to create trade offer: %w", err)
+	}
+
+	s.logger.Info("Trade offer created",
+		zap.Int("offer_id", offer.ID),
+		zap.Int("proposer_id", proposerID),
+		zap.Int("recipient_id", req.RecipientID))
+
+	return offer, nil
+}
+
+func (s *service) GetOffer(userID, offerID int) (*entity.TradeOffer, error) {
+	return s.offerFor(userID, offerID)
+}
+
+// offerFor loads an offer the user is a party to
+func (s *service) offerFor(userID, offerID int) (*entity.TradeOffer, error) {
+	offer, err := s.getOffer(offerID)
+	if err != nil {
+		return nil, err
+	}
+	if !offer.Involves(userID) {
+		return nil, ErrOfferNotFound
+	}
+	return offer, nil
+}
+
+func (s *service) ListOffers(userID int, query OfferQuery) ([]*entity.TradeOffer, error) {
+	filter := repository.OfferFilter{Status: entity.OfferStatus(query.Status), Limit: query.Limit}
+	switch query.Role {
+	case "sent":
+		filter.ProposerID = userID
+	case "received":
+		filter.RecipientID = userID
+	default:
+		filter.UserID = userID
+	}
+	return s.listOffers(filter)
+}
+
+func (s *service) AcceptOffer(userID, offerID int) (*entity.TradeOffer, error) {
+	offer, err := s.pendingOffer(userID, offerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items may have been made untradable since the offer was created
+	for _, items := range [][]itemEntity.RewardItem{offer.Offered, offer.Requested} {
+		if len(items) == 0 {
+			continue
+		}
+		if _, err := s.validateItems(items); err != nil {
+			return nil, err
+		}
+	}
+
+	// Accept the offer before moving items so a concurrent accept or cancel cannot also act on it
+	if err := s.respond(offer, entity.OfferStatusAccepted); err != nil {
+		return nil, err
+	}
+
+	ref := itemEntity.TransactionRef{
+		Type:  ReferenceTypeOffer,
+		ID:    strconv.Itoa(offer.ID),
+		Actor: itemEntity.UserActor(userID),
+	}
+	if err := s.itemService.TransferItems(offer.Transfers(), SourceTrade, ref); err != nil {
+		// 아이템이 이동하지 않았으므로 제안을 다시 대기 상태로 되돌림
+		if _, revertErr := s.repo.UpdateOfferStatus(offer.ID, entity.OfferStatusAccepted, entity.OfferStatusPending, nil); revertErr != nil {
+			s.logger.Error("Failed to reopen trade offer", zap.Error(revertErr), zap.Int("offer_id", offer.ID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Trade offer accepted",
+		zap.Int("offer_id", offer.ID),
+		zap.Int("proposer_id", offer.ProposerID),
+		zap.Int("recipient_id", offer.RecipientID))
+
+	return offer, nil
+}
+
+func (s *service) DeclineOffer(userID, offerID int) (*entity.TradeOffer, error) {
+	offer, err := s.pendingOffer(userID, offerID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.respond(offer, entity.OfferStatusDeclined); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Trade offer declined", zap.Int("offer_id", offer.ID), zap.Int("user_id", userID))
+	return offer, nil
+}
+
+func (s *service) CancelOffer(userID, offerID int) (*entity.TradeOffer, error) {
+	offer, err := s.pendingOffer(userID, offerID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.respond(offer, entity.OfferStatusCancelled); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Trade offer cancelled", zap.Int("offer_id", offer.ID), zap.Int("user_id", userID))
+	return offer, nil
+}
+
+// Audit
+
+func (s *service) ListAllGifts(query AdminTradeQuery) ([]*entity.Gift, error) {
+	gifts, err := s.repo.ListGifts(repository.GiftFilter{UserID: query.UserID, Limit: query.Limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gifts: %w", err)
+	}
+	return gifts, nil
+}
+
+func (s *service) ListAllOffers(query AdminTradeQuery) ([]*entity.TradeOffer, error) {
+	return s.listOffers(repository.OfferFilter{
+		UserID: query.UserID,
+		Status: entity.OfferStatus(query.Status),
+		Limit:  query.Limit,
+	})
+}
+
+// getOffer loads an offer, marking it expired first if it ran past its expiry
+func (s *service) getOffer(offerID int) (*entity.TradeOffer, error) {
+	offer, err := s.repo.GetOffer(offerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOfferNotFound) {
+			return nil, ErrOfferNotFound
+		}
+		return nil, fmt.Errorf("failed to get trade offer: %w", err)
+	}
+	if err := s.expire(offer); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+func (s *service) listOffers(filter repository.OfferFilter) ([]*entity.TradeOffer, error) {
+	// Expiry is applied before filtering so a pending filter never returns expired offers
+	status := filter.Status
+	filter.Status = ""
+	limit := filter.Limit
+	filter.Limit = 0
+
+	offers, err := s.repo.ListOffers(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trade offers: %w", err)
+	}
+
+	result := make([]*entity.TradeOffer, 0, len(offers))
+	for _, offer := range offers {
+		if err := s.expire(offer); err != nil {
+			return nil, err
+		}
+		if status != "" && offer.Status != status {
+			continue
+		}
+		result = append(result, offer)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// expire closes a pending offer whose expiry has passed
+func (s *service) expire(offer *entity.TradeOffer) error {
+	if !offer.IsExpired(time.Now()) {
+		return nil
+	}
+	respondedAt := offer.ExpiresAt
+	updated, err := s.repo.UpdateOfferStatus(offer.ID, entity.OfferStatusPending, entity.OfferStatusExpired, &respondedAt)
+	if errors.Is(err, repository.ErrOfferStatusChanged) {
+		// 다른 요청이 먼저 상태를 바꿨으면 저장된 상태를 따름
+		updated, err = s.repo.GetOffer(offer.ID)
+	}
+	if err != nil {
+		s.logger.Error("Failed to expire trade offer", zap.Error(err), zap.Int("offer_id", offer.ID))
+		return fmt.Errorf("failed to expire trade offer: %w", err)
+	}
+	*offer = *updated
+	return nil
+}
+
+// pendingOffer loads an offer the user can still respond to: the recipient accepts or
+// declines, the proposer cancels.
+func (s *service) pendingOffer(userID, offerID int, asProposer bool) (*entity.TradeOffer, error) {
+	offer, err := s.offerFor(userID, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if (asProposer && offer.ProposerID != userID) || (!asProposer && offer.RecipientID != userID) {
+		return nil, ErrNotOfferParty
+	}
+	if offer.Status == entity.OfferStatusExpired {
+		return nil, ErrOfferExpired
+	}
+	if offer.Status != entity.OfferStatusPending {
+		return nil, fmt.Errorf("%w: %s", ErrOfferNotPending, offer.Status)
+	}
+	return offer, nil
+}
+
+// respond closes a pending offer; it fails with ErrOfferNotPending if another request closed it first
+func (s *service) respond(offer *entity.TradeOffer, status entity.OfferStatus) error {
+	now := time.Now()
+	updated, err := s.repo.UpdateOfferStatus(offer.ID, entity.OfferStatusPending, status, &now)
+	if err != nil {
+		if errors.Is(err, repository.ErrOfferStatusChanged) {
+			return fmt.Errorf("%w: changed by another request", ErrOfferNotPending)
+		}
+		s.logger.Error("Failed to update trade offer", zap.Error(err), zap.Int("offer_id", offer.ID))
+		return fmt.Errorf("failed to update trade offer: %w", err)
+	}
+	*offer = *updated
+	return nil
+}
+
+// validateCounterparty checks that the other side is a different, existing user
+func (s *service) validateCounterparty(userID, otherID int) error {
+	if userID == otherID {
+		return fmt.Errorf("%w: cannot trade with yourself", ErrInvalidTrade)
+	}
+	if _, err := s.userService.GetUser(otherID); err != nil {
+		if errors.Is(err, userRepository.ErrUserNotFound) {
+			return ErrRecipientNotFound
+		}
+		return fmt.Errorf("failed to get recipient: %w", err)
+	}
+	return nil
+}
+
+// validateItems checks that every item exists and is tradable and returns the items merged
+// per item ID. Expiry is not part of a trade; moved units keep their own.
+func (s *service) validateItems(items []itemEntity.RewardItem) ([]itemEntity.RewardItem, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no items", ErrInvalidTrade)
+	}
+
+	normalized := make([]itemEntity.RewardItem, 0, len(items))
+	for _, reward := range items {
+		if reward.Count <= 0 {
+			return nil, fmt.Errorf("%w: invalid count for item %d", ErrInvalidTrade, reward.ItemID)
+		}
+		template, err := s.itemService.GetItem(reward.ItemID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: item %d not found", ErrInvalidTrade, reward.ItemID)
+		}
+		if !template.Tradable || template.IsArchived() {
+			return nil, fmt.Errorf("%w: item %d", ErrItemNotTradable, reward.ItemID)
+		}
+		normalized = append(normalized, itemEntity.RewardItem{ItemID: reward.ItemID, Count: reward.Count})
+	}
+	return itemEntity.MergeRewardItems(normalized), nil
+}
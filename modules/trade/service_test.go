@@ -0,0 +1,214 @@
+package trade
+
+import (
+	"testing"
+	"time"
+
+	"fxserver/modules/item"
+	itemEntity "fxserver/modules/item/entity"
+	"fxserver/modules/item/itemtest"
+	itemRepository "fxserver/modules/item/repository"
+	"fxserver/modules/trade/entity"
+	"fxserver/modules/trade/repository"
+	"fxserver/modules/user"
+	userRepository "fxserver/modules/user/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	goldID    = 1 // tradable currency
+	diamondID = 2 // not tradable
+	potionID  = 3 // tradable consumable
+	swordID   = 4 // tradable equipment
+)
+
+type fixture struct {
+	svc     Service
+	items   *itemtest.Fixture
+	aliceID int
+	bobID   int
+}
+
+func setupTradeService(t *testing.T) fixture {
+	logger := zap.NewNop()
+	items := itemtest.New()
+	userService := user.NewService(userRepository.NewMemoryUserRepository(), logger)
+
+	var ids []int
+	for _, name := range []string{"alice", "bob"} {
+		created, err := userService.CreateUser(user.CreateUserRequest{
+			Name:     name,
+			Email:    name + "@example.com",
+			Age:      20,
+			Password: "password123",
+		})
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+
+	svc := NewService(ServiceParam{
+		Repository:  repository.NewMemoryRepository(),
+		ItemService: items.Service,
+		UserService: userService,
+		Logger:      logger,
+	})
+	return fixture{svc: svc, items: items, aliceID: ids[0], bobID: ids[1]}
+}
+
+func TestSendGift(t *testing.T) {
+	f := setupTradeService(t)
+	require.NoError(t, f.items.Items.AddToInventory(f.aliceID, goldID, 1000, "admin", itemEntity.TransactionRef{}))
+	require.NoError(t, f.items.Items.AddToInventory(f.aliceID, diamondID, 10, "admin", itemEntity.TransactionRef{}))
+
+	response, err := f.svc.SendGift(f.aliceID, SendGiftRequest{
+		ReceiverID: f.bobID,
+		Items:      []itemEntity.RewardItem{{ItemID: goldID, Count: 300}},
+		Message:    "for you",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.GiftsSentToday)
+	assert.Equal(t, 700, f.items.Balance(f.aliceID, goldID))
+	assert.Equal(t, 300, f.items.Balance(f.bobID, goldID))
+
+	// Both sides get a ledger entry referencing the gift
+	for _, userID := range []int{f.aliceID, f.bobID} {
+		entries, err := f.items.Items.GetInventoryTransactions(itemRepository.TransactionFilter{UserID: userID, Source: SourceGift})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, ReferenceTypeGift, entries[0].ReferenceType)
+		assert.Equal(t, itemEntity.UserActor(f.aliceID), entries[0].Actor)
+	}
+
+	// Untradable items, self-gifts and unknown receivers are rejected
+	_, err = f.svc.SendGift(f.aliceID, SendGiftRequest{ReceiverID: f.bobID, Items: []itemEntity.RewardItem{{ItemID: diamondID, Count: 1}}})
+	assert.ErrorIs(t, err, ErrItemNotTradable)
+	_, err = f.svc.SendGift(f.aliceID, SendGiftRequest{ReceiverID: f.aliceID, Items: []itemEntity.RewardItem{{ItemID: goldID, Count: 1}}})
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+	_, err = f.svc.SendGift(f.aliceID, SendGiftRequest{ReceiverID: 999, Items: []itemEntity.RewardItem{{ItemID: goldID, Count: 1}}})
+	assert.ErrorIs(t, err, ErrRecipientNotFound)
+
+	// A failed transfer leaves no gift behind
+	_, err = f.svc.SendGift(f.aliceID, SendGiftRequest{ReceiverID: f.bobID, Items: []itemEntity.RewardItem{{ItemID: goldID, Count: 5000}}})
+	assert.ErrorIs(t, err, item.ErrInsufficientItem)
+	gifts, err := f.svc.ListGifts(f.aliceID, GiftQuery{Direction: "sent"})
+	require.NoError(t, err)
+	assert.Len(t, gifts, 1)
+}
+
+func TestDailyGiftLimit(t *testing.T) {
+	t.Setenv("TRADE_GIFT_DAILY_LIMIT", "2")
+	f := setupTradeService(t)
+	require.NoError(t, f.items.Items.AddToInventory(f.aliceID, goldID, 100, "admin", itemEntity.TransactionRef{}))
+
+	gift := SendGiftRequest{ReceiverID: f.bobID, Items: []itemEntity.RewardItem{{ItemID: goldID, Count: 10}}}
+	for i := 0; i < 2; i++ {
+		_, err := f.svc.SendGift(f.aliceID, gift)
+		require.NoError(t, err)
+	}
+	_, err := f.svc.SendGift(f.aliceID, gift)
+	assert.ErrorIs(t, err, ErrDailyGiftLimit)
+	assert.Equal(t, 80, f.items.Balance(f.aliceID, goldID))
+
+	// The limit is per sender
+	_, err = f.svc.SendGift(f.bobID, SendGiftRequest{ReceiverID: f.aliceID, Items: []itemEntity.RewardItem{{ItemID: goldID, Count: 5}}})
+	assert.NoError(t, err)
+}
+
+func TestTradeOfferAccept(t *testing.T) {
+	f := setupTradeService(t)
+	require.NoError(t, f.items.Items.AddToInventory(f.aliceID, swordID, 1, "admin", itemEntity.TransactionRef{}))
+	require.NoError(t, f.items.Items.AddToInventory(f.bobID, goldID, 500, "admin", itemEntity.TransactionRef{}))
+
+	swords, err := f.items.Items.GetUserInstances(f.aliceID)
+	require.NoError(t, err)
+	require.Len(t, swords, 1)
+	swords[0].Level = 7
+	require.NoError(t, f.items.Items.UpdateInstance(swords[0]))
+
+	offer, err := f.svc.CreateOffer(f.aliceID, CreateOfferRequest{
+		RecipientID: f.bobID,
+		Offered:     []itemEntity.RewardItem{{ItemID: swordID, Count: 1}},
+		Requested:   []itemEntity.RewardItem{{ItemID: goldID, Count: 400}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.OfferStatusPending, offer.Status)
+
+	// Only the recipient can accept, only the proposer can cancel
+	_, err = f.svc.AcceptOffer(f.aliceID, offer.ID)
+	assert.ErrorIs(t, err, ErrNotOfferParty)
+	_, err = f.svc.CancelOffer(f.bobID, offer.ID)
+	assert.ErrorIs(t, err, ErrNotOfferParty)
+
+	accepted, err := f.svc.AcceptOffer(f.bobID, offer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OfferStatusAccepted, accepted.Status)
+	assert.NotNil(t, accepted.RespondedAt)
+
+	assert.Equal(t, 400, f.items.Balance(f.aliceID, goldID))
+	assert.Equal(t, 100, f.items.Balance(f.bobID, goldID))
+	bobSwords, err := f.items.Items.GetUserInstances(f.bobID)
+	require.NoError(t, err)
+	require.Len(t, bobSwords, 1)
+	assert.Equal(t, swords[0].ID, bobSwords[0].ID)
+	assert.Equal(t, 7, bobSwords[0].Level)
+
+	_, err = f.svc.AcceptOffer(f.bobID, offer.ID)
+	assert.ErrorIs(t, err, ErrOfferNotPending)
+}
+
+func TestTradeOfferIsAllOrNothing(t *testing.T) {
+	f := setupTradeService(t)
+	require.NoError(t, f.items.Items.AddToInventory(f.aliceID, potionID, 5, "admin", itemEntity.TransactionRef{}))
+	require.NoError(t, f.items.Items.AddToInventory(f.bobID, goldID, 50, "admin", itemEntity.TransactionRef{}))
+
+	offer, err := f.svc.CreateOffer(f.aliceID, CreateOfferRequest{
+		RecipientID: f.bobID,
+		Offered:     []itemEntity.RewardItem{{ItemID: potionID, Count: 5}},
+		Requested:   []itemEntity.RewardItem{{ItemID: goldID, Count: 100}},
+	})
+	require.NoError(t, err)
+
+	// Bob cannot pay, so nothing moves and the offer stays open
+	_, err = f.svc.AcceptOffer(f.bobID, offer.ID)
+	assert.ErrorIs(t, err, item.ErrInsufficientItem)
+	assert.Equal(t, 5, f.items.Balance(f.aliceID, potionID))
+	assert.Equal(t, 0, f.items.Balance(f.bobID, potionID))
+	assert.Equal(t, 50, f.items.Balance(f.bobID, goldID))
+
+	declined, err := f.svc.DeclineOffer(f.bobID, offer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OfferStatusDeclined, declined.Status)
+}
+
+func TestTradeOfferExpiry(t *testing.T) {
+	f := setupTradeService(t)
+	require.NoError(t, f.items.Items.AddToInventory(f.aliceID, potionID, 1, "admin", itemEntity.TransactionRef{}))
+
+	offer, err := f.svc.CreateOffer(f.aliceID, CreateOfferRequest{
+		RecipientID: f.bobID,
+		Offered:     []itemEntity.RewardItem{{ItemID: potionID, Count: 1}},
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(DefaultOfferTTL), offer.ExpiresAt, time.Minute)
+
+	// Push the offer past its expiry
+	s := f.svc.(*service)
+	stored, err := s.repo.GetOffer(offer.ID)
+	require.NoError(t, err)
+	stored.ExpiresAt = time.Now().Add(-time.Second)
+	require.NoError(t, s.repo.UpdateOffer(stored))
+
+	_, err = f.svc.AcceptOffer(f.bobID, offer.ID)
+	assert.ErrorIs(t, err, ErrOfferExpired)
+	assert.Equal(t, 1, f.items.Balance(f.aliceID, potionID))
+
+	pending, err := f.svc.ListOffers(f.bobID, OfferQuery{Status: string(entity.OfferStatusPending)})
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	expired, err := f.svc.ListOffers(f.bobID, OfferQuery{Status: string(entity.OfferStatusExpired)})
+	require.NoError(t, err)
+	assert.Len(t, expired, 1)
+}
@@ -6,6 +6,7 @@ type ListResponse[T any] struct {
 	Data     []T    `json:"data"`              // Array of items
 	HasMore  bool   `json:"has_more"`          // Whether there are more items
 	TotalCount int  `json:"total_count,omitempty"` // Total count if available
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor for the next page (cursor-paginated lists only)
 }
 
 // EmptyResponse for operations that don't return data (like delete)
@@ -31,6 +32,18 @@ func NewList[T any](data []T, hasMore ...bool) ListResponse[T] {
 	}
 }
 
+// NewCursorList creates a list response for one page of a cursor-paginated query.
+// total is the number of matches across all pages; an empty nextCursor marks the last page.
+func NewCursorList[T any](data []T, nextCursor string, total int) ListResponse[T] {
+	return ListResponse[T]{
+		Object:     "list",
+		Data:       data,
+		HasMore:    nextCursor != "",
+		TotalCount: total,
+		NextCursor: nextCursor,
+	}
+}
+
 // NewEmpty creates an empty success response for deletions
 func NewEmpty(id string) EmptyResponse {
 	return EmptyResponse{
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Event is a domain event published after a state change has been committed.
+// Modules that own the change declare their event type constants (e.g. payment.EventPaymentCompleted).
+type Event struct {
+	Type       string
+	UserID     int
+	Value      int               // 이벤트가 나타내는 수량 (사용한 아이템 개수 등), 기본 1
+	Attributes map[string]string // 이벤트별 부가 정보 (item_id, payment_id 등)
+	OccurredAt time.Time
+}
+
+// Handler receives published events. Handlers run synchronously on the publishing
+// request, so they should return quickly and must not call back into the publisher.
+type Handler func(event Event)
+
+// Bus delivers events to the handlers subscribed to their type.
+// Subscribers register from an fx.Invoke rather than being injected into the bus, so
+// a module can react to events from services it (indirectly) depends on without a
+// dependency cycle. A nil *Bus drops every event, which keeps services usable without one,
+// so publishing services take it as an optional dependency (`optional:"true"` in their ServiceParam).
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	logger   *zap.Logger
+}
+
+func NewBus(logger *zap.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+		logger:   logger,
+	}
+}
+
+// Subscribe registers handler for the given event types
+func (b *Bus) Subscribe(handler Handler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, eventType := range eventTypes {
+		b.handlers[eventType] = append(b.handlers[eventType], handler)
+	}
+}
+
+// Publish delivers event to its subscribers. A panicking handler is logged and does not
+// affect the publisher or the other handlers.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Value == 0 {
+		event.Value = 1
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers[event.Type]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.deliver(handler, event)
+	}
+}
+
+func (b *Bus) deliver(handler Handler, event Event) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			b.logger.Error("Event handler panicked",
+				zap.String("event_type", event.Type),
+				zap.Int("user_id", event.UserID),
+				zap.Any("panic", recovered))
+		}
+	}()
+	handler(event)
+}
@@ -0,0 +1,501 @@
+// Package expr evaluates small arithmetic expressions written by admins, such as
+// reward rules ("hours * 10 * (paid_30d > 0 ? 2 : 1)").
+//
+// The language has no assignments, loops or user-defined functions, so every expression
+// finishes in time proportional to its length. Values are float64; comparisons and
+// logical operators yield 1 (true) or 0 (false) and any non-zero value counts as true.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrSyntax          = errors.New("syntax error")
+	ErrUnknownVariable = errors.New("unknown variable")
+	ErrDivisionByZero  = errors.New("division by zero")
+	ErrInvalidResult   = errors.New("result is not a finite number")
+)
+
+const (
+	// MaxLength caps the source length of an expression
+	MaxLength = 1000
+	// maxDepth caps nesting so deeply nested input cannot exhaust the stack
+	maxDepth = 32
+)
+
+// Expr is a parsed expression, safe for concurrent use
+type Expr struct {
+	source    string
+	root      node
+	variables []string
+}
+
+// Parse compiles an expression. The error wraps ErrSyntax with the position of the problem.
+func Parse(source string) (*Expr, error) {
+	if strings.TrimSpace(source) == "" {
+		return nil, fmt.Errorf("%w: empty expression", ErrSyntax)
+	}
+	if len(source) > MaxLength {
+		return nil, fmt.Errorf("%w: expression longer than %d characters", ErrSyntax, MaxLength)
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, variables: make(map[string]bool)}
+	root, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+
+	variables := make([]string, 0, len(p.variables))
+	for name := range p.variables {
+		variables = append(variables, name)
+	}
+	slices.Sort(variables)
+
+	return &Expr{source: source, root: root, variables: variables}, nil
+}
+
+// String returns the source the expression was parsed from
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Variables returns the sorted names of the variables the expression reads
+func (e *Expr) Variables() []string {
+	return slices.Clone(e.variables)
+}
+
+// Eval evaluates the expression; every variable it reads must be present in vars
+func (e *Expr) Eval(vars map[string]float64) (float64, error) {
+	value, err := e.root.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ErrInvalidResult
+	}
+	return value, nil
+}
+
+// Tokenizer
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// operators lists two-character operators before their one-character prefixes
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", ","}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case isDigit(c) || (c == '.' && i+1 < len(source) && isDigit(source[i+1])):
+			start := i
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			value, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid number %q at position %d", ErrSyntax, source[start:i], start+1)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: source[start:i], value: value, pos: start})
+
+		case isIdentStart(c):
+			start := i
+			for i < len(source) && (isIdentStart(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], pos: start})
+
+		default:
+			index := slices.IndexFunc(operators, func(op string) bool { return strings.HasPrefix(source[i:], op) })
+			if index < 0 {
+				return nil, fmt.Errorf("%w: unexpected character %q at position %d", ErrSyntax, c, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operators[index], pos: i})
+			i += len(operators[index])
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Parser
+
+type parser struct {
+	tokens    []token
+	pos       int
+	depth     int
+	variables map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token when it is one of the given operators
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind == tokenOperator && slices.Contains(ops, t.text) {
+		p.pos++
+		return t.text, true
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%w: unexpected end of expression", ErrSyntax)
+	}
+	return fmt.Errorf("%w: unexpected %q at position %d", ErrSyntax, t.text, t.pos+1)
+}
+
+func (p *parser) parseTernary() (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, fmt.Errorf("%w: expression nested deeper than %d levels", ErrSyntax, maxDepth)
+	}
+
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+// precedence lists binary operators from the loosest to the tightest binding level
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(precedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	op, ok := p.accept("-", "!")
+	if !ok {
+		return p.parsePrimary()
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, fmt.Errorf("%w: expression nested deeper than %d levels", ErrSyntax, maxDepth)
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &unaryNode{op: op, operand: operand}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		return numberNode(t.value), nil
+
+	case tokenIdent:
+		p.next()
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		p.variables[t.text] = true
+		return variableNode(t.text), nil
+
+	case tokenOperator:
+		if t.text == "(" {
+			p.next()
+			inner, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, exists := functions[name.text]
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown function %q at position %d", ErrSyntax, name.text, name.pos+1)
+	}
+
+	var args []node
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("%w: wrong number of arguments to %s at position %d", ErrSyntax, name.text, name.pos+1)
+	}
+	return &callNode{fn: fn, args: args}, nil
+}
+
+// Functions
+
+type function struct {
+	minArgs int
+	maxArgs int // -1: 가변 인자
+	apply   func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"min":   {minArgs: 1, maxArgs: -1, apply: func(args []float64) float64 { return slices.Min(args) }},
+	"max":   {minArgs: 1, maxArgs: -1, apply: func(args []float64) float64 { return slices.Max(args) }},
+	"abs":   {minArgs: 1, maxArgs: 1, apply: func(args []float64) float64 { return math.Abs(args[0]) }},
+	"floor": {minArgs: 1, maxArgs: 1, apply: func(args []float64) float64 { return math.Floor(args[0]) }},
+	"ceil":  {minArgs: 1, maxArgs: 1, apply: func(args []float64) float64 { return math.Ceil(args[0]) }},
+	"round": {minArgs: 1, maxArgs: 1, apply: func(args []float64) float64 { return math.Round(args[0]) }},
+	"clamp": {minArgs: 3, maxArgs: 3, apply: func(args []float64) float64 { return math.Min(math.Max(args[0], args[1]), args[2]) }},
+}
+
+// Syntax tree
+
+type node interface {
+	eval(vars map[string]float64) (float64, error)
+}
+
+type numberNode float64
+
+func (n numberNode) eval(map[string]float64) (float64, error) {
+	return float64(n), nil
+}
+
+type variableNode string
+
+func (n variableNode) eval(vars map[string]float64) (float64, error) {
+	value, exists := vars[string(n)]
+	if !exists {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownVariable, string(n))
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(vars map[string]float64) (float64, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "!" {
+		return boolValue(value == 0), nil
+	}
+	return -value, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(vars map[string]float64) (float64, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+
+	// 논리 연산자는 오른쪽을 필요할 때만 평가 (예: count > 0 && total / count > 10)
+	switch n.op {
+	case "&&":
+		if left == 0 {
+			return 0, nil
+		}
+	case "||":
+		if left != 0 {
+			return 1, nil
+		}
+	}
+
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		return boolValue(right != 0), nil
+	case "==":
+		return boolValue(left == right), nil
+	case "!=":
+		return boolValue(left != right), nil
+	case "<":
+		return boolValue(left < right), nil
+	case "<=":
+		return boolValue(left <= right), nil
+	case ">":
+		return boolValue(left > right), nil
+	case ">=":
+		return boolValue(left >= right), nil
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return left / right, nil
+	case "%":
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return math.Mod(left, right), nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", n.op)
+}
+
+type ternaryNode struct {
+	cond, then, otherwise node
+}
+
+func (n *ternaryNode) eval(vars map[string]float64) (float64, error) {
+	cond, err := n.cond.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	if cond != 0 {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+type callNode struct {
+	fn   function
+	args []node
+}
+
+func (n *callNode) eval(vars map[string]float64) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = value
+	}
+	return n.fn.apply(args), nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
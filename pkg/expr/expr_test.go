@@ -0,0 +1,88 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]float64{"hours": 3, "paid_30d": 1, "age": 20, "count": 0}
+
+	tests := []struct {
+		source string
+		want   float64
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"7 % 4", 3},
+		{"-2 * -3", 6},
+		{"hours * 10 * (paid_30d > 0 ? 2 : 1)", 60},
+		{"age >= 19 && age < 30", 1},
+		{"!(age >= 19) || hours == 2", 0},
+		{"count > 0 && 100 / count > 10", 0}, // 오른쪽은 평가하지 않음
+		{"age < 18 ? 1 : age < 30 ? 2 : 3", 2},
+		{"min(hours, 2) + max(1, 5, 4)", 7},
+		{"floor(2.7) + ceil(0.2) + round(1.5) + abs(-1)", 6},
+		{"clamp(hours * 100, 0, 250)", 250},
+		{".5 * 4", 2},
+	}
+
+	for _, tt := range tests {
+		e, err := Parse(tt.source)
+		require.NoError(t, err, tt.source)
+		got, err := e.Eval(vars)
+		require.NoError(t, err, tt.source)
+		assert.Equal(t, tt.want, got, tt.source)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	e, err := Parse("max(item_2, hours) * hours + payment_total")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hours", "item_2", "payment_total"}, e.Variables())
+
+	_, err = e.Eval(map[string]float64{"hours": 1, "item_2": 1})
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 2",
+		"hours ? 1",
+		"sqrt(4)",
+		"clamp(1, 2)",
+		"1 = 2",
+		"$hours",
+		"1.2.3",
+		strings.Repeat("(", 40) + "1" + strings.Repeat(")", 40),
+		strings.Repeat("-", 40) + "1",
+		strings.Repeat("1+", MaxLength),
+	} {
+		_, err := Parse(source)
+		assert.ErrorIs(t, err, ErrSyntax, source)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	e, err := Parse("10 / count")
+	require.NoError(t, err)
+	_, err = e.Eval(map[string]float64{"count": 0})
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	e, err = Parse("10 % count")
+	require.NoError(t, err)
+	_, err = e.Eval(map[string]float64{"count": 0})
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+
+	e, err = Parse("big * big")
+	require.NoError(t, err)
+	_, err = e.Eval(map[string]float64{"big": 1e308})
+	assert.ErrorIs(t, err, ErrInvalidResult)
+}
@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Locale is a supported client language
+type Locale string
+
+const (
+	Korean   Locale = "ko"
+	English  Locale = "en"
+	Japanese Locale = "ja"
+
+	// FallbackLocale is used when the client asks for nothing we support.
+	// Untranslated master data (item names, descriptions) is written in this locale.
+	FallbackLocale = Korean
+)
+
+// Supported lists every locale we ship, fallback first
+var Supported = []Locale{Korean, English, Japanese}
+
+// IsSupported reports whether the locale is one we ship
+func IsSupported(locale Locale) bool {
+	for _, supported := range Supported {
+		if locale == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse maps a language tag such as "en-US" or "JA" to a supported locale
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	locale := Locale(tag)
+	return locale, IsSupported(locale)
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header
+func Negotiate(acceptLanguage string) Locale {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{tag: tag, quality: quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	for _, c := range candidates {
+		if locale, ok := Parse(c.tag); ok {
+			return locale
+		}
+	}
+	return FallbackLocale
+}
+
+// FromRequest returns the locale for a request: the lang query parameter wins over
+// Accept-Language, and unsupported values fall back to FallbackLocale
+func FromRequest(c echo.Context) Locale {
+	if lang := c.QueryParam("lang"); lang != "" {
+		if locale, ok := Parse(lang); ok {
+			return locale
+		}
+	}
+	return Negotiate(c.Request().Header.Get("Accept-Language"))
+}
+
+// Text holds one string in several locales
+type Text map[Locale]string
+
+// Get returns the text for the locale, falling back to FallbackLocale and then to any translation
+func (t Text) Get(locale Locale) string {
+	if value := t[locale]; value != "" {
+		return value
+	}
+	if value := t[FallbackLocale]; value != "" {
+		return value
+	}
+	for _, supported := range Supported {
+		if value := t[supported]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{"", FallbackLocale},
+		{"en-US,en;q=0.9", English},
+		{"fr-FR, ja;q=0.8, en;q=0.5", Japanese},
+		{"en;q=0.3, ja;q=0.7", Japanese},
+		{"de, *;q=0.5", FallbackLocale},
+		{"ja;q=0, en", English},
+		{"KO-kr", Korean},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.header), tt.header)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest("GET", "/?lang=ja", nil)
+	req.Header.Set("Accept-Language", "en")
+	assert.Equal(t, Japanese, FromRequest(e.NewContext(req, httptest.NewRecorder())))
+
+	// An unsupported lang falls through to the header
+	req = httptest.NewRequest("GET", "/?lang=fr", nil)
+	req.Header.Set("Accept-Language", "en-GB")
+	assert.Equal(t, English, FromRequest(e.NewContext(req, httptest.NewRecorder())))
+}
+
+func TestTextFallback(t *testing.T) {
+	text := Text{Korean: "골드", English: "Gold"}
+	assert.Equal(t, "Gold", text.Get(English))
+	assert.Equal(t, "골드", text.Get(Japanese))
+	assert.Equal(t, "Gold", Text{English: "Gold"}.Get(Japanese))
+	assert.Equal(t, "", Text{}.Get(English))
+}
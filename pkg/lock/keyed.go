@@ -0,0 +1,43 @@
+package lock
+
+import "sync"
+
+// Keyed hands out one mutex per key, so requests for different players (or entities)
+// never wait on each other. Entries are dropped once nobody holds or waits on them.
+// The zero value is ready to use.
+type Keyed[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedEntry
+}
+
+type keyedEntry struct {
+	mu   sync.Mutex
+	refs int // 보유 중이거나 대기 중인 호출 수
+}
+
+// Lock blocks until the mutex for key is held and returns the function that releases it
+func (k *Keyed[K]) Lock(key K) (unlock func()) {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[K]*keyedEntry)
+	}
+	entry, ok := k.locks[key]
+	if !ok {
+		entry = &keyedEntry{}
+		k.locks[key] = entry
+	}
+	entry.refs++
+	k.mu.Unlock()
+
+	entry.mu.Lock()
+	return func() {
+		entry.mu.Unlock()
+
+		k.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
@@ -0,0 +1,49 @@
+package lock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedSerializesSameKey(t *testing.T) {
+	var locks Keyed[int]
+	var wg sync.WaitGroup
+	counter := 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := locks.Lock(1)
+			defer unlock()
+			value := counter
+			time.Sleep(time.Microsecond)
+			counter = value + 1
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, counter)
+	assert.Empty(t, locks.locks, "released entries are dropped")
+}
+
+func TestKeyedDoesNotBlockOtherKeys(t *testing.T) {
+	var locks Keyed[string]
+	unlock := locks.Lock("a")
+	defer unlock()
+
+	done := make(chan struct{})
+	go func() {
+		locks.Lock("b")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock on another key waited for key a")
+	}
+}